@@ -0,0 +1,203 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 支持自动补全的 shell
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+// 补全时可用的参数
+type completionFlag struct {
+	name   string
+	usage  string
+	isBool bool     // 是否为不带值的参数
+	isDir  bool     // 值是否为目录
+	values []string // 可选的值，为空表示任意值
+}
+
+// 将 shell 对应的自动补全脚本写入 w，补全的内容来自于 fs 中定义的参数。
+func writeCompletion(w io.Writer, shell string, fs *flag.FlagSet) error {
+	flags := completionFlags(fs)
+
+	buf := new(bytes.Buffer)
+	switch shell {
+	case "bash":
+		bashCompletion(buf, flags)
+	case "zsh":
+		zshCompletion(buf, flags)
+	case "fish":
+		fishCompletion(buf, flags)
+	case "powershell":
+		powershellCompletion(buf, flags)
+	default:
+		return errors.New(locale.Sprintf(locale.FlagInvalidCompletion, shell, strings.Join(completionShells, ",")))
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// 从 fs 中获取所有的参数，并为部分参数指定可选的值。
+func completionFlags(fs *flag.FlagSet) []*completionFlag {
+	outputs := []string{output.TypeHTML, output.TypeJSON, output.TypeRAML, output.TypeBlueprint, output.TypeSingle, output.TypeWord, output.TypePDF, output.TypeAsciidoc}
+	for i, typ := range outputs {
+		outputs[i] = typ + ":"
+	}
+
+	values := map[string][]string{
+		"format":           {vars.FormatText, vars.FormatJSON},
+		"pprof":            {vars.PprofCPU, vars.PprofMem},
+		"completion":       completionShells,
+		"output":           outputs,
+		"environment":      {types.EnvironmentProd, types.EnvironmentStaging, types.EnvironmentDev},
+		"export-lang-defs": input.Languages(),
+	}
+
+	flags := make([]*completionFlag, 0, 20)
+	fs.VisitAll(func(f *flag.Flag) {
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		flags = append(flags, &completionFlag{
+			name:   f.Name,
+			usage:  f.Usage,
+			isBool: ok && b.IsBoolFlag(),
+			isDir:  f.Name == "wd" || f.Name == "mock",
+			values: values[f.Name],
+		})
+	})
+
+	sort.Slice(flags, func(i, j int) bool { return flags[i].name < flags[j].name })
+	return flags
+}
+
+func bashCompletion(buf *bytes.Buffer, flags []*completionFlag) {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+	}
+
+	buf.WriteString("# bash completion for " + vars.Name + "\n\n")
+	buf.WriteString("_" + vars.Name + "() {\n")
+	buf.WriteString("    local cur prev\n")
+	buf.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	buf.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n\n")
+	buf.WriteString("    case \"$prev\" in\n")
+	for _, f := range flags {
+		switch {
+		case f.isBool:
+			continue
+		case f.isDir:
+			buf.WriteString("        -" + f.name + ")\n            COMPREPLY=($(compgen -d -- \"$cur\"))\n            return;;\n")
+		case len(f.values) > 0:
+			buf.WriteString("        -" + f.name + ")\n            COMPREPLY=($(compgen -W \"" + strings.Join(f.values, " ") + "\" -- \"$cur\"))\n            return;;\n")
+		default:
+			buf.WriteString("        -" + f.name + ")\n            COMPREPLY=()\n            return;;\n")
+		}
+	}
+	buf.WriteString("    esac\n\n")
+	buf.WriteString("    COMPREPLY=($(compgen -W \"" + strings.Join(names, " ") + "\" -- \"$cur\"))\n")
+	buf.WriteString("}\n\n")
+	buf.WriteString("complete -F _" + vars.Name + " " + vars.Name + "\n")
+}
+
+func zshCompletion(buf *bytes.Buffer, flags []*completionFlag) {
+	buf.WriteString("#compdef " + vars.Name + "\n\n")
+	buf.WriteString("_arguments \\\n")
+	for _, f := range flags {
+		spec := "-" + f.name + "[" + zshEscape(f.usage) + "]"
+		switch {
+		case f.isBool:
+		case f.isDir:
+			spec += ":" + f.name + ":_files -/"
+		case len(f.values) > 0:
+			spec += ":" + f.name + ":(" + strings.Join(f.values, " ") + ")"
+		default:
+			spec += ":" + f.name + ":"
+		}
+		buf.WriteString("    '" + spec + "' \\\n")
+	}
+	buf.WriteString("    && return 0\n")
+}
+
+// zsh 的 _arguments 中，描述内容里的 \、[、]、: 需要转义，
+// 同时整个内容被包含在单引号中，单引号也需要转义。
+func zshEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`, "\n", " ").Replace(s)
+	return strings.Replace(s, "'", `'\''`, -1)
+}
+
+func fishCompletion(buf *bytes.Buffer, flags []*completionFlag) {
+	buf.WriteString("# fish completion for " + vars.Name + "\n\n")
+	for _, f := range flags {
+		buf.WriteString("complete -c " + vars.Name + " -o " + f.name)
+		switch {
+		case f.isBool:
+			buf.WriteString(" -f")
+		case f.isDir:
+			buf.WriteString(" -r -a '(__fish_complete_directories)'")
+		case len(f.values) > 0:
+			buf.WriteString(" -x -a '" + strings.Join(f.values, " ") + "'")
+		default:
+			buf.WriteString(" -x")
+		}
+		buf.WriteString(" -d " + singleQuote(f.usage, `\'`) + "\n")
+	}
+}
+
+func powershellCompletion(buf *bytes.Buffer, flags []*completionFlag) {
+	buf.WriteString("# powershell completion for " + vars.Name + "\n\n")
+	buf.WriteString("Register-ArgumentCompleter -Native -CommandName " + vars.Name + " -ScriptBlock {\n")
+	buf.WriteString("    param($wordToComplete, $commandAst, $cursorPosition)\n\n")
+	buf.WriteString("    $elements = $commandAst.CommandElements\n")
+	buf.WriteString("    $index = $elements.Count - 1\n")
+	buf.WriteString("    if ($wordToComplete -ne '') { $index = $index - 1 }\n")
+	buf.WriteString("    $prev = ''\n")
+	buf.WriteString("    if ($index -gt 0) { $prev = $elements[$index].ToString() }\n\n")
+	buf.WriteString("    $values = switch ($prev) {\n")
+	for _, f := range flags {
+		if len(f.values) > 0 {
+			buf.WriteString("        '-" + f.name + "' { @(" + powershellList(f.values) + ") }\n")
+		}
+	}
+	buf.WriteString("        default { $null }\n")
+	buf.WriteString("    }\n\n")
+
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+	}
+	buf.WriteString("    if ($null -eq $values) { $values = @(" + powershellList(names) + ") }\n")
+	buf.WriteString("    $values | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	buf.WriteString("        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n")
+	buf.WriteString("    }\n")
+	buf.WriteString("}\n")
+}
+
+func powershellList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, singleQuote(item, "''"))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// 将 s 包含在单引号中，s 中的单引号替换成 escaped。
+func singleQuote(s, escaped string) string {
+	s = strings.Replace(s, "\n", " ", -1)
+	return "'" + strings.Replace(s, "'", escaped, -1) + "'"
+}
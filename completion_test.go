@@ -0,0 +1,87 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func newCompletionFlagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("apidoc", flag.ContinueOnError)
+	fs.Bool("h", false, "显示帮助信息")
+	fs.String("wd", "./", "指定工作目录")
+	fs.String("format", "text", "输出格式 [text:json]")
+	fs.String("port", ":8080", "It's the port")
+	return fs
+}
+
+func TestCompletionFlags(t *testing.T) {
+	a := assert.New(t)
+
+	flags := completionFlags(newCompletionFlagSet())
+	a.Equal(len(flags), 4)
+
+	a.Equal(flags[0].name, "format").
+		False(flags[0].isBool).
+		Equal(flags[0].values, []string{"text", "json"})
+	a.Equal(flags[1].name, "h").True(flags[1].isBool)
+	a.Equal(flags[2].name, "port").False(flags[2].isBool).Empty(flags[2].values)
+	a.Equal(flags[3].name, "wd").True(flags[3].isDir)
+}
+
+func TestWriteCompletion(t *testing.T) {
+	a := assert.New(t)
+	fs := newCompletionFlagSet()
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeCompletion(buf, "bash", fs))
+	a.True(strings.Contains(buf.String(), `compgen -W "text json"`)).
+		True(strings.Contains(buf.String(), `compgen -W "-format -h -port -wd"`))
+
+	buf.Reset()
+	a.NotError(writeCompletion(buf, "zsh", fs))
+	a.True(strings.Contains(buf.String(), `'-format[输出格式 \[text\:json\]]:format:(text json)'`)).
+		True(strings.Contains(buf.String(), `'-port[It'\''s the port]:port:'`))
+
+	buf.Reset()
+	a.NotError(writeCompletion(buf, "fish", fs))
+	a.True(strings.Contains(buf.String(), `complete -c apidoc -o port -x -d 'It\'s the port'`))
+
+	buf.Reset()
+	a.NotError(writeCompletion(buf, "powershell", fs))
+	a.True(strings.Contains(buf.String(), `'-format' { @('text', 'json') }`))
+
+	buf.Reset()
+	a.Error(writeCompletion(buf, "cmd", fs))
+	a.Empty(buf.String())
+}
+
+func TestRunCompletion(t *testing.T) {
+	a := assert.New(t)
+
+	out, code := runMain(a, "-completion", "bash")
+	a.Equal(code, 0).
+		True(strings.Contains(out, "-completion")).
+		True(strings.Contains(out, "bash zsh fish powershell"))
+
+	// 通过 bash 检测语法是否正确
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("未找到 bash")
+	}
+	cmd := exec.Command(bash, "--posix", "-n")
+	cmd.Stdin = strings.NewReader(out)
+	output, err := cmd.CombinedOutput()
+	a.NotError(err, string(output))
+
+	_, code = runMain(a, "-completion", "cmd")
+	a.Equal(code, 1)
+}
@@ -5,9 +5,15 @@
 package main
 
 import (
-	"io/ioutil"
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 
+	"github.com/issue9/utils"
 	"github.com/issue9/version"
 	yaml "gopkg.in/yaml.v2"
 
@@ -15,6 +21,7 @@ import (
 	"github.com/caixw/apidoc/locale"
 	"github.com/caixw/apidoc/output"
 	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
 )
 
 // 项目的配置内容，分别引用到了 input.Options 和 output.Options。
@@ -26,11 +33,14 @@ type config struct {
 	Version string           `yaml:"version"` // 产生此配置文件的程序版本号
 	Inputs  []*input.Options `yaml:"inputs"`  // 输入的配置项，可以指定多个项目
 	Output  *output.Options  `yaml:"output"`
+
+	// -lint 中额外的检测规则，为空表示不启用任何额外的规则
+	Lint *lintRuleSet `yaml:"lint,omitempty"`
 }
 
 // 加载 path 所指的文件内容到 *config 实例。
 func loadConfig(path string) (*config, error) {
-	data, err := ioutil.ReadFile(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -79,3 +89,94 @@ func (cfg *config) sanitize() error {
 
 	return nil
 }
+
+// 根据 wd 所在目录的内容生成一个配置文件，并写入到 wd 目录下的 .apidoc.yaml 中，
+// 返回配置文件的路径。
+//
+// 若 yes 为 false，会通过 w 和 r 向用户询问各个配置项的值，直接回车表示采用默认值；
+// 若 force 为 false，在配置文件已经存在的情况下，会返回错误信息。
+func genConfigFile(wd string, r io.Reader, w io.Writer, yes, force bool) (string, error) {
+	path := filepath.Join(wd, vars.ConfigFilename)
+	if !force && utils.FileExists(path) {
+		return "", errors.New(locale.Sprintf(locale.FlagConfigFileExists, path))
+	}
+
+	o, err := input.Detect(wd, true)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{o},
+		Output: &output.Options{
+			Type: output.TypeHTML,
+			Dir:  filepath.Join(o.Dir, "doc"),
+		},
+	}
+
+	if !yes {
+		promptConfig(cfg, bufio.NewReader(r), w)
+	}
+
+	// 用户输入的内容可能有误，写入之前先检测，防止生成无法加载的配置文件。
+	if err := cfg.sanitize(); err != nil {
+		return "", err
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	if err = os.WriteFile(path, data, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// 询问用户各个配置项的值，并修改 cfg 中的相应内容。
+// cfg 中的值作为各个配置项的默认值。
+func promptConfig(cfg *config, r *bufio.Reader, w io.Writer) {
+	o := cfg.Inputs[0]
+
+	if lang := prompt(r, w, locale.FlagPromptLang, o.Lang); lang != o.Lang {
+		o.Lang = lang
+		o.Exts = nil // 由 input.Options.Sanitize 根据新的语言重新指定
+	}
+
+	dirs := prompt(r, w, locale.FlagPromptInputDirs, o.Dir)
+	cfg.Inputs = cfg.Inputs[:0]
+	for _, dir := range strings.Split(dirs, ",") {
+		dir = strings.TrimSpace(dir)
+		if len(dir) == 0 {
+			continue
+		}
+
+		cfg.Inputs = append(cfg.Inputs, &input.Options{
+			Lang:      o.Lang,
+			Dir:       dir,
+			Exts:      o.Exts,
+			Recursive: o.Recursive,
+		})
+	}
+	if len(cfg.Inputs) == 0 {
+		cfg.Inputs = append(cfg.Inputs, o)
+	}
+
+	cfg.Output.Dir = prompt(r, w, locale.FlagPromptOutputDir, cfg.Output.Dir)
+	cfg.Output.Type = prompt(r, w, locale.FlagPromptOutputType, cfg.Output.Type)
+}
+
+// 输出 key 对应的提示信息，并从 r 中读取一行内容。
+// 若读取的内容为空，则返回 def。
+func prompt(r *bufio.Reader, w io.Writer, key, def string) string {
+	locale.Fprintf(w, key, def)
+
+	line, _ := r.ReadString('\n') // 出错时依然可能读取到部分内容，比如 io.EOF
+	if line = strings.TrimSpace(line); len(line) == 0 {
+		return def
+	}
+	return line
+}
@@ -5,14 +5,19 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/issue9/assert"
+	yaml "gopkg.in/yaml.v2"
 
 	"github.com/caixw/apidoc/input"
 	"github.com/caixw/apidoc/output"
 	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
 )
 
 func TestConfig_sanitize(t *testing.T) {
@@ -50,3 +55,67 @@ func TestConfig_sanitize(t *testing.T) {
 	a.Error(err)
 	a.True(strings.HasPrefix(err.(*types.OptionsError).Field, "inputs[0]"))
 }
+
+func TestGenConfigFile(t *testing.T) {
+	a := assert.New(t)
+
+	wd, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(wd)
+	a.NotError(os.WriteFile(filepath.Join(wd, "main.go"), []byte("package main"), os.ModePerm))
+
+	// 不作询问，全部采用默认值
+	path, err := genConfigFile(wd, nil, nil, true, false)
+	a.NotError(err).Equal(path, filepath.Join(wd, vars.ConfigFilename))
+
+	data, err := os.ReadFile(path)
+	a.NotError(err)
+	cfg := &config{}
+	a.NotError(yaml.Unmarshal(data, cfg))
+
+	cfg, err = loadConfig(path)
+	a.NotError(err).NotNil(cfg)
+	a.Equal(len(cfg.Inputs), 1).
+		Equal(cfg.Inputs[0].Lang, "go").
+		Equal(cfg.Output.Dir, filepath.Join(cfg.Inputs[0].Dir, "doc"))
+
+	// 文件已经存在
+	path, err = genConfigFile(wd, nil, nil, true, false)
+	a.Error(err).Empty(path)
+
+	// 强制覆盖，并通过询问指定各配置项的值
+	in := strings.NewReader("php\n" + wd + "," + wd + "\n\n")
+	out := new(bytes.Buffer)
+	path, err = genConfigFile(wd, in, out, false, true)
+	a.NotError(err).NotEmpty(path).NotEmpty(out.String())
+
+	cfg, err = loadConfig(path)
+	a.NotError(err).NotNil(cfg)
+	a.Equal(len(cfg.Inputs), 2).
+		Equal(cfg.Inputs[0].Lang, "php").
+		Equal(cfg.Inputs[1].Dir, wd).
+		Contains(cfg.Inputs[1].Exts, ".php").
+		Equal(cfg.Output.Type, output.TypeHTML)
+
+	// 指定输出类型
+	in = strings.NewReader("\n\n\n" + output.TypeRAML + "\n")
+	path, err = genConfigFile(wd, in, new(bytes.Buffer), false, true)
+	a.NotError(err)
+	cfg, err = loadConfig(path)
+	a.NotError(err).NotNil(cfg)
+	a.Equal(cfg.Inputs[0].Lang, "go").
+		Equal(cfg.Output.Type, output.TypeRAML)
+
+	// 无效的语言、目录或是输出类型，不会生成配置文件
+	a.NotError(os.Remove(path))
+	for _, answers := range []string{
+		"not-exists-lang\n\n\n\n",
+		"\n" + filepath.Join(wd, "not-exists") + "\n\n\n",
+		"\n\n\nxml\n",
+	} {
+		path, err = genConfigFile(wd, strings.NewReader(answers), new(bytes.Buffer), false, true)
+		a.Error(err, answers).Empty(path)
+		_, err = os.Stat(filepath.Join(wd, vars.ConfigFilename))
+		a.True(os.IsNotExist(err), answers)
+	}
+}
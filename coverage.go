@@ -0,0 +1,76 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/types"
+)
+
+// 覆盖率明细中，表示某项内容是否存在的标记
+const (
+	coverageYes = "✓"
+	coverageNo  = "✗"
+)
+
+// 检测 wd 中配置的文档覆盖率是否达到 min，返回是否达到要求。
+//
+// 覆盖率的计算方式与 -stats 相同，不论是否达到要求，
+// 都会将缺少详细描述或是参数描述的 API 以表格的形式输出到 w。
+func checkCoverage(w io.Writer, wd string, min float64) bool {
+	if min < 0 || min > 100 {
+		erro.Println(locale.Sprintf(locale.FlagInvalidMinCoverage, min))
+		return false
+	}
+
+	cfg, err := load(wd)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	docs, _ := input.Parse(cfg.Inputs...)
+	if err := writeCoverage(w, docs.Uncovered()); err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	coverage := docs.Stats().Coverage
+	if coverage < min {
+		erro.Println(locale.Sprintf(locale.ErrCoverageTooLow, coverage, min))
+		return false
+	}
+
+	info.Println(locale.Sprintf(locale.FlagCoveragePassed, coverage, min))
+	return true
+}
+
+// 将 apis 的覆盖率明细以表格的形式写入 w，apis 为空时不输出任何内容。
+func writeCoverage(w io.Writer, apis []*types.API) error {
+	if len(apis) == 0 {
+		return nil
+	}
+
+	mark := func(ok bool) string {
+		if ok {
+			return coverageYes
+		}
+		return coverageNo
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, locale.Sprintf(locale.FlagCoverageHeader))
+	for _, api := range apis {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", strings.ToUpper(api.Method), api.URL, mark(len(api.Description) > 0), mark(api.HasParams()))
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 在临时目录中生成包含 code 和配置文件的项目，返回该目录。
+func coverageFixture(a *assert.Assertion, code string) string {
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Type: output.TypeJSON, Dir: filepath.Join(dir, "doc")},
+	}
+	data, err := yaml.Marshal(cfg)
+	a.NotError(err)
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.ConfigFilename), data, os.ModePerm))
+
+	return dir
+}
+
+// 覆盖率为 50% 的文档
+const halfCoveredCode = `package main
+
+// @api get /users/{id} user
+// 获取指定用户的信息
+// @apiParam id int 用户 ID
+// @apiSuccess 200 OK
+func user() {}
+
+// @api get /users users
+// @apiSuccess 200 OK
+func users() {}
+`
+
+// 覆盖率为 100% 的文档
+const fullCoveredCode = `package main
+
+// @api get /users/{id} user
+// 获取指定用户的信息
+// @apiParam id int 用户 ID
+// @apiSuccess 200 OK
+func user() {}
+`
+
+func TestCheckCoverage(t *testing.T) {
+	a := assert.New(t)
+
+	dir := coverageFixture(a, halfCoveredCode)
+	defer os.RemoveAll(dir)
+
+	buf := new(bytes.Buffer)
+	a.True(checkCoverage(buf, dir, 50))
+	a.True(strings.Contains(buf.String(), "/users ")).
+		False(strings.Contains(buf.String(), "/users/{id}"))
+
+	buf.Reset()
+	a.False(checkCoverage(buf, dir, 50.1))
+	a.True(strings.Contains(buf.String(), "/users "))
+
+	// 无效的值
+	a.False(checkCoverage(buf, dir, 101))
+	a.False(checkCoverage(buf, dir, -1))
+
+	dir = coverageFixture(a, fullCoveredCode)
+	defer os.RemoveAll(dir)
+
+	buf.Reset()
+	a.True(checkCoverage(buf, dir, 100))
+	a.Equal(buf.Len(), 0)
+}
+
+func TestWriteCoverage(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeCoverage(buf, nil))
+	a.Equal(buf.Len(), 0)
+
+	apis := []*types.API{
+		{Method: "get", URL: "/users"},
+		{Method: "post", URL: "/users", Description: "desc"},
+		{Method: "delete", URL: "/users/{id}", Params: []*types.Param{{Name: "id", Type: "int", Summary: "id"}}},
+	}
+	a.NotError(writeCoverage(buf, apis))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	a.Equal(len(lines), 4)
+	a.Equal(strings.Fields(lines[1]), []string{"GET", "/users", coverageNo, coverageNo})
+	a.Equal(strings.Fields(lines[2]), []string{"POST", "/users", coverageYes, coverageNo})
+	a.Equal(strings.Fields(lines[3]), []string{"DELETE", "/users/{id}", coverageNo, coverageYes})
+}
+
+func TestMain_minCoverage(t *testing.T) {
+	a := assert.New(t)
+
+	dir := coverageFixture(a, halfCoveredCode)
+	defer os.RemoveAll(dir)
+
+	out, code := runMain(a, "-wd", dir, "-min-coverage", "50")
+	a.Equal(code, 0).True(strings.Contains(out, "/users "))
+
+	out, code = runMain(a, "-wd", dir, "-min-coverage", "80")
+	a.Equal(code, 1).True(strings.Contains(out, "/users "))
+
+	_, code = runMain(a, "-wd", dir, "-min-coverage", "200")
+	a.Equal(code, 1)
+
+	// 只检测覆盖率，不生成文档
+	_, err := os.Stat(filepath.Join(dir, "doc"))
+	a.True(os.IsNotExist(err))
+}
@@ -0,0 +1,279 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 两个版本的文档之间的差异，各项均按地址和请求方法排序。
+type docDiff struct {
+	Added    []types.Endpoint `json:"added,omitempty"`
+	Removed  []types.Endpoint `json:"removed,omitempty"`
+	Modified []types.Endpoint `json:"modified,omitempty"`
+
+	// 修改的 API 中新增的 @apiBreakingChange
+	BreakingChanges []*diffBreakingChange `json:"breakingChanges,omitempty"`
+}
+
+// 某一 API 中新增的不兼容变更
+type diffBreakingChange struct {
+	Endpoint    types.Endpoint `json:"endpoint"`
+	Version     string         `json:"version"`
+	Description string         `json:"description"`
+}
+
+func (c *diffBreakingChange) String() string {
+	return c.Endpoint.String() + " v" + c.Version + " " + c.Description
+}
+
+// 比较 wd 与 oldWD 中配置的文档，并将差异以 format 指定的格式输出到 w。
+//
+// 返回是否通过检测，删除 API 和新增的不兼容变更都会破坏已有的调用方，
+// 有删除的 API 或是新增了 @apiBreakingChange 时返回 false。
+func runDiff(w io.Writer, wd, oldWD, format string) bool {
+	format = strings.ToLower(format)
+	switch format {
+	case vars.FormatText, vars.FormatJSON, vars.FormatGitHubActions:
+	default:
+		erro.Println(locale.Sprintf(locale.FlagInvalidFormat))
+		return false
+	}
+
+	docs, err := loadDoc(wd)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+	oldDocs, err := loadDoc(oldWD)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	d, err := diffDocs(oldDocs, docs)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	if err := d.write(w, format); err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	ok := true
+	if len(d.Removed) > 0 {
+		erro.Println(locale.Sprintf(locale.ErrDiffRemoved, len(d.Removed)))
+		ok = false
+	}
+	if len(d.BreakingChanges) > 0 {
+		erro.Println(locale.Sprintf(locale.ErrDiffBreakingChanges, len(d.BreakingChanges)))
+		ok = false
+	}
+	return ok
+}
+
+// 加载 wd 中的配置文件，并解析其中的文档。
+func loadDoc(wd string) (*types.Doc, error) {
+	cfg, err := load(wd)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, _ := input.Parse(cfg.Inputs...)
+	return docs, nil
+}
+
+// 比较旧版本的文档 old 与当前版本的文档 docs。
+//
+// 以请求方法和地址作为 API 的唯一标识，两者都存在，但 JSON 序列化之后的内容不同的，即为修改。
+// 修改的 API 中，仅存在于 docs 中的 @apiBreakingChange 即为新增的不兼容变更。
+func diffDocs(old, docs *types.Doc) (*docDiff, error) {
+	olds, err := marshalAPIs(old)
+	if err != nil {
+		return nil, err
+	}
+	news, err := marshalAPIs(docs)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &docDiff{}
+	for e, data := range news {
+		prev, found := olds[e]
+		switch {
+		case !found:
+			d.Added = append(d.Added, e)
+		case !bytes.Equal(prev, data):
+			d.Modified = append(d.Modified, e)
+		}
+	}
+	for e := range olds {
+		if _, found := news[e]; !found {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+
+	sortEndpoints(d.Added)
+	sortEndpoints(d.Removed)
+	sortEndpoints(d.Modified)
+
+	if len(d.Modified) > 0 {
+		oldAPIs, newAPIs := endpointAPIs(old), endpointAPIs(docs)
+		for _, e := range d.Modified {
+			d.BreakingChanges = append(d.BreakingChanges, diffBreakingChanges(e, oldAPIs[e], newAPIs[e])...)
+		}
+	}
+
+	return d, nil
+}
+
+// 返回 api 中新增的不兼容变更，按 api 中的声明顺序排列。
+func diffBreakingChanges(e types.Endpoint, old, api *types.API) []*diffBreakingChange {
+	var changes []*diffBreakingChange
+LOOP:
+	for _, c := range api.BreakingChanges {
+		for _, prev := range old.BreakingChanges {
+			if *prev == *c {
+				continue LOOP
+			}
+		}
+
+		changes = append(changes, &diffBreakingChange{
+			Endpoint:    e,
+			Version:     c.Version,
+			Description: c.Description,
+		})
+	}
+	return changes
+}
+
+// 以 API 对应的 Endpoint 为键名的 API 集合
+func endpointAPIs(docs *types.Doc) map[types.Endpoint]*types.API {
+	apis := make(map[types.Endpoint]*types.API, len(docs.Apis))
+	for _, api := range docs.Apis {
+		apis[api.Endpoint()] = api
+	}
+	return apis
+}
+
+// 将 docs 中的 API 序列化为 JSON，键名为 API 对应的 Endpoint。
+func marshalAPIs(docs *types.Doc) (map[types.Endpoint][]byte, error) {
+	apis := make(map[types.Endpoint][]byte, len(docs.Apis))
+	for _, api := range docs.Apis {
+		data, err := json.Marshal(api)
+		if err != nil {
+			return nil, err
+		}
+		apis[api.Endpoint()] = data
+	}
+	return apis, nil
+}
+
+func sortEndpoints(endpoints []types.Endpoint) {
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].URL != endpoints[j].URL {
+			return endpoints[i].URL < endpoints[j].URL
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+}
+
+// 将差异以 format 指定的格式写入 w。
+func (d *docDiff) write(w io.Writer, format string) error {
+	switch format {
+	case vars.FormatJSON:
+		data, err := json.MarshalIndent(d, "", strings.Repeat(" ", vars.JSONIndent))
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	case vars.FormatGitHubActions:
+		return d.writeAnnotations(w)
+	default:
+		return d.writeText(w)
+	}
+}
+
+// 以 + - ~ 分别表示新增、删除和修改的 API，! 表示新增的不兼容变更。
+func (d *docDiff) writeText(w io.Writer) error {
+	for _, item := range []struct {
+		mark      string
+		endpoints []types.Endpoint
+	}{
+		{mark: "+", endpoints: d.Added},
+		{mark: "-", endpoints: d.Removed},
+		{mark: "~", endpoints: d.Modified},
+	} {
+		for _, e := range item.endpoints {
+			if _, err := fmt.Fprintln(w, item.mark, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range d.BreakingChanges {
+		if _, err := fmt.Fprintln(w, "!", c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 以 GitHub Actions 的工作流命令输出，删除的 API 和新增的不兼容变更为 error，
+// 修改的为 warning，新增的为 notice。
+//
+// https://docs.github.com/actions/reference/workflow-commands-for-github-actions
+func (d *docDiff) writeAnnotations(w io.Writer) error {
+	changes := make([]fmt.Stringer, 0, len(d.BreakingChanges))
+	for _, c := range d.BreakingChanges {
+		changes = append(changes, c)
+	}
+
+	for _, item := range []struct {
+		command string
+		title   string
+		items   []fmt.Stringer
+	}{
+		{command: "error", title: locale.Sprintf(locale.FlagDiffRemoved), items: endpointStringers(d.Removed)},
+		{command: "error", title: locale.Sprintf(locale.FlagDiffBreakingChange), items: changes},
+		{command: "warning", title: locale.Sprintf(locale.FlagDiffModified), items: endpointStringers(d.Modified)},
+		{command: "notice", title: locale.Sprintf(locale.FlagDiffAdded), items: endpointStringers(d.Added)},
+	} {
+		for _, i := range item.items {
+			_, err := fmt.Fprintf(w, "::%s title=%s::%s\n", item.command, annotationPropertyReplacer.Replace(item.title), annotationDataReplacer.Replace(i.String()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func endpointStringers(endpoints []types.Endpoint) []fmt.Stringer {
+	items := make([]fmt.Stringer, 0, len(endpoints))
+	for _, e := range endpoints {
+		items = append(items, e)
+	}
+	return items
+}
+
+// 工作流命令中消息内容的转义
+var annotationDataReplacer = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// 工作流命令中属性值的转义，在消息内容的基础上，还需要转义 : 和 ,
+var annotationPropertyReplacer = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
@@ -0,0 +1,200 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func TestDiffDocs(t *testing.T) {
+	a := assert.New(t)
+
+	old := types.NewDoc()
+	old.NewAPI(&types.API{Method: "get", URL: "/users", Summary: "users"})
+	old.NewAPI(&types.API{Method: "delete", URL: "/users/{id}", Summary: "delete"})
+	old.NewAPI(&types.API{Method: "get", URL: "/users/{id}", Summary: "user"})
+	old.NewAPI(&types.API{Method: "get", URL: "/orders", Summary: "orders"})
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{Method: "get", URL: "/users", Summary: "users"})
+	docs.NewAPI(&types.API{Method: "get", URL: "/users/{id}", Summary: "获取用户"})
+	docs.NewAPI(&types.API{Method: "post", URL: "/users", Summary: "create"})
+	docs.NewAPI(&types.API{Method: "get", URL: "/reports", Summary: "reports"})
+
+	d, err := diffDocs(old, docs)
+	a.NotError(err)
+	a.Equal(d.Added, []types.Endpoint{{Method: "GET", URL: "/reports"}, {Method: "POST", URL: "/users"}}).
+		Equal(d.Removed, []types.Endpoint{{Method: "GET", URL: "/orders"}, {Method: "DELETE", URL: "/users/{id}"}}).
+		Equal(d.Modified, []types.Endpoint{{Method: "GET", URL: "/users/{id}"}})
+
+	a.Empty(d.BreakingChanges)
+
+	// 相同的文档
+	d, err = diffDocs(docs, docs)
+	a.NotError(err)
+	a.Empty(d.Added).Empty(d.Removed).Empty(d.Modified).Empty(d.BreakingChanges)
+
+	// 新增的 @apiBreakingChange，已有的不再列出，新增的 API 中的也不列出
+	v1 := &types.BreakingChange{Version: "1.0.0", Description: "删除了 name 字段"}
+	old = types.NewDoc()
+	old.NewAPI(&types.API{Method: "get", URL: "/users", Summary: "users", BreakingChanges: []*types.BreakingChange{v1}})
+	docs = types.NewDoc()
+	docs.NewAPI(&types.API{Method: "get", URL: "/users", Summary: "users", BreakingChanges: []*types.BreakingChange{
+		{Version: "1.0.0", Description: "删除了 name 字段"},
+		{Version: "2.0.0", Description: "删除了 email 字段"},
+	}})
+	docs.NewAPI(&types.API{Method: "post", URL: "/users", Summary: "create", BreakingChanges: []*types.BreakingChange{v1}})
+	d, err = diffDocs(old, docs)
+	a.NotError(err)
+	a.Equal(d.Modified, []types.Endpoint{{Method: "GET", URL: "/users"}}).
+		Equal(d.BreakingChanges, []*diffBreakingChange{
+			{Endpoint: types.Endpoint{Method: "GET", URL: "/users"}, Version: "2.0.0", Description: "删除了 email 字段"},
+		})
+}
+
+func TestDocDiff_write(t *testing.T) {
+	a := assert.New(t)
+
+	d := &docDiff{
+		Added:    []types.Endpoint{{Method: "POST", URL: "/users"}},
+		Removed:  []types.Endpoint{{Method: "GET", URL: "/orders?page=100%"}},
+		Modified: []types.Endpoint{{Method: "GET", URL: "/users/{id}"}},
+		BreakingChanges: []*diffBreakingChange{
+			{Endpoint: types.Endpoint{Method: "GET", URL: "/users/{id}"}, Version: "2.0.0", Description: "删除了 email 字段"},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	a.NotError(d.write(buf, vars.FormatText))
+	a.Equal(buf.String(), "+ POST /users\n- GET /orders?page=100%\n~ GET /users/{id}\n! GET /users/{id} v2.0.0 删除了 email 字段\n")
+
+	buf.Reset()
+	a.NotError(d.write(buf, vars.FormatJSON))
+	d2 := &docDiff{}
+	a.NotError(json.Unmarshal(buf.Bytes(), d2))
+	a.Equal(d2, d)
+
+	// 删除的在最前，且消息中的 % 需要转义
+	buf.Reset()
+	a.NotError(d.write(buf, vars.FormatGitHubActions))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	a.Equal(len(lines), 4)
+	a.True(strings.HasPrefix(lines[0], "::error title=")).
+		True(strings.HasSuffix(lines[0], "::GET /orders?page=100%25"))
+	a.True(strings.HasPrefix(lines[1], "::error title=")).
+		True(strings.HasSuffix(lines[1], "::GET /users/{id} v2.0.0 删除了 email 字段"))
+	a.True(strings.HasPrefix(lines[2], "::warning title=")).
+		True(strings.HasSuffix(lines[2], "::GET /users/{id}"))
+	a.True(strings.HasPrefix(lines[3], "::notice title=")).
+		True(strings.HasSuffix(lines[3], "::POST /users"))
+}
+
+func TestAnnotationPropertyReplacer(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(annotationPropertyReplacer.Replace("a:b,c%\n"), "a%3Ab%2Cc%25%0A")
+	a.Equal(annotationDataReplacer.Replace("a:b,c%\n"), "a:b,c%25%0A")
+}
+
+func TestRunDiff(t *testing.T) {
+	a := assert.New(t)
+
+	old := coverageFixture(a, `package main
+
+// @api get /users users
+// @apiSuccess 200 OK
+func users() {}
+
+// @api delete /users/{id} delete user
+// @apiParam id int 用户 ID
+// @apiSuccess 204 OK
+func deleteUser() {}
+
+// @api get /orders orders
+// @apiSuccess 200 OK
+func orders() {}
+`)
+	defer os.RemoveAll(old)
+
+	dir := coverageFixture(a, `package main
+
+// @api get /users users
+// @apiQuery page int 页码
+// @apiSuccess 200 OK
+func users() {}
+
+// @api post /users create user
+// @apiSuccess 201 OK
+func createUser() {}
+
+// @api get /orders orders
+// @apiSuccess 200 OK
+func orders() {}
+`)
+	defer os.RemoveAll(dir)
+
+	// 有删除的 API，以非零值退出
+	out, code := runMain(a, "-wd", dir, "-diff", old, "-format", vars.FormatGitHubActions)
+	a.Equal(code, 1)
+	annotation := regexp.MustCompile(`^::(error|warning|notice) title=[^:,]+::[A-Z]+ /\S*$`)
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	a.Equal(len(lines), 3, out)
+	for _, line := range lines {
+		a.True(annotation.MatchString(line), line)
+	}
+	a.True(strings.HasPrefix(lines[0], "::error ")).True(strings.HasSuffix(lines[0], "::DELETE /users/{id}"))
+	a.True(strings.HasPrefix(lines[1], "::warning ")).True(strings.HasSuffix(lines[1], "::GET /users"))
+	a.True(strings.HasPrefix(lines[2], "::notice ")).True(strings.HasSuffix(lines[2], "::POST /users"))
+
+	// 相同的文档
+	out, code = runMain(a, "-wd", old, "-diff", old)
+	a.Equal(code, 0).Empty(out)
+
+	out, code = runMain(a, "-wd", dir, "-diff", dir, "-format", vars.FormatJSON)
+	a.Equal(code, 0)
+	d := &docDiff{}
+	a.NotError(json.Unmarshal([]byte(out), d))
+	a.Empty(d.Added).Empty(d.Removed).Empty(d.Modified)
+
+	// 无效的格式
+	_, code = runMain(a, "-wd", dir, "-diff", old, "-format", "xml")
+	a.Equal(code, 1)
+
+	// 没有删除 API，但新增了不兼容的变更，同样以非零值退出
+	breaking := coverageFixture(a, `package main
+
+// @api get /users users
+// @apiBreakingChange v2.0.0 删除了 email 字段
+// @apiSuccess 200 OK
+func users() {}
+
+// @api delete /users/{id} delete user
+// @apiParam id int 用户 ID
+// @apiSuccess 204 OK
+func deleteUser() {}
+
+// @api get /orders orders
+// @apiSuccess 200 OK
+func orders() {}
+`)
+	defer os.RemoveAll(breaking)
+
+	out, code = runMain(a, "-wd", breaking, "-diff", old, "-format", vars.FormatGitHubActions)
+	a.Equal(code, 1)
+	lines = strings.Split(strings.TrimSpace(out), "\n")
+	a.Equal(len(lines), 2, out)
+	a.True(strings.HasPrefix(lines[0], "::error ")).True(strings.HasSuffix(lines[0], "::GET /users v2.0.0 删除了 email 字段"))
+	a.True(strings.HasPrefix(lines[1], "::warning ")).True(strings.HasSuffix(lines[1], "::GET /users"))
+}
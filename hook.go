@@ -0,0 +1,67 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// pre-commit 钩子的内容，%s 为 apidoc 的工作目录。
+const (
+	hookScript = `#!/bin/sh
+# 该文件由 apidoc -install-hook 生成，在提交之前检测文档内容。
+exec apidoc -wd "%s" -lint -strict
+`
+
+	hookCmd = "@echo off\r\nrem 该文件由 apidoc -install-hook 生成，在提交之前检测文档内容。\r\napidoc -wd \"%s\" -lint -strict\r\n"
+)
+
+// 在 wd 所在的 git 仓库中安装 pre-commit 钩子，返回钩子文件的路径。
+func installHook(wd string) (string, error) {
+	wd, err := filepath.Abs(wd)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = wd
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(strings.TrimSpace(string(out)), ".git", "hooks")
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "pre-commit")
+	if err = writeHook(path, fmt.Sprintf(hookScript, filepath.ToSlash(wd))); err != nil {
+		return "", err
+	}
+
+	if runtime.GOOS == "windows" {
+		if err = writeHook(path+".cmd", fmt.Sprintf(hookCmd, wd)); err != nil {
+			return "", err
+		}
+	}
+
+	return path, nil
+}
+
+// 写入钩子内容，并保证其可执行权限。
+func writeHook(path, content string) error {
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		return err
+	}
+
+	// 文件已经存在时，WriteFile 不会修改其权限
+	return os.Chmod(path, 0755)
+}
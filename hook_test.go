@@ -0,0 +1,60 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestInstallHook(t *testing.T) {
+	a := assert.New(t)
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git 不存在")
+	}
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	// 非 git 仓库
+	path, err := installHook(dir)
+	a.Error(err).Empty(path)
+
+	cmd := exec.Command("git", "init")
+	cmd.Dir = dir
+	a.NotError(cmd.Run())
+
+	// 在子目录中安装
+	wd := filepath.Join(dir, "src")
+	a.NotError(os.MkdirAll(wd, os.ModePerm))
+	path, err = installHook(wd)
+	a.NotError(err)
+
+	root, err := filepath.EvalSymlinks(dir) // macOS 下的临时目录为符号链接
+	a.NotError(err)
+	a.Equal(path, filepath.Join(root, ".git", "hooks", "pre-commit"))
+
+	data, err := os.ReadFile(path)
+	a.NotError(err)
+	a.True(strings.Contains(string(data), "-lint -strict"))
+
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		a.NotError(err)
+		a.True(fi.Mode()&0111 != 0)
+	}
+
+	// 可重复安装
+	_, err = installHook(wd)
+	a.NotError(err)
+}
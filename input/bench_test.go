@@ -0,0 +1,134 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/caixw/apidoc/input/encoding"
+	"github.com/caixw/apidoc/types"
+	"github.com/issue9/assert"
+)
+
+// 生成约 size 字节的 Go 代码，包含普通代码、字符串、单行注释和多行注释。
+func benchData(size int) []byte {
+	buf := new(bytes.Buffer)
+	for i := 0; buf.Len() < size; i++ {
+		n := strconv.Itoa(i)
+		buf.WriteString(`
+// f` + n + ` 是一个普通的函数
+func f` + n + `(s string) int {
+	x := "string with \"escape\" and /* not a comment */"
+	y := ` + "`raw string // not a comment`" + `
+	for i := 0; i < len(s); i++ {
+		x += s[i:i+1] // trailing comment
+	}
+	return len(s) + len(x) + len(y)
+}
+
+/*
+ * @api GET /users/` + n + ` 获取用户
+ * @apiGroup users
+ * @apiParam id int 用户的 ID
+ *
+ * @apiSuccess 200 OK
+ * @apiParam name string 用户名
+ * @apiExample json
+ * {"id": ` + n + `, "name": "n` + n + `"}
+ */
+func handler` + n + `(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+`)
+	}
+	return buf.Bytes()
+}
+
+// 与 parseFile 相同的方式读取所有的代码块，返回代码块的数量。
+func scanBlocks(data []byte, blocks []blocker) int {
+	l := &lexer{data: data, blocks: blocks}
+	count := 0
+	for {
+		b := l.block()
+		if b == nil {
+			return count
+		}
+
+		if _, ok := b.EndFunc(l); !ok {
+			return count
+		}
+		count++
+	}
+}
+
+// go1.27 linux/amd64 (1 CPU) BenchmarkLexer_1MB 	      98	  12090579 ns/op	  86.74 MB/s
+func BenchmarkLexer_1MB(b *testing.B) {
+	data := benchData(1 << 20)
+	blocks := langs["go"]
+
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		scanBlocks(data, blocks)
+	}
+}
+
+// 以 parseFile 分析 path 指向的文件，包含编码转换、代码块的查找和标签的解析。
+func benchParseFile(b *testing.B, path string) {
+	a := assert.New(b)
+	fi, err := os.Stat(path)
+	a.NotError(err).NotNil(fi)
+
+	o := &Options{Lang: "go", Encoding: encoding.DefaultEncoding}
+	blocks := langs["go"]
+
+	b.SetBytes(fi.Size())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		parseFile(types.NewDoc(), path, blocks, o)
+	}
+}
+
+func BenchmarkScanSmallFile(b *testing.B) {
+	benchParseFile(b, "./testdata/go/test1.go")
+}
+
+func BenchmarkScanLargeFile(b *testing.B) {
+	a := assert.New(b)
+	path := filepath.Join(b.TempDir(), "large.go")
+	a.NotError(os.WriteFile(path, benchData(100<<10), os.ModePerm))
+
+	benchParseFile(b, path)
+}
+
+func BenchmarkDetectDirLang(b *testing.B) {
+	a := assert.New(b)
+
+	for i := 0; i < b.N; i++ {
+		o, err := Detect("./testdir", true)
+		a.NotError(err).NotNil(o)
+	}
+}
+
+// 每次操作都会查找 langExts 中的所有扩展名。
+func BenchmarkGetLangByExt(b *testing.B) {
+	exts := make([]string, 0, len(langExts)*2)
+	for _, items := range langExts {
+		exts = append(exts, items...)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, ext := range exts {
+			if getLangByExt(ext) == "" {
+				b.Fatalf("getLangByExt(%s) 返回空值", ext)
+			}
+		}
+	}
+}
@@ -5,19 +5,25 @@
 package input
 
 import (
+	"bytes"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/caixw/apidoc/locale"
 )
 
-// 用于描述 block.Type 的值。
+// BlockType 表示代码块的类型，用于指定 Block.Type 的值。
+type BlockType int8
+
+// BlockType 可用的值
 const (
-	blockTypeNone     int8 = iota
-	blockTypeString        // 字符串，将被忽略。
-	blockTypeSComment      // 单行注释
-	blockTypeMComment      // 多行注释
+	blockTypeNone       BlockType = iota
+	BlockTypeString               // 字符串，其中的内容将被忽略
+	BlockTypeSComment             // 单行注释，连续的多行单行注释会被当作一个代码块
+	BlockTypeMComment             // 多行注释
+	BlockTypeDocComment           // 以 /** 开头的文档注释，普通的 /* 注释不会被当作文档
 )
 
 // blocker 接口定义了解析代码块的所有操作。
@@ -31,27 +37,86 @@ type blocker interface {
 	EndFunc(l *lexer) ([]rune, bool)
 }
 
-// block 定义了与语言相关的三种类型的代码块：单行注释，多行注释，字符串。
+// blocker 的起始位置总是一个固定的字符串时，可以实现此接口。
+// lexer 会根据这些字符串的首字节，快速跳过不可能是代码块起始位置的内容。
+type beginner interface {
+	beginString() string
+}
+
+// Block 定义了与语言相关的几种类型的代码块：字符串、单行注释、多行注释和文档注释。
 //
-// block 作为 blocker 的默认实现，能适应大部分语言的定义。
-type block struct {
-	Type   int8   // 代码块的类型，可以是字符串，单行注释或是多行注释
-	Begin  string // 块的起始字符串
-	End    string // 块的结束字符串，单行注释不用定义此值
-	Escape string // 当 Type 为 blockTypeString 时，此值表示转义字符，Type 为其它值时，此值无意义
+// Block 作为代码块的默认实现，能适应大部分语言的定义。
+// 外部可以通过 RegisterLang 将一组 Block 注册为一门新的语言，
+// 同一语言中的代码块按顺序匹配，起始字符串相同时，较长的应该定义在前面，
+// 比如 /** 应该在 /* 之前。
+type Block struct {
+	Type   BlockType // 代码块的类型
+	Begin  string    // 块的起始字符串
+	End    string    // 块的结束字符串，单行注释不用定义此值
+	Escape string    // 当 Type 为 BlockTypeString 时，此值表示转义字符，Type 为其它值时，此值无意义
+
+	endOnce sync.Once
+	end     *horspool // 查找 End 的实例，在第一次使用时初始化
+}
+
+// 检测 b 的各个字段是否正确
+func (b *Block) isValid() bool {
+	switch b.Type {
+	case BlockTypeString, BlockTypeMComment, BlockTypeDocComment:
+		return len(b.Begin) > 0 && len(b.End) > 0
+	case BlockTypeSComment:
+		return len(b.Begin) > 0
+	default:
+		return false
+	}
+}
+
+// 返回查找 b.End 的 horspool 实例。
+func (b *Block) endSearcher() *horspool {
+	b.endOnce.Do(func() {
+		b.end = newHorspool(b.End)
+	})
+	return b.end
 }
 
-func (b *block) BeginFunc(l *lexer) bool {
-	return l.match(b.Begin)
+// 从 l 的当前位置开始查找 b.End，返回其相对于 l.pos 的位置，不存在时返回 -1。
+func (b *Block) indexEnd(l *lexer) int {
+	if l.pos >= len(l.data) {
+		return -1
+	}
+	return b.endSearcher().index(l.data[l.pos:])
+}
+
+// 返回 b.Begin，供 lexer 跳过不可能是代码块起始位置的内容。
+func (b *Block) beginString() string {
+	return b.Begin
+}
+
+// BeginFunc 判断 l 的当前位置是否为 b 的起始位置，是则将 l 移至 Begin 之后。
+func (b *Block) BeginFunc(l *lexer) bool {
+	if !l.match(b.Begin) {
+		return false
+	}
+
+	// /**/ 只是一个空的普通注释，不能当作文档注释的开始。
+	if b.Type == BlockTypeDocComment && l.match("/") {
+		l.pos -= len(b.Begin) + 1
+		return false
+	}
+
+	return true
 }
 
-func (b *block) EndFunc(l *lexer) ([]rune, bool) {
+// EndFunc 从 l 的当前位置开始查找 b 的结束位置，并返回代码块中的内容。
+//
+// 字符串的内容总是返回空值；未找到结束位置时，返回 false。
+func (b *Block) EndFunc(l *lexer) ([]rune, bool) {
 	switch b.Type {
-	case blockTypeString:
+	case BlockTypeString:
 		return b.endString(l)
-	case blockTypeMComment:
+	case BlockTypeMComment, BlockTypeDocComment:
 		return b.endMComments(l)
-	case blockTypeSComment:
+	case BlockTypeSComment:
 		return b.endSComments(l)
 	default:
 		panic(locale.Sprintf(locale.ErrInvalidBlockType, b.Type))
@@ -61,25 +126,35 @@ func (b *block) EndFunc(l *lexer) ([]rune, bool) {
 // 从 l 的当前位置开始往后查找，直到找到 b 中定义的 end 字符串，
 // 将 l 中的指针移到该位置。
 // 正常找到结束符的返回 true，否则返回 false。
-func (b *block) endString(l *lexer) ([]rune, bool) {
-LOOP:
+func (b *Block) endString(l *lexer) ([]rune, bool) {
 	for {
-		switch {
-		case l.atEOF():
-			break LOOP
-		case (len(b.Escape) > 0) && l.match(b.Escape):
-			l.next()
-		case l.match(b.End):
-			return nil, true
-		default:
-			l.next()
+		index := b.indexEnd(l)
+		if index < 0 {
+			l.pos = len(l.data)
+			return nil, false
 		}
-	} // end for
-	return nil, false
+
+		// 结束符之前（包含与结束符相同的位置）是否有转义字符，
+		// 有的话，跳过转义字符及其之后的一个字符，再继续查找。
+		if len(b.Escape) > 0 {
+			end := l.pos + index + len(b.Escape)
+			if end > len(l.data) {
+				end = len(l.data)
+			}
+			if esc := bytes.Index(l.data[l.pos:end], []byte(b.Escape)); esc >= 0 {
+				l.pos += esc + len(b.Escape)
+				l.next()
+				continue
+			}
+		}
+
+		l.pos += index + len(b.End)
+		return nil, true
+	}
 }
 
 // 从 l 的当前位置往后开始查找连续的相同类型单行代码块。
-func (b *block) endSComments(l *lexer) ([]rune, bool) {
+func (b *Block) endSComments(l *lexer) ([]rune, bool) {
 	// 跳过除换行符以外的所有空白字符。
 	skipSpace := func() {
 		for {
@@ -117,31 +192,24 @@ func (b *block) endSComments(l *lexer) ([]rune, bool) {
 
 // 从 l 的当前位置一直到定义的 b.End 之间的所有字符。
 // 会对每一行应用 filterSymbols 规则。
-func (b *block) endMComments(l *lexer) ([]rune, bool) {
-	lines := make([][]rune, 0, 20)
-	line := make([]rune, 0, 100)
-
-LOOP:
-	for {
-		switch {
-		case l.atEOF():
-			return nil, false
-		case l.match(b.End):
-			lines = append(lines, filterSymbols(line, b.Begin))
-			break LOOP
-		default:
-			r := l.next()
-			line = append(line, r)
-			if r == '\n' {
-				lines = append(lines, filterSymbols(line, b.Begin))
-				line = make([]rune, 0, 100)
-			}
-		}
+func (b *Block) endMComments(l *lexer) ([]rune, bool) {
+	index := b.indexEnd(l)
+	if index < 0 {
+		l.pos = len(l.data)
+		return nil, false
 	}
 
-	ret := make([]rune, 0, 1000)
-	for _, v := range lines {
-		ret = append(ret, v...)
+	data := l.data[l.pos : l.pos+index]
+	l.pos += index + len(b.End)
+
+	ret := make([]rune, 0, len(data))
+	for len(data) > 0 {
+		size := bytes.IndexByte(data, '\n') + 1
+		if size == 0 {
+			size = len(data)
+		}
+		ret = append(ret, filterSymbols([]rune(string(data[:size])), b.Begin)...)
+		data = data[size:]
 	}
 	return ret, true
 }
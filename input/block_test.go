@@ -10,13 +10,13 @@ import (
 	"github.com/issue9/assert"
 )
 
-var _ blocker = &block{}
+var _ blocker = &Block{}
 
 func TestBlock_BeginFunc_EndFunc(t *testing.T) {
 	a := assert.New(t)
-	bStr := &block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: "\\"}
-	bSComment := &block{Type: blockTypeSComment, Begin: "//"}
-	bMComment := &block{Type: blockTypeMComment, Begin: "/*", End: "*/"}
+	bStr := &Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: "\\"}
+	bSComment := &Block{Type: BlockTypeSComment, Begin: "//"}
+	bMComment := &Block{Type: BlockTypeMComment, Begin: "/*", End: "*/"}
 
 	l := &lexer{
 		data: []byte("// scomment1\n// scomment2"),
@@ -30,10 +30,27 @@ func TestBlock_BeginFunc_EndFunc(t *testing.T) {
 	a.False(ok).Equal(len(ret), 0)
 }
 
+func TestBlock_BeginFunc_javaDoc(t *testing.T) {
+	a := assert.New(t)
+	b := &Block{Type: BlockTypeDocComment, Begin: "/**", End: "*/"}
+
+	l := &lexer{data: []byte("/** doc\n */")}
+	a.True(b.BeginFunc(l))
+	rs, ok := b.EndFunc(l)
+	a.True(ok).Equal(string(rs), " doc\n ")
+
+	l = &lexer{data: []byte("/* comment */")}
+	a.False(b.BeginFunc(l)).Equal(l.pos, 0)
+
+	// 空的普通注释
+	l = &lexer{data: []byte("/**/")}
+	a.False(b.BeginFunc(l)).Equal(l.pos, 0)
+}
+
 func TestBlock_endString(t *testing.T) {
 	a := assert.New(t)
-	b := &block{
-		Type:   blockTypeString,
+	b := &Block{
+		Type:   BlockTypeString,
 		Begin:  `"`,
 		End:    `"`,
 		Escape: "\\",
@@ -64,8 +81,8 @@ func TestBlock_endString(t *testing.T) {
 
 func TestBlock_endSComment(t *testing.T) {
 	a := assert.New(t)
-	b := &block{
-		Type:  blockTypeSComment,
+	b := &Block{
+		Type:  BlockTypeSComment,
 		Begin: `//`,
 	}
 
@@ -99,8 +116,8 @@ func TestBlock_endSComment(t *testing.T) {
 
 func TestBlock_endMComment(t *testing.T) {
 	a := assert.New(t)
-	b := &block{
-		Type:  blockTypeSComment,
+	b := &Block{
+		Type:  BlockTypeSComment,
 		Begin: "/*",
 		End:   "*/",
 	}
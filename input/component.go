@@ -0,0 +1,74 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import "bytes"
+
+var (
+	scriptBegin = []byte("<script")
+	scriptEnd   = []byte("</script>")
+)
+
+// 从 .vue 和 .svelte 等组件文件中提取 <script> 中的内容，
+// 模板和样式中可能包含与 javascript 注释相同的内容，需要排除。
+//
+// 为了保证行号不变，<script> 之外的内容会被替换成空格，只保留换行符。
+// 可以有多个 <script>，比如 vue 的 <script> 和 <script setup>。
+// 没有找到 <script> 时，第二个返回值为 false。
+func scriptFilter(data []byte) ([]byte, bool) {
+	ret := make([]byte, len(data))
+	blank := func(start, end int) {
+		for i := start; i < end; i++ {
+			if data[i] == '\n' {
+				ret[i] = '\n'
+			} else {
+				ret[i] = ' '
+			}
+		}
+	}
+
+	found := false
+	pos := 0
+	for {
+		begin := bytes.Index(data[pos:], scriptBegin)
+		if begin < 0 {
+			break
+		}
+		begin += pos
+
+		// 排除 <scripts> 之类的标签，以及未闭合的开始标签
+		next := begin + len(scriptBegin)
+		if next >= len(data) || (data[next] != '>' && data[next] != ' ' && data[next] != '\t' && data[next] != '\n' && data[next] != '\r') {
+			blank(pos, next)
+			pos = next
+			continue
+		}
+		gt := bytes.IndexByte(data[next:], '>')
+		if gt < 0 {
+			break
+		}
+		start := next + gt + 1
+
+		end := bytes.Index(data[start:], scriptEnd)
+		if end < 0 {
+			end = len(data)
+		} else {
+			end += start
+		}
+
+		blank(pos, start)
+		copy(ret[start:end], data[start:end])
+		found = true
+
+		pos = end
+		if pos < len(data) {
+			pos += len(scriptEnd)
+			blank(end, pos)
+		}
+	}
+	blank(pos, len(data))
+
+	return ret, found
+}
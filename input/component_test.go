@@ -0,0 +1,47 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestScriptFilter(t *testing.T) {
+	a := assert.New(t)
+
+	// 只保留 <script> 中的内容，位置和行号不变
+	test := func(src string, fields ...string) {
+		data, ok := scriptFilter([]byte(src))
+		a.True(ok, src).
+			Equal(len(data), len(src)).
+			Equal(bytes.Count(data, []byte{'\n'}), strings.Count(src, "\n")).
+			Equal(strings.Fields(string(data)), fields)
+
+		for i, b := range data {
+			if b != ' ' {
+				a.Equal(b, src[i])
+			}
+		}
+	}
+
+	test("<template>\n<p>// x</p>\n</template>\n<script>\n// @api\n</script>\n<style>/* */</style>", "//", "@api")
+
+	// 多个 <script>，带属性
+	test(`<script lang="ts">a</script><p>b</p><script setup>c</script>`, "a", "c")
+	test("<script\n  context=\"module\">a</script>", "a")
+
+	// 未闭合的 <script>，一直到文件末尾
+	test("<p>x</p><script>\nabc", "abc")
+
+	// 没有 <script>
+	for _, v := range []string{"", "<template>// @api</template>", "<scripts>abc</scripts>", "<script", "<script abc"} {
+		data, ok := scriptFilter([]byte(v))
+		a.False(ok, v).Equal(len(data), len(v))
+	}
+}
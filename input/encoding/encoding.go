@@ -8,7 +8,8 @@ package encoding
 import (
 	"bytes"
 	"errors"
-	"io/ioutil"
+	"io"
+	"os"
 	"sort"
 	"strings"
 
@@ -54,7 +55,7 @@ func Encodings() []string {
 // Transform 将 path 指向的文件内容，按 encoding 编码进行加载，
 // 并转换成 utf-8 之后返回其内容。
 func Transform(path, encoding string) ([]byte, error) {
-	data, err := ioutil.ReadFile(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -69,5 +70,5 @@ func Transform(path, encoding string) ([]byte, error) {
 	}
 
 	reader := transform.NewReader(bytes.NewReader(data), enc.NewDecoder())
-	return ioutil.ReadAll(reader)
+	return io.ReadAll(reader)
 }
@@ -5,7 +5,7 @@
 package encoding
 
 import (
-	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/issue9/assert"
@@ -21,7 +21,7 @@ func TestEncodings(t *testing.T) {
 func TestTransform(t *testing.T) {
 	a := assert.New(t)
 
-	u8, err := ioutil.ReadFile("./testdata/utf8")
+	u8, err := os.ReadFile("./testdata/utf8")
 	a.NotError(err).NotNil(u8)
 
 	utf8, err := Transform("./testdata/utf8", "utf-8")
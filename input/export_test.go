@@ -0,0 +1,8 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+// UnregisterLang 供 input_test 包中的测试清理注册的语言
+var UnregisterLang = unregisterLang
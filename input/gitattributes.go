@@ -0,0 +1,132 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	gitAttributesFilename = ".gitattributes"
+	linguistLanguageAttr  = "linguist-language="
+)
+
+// linguist 中的语言名称与 langs 中名称不一致的部分，
+// 其它的语言名称转换成小写之后即为 langs 中的名称。
+var linguistLangs = map[string]string{
+	"c":               "c++",
+	"protocol buffer": "protobuf",
+}
+
+// .gitattributes 中通过 linguist-language 为 pattern 指定的语言。
+type linguistHint struct {
+	pattern string // 规则的格式与 .apidocignore 相同
+	lang    string // langs 中的名称
+}
+
+// 加载 dir 目录下的 .gitattributes 文件中，所有通过 linguist-language 指定了语言的规则。
+//
+// 不被支持的语言会被忽略，文件不存在时返回 nil。
+func loadLinguistHints(dir string) ([]*linguistHint, error) {
+	data, err := os.ReadFile(filepath.Join(dir, gitAttributesFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	hints := make([]*linguistHint, 0, 10)
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || fields[0][0] == '#' {
+			continue
+		}
+
+		pattern := strings.Trim(fields[0], "/")
+		if len(pattern) == 0 {
+			continue
+		}
+
+		for _, attr := range fields[1:] {
+			if !strings.HasPrefix(attr, linguistLanguageAttr) {
+				continue
+			}
+
+			if lang := linguistLang(attr[len(linguistLanguageAttr):]); len(lang) > 0 {
+				hints = append(hints, &linguistHint{pattern: pattern, lang: lang})
+			}
+		}
+	}
+
+	return hints, s.Err()
+}
+
+// 将 linguist 的语言名称转换成 langs 中的名称，不被支持的返回空值。
+//
+// .gitattributes 中的空格需要以 - 代替，比如 Protocol-Buffer。
+func linguistLang(name string) string {
+	name = strings.ToLower(strings.Replace(name, "-", " ", -1))
+	if lang, found := linguistLangs[name]; found {
+		name = lang
+	}
+
+	if !langIsSupported(name) {
+		return ""
+	}
+	return name
+}
+
+// 返回 dir 目录下第一条至少匹配了一个文件的规则，都不匹配时返回 nil。
+// recursive 表示是否查找子目录；symlinks 表示是否跟随指向目录的符号链接。
+func detectLinguistHint(dir string, recursive, symlinks bool) (*linguistHint, error) {
+	hints, err := loadLinguistHints(dir)
+	if err != nil || len(hints) == 0 {
+		return nil, err
+	}
+
+	matched := make([]bool, len(hints))
+	walk := func(path string, fi os.FileInfo) error {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		for i, hint := range hints {
+			if !matched[i] && (ignore{hint.pattern}).match(rel) {
+				matched[i] = true
+			}
+		}
+		return nil
+	}
+
+	if err := walkDir(dir, recursive, symlinks, walk); err != nil {
+		return nil, err
+	}
+
+	for i, hint := range hints {
+		if matched[i] {
+			return hint, nil
+		}
+	}
+	return nil, nil
+}
+
+// 规则为 *.ext 的形式时，返回其中的扩展名，否则返回空值。
+func (hint *linguistHint) ext() string {
+	if !strings.HasPrefix(hint.pattern, "*.") {
+		return ""
+	}
+
+	ext := hint.pattern[1:]
+	if strings.ContainsAny(ext, "*?[/") {
+		return ""
+	}
+	return strings.ToLower(ext)
+}
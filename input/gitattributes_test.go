@@ -0,0 +1,91 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+// 在临时目录中生成 files 中的文件，键名为文件路径，键值为文件内容。
+func writeTempFiles(a *assert.Assertion, files map[string]string) string {
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		a.NotError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		a.NotError(os.WriteFile(path, []byte(content), os.ModePerm))
+	}
+
+	return dir
+}
+
+func TestLinguistLang(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(linguistLang("PHP"), "php")
+	a.Equal(linguistLang("JavaScript"), "javascript")
+	a.Equal(linguistLang("C#"), "c#")
+	a.Equal(linguistLang("C"), "c++")
+	a.Equal(linguistLang("Protocol-Buffer"), "protobuf")
+	a.Equal(linguistLang("Markdown"), "")
+}
+
+func TestLoadLinguistHints(t *testing.T) {
+	a := assert.New(t)
+
+	hints, err := loadLinguistHints("./testdir")
+	a.NotError(err).Nil(hints)
+
+	dir := writeTempFiles(a, map[string]string{
+		gitAttributesFilename: `# comment
+*.inc linguist-language=PHP
+*.md linguist-documentation
+*.tpl text linguist-language=Markdown
+/api/*.h   eol=lf linguist-language=C
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	hints, err = loadLinguistHints(dir)
+	a.NotError(err)
+	a.Equal(hints, []*linguistHint{
+		{pattern: "*.inc", lang: "php"},
+		{pattern: "api/*.h", lang: "c++"},
+	})
+
+	a.Equal(hints[0].ext(), ".inc")
+	a.Equal(hints[1].ext(), "")
+}
+
+func TestDetect_gitattributes(t *testing.T) {
+	a := assert.New(t)
+
+	dir := writeTempFiles(a, map[string]string{
+		gitAttributesFilename: "*.tpl linguist-language=Go\n*.inc linguist-language=PHP\n",
+		"main.c":              "",
+		"util.c":              "",
+		"util.h":              "",
+		"lib/api.inc":         "",
+	})
+	defer os.RemoveAll(dir)
+
+	// *.tpl 没有匹配的文件，使用 *.inc 的规则，而不是数量最多的 c++。
+	o, err := Detect(dir, true)
+	a.NotError(err).NotNil(o)
+	a.Equal(o.Lang, "php").
+		Equal(o.Exts, []string{".php", ".inc"}).
+		True(o.Recursive)
+	a.Equal(langExts["php"], []string{".php"}) // 不能修改默认的扩展名
+
+	// 不查找子目录时，没有匹配的文件，根据扩展名来检测。
+	o, err = Detect(dir, false)
+	a.NotError(err).NotNil(o)
+	a.Equal(o.Lang, "c++")
+}
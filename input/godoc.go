@@ -0,0 +1,172 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/caixw/apidoc/input/syntax"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 在错误信息中代替文件名
+const goDocFile = "go doc"
+
+// go doc 输出中，声明之后的注释内容的缩进，代码块也会在此基础上再缩进相同的长度。
+const goDocIndent = "    "
+
+// go doc 输出中各部分的标题，标题之后为各类声明
+var goDocSections = map[string]bool{
+	"CONSTANTS": true,
+	"VARIABLES": true,
+	"FUNCTIONS": true,
+	"TYPES":     true,
+}
+
+// go doc 输出中函数、方法或是类型的声明
+var goDocDecl = regexp.MustCompile(`^(?:func\s+(?:\([^)]*\)\s*)?|type\s+)([A-Za-z_][A-Za-z0-9_]*)`)
+
+// go doc 会将段落重新排版，标签可能出现在行中间
+var goDocTag = regexp.MustCompile(`[ \t]+` + vars.API)
+
+// go doc 输出中的一段注释
+type goDocBlock struct {
+	line   int    // 注释之前的声明在 go doc 输出中的行号
+	name   string // 注释对应的声明名称
+	indent string // 注释内容的缩进
+	lines  []string
+}
+
+// ParseGoDoc 从 go doc -all 的输出内容中获取文档。
+//
+// 包的注释和各个声明的注释分别作为一个代码块进行解析。
+// go doc 会重新排版注释，多个标签可能会被合并到同一行中，
+// 所以除了代码块之外，每个段落都会在标签之前重新断行。
+//
+// 若有语法错误，则返回第一个错误。
+func ParseGoDoc(r io.Reader) (*types.Doc, error) {
+	blocks, err := goDocBlocks(r)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := new(bytes.Buffer)
+	errlog := log.New(errs, "", 0)
+
+	docs := types.NewDoc()
+	for _, b := range blocks {
+		data := []rune(b.text())
+		if len(data) < miniSize {
+			continue
+		}
+
+		syntax.Parse(&syntax.Input{
+			File:  goDocFile,
+			Line:  b.line,
+			Data:  data,
+			Name:  b.name,
+			Error: errlog,
+
+			LangIsSupported: langIsSupported,
+		}, docs)
+	}
+
+	if errs.Len() > 0 {
+		return nil, errors.New(strings.SplitN(errs.String(), "\n", 2)[0])
+	}
+
+	if len(docs.Title) == 0 {
+		docs.Title = vars.DefaultTitle
+	}
+
+	return docs, nil
+}
+
+// 将 go doc 的输出拆分成多个注释块。
+//
+// 包的注释没有缩进，直到第一个部分的标题为止；
+// 其它声明的注释紧跟在声明之后，且缩进 goDocIndent，
+// 声明本身的多行内容以 tab 缩进，或是以 } 和 ) 结尾，不属于注释。
+func goDocBlocks(r io.Reader) ([]*goDocBlock, error) {
+	blocks := make([]*goDocBlock, 0, 10)
+	var cur *goDocBlock
+
+	s := bufio.NewScanner(r)
+	for ln := 1; s.Scan(); ln++ {
+		line := s.Text()
+
+		switch {
+		case ln == 1 && strings.HasPrefix(line, "package "):
+			cur = &goDocBlock{line: ln}
+			blocks = append(blocks, cur)
+		case goDocSections[line]:
+			cur = nil
+		case len(strings.TrimSpace(line)) == 0:
+			if cur != nil {
+				cur.lines = append(cur.lines, "")
+			}
+		case cur != nil && (cur.indent == "" || strings.HasPrefix(line, cur.indent)):
+			cur.lines = append(cur.lines, line)
+		case line[0] == '\t' || line[0] == '}' || line[0] == ')': // 多行声明的剩余部分
+		default:
+			cur = &goDocBlock{line: ln, name: goDocDeclName(line), indent: goDocIndent}
+			blocks = append(blocks, cur)
+		}
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// 获取函数、方法或是类型的名称，常量和变量的声明返回空值。
+func goDocDeclName(decl string) string {
+	matches := goDocDecl.FindStringSubmatch(decl)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// 还原成可以被 syntax.Parse 解析的内容
+func (b *goDocBlock) text() string {
+	buf := new(bytes.Buffer)
+	para := make([]string, 0, 10)
+
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		buf.WriteString(goDocTag.ReplaceAllString(strings.Join(para, " "), "\n"+vars.API))
+		buf.WriteByte('\n')
+		para = para[:0]
+	}
+
+	for _, line := range b.lines {
+		line = strings.TrimPrefix(line, b.indent)
+		switch {
+		case len(line) == 0:
+			flush()
+			buf.WriteByte('\n')
+		case line[0] == ' ' || line[0] == '\t': // 代码块，原样输出
+			flush()
+			buf.WriteString(strings.TrimPrefix(line, goDocIndent))
+			buf.WriteByte('\n')
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+
+	return buf.String()
+}
@@ -0,0 +1,130 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+)
+
+// go doc -all 的输出内容
+const goDocOutput = `package handlers // import "example.com/handlers"
+
+Package handlers 提供用户相关的接口。
+
+@apidoc 用户服务 @apiVersion 1.0.0 @apiBaseURL
+https://api.example.com
+
+CONSTANTS
+
+const MaxSize = 10
+    MaxSize 单页的最大数量
+
+
+FUNCTIONS
+
+func CreateUser(w http.ResponseWriter, r *http.Request)
+    CreateUser 创建用户
+
+    @api post /users 创建用户 @apiGroup users @apiRequest json @apiParam name
+    string 名称 @apiExample json
+
+        {
+            "name": "caixw"
+        }
+
+    @apiSuccess 201 OK
+
+func GetUser(w http.ResponseWriter, r *http.Request)
+    GetUser 获取用户
+
+    @api get /users/{id} 获取用户 @apiGroup users @apiParam id int 用户 ID
+    @apiSuccess 200 OK
+
+
+TYPES
+
+type Server struct {
+	// Name 名称
+	Name string
+}
+    Server 没有文档的类型
+
+func (s *Server) ListUsers(w http.ResponseWriter, r *http.Request)
+    ListUsers 用户列表
+
+    @api get /users 用户列表 @apiGroup users @apiSuccess 200 OK
+
+`
+
+func TestParseGoDoc(t *testing.T) {
+	a := assert.New(t)
+
+	docs, err := ParseGoDoc(strings.NewReader(goDocOutput))
+	a.NotError(err).NotNil(docs)
+	a.Equal(docs.Title, "用户服务").
+		Equal(docs.Version, "1.0.0").
+		Equal(docs.BaseURL, "https://api.example.com")
+
+	a.Equal(len(docs.Apis), 3)
+	sort.Slice(docs.Apis, func(i, j int) bool { return docs.Apis[i].Name < docs.Apis[j].Name })
+
+	create := docs.Apis[0]
+	a.Equal(create.Name, "CreateUser").
+		Equal(create.Method, "post").
+		Equal(create.URL, "/users").
+		Equal(create.Group, "users").
+		Equal(create.Request.Params, []*types.Param{{Name: "name", Type: "string", Summary: "名称"}}).
+		Equal(create.Request.Examples[0].Code, "{\n    \"name\": \"caixw\"\n}").
+		Equal(create.Success.Code, "201")
+
+	get := docs.Apis[1]
+	a.Equal(get.Name, "GetUser").
+		Equal(get.URL, "/users/{id}").
+		Equal(get.Params, []*types.Param{{Name: "id", Type: "int", Summary: "用户 ID"}}).
+		Equal(get.Success.Code, "200")
+
+	list := docs.Apis[2]
+	a.Equal(list.Name, "ListUsers").
+		Equal(list.URL, "/users").
+		Equal(list.Success.Code, "200")
+}
+
+func TestParseGoDoc_error(t *testing.T) {
+	a := assert.New(t)
+
+	docs, err := ParseGoDoc(strings.NewReader(`package handlers
+
+FUNCTIONS
+
+func GetUser(w http.ResponseWriter, r *http.Request)
+    @api get /users/{id}
+`))
+	a.Error(err).Nil(docs)
+	a.True(strings.Contains(err.Error(), goDocFile))
+
+	// 没有任何文档
+	docs, err = ParseGoDoc(strings.NewReader("package handlers\n\nPackage handlers 没有文档\n"))
+	a.NotError(err).NotNil(docs)
+	a.Empty(docs.Apis)
+}
+
+func TestGoDocDeclName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(goDocDeclName("func GetUser(w http.ResponseWriter, r *http.Request)"), "GetUser")
+	a.Equal(goDocDeclName("func (s *Server) ListUsers(w http.ResponseWriter)"), "ListUsers")
+	a.Equal(goDocDeclName("func (Server) List()"), "List")
+	a.Equal(goDocDeclName("type Server struct {"), "Server")
+
+	a.Empty(goDocDeclName("const MaxSize = 10"))
+	a.Empty(goDocDeclName("var ErrNotFound = errors.New(\"not found\")"))
+	a.Empty(goDocDeclName(""))
+}
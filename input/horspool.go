@@ -0,0 +1,49 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+// horspool 以 Boyer-Moore-Horspool 算法查找固定的字符串，
+// 用于在代码块中查找结束符。
+//
+// 坏字符表只在创建时计算一次，之后可以在多个协程中同时使用。
+type horspool struct {
+	pattern []byte
+	skip    [256]int // 文本中与模式串最后一个字节对齐的字节不匹配时，可以跳过的长度
+}
+
+func newHorspool(pattern string) *horspool {
+	h := &horspool{pattern: []byte(pattern)}
+
+	size := len(h.pattern)
+	for i := range h.skip {
+		h.skip[i] = size
+	}
+	for i := 0; i < size-1; i++ {
+		h.skip[h.pattern[i]] = size - 1 - i
+	}
+
+	return h
+}
+
+// 返回模式串在 data 中第一次出现的位置，不存在时返回 -1。
+func (h *horspool) index(data []byte) int {
+	size := len(h.pattern)
+	if size == 0 {
+		return 0
+	}
+
+	last := size - 1
+	for i := 0; i+size <= len(data); i += h.skip[data[i+last]] {
+		j := last
+		for j >= 0 && data[i+j] == h.pattern[j] {
+			j--
+		}
+		if j < 0 {
+			return i
+		}
+	}
+
+	return -1
+}
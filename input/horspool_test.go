@@ -0,0 +1,35 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestHorspool_index(t *testing.T) {
+	a := assert.New(t)
+
+	data := []byte("/* 注释 */ x := \"*/\" // */\n*")
+	for _, pattern := range []string{"*/", "\"", "//", "\n", "*", "注释", "x", "not found", "", string(data), string(data) + "*"} {
+		h := newHorspool(pattern)
+		a.Equal(h.index(data), bytes.Index(data, []byte(pattern)), "pattern:%q", pattern)
+	}
+
+	a.Equal(newHorspool("*/").index(nil), -1)
+}
+
+func TestBeginBytes(t *testing.T) {
+	a := assert.New(t)
+
+	firsts := beginBytes(langs["go"])
+	a.True(firsts['/']).True(firsts['"']).True(firsts['`'])
+	a.False(firsts['*']).False(firsts['a'])
+
+	firsts = beginBytes(langs["pascal"])
+	a.True(firsts['\'']).True(firsts['"']).True(firsts['{']).True(firsts['('])
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caixw/apidoc/vars"
+)
+
+// 由 .apidocignore 文件指定的忽略规则，每一条规则为一个 glob 模式。
+//
+// 规则的格式与 .gitignore 相似：
+//   - 空行和以 # 开头的行会被忽略；
+//   - 规则中的路径均相对于输入目录，以 / 作为分隔符；
+//   - 不包含 / 的规则，可以匹配任意层级下的文件或目录名；
+//   - ** 可以匹配零个或多个目录。
+type ignore []string
+
+// 加载 dir 目录下的 .apidocignore 文件，文件不存在时返回 nil。
+func loadIgnore(dir string) (ignore, error) {
+	data, err := os.ReadFile(filepath.Join(dir, vars.IgnoreFilename))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ig := ignore{}
+	s := bufio.NewScanner(bytes.NewReader(data))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		line = strings.Trim(line, "/")
+		if len(line) > 0 {
+			ig = append(ig, line)
+		}
+	}
+
+	return ig, s.Err()
+}
+
+// 判断相对于输入目录的路径 rel 是否需要忽略。
+//
+// 目录被忽略时，其下的所有文件都会被忽略，由调用方跳过整个目录。
+func (ig ignore) match(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	names := strings.Split(rel, "/")
+
+	for _, pattern := range ig {
+		patterns := strings.Split(pattern, "/")
+		if len(patterns) == 1 { // 不包含 /，匹配任意层级
+			patterns = append([]string{"**"}, patterns...)
+		}
+
+		if matchSegments(patterns, names) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// 逐段匹配路径，patterns 中的 ** 可以匹配零个或多个段。
+func matchSegments(patterns, names []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			for i := 0; i <= len(names); i++ {
+				if matchSegments(patterns[1:], names[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(names) == 0 {
+			return false
+		}
+
+		if matched, err := filepath.Match(patterns[0], names[0]); err != nil || !matched {
+			return false
+		}
+		patterns = patterns[1:]
+		names = names[1:]
+	}
+
+	return len(names) == 0
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/vars"
+)
+
+func TestLoadIgnore(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	// 文件不存在
+	ig, err := loadIgnore(dir)
+	a.NotError(err).Nil(ig)
+
+	content := "# comment\n\n  vendor/  \n/docs/*.go\n**/*_test.go\n"
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.IgnoreFilename), []byte(content), os.ModePerm))
+	ig, err = loadIgnore(dir)
+	a.NotError(err)
+	a.Equal(ig, ignore{"vendor", "docs/*.go", "**/*_test.go"})
+}
+
+func TestIgnore_match(t *testing.T) {
+	a := assert.New(t)
+
+	ig := ignore{"vendor", "docs/*.go", "**/*_test.go", "internal/**/mock"}
+
+	a.True(ig.match("vendor"))
+	a.True(ig.match("src/vendor"))
+	a.True(ig.match("docs/a.go"))
+	a.True(ig.match("a_test.go"))
+	a.True(ig.match("src/a/b_test.go"))
+	a.True(ig.match("internal/mock"))
+	a.True(ig.match("internal/a/b/mock"))
+	a.True(ig.match(filepath.Join("src", "vendor")))
+
+	a.False(ig.match("vendor.go"))
+	a.False(ig.match("docs/sub/a.go"))
+	a.False(ig.match("src/docs/a.go"))
+	a.False(ig.match("a.go"))
+	a.False(ig.match("internal/mock/a.go"))
+	a.False(ignore(nil).match("a.go"))
+}
+
+func TestRecursivePath_ignore(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	// dir/.apidocignore
+	// dir/a.go
+	// dir/a_test.go
+	// dir/vendor/b.go
+	// dir/sub/c.go
+	// dir/sub/c_test.go
+	files := []string{"a.go", "a_test.go", "vendor/b.go", "sub/c.go", "sub/c_test.go"}
+	for _, file := range files {
+		path := filepath.Join(dir, filepath.FromSlash(file))
+		a.NotError(os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		a.NotError(os.WriteFile(path, []byte("package a"), os.ModePerm))
+	}
+	content := "vendor\n*_test.go\n"
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.IgnoreFilename), []byte(content), os.ModePerm))
+
+	opt := &Options{Dir: dir, Recursive: true, Exts: []string{".go"}}
+	paths, err := recursivePath(opt)
+	a.NotError(err)
+	a.Equal(paths, []string{
+		filepath.Join(dir, "a.go"),
+		filepath.Join(dir, "sub", "c.go"),
+	})
+
+	exts, err := detectExts(dir, true, false)
+	a.NotError(err)
+	a.Equal(exts[".go"], 2)
+}
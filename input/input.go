@@ -53,7 +53,7 @@ func Parse(options ...*Options) (*types.Doc, time.Duration) {
 }
 
 func parse(docs *types.Doc, o *Options) error {
-	blocks, found := langs[o.Lang]
+	blocks, found := getLang(o.Lang)
 	if !found {
 		return errors.New(locale.Sprintf(locale.ErrUnsupportedInputLang, o.Lang))
 	}
@@ -91,8 +91,17 @@ func parseFile(docs *types.Doc, path string, blocks []blocker, o *Options) {
 		return
 	}
 
+	if filter := langFilters[o.Lang]; filter != nil {
+		var ok bool
+		if data, ok = filter(data); !ok {
+			syntax.OutputError(o.WarnLog, path, o.StartLineNumber, locale.ErrScriptNotFound)
+			return
+		}
+	}
+
 	l := &lexer{data: data, blocks: blocks}
 	var block blocker
+	declName := langDeclNames[o.Lang]
 
 	wg := sync.WaitGroup{}
 	defer wg.Wait()
@@ -121,19 +130,27 @@ func parseFile(docs *types.Doc, path string, blocks []blocker, o *Options) {
 			continue
 		}
 
+		name := ""
+		if declName != nil {
+			name = declName(l.data[l.pos:])
+		}
+
 		wg.Add(1)
-		go func(rs []rune, ln int) {
+		go func(rs []rune, ln int, name string) {
 			i := &syntax.Input{
 				File:  path,
 				Line:  ln,
 				Data:  rs,
+				Name:  name,
 				Error: o.ErrorLog,
 				Warn:  o.WarnLog,
+
+				LangIsSupported: langIsSupported,
 			}
 			syntax.Parse(i, docs)
 
 			wg.Done()
-		}(rs, ln)
+		}(rs, ln, name)
 	} // end for
 }
 
@@ -150,21 +167,112 @@ func recursivePath(o *Options) ([]string, error) {
 		return false
 	}
 
-	walk := func(path string, fi os.FileInfo, err error) error {
-		if err != nil {
-			return err
+	walk := func(path string, fi os.FileInfo) error {
+		if !extIsEnabled(filepath.Ext(path)) {
+			return nil
 		}
-		if fi.IsDir() && !o.Recursive && path != o.Dir {
-			return filepath.SkipDir
-		} else if extIsEnabled(filepath.Ext(path)) {
-			paths = append(paths, path)
+
+		if o.MaxFileSize > 0 && fi.Size() > o.MaxFileSize {
+			if o.WarnLog != nil {
+				o.WarnLog.Println(locale.Sprintf(locale.ErrFileTooLarge, path, o.MaxFileSize))
+			}
+			return nil
 		}
+		paths = append(paths, path)
 		return nil
 	}
 
-	if err := filepath.Walk(o.Dir, walk); err != nil {
+	if err := walkDir(o.Dir, o.Recursive, o.FollowSymlinks, walk); err != nil {
 		return nil, err
 	}
 
 	return paths, nil
 }
+
+// 遍历 dir 下的所有文件，并对每个文件调用 fn。
+//
+// recursive 表示是否查找子目录；
+// symlinks 表示是否跟随指向目录的符号链接，
+// 已经访问过的目录会被记录下来，以防止符号链接造成的循环。
+// 通过符号链接找到的文件，传递给 fn 的依然是以符号链接为前缀的路径。
+// dir 下的 .apidocignore 文件所匹配的文件和目录会被跳过。
+func walkDir(dir string, recursive, symlinks bool, fn func(path string, fi os.FileInfo) error) error {
+	ig, err := loadIgnore(dir)
+	if err != nil {
+		return err
+	}
+
+	visited := map[string]bool{}
+
+	var walk func(root, prefix string) error
+	walk = func(root, prefix string) error {
+		return filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			name := path
+			if root != prefix {
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+				name = filepath.Join(prefix, rel)
+			}
+
+			if len(ig) > 0 && name != dir {
+				rel, err := filepath.Rel(dir, name)
+				if err != nil {
+					return err
+				}
+
+				if ig.match(rel) {
+					if fi.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+			}
+
+			if symlinks && fi.Mode()&os.ModeSymlink != 0 {
+				target, err := filepath.EvalSymlinks(path)
+				if err != nil { // 无效的符号链接，直接忽略
+					return nil
+				}
+
+				if fi, err = os.Stat(target); err != nil {
+					return err
+				}
+
+				if fi.IsDir() {
+					if (!recursive && name != dir) || visited[target] {
+						return nil
+					}
+					return walk(target, name)
+				}
+			}
+
+			if fi.IsDir() {
+				if !recursive && name != dir {
+					return filepath.SkipDir
+				}
+
+				if symlinks {
+					real, err := filepath.EvalSymlinks(path)
+					if err != nil {
+						return err
+					}
+					if visited[real] {
+						return filepath.SkipDir
+					}
+					visited[real] = true
+				}
+				return nil
+			}
+
+			return fn(name, fi)
+		})
+	}
+
+	return walk(dir, dir)
+}
@@ -5,7 +5,11 @@
 package input
 
 import (
+	"bytes"
+	"log"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/caixw/apidoc/input/encoding"
@@ -25,10 +29,13 @@ func TestParse(t *testing.T) {
 	testParse(a, "pascal")
 	testParse(a, "perl")
 	testParse(a, "php")
+	testParse(a, "protobuf")
 	testParse(a, "python")
 	testParse(a, "ruby")
 	testParse(a, "rust")
+	testParse(a, "svelte")
 	testParse(a, "swift")
+	testParse(a, "vue")
 }
 
 func testParse(a *assert.Assertion, lang string) {
@@ -70,12 +77,82 @@ func TestParseFile(t *testing.T) {
 	testParseFile(a, "pascal", "./testdata/pascal/test1.pas")
 	testParseFile(a, "perl", "./testdata/perl/test1.pl")
 	testParseFile(a, "php", "./testdata/php/test1.php")
+	testParseFile(a, "protobuf", "./testdata/protobuf/test1.proto")
 	testParseFile(a, "python", "./testdata/python/test1.py")
 	testParseFile(a, "ruby", "./testdata/ruby/test1.rb")
 	testParseFile(a, "rust", "./testdata/rust/test1.rs")
 	testParseFile(a, "swift", "./testdata/swift/test1.swift")
 }
 
+// 组件文件只解析 <script> 中的内容
+func TestParseFile_component(t *testing.T) {
+	a := assert.New(t)
+
+	for lang, path := range map[string]string{
+		"svelte": "./testdata/svelte/test1.svelte",
+		"vue":    "./testdata/vue/test1.vue",
+	} {
+		docs := types.NewDoc()
+		errLog := new(bytes.Buffer)
+		warnLog := new(bytes.Buffer)
+		o := &Options{
+			Lang:            lang,
+			StartLineNumber: 1,
+			Encoding:        encoding.DefaultEncoding,
+			ErrorLog:        log.New(errLog, "", 0),
+			WarnLog:         log.New(warnLog, "", 0),
+		}
+		parseFile(docs, path, langs[lang], o)
+		a.Empty(errLog.String(), lang).Empty(warnLog.String(), lang)
+		a.Equal(len(docs.Apis), 2, lang)
+
+		for _, api := range docs.Apis {
+			a.Equal(api.URL, "/users/login", lang).
+				Equal(api.Group, "users", lang)
+		}
+	}
+
+	// 没有 <script>
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "empty.vue")
+	a.NotError(os.WriteFile(path, []byte("<template>\n<div>// @api GET /users users</div>\n</template>\n"), os.ModePerm))
+
+	docs := types.NewDoc()
+	warnLog := new(bytes.Buffer)
+	parseFile(docs, path, langs["vue"], &Options{Lang: "vue", Encoding: encoding.DefaultEncoding, WarnLog: log.New(warnLog, "", 0)})
+	a.Empty(docs.Apis).
+		True(strings.Contains(warnLog.String(), path))
+}
+
+// @apiCodegen 中的语言需要是 langs 中的语言
+func TestParseFile_codegen(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	parse := func(lang string) (*types.Doc, string) {
+		path := filepath.Join(dir, "main.go")
+		code := "// @api GET /users users\n// @apiCodegen " + lang + " methodName listUsers\n// @apiSuccess 200 OK\nfunc users() {}\n"
+		a.NotError(os.WriteFile(path, []byte(code), os.ModePerm))
+
+		docs := types.NewDoc()
+		errLog := new(bytes.Buffer)
+		parseFile(docs, path, langs["go"], &Options{Lang: "go", Encoding: encoding.DefaultEncoding, ErrorLog: log.New(errLog, "", 0)})
+		return docs, errLog.String()
+	}
+
+	docs, errs := parse("java")
+	a.Empty(errs).Equal(len(docs.Apis), 1)
+	a.Equal(docs.Apis[0].Codegen, map[string]map[string]string{"java": {"methodName": "listUsers"}})
+
+	docs, errs = parse("cobol")
+	a.Empty(docs.Apis).True(strings.Contains(errs, "cobol"))
+}
+
 func testParseFile(a *assert.Assertion, lang, path string) {
 	docs := types.NewDoc()
 	a.NotNil(docs)
@@ -153,3 +230,92 @@ func TestRecursivePath(t *testing.T) {
 		filepath.Join("testdir", "testfile.1"),
 	})
 }
+
+func TestRecursivePath_maxFileSize(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	const size = 1024
+	small := filepath.Join(dir, "small.go")
+	large := filepath.Join(dir, "large.go")
+	a.NotError(os.WriteFile(small, bytes.Repeat([]byte{'a'}, size), os.ModePerm))
+	a.NotError(os.WriteFile(large, bytes.Repeat([]byte{'a'}, size+1), os.ModePerm))
+
+	// 未指定 MaxFileSize，不作限制
+	opt := &Options{Dir: dir, Exts: []string{".go"}}
+	paths, err := recursivePath(opt)
+	a.NotError(err).Equal(len(paths), 2)
+
+	warn := new(bytes.Buffer)
+	opt.MaxFileSize = size
+	opt.WarnLog = log.New(warn, "", 0)
+	paths, err = recursivePath(opt)
+	a.NotError(err).Equal(paths, []string{small})
+	a.True(strings.Contains(warn.String(), large)).
+		False(strings.Contains(warn.String(), small))
+}
+
+func TestRecursivePath_followSymlinks(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	// dir/src/a.go
+	// dir/root/b.go
+	// dir/root/link -> dir/src
+	// dir/root/loop -> dir/root
+	src := filepath.Join(dir, "src")
+	root := filepath.Join(dir, "root")
+	a.NotError(os.Mkdir(src, os.ModePerm))
+	a.NotError(os.Mkdir(root, os.ModePerm))
+	a.NotError(os.WriteFile(filepath.Join(src, "a.go"), []byte("package a"), os.ModePerm))
+	a.NotError(os.WriteFile(filepath.Join(root, "b.go"), []byte("package b"), os.ModePerm))
+	if err := os.Symlink(src, filepath.Join(root, "link")); err != nil {
+		t.Skip("不支持符号链接：", err)
+	}
+	a.NotError(os.Symlink(root, filepath.Join(root, "loop")))
+
+	opt := &Options{Dir: root, Recursive: true, Exts: []string{".go"}}
+	paths, err := recursivePath(opt)
+	a.NotError(err)
+	a.Equal(paths, []string{filepath.Join(root, "b.go")})
+
+	opt.FollowSymlinks = true
+	paths, err = recursivePath(opt)
+	a.NotError(err)
+	a.Equal(paths, []string{
+		filepath.Join(root, "b.go"),
+		filepath.Join(root, "link", "a.go"),
+	})
+
+	// 未指定 Recursive，不会进入符号链接指向的目录
+	opt.Recursive = false
+	paths, err = recursivePath(opt)
+	a.NotError(err)
+	a.Equal(paths, []string{filepath.Join(root, "b.go")})
+
+	exts, err := detectExts(root, true, true)
+	a.NotError(err)
+	a.Equal(exts[".go"], 2)
+
+	// 通过 DetectWithOptions 检测，符号链接指向的文件也参与统计
+	a.NotError(os.WriteFile(filepath.Join(src, "c.php"), []byte("<?php"), os.ModePerm))
+	a.NotError(os.WriteFile(filepath.Join(src, "d.php"), []byte("<?php"), os.ModePerm))
+	a.NotError(os.WriteFile(filepath.Join(src, "e.php"), []byte("<?php"), os.ModePerm))
+	o, err := DetectWithOptions(root, &DetectOptions{Recursive: true})
+	a.NotError(err)
+	a.Equal(o.Lang, "go").False(o.FollowSymlinks)
+
+	o, err = DetectWithOptions(root, &DetectOptions{Recursive: true, FollowSymlinks: true})
+	a.NotError(err)
+	a.Equal(o.Lang, "php").True(o.FollowSymlinks)
+
+	o, err = Detect(root, true)
+	a.NotError(err)
+	a.Equal(o.Lang, "go").False(o.FollowSymlinks)
+}
@@ -5,17 +5,25 @@
 package input
 
 import (
+	"errors"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/caixw/apidoc/locale"
 )
 
+// 保护 langs 和 langExts 的并发读写
+var langsMu sync.RWMutex
+
 // 所有支持的语言模型定义
 //
 // NOTE: 应该保持键名为非大写，按字母顺序排列，方便查找。
 // langs 应该和 langExts 保持一一对应关系。
 var langs = map[string][]blocker{
 	// C#
-	"c#": cStyle,
+	"c#": javaDocStyle,
 
 	// c/c++
 	"c++": cStyle,
@@ -25,103 +33,129 @@ var langs = map[string][]blocker{
 
 	// erlang
 	"erlang": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `%`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `%`},
 	},
 
 	// go
 	"go": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeString, Begin: "`", End: "`"},
-		&block{Type: blockTypeSComment, Begin: `//`},
-		&block{Type: blockTypeMComment, Begin: `/*`, End: `*/`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "`", End: "`"},
+		&Block{Type: BlockTypeSComment, Begin: `//`},
+		&Block{Type: BlockTypeMComment, Begin: `/*`, End: `*/`},
 	},
 
 	// groovy
 	"groovy": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeString, Begin: "'", End: "'", Escape: `\`},
-		&block{Type: blockTypeString, Begin: "'''", End: "'''", Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `//`},
-		&block{Type: blockTypeMComment, Begin: `/*`, End: `*/`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "'''", End: "'''", Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `//`},
+		&Block{Type: BlockTypeMComment, Begin: `/*`, End: `*/`},
 	},
 
 	// java
-	"java": cStyle,
+	"java": javaDocStyle,
 
 	// javascript
-	"javascript": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeString, Begin: "'", End: "'", Escape: `\`},
-		&block{Type: blockTypeString, Begin: "`", End: "`", Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `//`},
-		&block{Type: blockTypeMComment, Begin: `/*`, End: `*/`},
-		// NOTE: js 中若出现 /*abc/.test() 应该是先优先注释的。放最后，优先匹配 // 和 /*
-		&block{Type: blockTypeString, Begin: "/", End: "/", Escape: `\`}, // 正则表达式
-	},
+	"javascript": jsStyle,
 
 	// pascal/delphi
 	"pascal": {
 		newPascalStringBlock('\''),
 		newPascalStringBlock('"'),
-		&block{Type: blockTypeMComment, Begin: "{", End: "}"},
-		&block{Type: blockTypeMComment, Begin: "(*", End: "*)"},
+		&Block{Type: BlockTypeMComment, Begin: "{", End: "}"},
+		&Block{Type: BlockTypeMComment, Begin: "(*", End: "*)"},
 	},
 
 	// perl
 	"perl": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeString, Begin: "'", End: "'", Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `#`},
-		&block{Type: blockTypeMComment, Begin: "\n=pod\n", End: "\n=cut\n"},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `#`},
+		&Block{Type: BlockTypeMComment, Begin: "\n=pod\n", End: "\n=cut\n"},
+	},
+
+	// protobuf
+	"protobuf": {
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `//`},
+		&Block{Type: BlockTypeMComment, Begin: `/*`, End: `*/`},
 	},
 
 	// python
 	"python": {
-		&block{Type: blockTypeMComment, Begin: `"""`, End: `"""`},
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `#`},
+		&Block{Type: BlockTypeMComment, Begin: `"""`, End: `"""`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `#`},
 	},
 
 	// php
 	"php": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeString, Begin: "'", End: "'", Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `//`},
-		&block{Type: blockTypeMComment, Begin: `/*`, End: `*/`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `//`},
+		&Block{Type: BlockTypeDocComment, Begin: `/**`, End: `*/`}, // 需要在 /* 之前定义
+		&Block{Type: BlockTypeString, Begin: `/*`, End: `*/`},      // 普通的多行注释，忽略
 	},
 
 	// ruby
 	"ruby": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeString, Begin: "'", End: "'", Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `#`},
-		&block{Type: blockTypeMComment, Begin: "\n=begin\n", End: "\n=end\n"},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `#`},
+		&Block{Type: BlockTypeMComment, Begin: "\n=begin\n", End: "\n=end\n"},
 	},
 
 	// rust
 	"rust": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `///`}, // 需要在 // 之前定义
-		&block{Type: blockTypeSComment, Begin: `//`},
-		&block{Type: blockTypeMComment, Begin: `/*`, End: `*/`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `///`}, // 需要在 // 之前定义
+		&Block{Type: BlockTypeSComment, Begin: `//`},
+		&Block{Type: BlockTypeMComment, Begin: `/*`, End: `*/`},
 	},
 
 	// scala
 	"scala": cStyle,
 
+	// svelte，只解析 <script> 中的内容
+	"svelte": jsStyle,
+
 	// swift
 	"swift": {
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-		&block{Type: blockTypeSComment, Begin: `//`},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: `//`},
 		newSwiftNestMCommentBlock("/*", "*/"),
 	},
+
+	// vue，只解析 <script> 中的内容
+	"vue": jsStyle,
 }
 
 var cStyle = []blocker{
-	&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: `\`},
-	&block{Type: blockTypeSComment, Begin: `//`},
-	&block{Type: blockTypeMComment, Begin: `/*`, End: `*/`},
+	&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+	&Block{Type: BlockTypeSComment, Begin: `//`},
+	&Block{Type: BlockTypeMComment, Begin: `/*`, End: `*/`},
+}
+
+var jsStyle = []blocker{
+	&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+	&Block{Type: BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+	&Block{Type: BlockTypeString, Begin: "`", End: "`", Escape: `\`},
+	&Block{Type: BlockTypeSComment, Begin: `//`},
+	&Block{Type: BlockTypeDocComment, Begin: `/**`, End: `*/`}, // 需要在 /* 之前定义
+	&Block{Type: BlockTypeString, Begin: `/*`, End: `*/`},      // 普通的多行注释，忽略
+	// NOTE: js 中若出现 /*abc/.test() 应该是先优先注释的。放最后，优先匹配 // 和 /*
+	&Block{Type: BlockTypeString, Begin: "/", End: "/", Escape: `\`}, // 正则表达式
+}
+
+// 以 /** */ 作为文档注释的语言，普通的 /* */ 注释会被忽略。
+var javaDocStyle = []blocker{
+	&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+	&Block{Type: BlockTypeSComment, Begin: `//`},
+	&Block{Type: BlockTypeDocComment, Begin: `/**`, End: `*/`}, // 需要在 /* 之前定义
+	&Block{Type: BlockTypeString, Begin: `/*`, End: `*/`},      // 普通的多行注释，忽略
 }
 
 // 各语言默认支持的文件扩展名。
@@ -141,15 +175,37 @@ var langExts = map[string][]string{
 	"pascal":     {".pas", ".pp"},
 	"perl":       {".perl", ".prl", ".pl"},
 	"php":        {".php"},
+	"protobuf":   {".proto"},
 	"python":     {".py"},
 	"ruby":       {".rb"},
 	"rust":       {".rs"},
 	"scala":      {".scala"},
+	"svelte":     {".svelte"},
 	"swift":      {".swift"},
+	"vue":        {".vue"},
+}
+
+// 从注释块之后的代码中提取声明的名称，比如函数名等，
+// 提取的名称会作为 API.Name 的默认值。
+//
+// 键名为 langs 中的键名，没有对应项的语言不作提取。
+var langDeclNames = map[string]func(code []byte) string{
+	"protobuf": protobufDeclName,
+}
+
+// 在解析之前对文件内容进行预处理，返回值中的 bool 表示内容是否有效。
+//
+// 键名为 langs 中的键名，没有对应项的语言直接解析整个文件。
+var langFilters = map[string]func(data []byte) ([]byte, bool){
+	"svelte": scriptFilter,
+	"vue":    scriptFilter,
 }
 
 // Languages 返回所有支持的语言
 func Languages() []string {
+	langsMu.RLock()
+	defer langsMu.RUnlock()
+
 	ret := make([]string, 0, len(langs))
 	for l := range langs {
 		ret = append(ret, l)
@@ -164,8 +220,17 @@ func Languages() []string {
 
 // 根据扩展名获取其对应的语言名称。
 // 若返回空值，则表示没有找到对应的。
+//
+// ext 一般来自 filepath.Ext，没有扩展名时为空，以 . 结尾的文件名则只有一个 .，
+// 这两种情况都不可能有对应的语言，直接返回空值。
 func getLangByExt(ext string) string {
+	if len(ext) <= 1 {
+		return ""
+	}
 	ext = strings.ToLower(ext)
+
+	langsMu.RLock()
+	defer langsMu.RUnlock()
 	for lang, exts := range langExts {
 		for _, elem := range exts {
 			if elem == ext {
@@ -179,6 +244,92 @@ func getLangByExt(ext string) string {
 // 是否支持该语言
 func langIsSupported(lang string) bool {
 	// 由测试函数保证 langs 和 langExts 拥有相同的键名。
-	_, found := langs[lang]
+	_, found := getLang(lang)
 	return found
 }
+
+// 获取指定语言的代码块定义
+func getLang(lang string) ([]blocker, bool) {
+	langsMu.RLock()
+	defer langsMu.RUnlock()
+
+	blocks, found := langs[lang]
+	return blocks, found
+}
+
+// 获取指定语言默认支持的文件扩展名
+func getLangExts(lang string) []string {
+	langsMu.RLock()
+	defer langsMu.RUnlock()
+
+	return langExts[lang]
+}
+
+// RegisterLang 以 blocks 作为代码块定义注册一门新的语言。
+//
+// name 为语言名称，会被转换成非大写状态，不能与已有的语言同名；
+// blocks 为该语言的代码块定义，按顺序进行匹配；
+// exts 为该语言默认支持的文件扩展名，需要带 . 符号。
+//
+// 注册之后，可以像内置的语言一样在 Options.Lang 中使用。
+func RegisterLang(name string, blocks []*Block, exts ...string) error {
+	newError := func(field, msg string) error {
+		return errors.New(locale.Sprintf(locale.ErrInvalidLangRegister, name, field, locale.Sprintf(msg)))
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) == 0 {
+		return newError("name", locale.ErrRequired)
+	}
+
+	if len(exts) == 0 {
+		return newError("exts", locale.ErrRequired)
+	}
+	es := make([]string, 0, len(exts))
+	for i, ext := range exts {
+		if len(ext) <= 1 || ext[0] != '.' {
+			return newError("exts["+strconv.Itoa(i)+"]", locale.ErrInvalidFormat)
+		}
+		es = append(es, strings.ToLower(ext))
+	}
+
+	if len(blocks) == 0 {
+		return newError("blocks", locale.ErrRequired)
+	}
+	bs := make([]blocker, 0, len(blocks))
+	for i, b := range blocks {
+		if b == nil || !b.isValid() {
+			return newError("blocks["+strconv.Itoa(i)+"]", locale.ErrInvalidValue)
+		}
+		bs = append(bs, b)
+	}
+
+	return registerLang(name, bs, es...)
+}
+
+// 注册一门新的语言。
+//
+// name 为语言名称，应该使用非大写状态；
+// blocks 为该语言的代码块定义；
+// exts 为该语言默认支持的文件扩展名。
+func registerLang(name string, blocks []blocker, exts ...string) error {
+	langsMu.Lock()
+	defer langsMu.Unlock()
+
+	if _, found := langs[name]; found {
+		return errors.New(locale.Sprintf(locale.ErrLangExists, name))
+	}
+
+	langs[name] = blocks
+	langExts[name] = exts
+	return nil
+}
+
+// 注册一门语言，若已经存在同名的语言，则替换其代码块定义和扩展名。
+func setLang(name string, blocks []blocker, exts ...string) {
+	langsMu.Lock()
+	defer langsMu.Unlock()
+
+	langs[name] = blocks
+	langExts[name] = exts
+}
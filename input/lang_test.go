@@ -5,6 +5,9 @@
 package input
 
 import (
+	"path/filepath"
+	"strconv"
+	"sync"
 	"testing"
 	"unicode"
 
@@ -18,18 +21,19 @@ func TestLangs(t *testing.T) {
 	a.Contains(list, "go", "php")
 }
 
-// 检测 block.Type 的取值是否正确。
+// 检测 Block.Type 的取值是否正确。
 func TestChkBlockType(t *testing.T) {
 	a := assert.New(t)
 
 	for name, blocks := range langs {
 		for index, blk := range blocks {
-			b, ok := blk.(*block)
+			b, ok := blk.(*Block)
 			if !ok {
 				continue
 			}
-			v := (b.Type == blockTypeString || b.Type == blockTypeMComment || b.Type == blockTypeSComment)
+			v := (b.Type == BlockTypeString || b.Type == BlockTypeMComment || b.Type == BlockTypeSComment || b.Type == BlockTypeDocComment)
 			a.True(v, "langs[%v].[%v].Type 值为非法值", name, index)
+			a.True(b.isValid(), "langs[%v].[%v] 的定义不正确", name, index)
 		}
 	}
 }
@@ -77,7 +81,74 @@ func TestGetLangByExt(t *testing.T) {
 	a.Equal(getLangByExt(".h"), "c++")
 	a.Equal(getLangByExt(".c"), "c++")
 	a.Equal(getLangByExt(".php"), "php")
+	a.Equal(getLangByExt(".GO"), "go")
 
 	a.Equal(getLangByExt("php"), "")         // 扩展名不带.符号，查不到
 	a.Equal(getLangByExt(".not exists"), "") // 真的不存在此扩展名
+
+	// 来自 filepath.Ext 的特殊值
+	a.Equal(getLangByExt(filepath.Ext("Makefile")), "")   // 没有扩展名
+	a.Equal(getLangByExt(filepath.Ext("file.")), "")      // 只有 .
+	a.Equal(getLangByExt(filepath.Ext(".gitignore")), "") // 隐藏文件
+	a.Equal(getLangByExt(filepath.Ext("MAIN.GO")), "go")
+	a.Equal(getLangByExt(""), "")
+	a.Equal(getLangByExt("."), "")
+}
+
+func TestRegisterLang(t *testing.T) {
+	a := assert.New(t)
+	defer unregisterLang("test-lang")
+
+	a.NotError(registerLang("test-lang", cStyle, ".test-lang"))
+	a.True(langIsSupported("test-lang"))
+	a.Equal(getLangByExt(".test-lang"), "test-lang")
+	a.Equal(getLangExts("test-lang"), []string{".test-lang"})
+
+	// 重复注册
+	a.Error(registerLang("test-lang", cStyle, ".test-lang"))
+	a.Error(registerLang("go", cStyle, ".go"))
+}
+
+// 需要配合 go test -race 使用
+func TestLangs_concurrent(t *testing.T) {
+	a := assert.New(t)
+
+	names := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		names = append(names, "concurrent-"+strconv.Itoa(i))
+	}
+	defer unregisterLang(names...)
+
+	wg := sync.WaitGroup{}
+	for _, name := range names {
+		wg.Add(2)
+
+		go func(name string) {
+			defer wg.Done()
+			a.NotError(registerLang(name, cStyle, "."+name))
+		}(name)
+
+		go func(name string) {
+			defer wg.Done()
+			Languages()
+			getLangByExt("." + name)
+			langIsSupported(name)
+			getLangExts(name)
+		}(name)
+	}
+	wg.Wait()
+
+	for _, name := range names {
+		a.True(langIsSupported(name))
+	}
+}
+
+func unregisterLang(names ...string) {
+	langsMu.Lock()
+	defer langsMu.Unlock()
+
+	for _, name := range names {
+		delete(langs, name)
+		delete(langExts, name)
+	}
 }
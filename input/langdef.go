@@ -0,0 +1,202 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/vars"
+)
+
+// langDef 中 Type 字段可用的值与 Block.Type 的对应关系
+var langDefBlockTypes = map[string]BlockType{
+	"string":   BlockTypeString,
+	"scomment": BlockTypeSComment,
+	"mcomment": BlockTypeMComment,
+	"javadoc":  BlockTypeDocComment,
+}
+
+// 外部 JSON 文件中的语言定义，格式可参考 docs/langdefs.schema.json。
+type langDef struct {
+	Name   string          `json:"name"`   // 语言名称，应该使用非大写状态
+	Exts   []string        `json:"exts"`   // 默认支持的文件扩展名，需要带 . 符号
+	Blocks []*langDefBlock `json:"blocks"` // 代码块定义
+}
+
+// 与 Block 相对应的定义
+type langDefBlock struct {
+	Type   string `json:"type"`             // 代码块的类型，可以是 langDefBlockTypes 中的键名
+	Begin  string `json:"begin"`            // 起始字符串
+	End    string `json:"end,omitempty"`    // 结束字符串，单行注释不用定义此值
+	Escape string `json:"escape,omitempty"` // 字符串的转义字符
+}
+
+// LoadLangDefs 从 path 指定的 JSON 文件中加载语言定义，并注册到支持的语言中。
+//
+// 文件内容为一个语言定义的数组，格式可参考 docs/langdefs.schema.json。
+// 与已有语言同名的定义会替换该语言原来的定义，可以配合 ExportLangDefs
+// 导出内置的语言定义，修改之后再重新加载。
+// 只要有一个定义不正确，所有的定义都不会被注册。
+func LoadLangDefs(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	defs := make([]*langDef, 0, 5)
+	if err = json.Unmarshal(data, &defs); err != nil {
+		return err
+	}
+
+	names := make(map[string]bool, len(defs))
+	for i, def := range defs {
+		if err := def.sanitize(path, "["+strconv.Itoa(i)+"]"); err != nil {
+			return err
+		}
+
+		if names[def.Name] {
+			return errors.New(locale.Sprintf(locale.ErrLangExists, def.Name))
+		}
+		names[def.Name] = true
+	}
+
+	for _, def := range defs {
+		setLang(def.Name, def.blocks(), def.Exts...)
+	}
+
+	return nil
+}
+
+// ExportLangDefs 将 names 指定的语言定义以 LoadLangDefs 可以加载的格式写入 w。
+//
+// 包含自定义 blocker 的语言（比如 pascal 和 swift）以及需要预处理的语言（比如 vue），
+// 无法以 JSON 的形式表示，会返回错误。
+func ExportLangDefs(w io.Writer, names ...string) error {
+	defs := make([]*langDef, 0, len(names))
+	for _, name := range names {
+		def, err := newLangDef(name)
+		if err != nil {
+			return err
+		}
+		defs = append(defs, def)
+	}
+
+	data, err := json.MarshalIndent(defs, "", strings.Repeat(" ", vars.JSONIndent))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// 根据已经注册的语言生成 langDef 实例
+func newLangDef(name string) (*langDef, error) {
+	blocks, found := getLang(name)
+	if !found {
+		return nil, errors.New(locale.Sprintf(locale.ErrUnsupportedInputLang, name))
+	}
+	if _, found := langFilters[name]; found {
+		return nil, errors.New(locale.Sprintf(locale.ErrLangNotExportable, name))
+	}
+
+	def := &langDef{
+		Name:   name,
+		Exts:   getLangExts(name),
+		Blocks: make([]*langDefBlock, 0, len(blocks)),
+	}
+	for _, blk := range blocks {
+		b, ok := blk.(*Block)
+		if !ok {
+			return nil, errors.New(locale.Sprintf(locale.ErrLangNotExportable, name))
+		}
+
+		def.Blocks = append(def.Blocks, &langDefBlock{
+			Type:   langDefBlockTypeName(b.Type),
+			Begin:  b.Begin,
+			End:    b.End,
+			Escape: b.Escape,
+		})
+	}
+
+	return def, nil
+}
+
+// 返回 Block.Type 在 langDefBlockTypes 中对应的键名
+func langDefBlockTypeName(typ BlockType) string {
+	for name, t := range langDefBlockTypes {
+		if t == typ {
+			return name
+		}
+	}
+	panic(locale.Sprintf(locale.ErrInvalidBlockType, typ))
+}
+
+// 检测并修正 def 中的内容，field 为 def 在文件中的位置，用于生成错误信息。
+func (def *langDef) sanitize(path, field string) error {
+	newError := func(f, msg string) error {
+		return errors.New(locale.Sprintf(locale.ErrInvalidLangDef, path, field+"."+f, locale.Sprintf(msg)))
+	}
+
+	def.Name = strings.ToLower(strings.TrimSpace(def.Name))
+	if len(def.Name) == 0 {
+		return newError("name", locale.ErrRequired)
+	}
+
+	if len(def.Exts) == 0 {
+		return newError("exts", locale.ErrRequired)
+	}
+	for i, ext := range def.Exts {
+		if len(ext) <= 1 || ext[0] != '.' {
+			return newError("exts["+strconv.Itoa(i)+"]", locale.ErrInvalidFormat)
+		}
+		def.Exts[i] = strings.ToLower(ext)
+	}
+
+	if len(def.Blocks) == 0 {
+		return newError("blocks", locale.ErrRequired)
+	}
+	for i, b := range def.Blocks {
+		f := "blocks[" + strconv.Itoa(i) + "]."
+		if b == nil {
+			return newError(f[:len(f)-1], locale.ErrRequired)
+		}
+
+		typ, found := langDefBlockTypes[b.Type]
+		if !found {
+			return newError(f+"type", locale.ErrInvalidValue)
+		}
+
+		if len(b.Begin) == 0 {
+			return newError(f+"begin", locale.ErrRequired)
+		}
+
+		if typ != BlockTypeSComment && len(b.End) == 0 {
+			return newError(f+"end", locale.ErrRequired)
+		}
+	}
+
+	return nil
+}
+
+// 将 def 中的代码块定义转换成 blocker
+func (def *langDef) blocks() []blocker {
+	blocks := make([]blocker, 0, len(def.Blocks))
+	for _, b := range def.Blocks {
+		blocks = append(blocks, &Block{
+			Type:   langDefBlockTypes[b.Type],
+			Begin:  b.Begin,
+			End:    b.End,
+			Escape: b.Escape,
+		})
+	}
+	return blocks
+}
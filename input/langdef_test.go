@@ -0,0 +1,167 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestLoadLangDefs(t *testing.T) {
+	a := assert.New(t)
+	defer unregisterLang("testdsl")
+
+	a.NotError(LoadLangDefs("./testdata/langdefs.json"))
+	a.True(langIsSupported("testdsl"))
+	a.Equal(getLangExts("testdsl"), []string{".tdsl", ".tds"})
+	a.Equal(getLangByExt(".TDSL"), "testdsl")
+
+	blocks, found := getLang("testdsl")
+	a.True(found).Equal(blocks, []blocker{
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+		&Block{Type: BlockTypeSComment, Begin: "--"},
+		&Block{Type: BlockTypeMComment, Begin: "{-", End: "-}"},
+	})
+
+	// 重复加载，替换原有的定义
+	a.NotError(LoadLangDefs("./testdata/langdefs.json"))
+	a.True(langIsSupported("testdsl"))
+
+	// 文件不存在
+	a.Error(LoadLangDefs("./testdata/not-exists.json"))
+}
+
+func TestLoadLangDefs_parse(t *testing.T) {
+	a := assert.New(t)
+	defer unregisterLang("testdsl")
+
+	a.NotError(LoadLangDefs("./testdata/langdefs.json"))
+
+	dir := writeTempFiles(a, map[string]string{
+		"api.tdsl": `
+x = "-- not a comment"
+
+-- @api get /users users
+-- @apiGroup users
+-- @apiSuccess 200 OK
+
+{-
+ @api delete /users/{id} delete user
+ @apiGroup users
+ @apiParam id int 用户 ID
+ @apiSuccess 204 no content
+-}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	o := &Options{Lang: "testdsl", Dir: dir}
+	a.NotError(o.Sanitize())
+	docs, _ := Parse(o)
+	a.Equal(len(docs.Apis), 2)
+}
+
+func TestLoadLangDefs_invalid(t *testing.T) {
+	a := assert.New(t)
+
+	data := []string{
+		`{"name":"x"}`, // 不是数组
+		`[{"exts":[".x"],"blocks":[{"type":"scomment","begin":"#"}]}]`,             // 缺少 name
+		`[{"name":"x","blocks":[{"type":"scomment","begin":"#"}]}]`,                // 缺少 exts
+		`[{"name":"x","exts":["x"],"blocks":[{"type":"scomment","begin":"#"}]}]`,   // 扩展名格式不正确
+		`[{"name":"x","exts":[".x"]}]`,                                             // 缺少 blocks
+		`[{"name":"x","exts":[".x"],"blocks":[{"type":"comment","begin":"#"}]}]`,   // 无效的 type
+		`[{"name":"x","exts":[".x"],"blocks":[{"type":"scomment"}]}]`,              // 缺少 begin
+		`[{"name":"x","exts":[".x"],"blocks":[{"type":"mcomment","begin":"/*"}]}]`, // 缺少 end
+		`[{"name":"x","exts":[".x"],"blocks":[{"type":"scomment","begin":"#"}]},
+		  {"name":"X","exts":[".y"],"blocks":[{"type":"scomment","begin":"#"}]}]`, // 重复的语言
+	}
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "langdefs.json")
+
+	for _, v := range data {
+		a.NotError(os.WriteFile(path, []byte(v), os.ModePerm))
+		a.Error(LoadLangDefs(path), v)
+		a.False(langIsSupported("x"), v)
+	}
+}
+
+func TestExportLangDefs(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(ExportLangDefs(buf, "go", "python"))
+	defs := []*langDef{}
+	a.NotError(json.Unmarshal(buf.Bytes(), &defs))
+	a.Equal(len(defs), 2)
+	a.Equal(defs[0].Name, "go").
+		Equal(defs[0].Exts, []string{".go"}).
+		Equal(len(defs[0].Blocks), len(langs["go"]))
+	for i, b := range defs[0].Blocks {
+		blk := langs["go"][i].(*Block)
+		a.Equal(b.Type, langDefBlockTypeName(blk.Type)).
+			Equal(b.Begin, blk.Begin).
+			Equal(b.End, blk.End).
+			Equal(b.Escape, blk.Escape)
+	}
+	a.Equal(defs[1].Name, "python")
+
+	// 包含自定义 blocker 的语言
+	a.Error(ExportLangDefs(buf, "pascal"))
+	a.Error(ExportLangDefs(buf, "swift"))
+	a.Error(ExportLangDefs(buf, "vue")) // 需要预处理
+
+	// 不存在的语言
+	a.Error(ExportLangDefs(buf, "not-exists"))
+}
+
+func TestExportLangDefs_reload(t *testing.T) {
+	a := assert.New(t)
+
+	blocks, exts := langs["go"], langExts["go"]
+	defer setLang("go", blocks, exts...)
+
+	// 导出 go，添加以 # 开头的单行注释之后重新加载
+	buf := new(bytes.Buffer)
+	a.NotError(ExportLangDefs(buf, "go"))
+	defs := []*langDef{}
+	a.NotError(json.Unmarshal(buf.Bytes(), &defs))
+	defs[0].Blocks = append(defs[0].Blocks, &langDefBlock{Type: "scomment", Begin: "#"})
+
+	dir := writeTempFiles(a, map[string]string{
+		"main.go": `package main
+
+# @api get /users users
+# @apiGroup users
+# @apiSuccess 200 OK
+func users() {}
+`,
+	})
+	defer os.RemoveAll(dir)
+
+	data, err := json.Marshal(defs)
+	a.NotError(err)
+	path := filepath.Join(dir, "go.lang.json")
+	a.NotError(os.WriteFile(path, data, os.ModePerm))
+
+	o := &Options{Lang: "go", Dir: dir}
+	a.NotError(o.Sanitize())
+	docs, _ := Parse(o)
+	a.Equal(len(docs.Apis), 0)
+
+	a.NotError(LoadLangDefs(path))
+	b, found := getLang("go")
+	a.True(found).Equal(len(b), len(blocks)+1)
+	docs, _ = Parse(o)
+	a.Equal(len(docs.Apis), 1)
+}
@@ -15,6 +15,7 @@ type lexer struct {
 	data    []byte
 	pos     int
 	isAtEOF bool
+	firsts  *[256]bool // 所有代码块起始字符串的首字节，为 nil 表示尚未初始化
 
 	ln    int // 上次记录的行号
 	lnPos int // 上次记录行号时所在的位置
@@ -72,7 +73,17 @@ func (l *lexer) lineNumber() int {
 
 // 从当前位置往后查找，直到找到第一个与 blocks 中某个相匹配的，并返回该 blocker 。
 func (l *lexer) block() blocker {
+	if l.firsts == nil {
+		l.firsts = beginBytes(l.blocks)
+	}
+
 	for {
+		// 跳过不可能是代码块起始位置的字节。UTF-8 的后续字节不会出现在
+		// 任何字符串的首字节中，所以不会停在一个字符的中间。
+		for l.pos < len(l.data) && !l.firsts[l.data[l.pos]] {
+			l.pos++
+		}
+
 		if l.atEOF() {
 			return nil
 		}
@@ -86,3 +97,20 @@ func (l *lexer) block() blocker {
 		l.next()
 	}
 }
+
+// 返回所有代码块起始字符串的首字节。
+// 若其中有未实现 beginner 接口的 blocker，则所有字节都可能是起始位置。
+func beginBytes(blocks []blocker) *[256]bool {
+	firsts := &[256]bool{}
+	for _, b := range blocks {
+		begin, ok := b.(beginner)
+		if !ok || len(begin.beginString()) == 0 {
+			for i := range firsts {
+				firsts[i] = true
+			}
+			return firsts
+		}
+		firsts[begin.beginString()[0]] = true
+	}
+	return firsts
+}
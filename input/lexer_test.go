@@ -67,10 +67,10 @@ func TestLexer_block(t *testing.T) {
 	a := assert.New(t)
 
 	blocks := []blocker{
-		&block{Type: blockTypeSComment, Begin: "//"},
-		&block{Type: blockTypeMComment, Begin: "/*", End: "*/"},
-		&block{Type: blockTypeMComment, Begin: "\n=pod", End: "\n=cut"},
-		&block{Type: blockTypeString, Begin: `"`, End: `"`, Escape: "\\"},
+		&Block{Type: BlockTypeSComment, Begin: "//"},
+		&Block{Type: BlockTypeMComment, Begin: "/*", End: "*/"},
+		&Block{Type: BlockTypeMComment, Begin: "\n=pod", End: "\n=cut"},
+		&Block{Type: BlockTypeString, Begin: `"`, End: `"`, Escape: "\\"},
 	}
 
 	l := &lexer{
@@ -95,34 +95,65 @@ mcomment2
 	}
 
 	b := l.block() // scomment1
-	a.Equal(b.(*block).Type, blockTypeSComment)
+	a.Equal(b.(*Block).Type, BlockTypeSComment)
 	rs, err := b.EndFunc(l)
 	a.NotError(err).Equal(string(rs), " scomment1\n scomment2\n")
 
 	b = l.block() // string1
-	a.Equal(b.(*block).Type, blockTypeString)
+	a.Equal(b.(*Block).Type, BlockTypeString)
 	_, err = b.EndFunc(l)
 	a.NotError(err)
 
 	b = l.block() // string2
-	a.Equal(b.(*block).Type, blockTypeString)
+	a.Equal(b.(*Block).Type, BlockTypeString)
 	_, err = b.EndFunc(l)
 	a.NotError(err)
 
 	b = l.block()
-	a.Equal(b.(*block).Type, blockTypeMComment) // mcomment1
+	a.Equal(b.(*Block).Type, BlockTypeMComment) // mcomment1
 	rs, err = b.EndFunc(l)
 	a.NotError(err).Equal(string(rs), "\nmcomment1\nmcomment2\n")
 
 	/* 测试一段单行注释后紧跟 \n=pod 形式的多行注释，是否会出错 */
 
 	b = l.block() // scomment3,scomment4
-	a.Equal(b.(*block).Type, blockTypeSComment)
+	a.Equal(b.(*Block).Type, BlockTypeSComment)
 	rs, err = b.EndFunc(l)
 	a.NotError(err).Equal(string(rs), " scomment3\n scomment4\n")
 
 	b = l.block() // mcomment3,mcomment4
-	a.Equal(b.(*block).Type, blockTypeMComment)
+	a.Equal(b.(*Block).Type, BlockTypeMComment)
 	rs, err = b.EndFunc(l)
 	a.NotError(err).Equal(string(rs), "\n mcomment3\n mcomment4")
 }
+
+func TestLexer_block_javaDoc(t *testing.T) {
+	a := assert.New(t)
+
+	l := &lexer{
+		data: []byte(`/* comment1 */
+/**/ int i;
+/**
+ * doc1
+ */
+`),
+		blocks: langs["java"],
+	}
+
+	b := l.block() // comment1
+	a.Equal(b.(*Block).Type, BlockTypeString)
+	rs, ok := b.EndFunc(l)
+	a.True(ok).Nil(rs)
+
+	b = l.block() // /**/
+	a.Equal(b.(*Block).Type, BlockTypeString)
+	rs, ok = b.EndFunc(l)
+	a.True(ok).Nil(rs)
+
+	b = l.block() // doc1
+	a.Equal(b.(*Block).Type, BlockTypeDocComment)
+	rs, ok = b.EndFunc(l)
+	a.True(ok).Equal(string(rs), "\ndoc1\n ")
+
+	a.Nil(l.block())
+}
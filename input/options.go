@@ -29,6 +29,8 @@ type Options struct {
 	Exts            []string `yaml:"exts,omitempty"`            // 需要扫描的文件扩展名，若未指定，则使用默认值
 	Recursive       bool     `yaml:"recursive"`                 // 是否查找 Dir 的子目录
 	Encoding        string   `yaml:"encoding,omitempty"`        // 文件的编码
+	MaxFileSize     int64    `yaml:"maxFileSize,omitempty"`     // 文件的最大字节数，超过此值的文件将被忽略，0 表示不限制
+	FollowSymlinks  bool     `yaml:"followSymlinks,omitempty"`  // 是否跟随指向目录的符号链接
 }
 
 // Sanitize 检测 Options 变量是否符合要求
@@ -53,6 +55,10 @@ func (opt *Options) Sanitize() *types.OptionsError {
 		opt.Encoding = encoding.DefaultEncoding
 	}
 
+	if opt.MaxFileSize < 0 {
+		return &types.OptionsError{Field: "maxFileSize", Message: locale.Sprintf(locale.ErrInvalidValue)}
+	}
+
 	if len(opt.Exts) > 0 {
 		exts := make([]string, 0, len(opt.Exts))
 		for _, ext := range opt.Exts {
@@ -67,22 +73,62 @@ func (opt *Options) Sanitize() *types.OptionsError {
 		}
 		opt.Exts = exts
 	} else {
-		opt.Exts = langExts[opt.Lang]
+		opt.Exts = getLangExts(opt.Lang)
 	}
 
 	return nil
 }
 
+// DetectOptions 指定 DetectWithOptions 的检测方式。
+type DetectOptions struct {
+	Recursive      bool // 是否查找子目录
+	FollowSymlinks bool // 是否跟随指向目录的符号链接，默认为 false
+}
+
 // Detect 检测指定目录下的内容，并为其生成一个合适的 Options 实例。
 //
-// 检测依据为根据扩展名来做统计，数量最大且被支持的获胜。
+// 相当于 DetectWithOptions(dir, &DetectOptions{Recursive: recursive})，
+// 不会跟随指向目录的符号链接。
 func Detect(dir string, recursive bool) (*Options, error) {
+	return DetectWithOptions(dir, &DetectOptions{Recursive: recursive})
+}
+
+// DetectWithOptions 检测指定目录下的内容，并为其生成一个合适的 Options 实例。
+//
+// 若 dir 下的 .gitattributes 通过 linguist-language 为某些文件指定了被支持的语言，
+// 则直接使用该语言；否则根据扩展名来做统计，数量最大且被支持的获胜。
+// opt 为 nil 时，表示不查找子目录，也不跟随符号链接。
+func DetectWithOptions(dir string, opt *DetectOptions) (*Options, error) {
+	if opt == nil {
+		opt = &DetectOptions{}
+	}
+	recursive, symlinks := opt.Recursive, opt.FollowSymlinks
+
 	dir, err := filepath.Abs(dir)
 	if err != nil {
 		return nil, err
 	}
 
-	exts, err := detectExts(dir, recursive)
+	hint, err := detectLinguistHint(dir, recursive, symlinks)
+	if err != nil {
+		return nil, err
+	}
+	if hint != nil {
+		exts := getLangExts(hint.lang)
+		if ext := hint.ext(); len(ext) > 0 && getLangByExt(ext) != hint.lang {
+			exts = append(append(make([]string, 0, len(exts)+1), exts...), ext)
+		}
+
+		return &Options{
+			Lang:           hint.lang,
+			Dir:            dir,
+			Exts:           exts,
+			Recursive:      recursive,
+			FollowSymlinks: symlinks,
+		}, nil
+	}
+
+	exts, err := detectExts(dir, recursive, symlinks)
 	if err != nil {
 		return nil, err
 	}
@@ -120,34 +166,24 @@ func Detect(dir string, recursive bool) (*Options, error) {
 		StartLineNumber: 0,
 		Lang:            lang,
 		Dir:             dir,
-		Exts:            langExts[lang],
+		Exts:            getLangExts(lang),
 		Recursive:       recursive,
+		FollowSymlinks:  symlinks,
 	}, nil
 }
 
 // 返回 dir 目录下文件类型及对应的文件数量的一个集合。
-// recursive 表示是否查找子目录。
-func detectExts(dir string, recursive bool) (map[string]int, error) {
+// recursive 表示是否查找子目录；symlinks 表示是否跟随指向目录的符号链接。
+func detectExts(dir string, recursive, symlinks bool) (map[string]int, error) {
 	exts := map[string]int{}
 
-	walk := func(path string, fi os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if fi.IsDir() {
-			if !recursive && dir != path {
-				return filepath.SkipDir
-			}
-		} else {
-			ext := strings.ToLower(filepath.Ext(path))
-			exts[ext]++
-		}
-
+	walk := func(path string, fi os.FileInfo) error {
+		ext := strings.ToLower(filepath.Ext(path))
+		exts[ext]++
 		return nil
 	}
 
-	if err := filepath.Walk(dir, walk); err != nil {
+	if err := walkDir(dir, recursive, symlinks, walk); err != nil {
 		return nil, err
 	}
 
@@ -30,17 +30,20 @@ func TestOptions_Sanitize(t *testing.T) {
 	o.Exts = []string{"c1", ".c2"}
 	a.NotError(o.Sanitize())
 	a.Equal(o.Exts, []string{".c1", ".c2"})
+
+	o.MaxFileSize = -1
+	a.Error(o.Sanitize())
 }
 
 func TestDetectExts(t *testing.T) {
 	a := assert.New(t)
 
-	files, err := detectExts("./testdir", false)
+	files, err := detectExts("./testdir", false, false)
 	a.NotError(err)
 	a.Equal(len(files), 4)
 	a.Equal(files[".php"], 1).Equal(files[".c"], 1)
 
-	files, err = detectExts("./testdir", true)
+	files, err = detectExts("./testdir", true, false)
 	a.NotError(err)
 	a.Equal(len(files), 5)
 	a.Equal(files[".php"], 1).Equal(files[".1"], 3)
@@ -52,4 +55,5 @@ func TestDetect(t *testing.T) {
 	o, err := Detect("./testdir", true)
 	a.NotError(err).NotEmpty(o)
 	a.NotContains(o.Exts, ".1") // .1 不存在于已定义的语言中
+	a.False(o.FollowSymlinks)   // 默认不跟随符号链接
 }
@@ -19,6 +19,10 @@ func newPascalStringBlock(symbol byte) blocker {
 	}
 }
 
+func (b *pascalStringBlock) beginString() string {
+	return b.symbol
+}
+
 func (b *pascalStringBlock) BeginFunc(l *lexer) bool {
 	return l.match(b.symbol)
 }
@@ -0,0 +1,21 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import "regexp"
+
+// protobuf 中 rpc 或是 message 的声明
+var protobufDecl = regexp.MustCompile(`^\s*(?:rpc|message)\s+([A-Za-z_][A-Za-z0-9_]*)`)
+
+// 从注释块之后的代码中获取紧跟着的 rpc 或是 message 的名称。
+//
+// 注释块与声明之间只能有空行，若第一个非空行不是 rpc 或是 message 声明，则返回空值。
+func protobufDeclName(code []byte) string {
+	matches := protobufDecl.FindSubmatch(code)
+	if len(matches) < 2 {
+		return ""
+	}
+	return string(matches[1])
+}
@@ -0,0 +1,43 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/input/encoding"
+	"github.com/caixw/apidoc/types"
+)
+
+func TestProtobufDeclName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(protobufDeclName([]byte("rpc Login (Request) returns (Response);")), "Login")
+	a.Equal(protobufDeclName([]byte("\n    rpc Login(Request) returns (Response);")), "Login")
+	a.Equal(protobufDeclName([]byte("\n\nmessage User_1 {\n}")), "User_1")
+	a.Equal(protobufDeclName([]byte("  message User{}")), "User")
+
+	a.Empty(protobufDeclName([]byte("service Users {")))
+	a.Empty(protobufDeclName([]byte("int x;\nrpc Login (Request) returns (Response);")))
+	a.Empty(protobufDeclName([]byte("rpcLogin (Request)")))
+	a.Empty(protobufDeclName(nil))
+}
+
+func TestParseFile_protobuf(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	o := &Options{Lang: "protobuf", Encoding: encoding.DefaultEncoding}
+	parseFile(docs, "./testdata/protobuf/test1.proto", langs["protobuf"], o)
+	a.Equal(2, len(docs.Apis))
+
+	names := map[string]string{}
+	for _, api := range docs.Apis {
+		names[api.Method] = api.Name
+	}
+	a.Equal(names, map[string]string{"POST": "Login", "DELETE": "Logout"})
+}
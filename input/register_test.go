@@ -0,0 +1,94 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package input_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/input"
+)
+
+// 以 -- 作为单行注释，--[[ ]] 作为多行注释的语言
+var luaBlocks = []*input.Block{
+	{Type: input.BlockTypeString, Begin: `"`, End: `"`, Escape: `\`},
+	{Type: input.BlockTypeString, Begin: "'", End: "'", Escape: `\`},
+	{Type: input.BlockTypeMComment, Begin: "--[[", End: "]]"}, // 需要在 -- 之前定义
+	{Type: input.BlockTypeSComment, Begin: "--"},
+}
+
+func TestRegisterLang(t *testing.T) {
+	a := assert.New(t)
+	defer input.UnregisterLang("test-lua")
+
+	a.NotError(input.RegisterLang("Test-Lua", luaBlocks, ".TLUA"))
+	a.Contains(input.Languages(), "test-lua")
+
+	// 重复注册
+	a.Error(input.RegisterLang("test-lua", luaBlocks, ".tlua"))
+	a.Error(input.RegisterLang("go", luaBlocks, ".go"))
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `local s = "-- @api GET /ignored 字符串中的内容"
+
+--[[
+@api GET /users 获取用户列表
+@apiGroup users
+@apiSuccess 200 OK
+]]
+function users() end
+
+-- @api POST /users 添加用户
+-- @apiGroup users
+-- @apiSuccess 201 OK
+function create() end
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.tlua"), []byte(code), os.ModePerm))
+
+	errLog := new(bytes.Buffer)
+	o := &input.Options{Lang: "test-lua", Dir: dir, ErrorLog: log.New(errLog, "", 0)}
+	a.Nil(o.Sanitize())
+	a.Equal(o.Exts, []string{".tlua"})
+
+	docs, _ := input.Parse(o)
+	a.Empty(errLog.String())
+	a.Equal(len(docs.Apis), 2)
+	for _, api := range docs.Apis {
+		a.Equal(api.URL, "/users").Equal(api.Group, "users")
+	}
+}
+
+func TestRegisterLang_invalid(t *testing.T) {
+	a := assert.New(t)
+	defer input.UnregisterLang("test-invalid")
+
+	// 名称和扩展名
+	a.Error(input.RegisterLang(" ", luaBlocks, ".tlua"))
+	a.Error(input.RegisterLang("test-invalid", luaBlocks))
+	a.Error(input.RegisterLang("test-invalid", luaBlocks, "tlua"))
+	a.Error(input.RegisterLang("test-invalid", luaBlocks, "."))
+
+	// 代码块
+	for _, blocks := range [][]*input.Block{
+		nil,
+		{nil},
+		{{Begin: "--"}},
+		{{Type: input.BlockTypeSComment}},
+		{{Type: input.BlockTypeMComment, Begin: "--[["}},
+		{{Type: input.BlockTypeDocComment, Begin: "---[[", End: ""}},
+		{{Type: input.BlockTypeString, Begin: `"`}},
+	} {
+		a.Error(input.RegisterLang("test-invalid", blocks, ".tlua"), blocks)
+	}
+	a.NotContains(input.Languages(), "test-invalid")
+}
@@ -22,6 +22,10 @@ func newSwiftNestMCommentBlock(begin, end string) blocker {
 	}
 }
 
+func (b *swiftNestMCommentBlock) beginString() string {
+	return b.begin
+}
+
 func (b *swiftNestMCommentBlock) BeginFunc(l *lexer) bool {
 	if l.match(b.begin) {
 		b.level++
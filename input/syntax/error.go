@@ -34,7 +34,7 @@ func (l *lexer) syntaxWarn(format string, v ...interface{}) {
 
 // 输出语法错误
 func (t *tag) syntaxError(format string, v ...interface{}) {
-	OutputError(t.lexer.input.Warn, t.lexer.input.File, t.lineNumber(), format, v...)
+	OutputError(t.lexer.input.Error, t.lexer.input.File, t.lineNumber(), format, v...)
 }
 
 // 输出语法警告信息
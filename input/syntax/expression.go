@@ -0,0 +1,109 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package syntax
+
+import "strings"
+
+// 判断 @apiCallback 中的回调地址是否合法。
+//
+// 回调地址中至少包含一个由 {} 包含的运行时表达式，比如：
+//
+//	{$request.body#/callbackUrl}
+//	https://example.com/notify?id={$request.query.id}
+func isCallbackExpression(expr string) bool {
+	found := false
+	for {
+		start := strings.IndexByte(expr, '{')
+		end := strings.IndexByte(expr, '}')
+		if start < 0 {
+			return end < 0 && found
+		}
+		if end < start {
+			return false
+		}
+
+		if !isRuntimeExpression(expr[start+1 : end]) {
+			return false
+		}
+		found = true
+		expr = expr[end+1:]
+	}
+}
+
+// 判断 expr 是否为 OpenAPI 规范中的运行时表达式，其 ABNF 定义如下：
+//
+//	expression = ( "$url" / "$method" / "$statusCode" / "$request." source / "$response." source )
+//	source = ( header-reference / query-reference / path-reference / body-reference )
+//	header-reference = "header." token
+//	query-reference = "query." name
+//	path-reference = "path." name
+//	body-reference = "body" ["#" json-pointer ]
+func isRuntimeExpression(expr string) bool {
+	switch {
+	case expr == "$url" || expr == "$method" || expr == "$statusCode":
+		return true
+	case strings.HasPrefix(expr, "$request."):
+		return isExpressionSource(expr[len("$request."):])
+	case strings.HasPrefix(expr, "$response."):
+		return isExpressionSource(expr[len("$response."):])
+	default:
+		return false
+	}
+}
+
+func isExpressionSource(source string) bool {
+	switch {
+	case strings.HasPrefix(source, "header."):
+		return isToken(source[len("header."):])
+	case strings.HasPrefix(source, "query."):
+		return len(source) > len("query.")
+	case strings.HasPrefix(source, "path."):
+		return len(source) > len("path.")
+	case source == "body":
+		return true
+	case strings.HasPrefix(source, "body#"):
+		return isJSONPointer(source[len("body#"):])
+	default:
+		return false
+	}
+}
+
+// 判断 s 是否为 RFC 7230 中定义的 token
+func isToken(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// 判断 s 是否为 RFC 6901 中定义的 JSON Pointer
+func isJSONPointer(s string) bool {
+	if len(s) == 0 {
+		return true
+	}
+	if s[0] != '/' {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '~' {
+			continue
+		}
+
+		if i == len(s)-1 || (s[i+1] != '0' && s[i+1] != '1') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package syntax
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestIsCallbackExpression(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isCallbackExpression("{$request.body#/callbackUrl}"))
+	a.True(isCallbackExpression("https://example.com/notify?id={$request.query.id}&email={$request.body#/email}"))
+	a.True(isCallbackExpression("{$url}"))
+
+	a.False(isCallbackExpression("https://example.com/notify"))
+	a.False(isCallbackExpression("$request.body#/callbackUrl"))
+	a.False(isCallbackExpression("{$request.body#/callbackUrl"))
+	a.False(isCallbackExpression("$request.body#/callbackUrl}"))
+	a.False(isCallbackExpression("}{$url}"))
+	a.False(isCallbackExpression("{$url}{$request}"))
+	a.False(isCallbackExpression(""))
+}
+
+func TestIsRuntimeExpression(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isRuntimeExpression("$url"))
+	a.True(isRuntimeExpression("$method"))
+	a.True(isRuntimeExpression("$statusCode"))
+	a.True(isRuntimeExpression("$request.header.X-Callback"))
+	a.True(isRuntimeExpression("$request.query.url"))
+	a.True(isRuntimeExpression("$request.path.id"))
+	a.True(isRuntimeExpression("$request.body"))
+	a.True(isRuntimeExpression("$request.body#/callbackUrl"))
+	a.True(isRuntimeExpression("$response.body#/a~0b/c~1d"))
+	a.True(isRuntimeExpression("$response.header.Location"))
+
+	a.False(isRuntimeExpression(""))
+	a.False(isRuntimeExpression("$status"))
+	a.False(isRuntimeExpression("request.body"))
+	a.False(isRuntimeExpression("$request.cookie.id"))
+	a.False(isRuntimeExpression("$request.header."))
+	a.False(isRuntimeExpression("$request.header.X Callback"))
+	a.False(isRuntimeExpression("$request.query."))
+	a.False(isRuntimeExpression("$request.path."))
+	a.False(isRuntimeExpression("$request.body#callbackUrl"))
+	a.False(isRuntimeExpression("$request.body#/a~2"))
+	a.False(isRuntimeExpression("$request.body#/a~"))
+}
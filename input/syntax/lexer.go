@@ -99,7 +99,7 @@ func (l *lexer) matchTag(tagName string) bool {
 		return false
 	}
 
-	if !unicode.IsSpace(l.input.Data[l.pos]) {
+	if !l.atEOF() && !unicode.IsSpace(l.input.Data[l.pos]) {
 		l.backup()
 		return false
 	}
@@ -230,6 +230,26 @@ func (t *tag) readLine() string {
 	return string(trimRight(t.data[start:t.pos]))
 }
 
+// 读取当前行中剩余的内容，不包含首尾空格。
+// 与 readLine 不同，不会跳过行首的换行符，若当前行已经没有内容，则返回空值。
+func (t *tag) readRestLine() string {
+	for {
+		if t.atEOF() || t.data[t.pos] == '\n' || !unicode.IsSpace(t.data[t.pos]) {
+			break
+		}
+		t.pos++
+	}
+
+	start := t.pos
+	for {
+		if t.atEOF() || t.data[t.pos] == '\n' {
+			break
+		}
+		t.pos++
+	}
+	return string(trimRight(t.data[start:t.pos]))
+}
+
 // 读取从当前位置到结尾的所有内容，去掉首尾空格
 func (t *tag) readEnd() string {
 	if t.atEOF() {
@@ -22,6 +22,20 @@ func TestLexer_readLine(t *testing.T) {
 	a.Equal(l.readLine(), []rune("line2"))
 }
 
+func TestTag_readRestLine(t *testing.T) {
+	a := assert.New(t)
+	l := newLexerString("")
+
+	tag := &tag{lexer: l, data: []rune(" line1 \n line2")}
+	a.Equal(tag.readRestLine(), "line1")
+	a.Equal(tag.readRestLine(), "") // 不会跳过换行符
+	a.Equal(tag.pos, 7)
+
+	tag.pos++
+	a.Equal(tag.readRestLine(), "line2")
+	a.True(tag.atEOF())
+}
+
 func TestLexer_lineNumber(t *testing.T) {
 	a := assert.New(t)
 	l := newLexerString("\n\n")
@@ -80,6 +94,12 @@ func TestLexer_matchTag(t *testing.T) {
 	l.pos++
 	a.False(l.matchTag("@line")) // 不匹配部分内容
 	a.True(l.matchTag("@line2"))
+
+	// 标签位于内容的最后
+	l = newLexerString("@line1")
+	a.False(l.matchTag("@line"))
+	a.True(l.matchTag("@line1"))
+	a.True(l.atEOF())
 }
 
 func TestLexer_skipSpace(t *testing.T) {
@@ -6,7 +6,17 @@
 package syntax
 
 import (
+	"encoding/json"
 	"log"
+	"mime"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/issue9/is"
 
@@ -20,8 +30,12 @@ type Input struct {
 	File  string      // 该段代码所在的文件
 	Line  int         // 该段代码在文件中的行号
 	Data  []rune      // 需要解析的代码段
+	Name  string      // 代码段之后紧跟着的声明名称，作为 API.Name 的默认值，可以为空
 	Error *log.Logger // 出错时的输出通道
 	Warn  *log.Logger // 警告信息的输出通道
+
+	// 判断 @apiCodegen 中的语言是否被支持，为空表示不作检测
+	LangIsSupported func(lang string) bool
 }
 
 // Parse 分析一段代码，并将结果保存到 d 中。
@@ -43,7 +57,13 @@ func Parse(input *Input, d *types.Doc) {
 				return
 			}
 
+			if len(api.Name) == 0 {
+				api.Name = input.Name
+			}
 			d.NewAPI(api)
+		case l.matchTag(vars.APIGRPC): // 没有对应的 REST 地址
+			l.syntaxWarn(locale.ErrTagOutsideAPI, vars.APIGRPC, vars.API)
+			l.readTag()
 		case l.match(vars.API):
 			l.backup()
 			l.syntaxWarn(locale.ErrUnknownTag, l.readWord())
@@ -62,7 +82,9 @@ func Parse(input *Input, d *types.Doc) {
 // @apidoc title of doc
 // @apiVersion 2.0
 // @apiBaseURL https://api.caixw.io
+// @apiBasePath /v1
 // @apiLicense MIT https://opensource.org/licenses/MIT
+// @apiSecurity token apiKey header X-API-Key
 //
 // @apiContent
 // content1
@@ -108,6 +130,17 @@ func (l *lexer) scanAPIDoc(d *types.Doc) bool {
 				t.syntaxError(locale.ErrTagArgTooMuch, vars.APIBaseURL)
 				return false
 			}
+		case l.matchTag(vars.APIBasePath):
+			t := l.readTag()
+			d.BasePath = t.readWord()
+			if len(d.BasePath) == 0 {
+				t.syntaxError(locale.ErrTagArgNotEnough, vars.APIBasePath)
+				return false
+			}
+			if !t.atEOF() {
+				t.syntaxError(locale.ErrTagArgTooMuch, vars.APIBasePath)
+				return false
+			}
 		case l.matchTag(vars.APILicense):
 			t := l.readTag()
 			d.LicenseName = t.readWord()
@@ -124,6 +157,14 @@ func (l *lexer) scanAPIDoc(d *types.Doc) bool {
 				t.syntaxError(locale.ErrTagArgTooMuch, vars.APILicense)
 				return false
 			}
+		case l.matchTag(vars.APISecurity):
+			if !l.scanSecurity(d) {
+				return false
+			}
+		case l.matchTag(vars.APISDK):
+			if !l.scanSDK(d) {
+				return false
+			}
 		case l.matchTag(vars.APIContent):
 			d.Content = l.readEnd()
 		case l.match(vars.API): // 不认识的标签
@@ -139,6 +180,124 @@ func (l *lexer) scanAPIDoc(d *types.Doc) bool {
 	} // end for
 }
 
+// 解析 @apiSDK language package url，可以指定多个，language 不能重复。
+func (l *lexer) scanSDK(d *types.Doc) bool {
+	t := l.readTag()
+
+	sdk := &types.SDK{
+		Language: t.readWord(),
+		Package:  t.readWord(),
+		URL:      t.readWord(),
+	}
+	if len(sdk.Language) == 0 || len(sdk.Package) == 0 || len(sdk.URL) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APISDK)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APISDK)
+		return false
+	}
+
+	if !is.URL(sdk.URL) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APISDK, sdk.URL)
+		return false
+	}
+
+	for _, item := range d.SDKs {
+		if item.Language == sdk.Language {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APISDK, sdk.Language)
+			return false
+		}
+	}
+
+	d.SDKs = append(d.SDKs, sdk)
+	return true
+}
+
+// 解析 @apiSecurity 标签，根据认证方式的类型，其后的参数也各不相同：
+//
+// @apiSecurity token apiKey header X-API-Key
+// @apiSecurity basic http basic
+// @apiSecurity oauth oauth2 https://example.com/oauth/token [scope1,scope2]
+// @apiSecurity oidc openIdConnect https://example.com/.well-known/openid-configuration
+func (l *lexer) scanSecurity(d *types.Doc) bool {
+	t := l.readTag()
+	s := &types.Security{
+		Name: t.readWord(),
+		Type: t.readWord(),
+	}
+	if len(s.Name) == 0 || len(s.Type) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APISecurity)
+		return false
+	}
+
+	switch s.Type {
+	case types.SecurityTypeAPIKey:
+		s.In = t.readWord()
+		s.Key = t.readWord()
+		if len(s.In) == 0 || len(s.Key) == 0 {
+			t.syntaxError(locale.ErrTagArgNotEnough, vars.APISecurity)
+			return false
+		}
+		if s.In != "header" && s.In != "query" && s.In != "cookie" {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APISecurity, s.In)
+			return false
+		}
+	case types.SecurityTypeHTTP:
+		s.Scheme = t.readWord()
+		if len(s.Scheme) == 0 {
+			t.syntaxError(locale.ErrTagArgNotEnough, vars.APISecurity)
+			return false
+		}
+	case types.SecurityTypeOAuth2, types.SecurityTypeOpenIDConnect:
+		s.URL = t.readWord()
+		if len(s.URL) == 0 {
+			t.syntaxError(locale.ErrTagArgNotEnough, vars.APISecurity)
+			return false
+		}
+		if !is.URL(s.URL) {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APISecurity, s.URL)
+			return false
+		}
+
+		if scopes := t.readWord(); len(scopes) > 0 && s.Type == types.SecurityTypeOAuth2 {
+			for _, scope := range strings.Split(scopes, ",") {
+				if len(scope) == 0 || inStrings(s.Scopes, scope) {
+					t.syntaxError(locale.ErrInvalidTagValue, vars.APISecurity, scopes)
+					return false
+				}
+				s.Scopes = append(s.Scopes, scope)
+			}
+		} else if len(scopes) > 0 {
+			t.syntaxError(locale.ErrTagArgTooMuch, vars.APISecurity)
+			return false
+		}
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APISecurity, s.Type)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APISecurity)
+		return false
+	}
+
+	if d.SecuritySchemes == nil {
+		d.SecuritySchemes = make(map[string]*types.Security, 5)
+	}
+	if _, found := d.SecuritySchemes[s.Name]; found {
+		t.syntaxError(locale.ErrDuplicateTagValue, vars.APISecurity, s.Name)
+		return false
+	}
+	d.SecuritySchemes[s.Name] = s
+
+	return true
+}
+
+// @api 的简要描述超过此长度（字符数）时给出警告
+const maxSummaryLength = 120
+
 // 解析 @api 及其子标签
 func (l *lexer) scanAPI() (*types.API, bool) {
 	api := &types.API{}
@@ -152,7 +311,15 @@ func (l *lexer) scanAPI() (*types.API, bool) {
 		return nil, false
 	}
 
+	// 简要描述仅有一行，更多的内容应该放在之后的详细描述中。
+	if n := utf8.RuneCountInString(api.Summary); n > maxSummaryLength {
+		t.syntaxWarn(locale.ErrSummaryTooLong, vars.API, n, maxSummaryLength)
+	}
+
 	api.Description = t.readEnd()
+
+	// @apiNullable 指定的参数名称，需要等所有参数都解析完之后才能设置。
+	var nullables []string
 LOOP:
 	for {
 		switch {
@@ -162,6 +329,90 @@ LOOP:
 			if !l.scanGroup(api) {
 				return nil, false
 			}
+		case l.matchTag(vars.APIResponseHeader):
+			if !l.scanResponseHeader(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APISafe):
+			if !l.scanFlag(vars.APISafe, &api.Safe) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIIdempotent):
+			if !l.scanFlag(vars.APIIdempotent, &api.Idempotent) {
+				return nil, false
+			}
+		case l.matchTag(vars.APINote):
+			if !l.scanNote(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APICallback):
+			if !l.scanCallback(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIAsync):
+			if !l.scanAsync(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIEnvironment):
+			if !l.scanEnvironment(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIOwner):
+			if !l.scanOwner(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIMetric):
+			if !l.scanMetric(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APILinkTo):
+			if !l.scanLinkTo(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIContract):
+			if !l.scanContract(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APITodo):
+			if !l.scanTodo(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIMediaType):
+			if !l.scanMediaType(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIAuthError):
+			if !l.scanAuthError(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIConflict):
+			if !l.scanConflict(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIThrows):
+			if !l.scanThrows(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIRetry):
+			if !l.scanRetry(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APICacheControl):
+			if !l.scanCacheControl(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APICORS):
+			if !l.scanCORS(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APITryIt):
+			if !l.scanTryIt(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIOrder):
+			if !l.scanOrder(api) {
+				return nil, false
+			}
 		case l.matchTag(vars.APIQuery):
 			if !l.scanAPIQueries(api) {
 				return nil, false
@@ -188,6 +439,174 @@ LOOP:
 				return nil, false
 			}
 			api.Success = resp
+		case l.matchTag(vars.APIProduces):
+			if !l.scanProduces(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIConsumes):
+			if !l.scanConsumes(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APINullable):
+			if !l.scanNullable(&nullables) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIFormat):
+			if !l.scanFormat(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APICalls):
+			if !l.scanCalls(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIAccess):
+			if !l.scanAccess(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIScope):
+			if !l.scanScope(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIMultipart):
+			if !l.scanFlag(vars.APIMultipart, &api.Multipart) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIRequestID):
+			if !l.scanRequestID(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APISSE):
+			if !l.scanFlag(vars.APISSE, &api.SSE) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIEvent):
+			if !l.scanEvent(vars.APIEvent, &api.Events, true) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIWebSocket):
+			if !l.scanFlag(vars.APIWebSocket, &api.WebSocket) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIWSSend):
+			if !l.scanEvent(vars.APIWSSend, &api.WSSend, false) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIWSReceive):
+			if !l.scanEvent(vars.APIWSReceive, &api.WSReceive, false) {
+				return nil, false
+			}
+		case l.matchTag(vars.APISocketIO):
+			if !l.scanSocketIO(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIProducesEvent):
+			if !l.scanEvent(vars.APIProducesEvent, &api.ProducedEvents, false) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIConsumesEvent):
+			if !l.scanEvent(vars.APIConsumesEvent, &api.ConsumedEvents, false) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIChangelog):
+			if !l.scanChangelog(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIBreakingChange):
+			if !l.scanBreakingChange(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIGRPC):
+			if !l.scanGRPC(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIGraphQL):
+			if !l.scanGraphQL(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIOperationID):
+			if !l.scanOperationID(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APITimeout):
+			if !l.scanTimeout(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APITimeBudget):
+			if !l.scanTimeBudget(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIMockDelay):
+			if !l.scanMockDelay(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIThrottle):
+			if !l.scanThrottle(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIQuota):
+			if !l.scanQuota(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIBatch):
+			if !l.scanBatch(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIContentLength):
+			if !l.scanContentLength(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APICodegen):
+			if !l.scanCodegen(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIDataClassification):
+			if !l.scanDataClassification(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIAudience):
+			if !l.scanAudience(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIFeatureFlag):
+			if !l.scanFeatureFlag(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIRegion):
+			if !l.scanRegion(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APITenant):
+			if !l.scanTenant(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APICircuitBreaker):
+			if !l.scanCircuitBreaker(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APISLA):
+			if !l.scanSLA(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIIdempotencyKey):
+			if !l.scanIdempotencyKey(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIContentNegotiation):
+			if !l.scanContentNegotiation(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIAuth):
+			if !l.scanAuth(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIContentType):
+			if !l.scanContentType(api) {
+				return nil, false
+			}
+		case l.matchTag(vars.APIExtension):
+			if !l.scanExtension(api) {
+				return nil, false
+			}
 		case l.match(vars.API): // 不认识的标签
 			l.backup()
 			l.syntaxWarn(locale.ErrUnknownTag, l.readWord())
@@ -209,6 +628,31 @@ LOOP:
 		api.Group = vars.DefaultGroupName
 	}
 
+	// 只涉及 api 自身各标签之间一致性的检测，都在此处以警告信息输出。
+	l.checkProduces(api)
+	l.checkSafe(api)
+	l.checkRetry(api)
+	l.checkIdempotencyKey(api)
+	l.checkBatch(api)
+	l.checkContentLength(api)
+	l.checkMultipart(api)
+	l.checkMediaType(api)
+	l.checkSSE(api)
+	l.checkWebSocket(api)
+	l.checkAccess(api)
+	l.checkCacheControl(api)
+	l.checkCORS(api)
+	l.checkCircuitBreaker(api)
+	l.checkTenant(api)
+	l.checkRegion(api)
+	l.checkQuota(api)
+	l.checkAsync(api)
+	l.setNullable(api, nullables)
+
+	sort.SliceStable(api.Changelog, func(i, j int) bool {
+		return types.CompareVersion(api.Changelog[i].Version, api.Changelog[j].Version) > 0
+	})
+
 	return api, true
 }
 
@@ -229,46 +673,2525 @@ func (l *lexer) scanGroup(api *types.API) bool {
 	return true
 }
 
-func (l *lexer) scanAPIQueries(api *types.API) bool {
-	if api.Queries == nil {
-		api.Queries = make([]*types.Param, 0, 1)
+// 解析 @apiResponseHeader code name type summary，code 为 * 表示所有的状态码。
+func (l *lexer) scanResponseHeader(api *types.API) bool {
+	t := l.readTag()
+
+	code := t.readWord()
+	h := &types.ResponseHeader{
+		Name:    t.readWord(),
+		Type:    t.readWord(),
+		Summary: t.readLine(),
+	}
+	if len(code) == 0 || len(h.Name) == 0 || len(h.Type) == 0 || len(h.Summary) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIResponseHeader)
+		return false
 	}
 
-	if p, ok := l.scanAPIParam(vars.APIQuery); ok {
-		api.Queries = append(api.Queries, p)
-		return true
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIResponseHeader)
+		return false
 	}
-	return false
+
+	if code != "*" {
+		if _, err := strconv.Atoi(code); err != nil {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIResponseHeader, code)
+			return false
+		}
+	}
+
+	for _, header := range api.ResponseHeaders[code] {
+		if strings.EqualFold(header.Name, h.Name) {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIResponseHeader, h.Name)
+			return false
+		}
+	}
+
+	if api.ResponseHeaders == nil {
+		api.ResponseHeaders = make(map[string][]*types.ResponseHeader, 2)
+	}
+	api.ResponseHeaders[code] = append(api.ResponseHeaders[code], h)
+	return true
 }
 
-func (l *lexer) scanAPIParams(api *types.API) bool {
-	if api.Params == nil {
-		api.Params = make([]*types.Param, 0, 1)
+// 解析 @apiSafe 和 @apiIdempotent 等不带参数的标签，并将 flag 设置为 true。
+func (l *lexer) scanFlag(tagName string, flag *bool) bool {
+	t := l.readTag()
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, tagName)
+		return false
 	}
 
-	if p, ok := l.scanAPIParam(vars.APIParam); ok {
-		api.Params = append(api.Params, p)
-		return true
+	if *flag {
+		t.syntaxError(locale.ErrDuplicateTag, tagName)
+		return false
 	}
-	return false
+
+	*flag = true
+	return true
 }
 
-// 解析 @apiRequest 及其子标签
-func (l *lexer) scanAPIRequest() (*types.Request, bool) {
+// POST 和 DELETE 通常会修改服务端的数据，使用 @apiSafe 时给出警告。
+func (l *lexer) checkSafe(api *types.API) {
+	if !api.Safe {
+		return
+	}
+
+	method := strings.ToUpper(api.Method)
+	if method == "POST" || method == "DELETE" {
+		l.syntaxWarn(locale.ErrUnsafeMethod, method, vars.APISafe)
+	}
+}
+
+// 解析 @apiNote [type] text，type 可以省略，默认为 info；text 可以跨行。
+func (l *lexer) scanNote(api *types.API) bool {
 	t := l.readTag()
-	r := &types.Request{
-		Type:     t.readLine(),
-		Headers:  map[string]string{},
-		Params:   []*types.Param{},
-		Examples: []*types.Example{},
+
+	start := t.pos
+	typ := t.readWord()
+	switch typ {
+	case types.NoteTypeInfo, types.NoteTypeWarning, types.NoteTypeDanger, types.NoteTypeTip:
+	default: // 未指定类型，该单词属于 text 的一部分
+		t.pos = start
+		typ = types.NoteTypeInfo
 	}
-	if !t.atEOF() {
-		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIRequest)
-		return nil, false
+
+	text := t.readEnd()
+	if len(text) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APINote)
+		return false
 	}
 
-LOOP:
-	for {
+	api.Notes = append(api.Notes, &types.Note{Type: typ, Text: text})
+	return true
+}
+
+// 解析 @apiOrder order，order 必须为大于 0 的整数。
+func (l *lexer) scanOrder(api *types.API) bool {
+	t := l.readTag()
+
+	word := t.readWord()
+	if len(word) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIOrder)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIOrder)
+		return false
+	}
+
+	if api.Order > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIOrder)
+		return false
+	}
+
+	order, err := strconv.Atoi(word)
+	if err != nil || order <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIOrder, word)
+		return false
+	}
+
+	api.Order = order
+	return true
+}
+
+// 解析 @apiCallback name expression method path
+func (l *lexer) scanCallback(api *types.API) bool {
+	t := l.readTag()
+
+	c := &types.Callback{
+		Name:       t.readWord(),
+		Expression: t.readWord(),
+		Method:     t.readWord(),
+		Path:       t.readWord(),
+	}
+	if len(c.Name) == 0 || len(c.Expression) == 0 || len(c.Method) == 0 || len(c.Path) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APICallback)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APICallback)
+		return false
+	}
+
+	if !isCallbackExpression(c.Expression) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APICallback, c.Expression)
+		return false
+	}
+
+	for _, callback := range api.Callbacks {
+		if callback.Name == c.Name {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APICallback, c.Name)
+			return false
+		}
+	}
+
+	api.Callbacks = append(api.Callbacks, c)
+	return true
+}
+
+// 邮箱地址的基本格式，来自 RFC 5322 的简化版本，不支持引号和注释等形式。
+var emailExpr = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+
+// 解析 @apiOwner team [email]
+//
+// team 可以包含空格，若有多个单词，且最后一个单词包含 @，则将其作为 email 处理。
+func (l *lexer) scanOwner(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Owner != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIOwner)
+		return false
+	}
+
+	o := &types.Owner{Team: t.readLine()}
+	if len(o.Team) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIOwner)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIOwner)
+		return false
+	}
+
+	if index := strings.LastIndexAny(o.Team, " \t"); index > 0 && strings.IndexByte(o.Team[index+1:], '@') >= 0 {
+		o.Email = o.Team[index+1:]
+		o.Team = strings.TrimSpace(o.Team[:index])
+	}
+
+	if len(o.Email) > 0 && !emailExpr.MatchString(o.Email) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIOwner, o.Email)
+		return false
+	}
+
+	api.Owner = o
+	return true
+}
+
+// 解析 @apiEnvironment env description
+//
+// env 可以是 prod、staging、dev 和 all，多个 @apiEnvironment 的内容会累加。
+func (l *lexer) scanEnvironment(api *types.API) bool {
+	t := l.readTag()
+
+	env := t.readWord()
+	text := t.readEnd()
+	if len(env) == 0 || len(text) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIEnvironment)
+		return false
+	}
+
+	switch env {
+	case types.EnvironmentProd, types.EnvironmentStaging, types.EnvironmentDev, types.EnvironmentAll:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIEnvironment, env)
+		return false
+	}
+
+	api.Environments = append(api.Environments, &types.EnvironmentNote{Environment: env, Text: text})
+	return true
+}
+
+// 解析 @apiMetric name value unit
+func (l *lexer) scanMetric(api *types.API) bool {
+	t := l.readTag()
+
+	name := t.readWord()
+	value := t.readWord()
+	unit := t.readWord()
+	if len(name) == 0 || len(value) == 0 || len(unit) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIMetric)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIMetric)
+		return false
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIMetric, value)
+		return false
+	}
+
+	for _, m := range api.Metrics {
+		if m.Name == name {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIMetric, name)
+			return false
+		}
+	}
+
+	api.Metrics = append(api.Metrics, &types.Metric{Name: name, Value: v, Unit: unit})
+	return true
+}
+
+// 解析 @apiLinkTo url [label]
+func (l *lexer) scanLinkTo(api *types.API) bool {
+	t := l.readTag()
+
+	link := &types.Link{
+		URL:   t.readWord(),
+		Label: t.readLine(),
+	}
+	if len(link.URL) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APILinkTo)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APILinkTo)
+		return false
+	}
+
+	if _, err := url.Parse(link.URL); err != nil {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APILinkTo, link.URL)
+		return false
+	}
+
+	api.Links = append(api.Links, link)
+	return true
+}
+
+// 解析 @apiContract suite url
+func (l *lexer) scanContract(api *types.API) bool {
+	t := l.readTag()
+
+	contract := &types.Contract{
+		Suite: t.readWord(),
+		URL:   t.readWord(),
+	}
+	if len(contract.Suite) == 0 || len(contract.URL) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIContract)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIContract)
+		return false
+	}
+
+	if !is.URL(contract.URL) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIContract, contract.URL)
+		return false
+	}
+
+	for _, c := range api.Contracts {
+		if c.Suite == contract.Suite {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIContract, contract.Suite)
+			return false
+		}
+	}
+
+	api.Contracts = append(api.Contracts, contract)
+	return true
+}
+
+// 解析 @apiTodo message
+//
+// 每一个 @apiTodo 都会输出一条警告信息，方便在 -lint 中发现未完成的文档。
+func (l *lexer) scanTodo(api *types.API) bool {
+	t := l.readTag()
+
+	msg := t.readLine()
+	if len(msg) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APITodo)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APITodo)
+		return false
+	}
+
+	t.syntaxWarn(locale.ErrTodo, msg)
+	api.Todos = append(api.Todos, msg)
+	return true
+}
+
+// 解析 @apiThrows code description
+//
+// code 只能由字母、数字和下划线组成，比如 NOT_FOUND 和 40401。
+func (l *lexer) scanThrows(api *types.API) bool {
+	t := l.readTag()
+
+	e := &types.ErrorCode{
+		Code:    t.readWord(),
+		Summary: t.readLine(),
+	}
+	if len(e.Code) == 0 || len(e.Summary) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIThrows)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIThrows)
+		return false
+	}
+
+	for _, r := range e.Code {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIThrows, e.Code)
+			return false
+		}
+	}
+
+	for _, code := range api.ErrorCodes {
+		if code.Code == e.Code {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIThrows, e.Code)
+			return false
+		}
+	}
+
+	api.ErrorCodes = append(api.ErrorCodes, e)
+	return true
+}
+
+// 解析 @apiAuthError code schema description
+//
+// code 只能是 401 或是 403，相当于不需要 @apiError 的简化写法。
+func (l *lexer) scanAuthError(api *types.API) bool {
+	t := l.readTag()
+
+	e := &types.AuthError{
+		Code:    t.readWord(),
+		Schema:  t.readWord(),
+		Summary: t.readLine(),
+	}
+	if len(e.Code) == 0 || len(e.Schema) == 0 || len(e.Summary) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIAuthError)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIAuthError)
+		return false
+	}
+
+	if e.Code != "401" && e.Code != "403" {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIAuthError, e.Code)
+		return false
+	}
+
+	for _, err := range api.AuthErrors {
+		if err.Code == e.Code {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIAuthError, e.Code)
+			return false
+		}
+	}
+
+	api.AuthErrors = append(api.AuthErrors, e)
+	return true
+}
+
+// 解析 @apiConflict condition schema description
+//
+// 相当于不需要 @apiError 的 409 简化写法，可以指定多个，condition 不能重复。
+func (l *lexer) scanConflict(api *types.API) bool {
+	t := l.readTag()
+
+	c := &types.Conflict{
+		Condition: t.readWord(),
+		Schema:    t.readWord(),
+		Summary:   t.readLine(),
+	}
+	if len(c.Condition) == 0 || len(c.Schema) == 0 || len(c.Summary) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIConflict)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIConflict)
+		return false
+	}
+
+	for _, conflict := range api.Conflicts {
+		if conflict.Condition == c.Condition {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIConflict, c.Condition)
+			return false
+		}
+	}
+
+	api.Conflicts = append(api.Conflicts, c)
+	return true
+}
+
+// 解析 @apiRetry strategy [maxAttempts:n] [backoff:linear|exponential] [retryOn:code1,code2]
+//
+// strategy 之后的选项可以以任意顺序出现，但每个选项只能出现一次。
+func (l *lexer) scanRetry(api *types.API) bool {
+	t := l.readTag()
+
+	r := &types.Retry{Strategy: t.readWord()}
+	if len(r.Strategy) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIRetry)
+		return false
+	}
+
+	keys := make(map[string]bool, 3)
+	for word := t.readWord(); len(word) > 0; word = t.readWord() {
+		index := strings.IndexByte(word, ':')
+		if index <= 0 || index == len(word)-1 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIRetry, word)
+			return false
+		}
+		key, val := word[:index], word[index+1:]
+
+		if keys[key] {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIRetry, key)
+			return false
+		}
+		keys[key] = true
+
+		switch key {
+		case "maxAttempts":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APIRetry, word)
+				return false
+			}
+			r.MaxAttempts = n
+		case "backoff":
+			if val != types.BackoffLinear && val != types.BackoffExponential {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APIRetry, word)
+				return false
+			}
+			r.Backoff = val
+		case "retryOn":
+			for _, code := range strings.Split(val, ",") {
+				c, err := strconv.Atoi(code)
+				if err != nil || c < 100 || c > 599 {
+					t.syntaxError(locale.ErrInvalidTagValue, vars.APIRetry, word)
+					return false
+				}
+				r.RetryOn = append(r.RetryOn, c)
+			}
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIRetry, word)
+			return false
+		}
+	}
+
+	if api.Retry != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIRetry)
+		return false
+	}
+
+	api.Retry = r
+	return true
+}
+
+// 重试非幂等的 API 可能会产生副作用，使用 @apiRetry 时，
+// 若未标记 @apiIdempotent 或是 @apiSafe，则给出警告。
+func (l *lexer) checkRetry(api *types.API) {
+	if api.Retry == nil || api.Idempotent || api.Safe {
+		return
+	}
+
+	l.syntaxWarn(locale.ErrRetryNotIdempotent, vars.APIIdempotent, vars.APIRetry)
+}
+
+// 解析 @apiIdempotencyKey header description
+func (l *lexer) scanIdempotencyKey(api *types.API) bool {
+	t := l.readTag()
+
+	if api.IdempotencyKey != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIIdempotencyKey)
+		return false
+	}
+
+	header := t.readWord()
+	summary := t.readEnd()
+	if len(header) == 0 || len(summary) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIIdempotencyKey)
+		return false
+	}
+
+	api.IdempotencyKey = &types.IdempotencyKey{Header: header, Summary: summary}
+	return true
+}
+
+// 解析 @apiRequestID header [auto|required|optional]，未指定方式时为 auto。
+func (l *lexer) scanRequestID(api *types.API) bool {
+	t := l.readTag()
+
+	if api.RequestID != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIRequestID)
+		return false
+	}
+
+	header := t.readWord()
+	mode := t.readWord()
+	if len(header) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIRequestID)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIRequestID)
+		return false
+	}
+
+	switch mode {
+	case "":
+		mode = types.RequestIDAuto
+	case types.RequestIDAuto, types.RequestIDRequired, types.RequestIDOptional:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIRequestID, mode)
+		return false
+	}
+
+	api.RequestID = &types.RequestID{Header: header, Mode: mode}
+	return true
+}
+
+// 解析 @apiEvent name type description、@apiWSSend name type [description]、
+// @apiWSReceive name type [description]、@apiProducesEvent name schema [description]
+// 和 @apiConsumesEvent name schema [description]，可以指定多个，name 不能重复。
+//
+// requireSummary 表示 description 是否为必须的。
+func (l *lexer) scanEvent(tagName string, events *[]*types.Event, requireSummary bool) bool {
+	t := l.readTag()
+
+	e := &types.Event{
+		Name:    t.readWord(),
+		Type:    t.readWord(),
+		Summary: t.readLine(),
+	}
+	if len(e.Name) == 0 || len(e.Type) == 0 || (requireSummary && len(e.Summary) == 0) {
+		t.syntaxError(locale.ErrTagArgNotEnough, tagName)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, tagName)
+		return false
+	}
+
+	for _, event := range *events {
+		if event.Name == e.Name {
+			t.syntaxError(locale.ErrDuplicateTagValue, tagName, e.Name)
+			return false
+		}
+	}
+
+	*events = append(*events, e)
+	return true
+}
+
+// 解析 @apiSocketIO namespace event emit|on type description，可以指定多个，
+// 同一命名空间下，相同方向的事件名称不能重复。
+func (l *lexer) scanSocketIO(api *types.API) bool {
+	t := l.readTag()
+
+	namespace := t.readWord()
+	e := &types.SocketIOEvent{
+		Event:     t.readWord(),
+		Direction: t.readWord(),
+		Type:      t.readWord(),
+		Summary:   t.readLine(),
+	}
+	if len(namespace) == 0 || len(e.Event) == 0 || len(e.Direction) == 0 || len(e.Type) == 0 || len(e.Summary) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APISocketIO)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APISocketIO)
+		return false
+	}
+
+	if namespace[0] != '/' {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APISocketIO, namespace)
+		return false
+	}
+
+	if e.Direction != types.SocketIOEmit && e.Direction != types.SocketIOOn {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APISocketIO, e.Direction)
+		return false
+	}
+
+	var ns *types.SocketIONamespace
+	for _, item := range api.SocketIO {
+		if item.Namespace == namespace {
+			ns = item
+			break
+		}
+	}
+	if ns == nil {
+		ns = &types.SocketIONamespace{Namespace: namespace}
+		api.SocketIO = append(api.SocketIO, ns)
+	}
+
+	for _, event := range ns.Events {
+		if event.Event == e.Event && event.Direction == e.Direction {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APISocketIO, namespace+" "+e.Event)
+			return false
+		}
+	}
+
+	ns.Events = append(ns.Events, e)
+	return true
+}
+
+// 事件流只能通过 GET 请求建立，@apiEvent 也只对 @apiSSE 有意义，不符合时给出警告。
+func (l *lexer) checkSSE(api *types.API) {
+	if !api.SSE {
+		if len(api.Events) > 0 {
+			l.syntaxWarn(locale.ErrMissingDependentTag, vars.APIEvent, vars.APISSE)
+		}
+		return
+	}
+
+	if method := strings.ToUpper(api.Method); method != "GET" {
+		l.syntaxWarn(locale.ErrSSENotGet, vars.APISSE, method)
+	}
+}
+
+// WebSocket 只能通过 GET 请求升级协议，@apiWSSend、@apiWSReceive 和 @apiSocketIO
+// 也只对 @apiWebSocket 有意义，不符合时给出警告。
+func (l *lexer) checkWebSocket(api *types.API) {
+	if !api.WebSocket {
+		if len(api.WSSend) > 0 {
+			l.syntaxWarn(locale.ErrMissingDependentTag, vars.APIWSSend, vars.APIWebSocket)
+		}
+		if len(api.WSReceive) > 0 {
+			l.syntaxWarn(locale.ErrMissingDependentTag, vars.APIWSReceive, vars.APIWebSocket)
+		}
+		if len(api.SocketIO) > 0 {
+			l.syntaxWarn(locale.ErrMissingDependentTag, vars.APISocketIO, vars.APIWebSocket)
+		}
+		return
+	}
+
+	if method := strings.ToUpper(api.Method); method != "GET" {
+		l.syntaxWarn(locale.ErrWebSocketNotGet, vars.APIWebSocket, method)
+	}
+}
+
+// @apiChangelog 中日期的格式
+const changelogDateLayout = "2006-01-02"
+
+// 解析 @apiChangelog version date description，每个版本一条记录。
+func (l *lexer) scanChangelog(api *types.API) bool {
+	t := l.readTag()
+
+	entry := &types.ChangelogEntry{
+		Version:     t.readWord(),
+		Date:        t.readWord(),
+		Description: t.readLine(),
+	}
+	if len(entry.Version) == 0 || len(entry.Date) == 0 || len(entry.Description) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIChangelog)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIChangelog)
+		return false
+	}
+
+	if _, err := time.Parse(changelogDateLayout, entry.Date); err != nil {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIChangelog, entry.Date)
+		return false
+	}
+
+	for _, e := range api.Changelog {
+		if types.CompareVersion(e.Version, entry.Version) == 0 {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIChangelog, entry.Version)
+			return false
+		}
+	}
+
+	api.Changelog = append(api.Changelog, entry)
+	return true
+}
+
+// 解析 @apiBreakingChange version description，版本号的 v 前缀会被去掉。
+func (l *lexer) scanBreakingChange(api *types.API) bool {
+	t := l.readTag()
+
+	change := &types.BreakingChange{
+		Version:     strings.TrimPrefix(t.readWord(), "v"),
+		Description: t.readLine(),
+	}
+	if len(change.Version) == 0 || len(change.Description) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIBreakingChange)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIBreakingChange)
+		return false
+	}
+
+	for _, c := range api.BreakingChanges {
+		if types.CompareVersion(c.Version, change.Version) == 0 {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIBreakingChange, change.Version)
+			return false
+		}
+	}
+
+	api.BreakingChanges = append(api.BreakingChanges, change)
+	return true
+}
+
+// 解析 @apiGRPC Service.Method，Service 可以带上 protobuf 的包名。
+func (l *lexer) scanGRPC(api *types.API) bool {
+	t := l.readTag()
+
+	if len(api.GRPCMethod) > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIGRPC)
+		return false
+	}
+
+	method := t.readWord()
+	if len(method) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIGRPC)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIGRPC)
+		return false
+	}
+
+	index := strings.LastIndexByte(method, '.')
+	if index <= 0 || index == len(method)-1 || method[0] == '.' || strings.Contains(method, "..") {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIGRPC, method)
+		return false
+	}
+
+	api.GRPCMethod = method
+	return true
+}
+
+// 解析 @apiGraphQL operationName [query|mutation|subscription]，未指定类型时为 query。
+func (l *lexer) scanGraphQL(api *types.API) bool {
+	t := l.readTag()
+
+	if api.GraphQL != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIGraphQL)
+		return false
+	}
+
+	name := t.readWord()
+	typ := t.readWord()
+	if len(name) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIGraphQL)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIGraphQL)
+		return false
+	}
+
+	if !isIdentifier(name) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIGraphQL, name)
+		return false
+	}
+
+	switch typ {
+	case "":
+		typ = types.GraphQLQuery
+	case types.GraphQLQuery, types.GraphQLMutation, types.GraphQLSubscription:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIGraphQL, typ)
+		return false
+	}
+
+	api.GraphQL = &types.GraphQL{Operation: name, Type: typ}
+	return true
+}
+
+// 解析 @apiOperationID identifier
+//
+// identifier 会被 SDK 生成工具用作方法名，所以只能由字母、数字和下划线组成，且不能以数字开头。
+func (l *lexer) scanOperationID(api *types.API) bool {
+	t := l.readTag()
+
+	if len(api.OperationID) > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIOperationID)
+		return false
+	}
+
+	id := t.readWord()
+	if len(id) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIOperationID)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIOperationID)
+		return false
+	}
+
+	if !isIdentifier(id) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIOperationID, id)
+		return false
+	}
+
+	api.OperationID = id
+	return true
+}
+
+// 是否为合法的标识符：由 ASCII 字母、数字和下划线组成，且不能以数字开头。
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+// list 中是否包含 s
+func inStrings(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// @apiTimeout 超过此值（毫秒）时给出警告
+const maxTimeout = 30000
+
+// 解析 @apiTimeout milliseconds [p50|p90|p99|max]，未指定统计方式时为 max。
+func (l *lexer) scanTimeout(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Timeout != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APITimeout)
+		return false
+	}
+
+	value := t.readWord()
+	percentile := t.readWord()
+	if len(value) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APITimeout)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APITimeout)
+		return false
+	}
+
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APITimeout, value)
+		return false
+	}
+
+	switch percentile {
+	case "":
+		percentile = types.PercentileMax
+	case types.PercentileP50, types.PercentileP90, types.PercentileP99, types.PercentileMax:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APITimeout, percentile)
+		return false
+	}
+
+	if ms > maxTimeout {
+		t.syntaxWarn(locale.ErrTimeoutTooLarge, vars.APITimeout, ms, maxTimeout)
+	}
+
+	api.Timeout = &types.Timeout{Value: ms, Percentile: percentile}
+	return true
+}
+
+// 解析 @apiBatch maxItems [parallel|sequential]，未指定处理方式时为 sequential。
+func (l *lexer) scanBatch(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Batch != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIBatch)
+		return false
+	}
+
+	value := t.readWord()
+	mode := t.readWord()
+	if len(value) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIBatch)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIBatch)
+		return false
+	}
+
+	items, err := strconv.Atoi(value)
+	if err != nil || items <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIBatch, value)
+		return false
+	}
+
+	switch mode {
+	case "":
+		mode = types.BatchSequential
+	case types.BatchParallel, types.BatchSequential:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIBatch, mode)
+		return false
+	}
+
+	api.Batch = &types.Batch{MaxItems: items, Mode: mode}
+	return true
+}
+
+// 解析 @apiTimeBudget milliseconds [upstream:service:milliseconds, ...]
+//
+// 上游服务之间以逗号或是空格分隔，分配给上游服务的时间之和超过总时间时给出警告。
+func (l *lexer) scanTimeBudget(api *types.API) bool {
+	t := l.readTag()
+
+	if api.TimeBudget != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APITimeBudget)
+		return false
+	}
+
+	value := t.readWord()
+	upstreams := t.readLine()
+	if len(value) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APITimeBudget)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APITimeBudget)
+		return false
+	}
+
+	total, err := strconv.Atoi(value)
+	if err != nil || total <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APITimeBudget, value)
+		return false
+	}
+
+	budget := &types.TimeBudget{Total: total}
+	items := strings.FieldsFunc(upstreams, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+	for _, item := range items {
+		fields := strings.Split(item, ":")
+		if len(fields) != 3 || fields[0] != "upstream" || len(fields[1]) == 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APITimeBudget, item)
+			return false
+		}
+
+		ms, err := strconv.Atoi(fields[2])
+		if err != nil || ms <= 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APITimeBudget, item)
+			return false
+		}
+
+		for _, u := range budget.Upstreams {
+			if u.Service == fields[1] {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APITimeBudget, fields[1])
+				return false
+			}
+		}
+
+		budget.Upstreams = append(budget.Upstreams, &types.UpstreamBudget{Service: fields[1], Value: ms})
+	}
+
+	if allocated := budget.Allocated(); allocated > total {
+		t.syntaxWarn(locale.ErrTimeBudgetExceeded, vars.APITimeBudget, allocated, total)
+	}
+
+	api.TimeBudget = budget
+	return true
+}
+
+// 解析 @apiMockDelay milliseconds
+func (l *lexer) scanMockDelay(api *types.API) bool {
+	t := l.readTag()
+
+	if api.MockDelay > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIMockDelay)
+		return false
+	}
+
+	value := t.readWord()
+	if len(value) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIMockDelay)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIMockDelay)
+		return false
+	}
+
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIMockDelay, value)
+		return false
+	}
+
+	api.MockDelay = ms
+	return true
+}
+
+// 解析 @apiContentLength maxBytes
+func (l *lexer) scanContentLength(api *types.API) bool {
+	t := l.readTag()
+
+	if api.MaxRequestBodyBytes > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIContentLength)
+		return false
+	}
+
+	value := t.readWord()
+	if len(value) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIContentLength)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIContentLength)
+		return false
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIContentLength, value)
+		return false
+	}
+
+	api.MaxRequestBodyBytes = size
+	return true
+}
+
+// 解析 @apiThrottle requests per second|minute|hour
+func (l *lexer) scanThrottle(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Throttle != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIThrottle)
+		return false
+	}
+
+	requests := t.readWord()
+	per := t.readWord()
+	window := t.readWord()
+	if len(requests) == 0 || len(per) == 0 || len(window) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIThrottle)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIThrottle)
+		return false
+	}
+
+	n, err := strconv.Atoi(requests)
+	if err != nil || n <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIThrottle, requests)
+		return false
+	}
+
+	if per != "per" {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIThrottle, per)
+		return false
+	}
+
+	switch window {
+	case types.ThrottleWindowSecond, types.ThrottleWindowMinute, types.ThrottleWindowHour:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIThrottle, window)
+		return false
+	}
+
+	api.Throttle = &types.Throttle{Requests: n, Window: window}
+	return true
+}
+
+// 解析 @apiQuota limit per day|month|year [scope:org|user|key]
+func (l *lexer) scanQuota(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Quota != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIQuota)
+		return false
+	}
+
+	limit := t.readWord()
+	per := t.readWord()
+	window := t.readWord()
+	if len(limit) == 0 || len(per) == 0 || len(window) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIQuota)
+		return false
+	}
+
+	scope := ""
+	if word := t.readWord(); len(word) > 0 {
+		if !strings.HasPrefix(word, "scope:") {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIQuota, word)
+			return false
+		}
+
+		scope = word[len("scope:"):]
+		switch scope {
+		case types.QuotaScopeOrg, types.QuotaScopeUser, types.QuotaScopeKey:
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIQuota, word)
+			return false
+		}
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIQuota)
+		return false
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIQuota, limit)
+		return false
+	}
+
+	if per != "per" {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIQuota, per)
+		return false
+	}
+
+	switch window {
+	case types.QuotaWindowDay, types.QuotaWindowMonth, types.QuotaWindowYear:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIQuota, window)
+		return false
+	}
+
+	api.Quota = &types.Quota{Limit: n, Window: window, Scope: scope}
+	return true
+}
+
+// @apiQuota 的整个周期都要比 @apiThrottle 的时间窗口长，
+// 若配额还少于一个时间窗口内允许的请求次数，则两者相互矛盾。
+func (l *lexer) checkQuota(api *types.API) {
+	if api.Quota == nil || api.Throttle == nil {
+		return
+	}
+
+	if api.Quota.Limit < api.Throttle.Requests {
+		l.syntaxWarn(locale.ErrQuotaConflict, strings.ToUpper(api.Method), api.URL, api.Quota.Limit, api.Quota.Window, api.Throttle.Requests, api.Throttle.Window)
+	}
+}
+
+// 解析 @apiAudience audience1[,audience2...]
+func (l *lexer) scanAudience(api *types.API) bool {
+	t := l.readTag()
+
+	if len(api.Audiences) > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIAudience)
+		return false
+	}
+
+	list := t.readWord()
+	if len(list) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIAudience)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIAudience)
+		return false
+	}
+
+	audiences := strings.Split(list, ",")
+	for i, audience := range audiences {
+		switch audience {
+		case types.AudiencePublic, types.AudiencePartner, types.AudienceInternal:
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIAudience, audience)
+			return false
+		}
+
+		for _, prev := range audiences[:i] {
+			if prev == audience {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APIAudience, audience)
+				return false
+			}
+		}
+	}
+
+	api.Audiences = audiences
+	return true
+}
+
+// 解析 @apiDataClassification pii|sensitive|confidential|public
+func (l *lexer) scanDataClassification(api *types.API) bool {
+	t := l.readTag()
+
+	if len(api.DataClassification) > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIDataClassification)
+		return false
+	}
+
+	c := t.readWord()
+	if len(c) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIDataClassification)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIDataClassification)
+		return false
+	}
+
+	switch c {
+	case types.DataClassificationPII, types.DataClassificationSensitive, types.DataClassificationConfidential, types.DataClassificationPublic:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIDataClassification, c)
+		return false
+	}
+
+	api.DataClassification = c
+	return true
+}
+
+// 解析 @apiFeatureFlag name [provider:launchDarkly|statsig|custom]
+func (l *lexer) scanFeatureFlag(api *types.API) bool {
+	t := l.readTag()
+
+	if api.FeatureFlag != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIFeatureFlag)
+		return false
+	}
+
+	name := t.readWord()
+	provider := t.readWord()
+	if len(name) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIFeatureFlag)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIFeatureFlag)
+		return false
+	}
+
+	if len(provider) > 0 {
+		if !strings.HasPrefix(provider, "provider:") {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIFeatureFlag, provider)
+			return false
+		}
+
+		switch provider = provider[len("provider:"):]; provider {
+		case types.FeatureFlagLaunchDarkly, types.FeatureFlagStatsig, types.FeatureFlagCustom:
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIFeatureFlag, provider)
+			return false
+		}
+	}
+
+	api.FeatureFlag = &types.FeatureFlag{Name: name, Provider: provider}
+	return true
+}
+
+// @apiSLA 的可用性低于此值（百分比）时给出警告
+const minSLAAvailability = 99
+
+// 解析 @apiSLA availability [rpo:duration] [rto:duration]，
+// 其中 duration 为 time.ParseDuration 可以解析的格式。
+func (l *lexer) scanSLA(api *types.API) bool {
+	t := l.readTag()
+
+	if api.SLA != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APISLA)
+		return false
+	}
+
+	value := t.readWord()
+	if len(value) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APISLA)
+		return false
+	}
+
+	availability, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(availability > 0 && availability <= 100) { // 同时排除了 NaN
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APISLA, value)
+		return false
+	}
+	sla := &types.SLA{Availability: availability}
+
+	for i := 0; i < 2 && !t.atEOF(); i++ {
+		word := t.readWord()
+		index := strings.IndexByte(word, ':')
+		if index <= 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APISLA, word)
+			return false
+		}
+
+		key, duration := word[:index], word[index+1:]
+		if d, err := time.ParseDuration(duration); err != nil || d <= 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APISLA, word)
+			return false
+		}
+
+		switch {
+		case key == "rpo" && sla.RPO == "":
+			sla.RPO = duration
+		case key == "rto" && sla.RTO == "":
+			sla.RTO = duration
+		case key == "rpo" || key == "rto":
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APISLA, key)
+			return false
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APISLA, word)
+			return false
+		}
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APISLA)
+		return false
+	}
+
+	if availability < minSLAAvailability {
+		t.syntaxWarn(locale.ErrSLAAvailabilityTooLow, vars.APISLA, value, minSLAAvailability)
+	}
+
+	api.SLA = sla
+	return true
+}
+
+// 解析 @apiCircuitBreaker [threshold:percent] [timeout:duration] [halfOpenRequests:n]
+func (l *lexer) scanCircuitBreaker(api *types.API) bool {
+	t := l.readTag()
+
+	if api.CircuitBreaker != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APICircuitBreaker)
+		return false
+	}
+
+	cb := &types.CircuitBreaker{}
+	for i := 0; i < 3 && !t.atEOF(); i++ {
+		word := t.readWord()
+		index := strings.IndexByte(word, ':')
+		if index <= 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APICircuitBreaker, word)
+			return false
+		}
+
+		key, value := word[:index], word[index+1:]
+		switch key {
+		case "threshold":
+			if cb.Threshold > 0 {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APICircuitBreaker, key)
+				return false
+			}
+			percent, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+			if err != nil || percent <= 0 || percent > 100 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APICircuitBreaker, word)
+				return false
+			}
+			cb.Threshold = percent
+		case "timeout":
+			if cb.Timeout != "" {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APICircuitBreaker, key)
+				return false
+			}
+			if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APICircuitBreaker, word)
+				return false
+			}
+			cb.Timeout = value
+		case "halfOpenRequests":
+			if cb.HalfOpenRequests > 0 {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APICircuitBreaker, key)
+				return false
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APICircuitBreaker, word)
+				return false
+			}
+			cb.HalfOpenRequests = n
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APICircuitBreaker, word)
+			return false
+		}
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APICircuitBreaker)
+		return false
+	}
+
+	api.CircuitBreaker = cb
+	return true
+}
+
+// 熔断和重试需要配合使用，只指定了 @apiCircuitBreaker 而没有 @apiRetry 的给出警告。
+func (l *lexer) checkCircuitBreaker(api *types.API) {
+	if api.CircuitBreaker != nil && api.Retry == nil {
+		l.syntaxWarn(locale.ErrRetryMissing, strings.ToUpper(api.Method), api.URL, vars.APICircuitBreaker, vars.APIRetry)
+	}
+}
+
+// 解析 @apiRegion [available:region1,region2] [unavailable:region3]
+//
+// 可以同时指定 available 和 unavailable，两者相互矛盾的内容由 checkRegion 给出警告。
+func (l *lexer) scanRegion(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Region != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIRegion)
+		return false
+	}
+
+	region := &types.RegionPolicy{}
+	for i := 0; i < 2 && !t.atEOF(); i++ {
+		word := t.readWord()
+		index := strings.IndexByte(word, ':')
+		if index <= 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIRegion, word)
+			return false
+		}
+
+		var list *[]string
+		switch key := word[:index]; key {
+		case "available":
+			list = &region.Available
+		case "unavailable":
+			list = &region.Unavailable
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIRegion, word)
+			return false
+		}
+		if len(*list) > 0 {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIRegion, word[:index])
+			return false
+		}
+
+		regions := strings.Split(word[index+1:], ",")
+		for j, r := range regions {
+			if len(r) == 0 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APIRegion, word)
+				return false
+			}
+			if inStrings(regions[:j], r) {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APIRegion, r)
+				return false
+			}
+		}
+		*list = regions
+	}
+
+	if len(region.Available) == 0 && len(region.Unavailable) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIRegion)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIRegion)
+		return false
+	}
+
+	api.Region = region
+	return true
+}
+
+// 同一区域不能同时出现在 @apiRegion 的 available 和 unavailable 中。
+func (l *lexer) checkRegion(api *types.API) {
+	if api.Region == nil {
+		return
+	}
+
+	for _, available := range api.Region.Available {
+		for _, unavailable := range api.Region.Unavailable {
+			if available == unavailable {
+				l.syntaxWarn(locale.ErrRegionConflict, strings.ToUpper(api.Method), api.URL, available)
+			}
+		}
+	}
+}
+
+// 解析 @apiTenant [isolation:shared|dedicated] [scoping:header[:name]|path|subdomain]
+//
+// scoping 为 header 时，可以指定携带租户 ID 的报头名称，默认为 X-Tenant-ID。
+func (l *lexer) scanTenant(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Tenant != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APITenant)
+		return false
+	}
+
+	tenant := &types.TenantPolicy{}
+	for i := 0; i < 2 && !t.atEOF(); i++ {
+		word := t.readWord()
+		fields := strings.SplitN(word, ":", 3)
+		if len(fields) < 2 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APITenant, word)
+			return false
+		}
+
+		switch fields[0] {
+		case "isolation":
+			if tenant.Isolation != "" {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APITenant, fields[0])
+				return false
+			}
+			if len(fields) > 2 || (fields[1] != types.TenantIsolationShared && fields[1] != types.TenantIsolationDedicated) {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APITenant, word)
+				return false
+			}
+			tenant.Isolation = fields[1]
+		case "scoping":
+			if tenant.Scoping != "" {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APITenant, fields[0])
+				return false
+			}
+			switch {
+			case fields[1] == types.TenantScopingHeader:
+				tenant.Header = types.DefaultTenantHeader
+				if len(fields) > 2 {
+					tenant.Header = fields[2]
+				}
+				if len(tenant.Header) == 0 {
+					t.syntaxError(locale.ErrInvalidTagValue, vars.APITenant, word)
+					return false
+				}
+			case len(fields) == 2 && (fields[1] == types.TenantScopingPath || fields[1] == types.TenantScopingSubdomain):
+			default:
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APITenant, word)
+				return false
+			}
+			tenant.Scoping = fields[1]
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APITenant, word)
+			return false
+		}
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APITenant)
+		return false
+	}
+
+	api.Tenant = tenant
+	return true
+}
+
+// 通过报头区分租户的 API，应该在请求中通过 @apiHeader 声明该报头。
+func (l *lexer) checkTenant(api *types.API) {
+	if api.Tenant == nil || api.Tenant.Scoping != types.TenantScopingHeader {
+		return
+	}
+
+	if !hasRequestHeader(api, api.Tenant.Header) {
+		l.syntaxWarn(locale.ErrTenantHeaderMissing, strings.ToUpper(api.Method), api.URL, api.Tenant.Header, vars.APIHeader)
+	}
+}
+
+// api 的请求中是否声明了名为 name 的报头，报头名称不区分大小写。
+func hasRequestHeader(api *types.API, name string) bool {
+	if api.Request == nil {
+		return false
+	}
+
+	for header := range api.Request.Headers {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// 解析 @apiAsync [pollUrl:path] [callbackHeader:name]，两个选项都是可选的，且不分先后。
+func (l *lexer) scanAsync(api *types.API) bool {
+	t := l.readTag()
+
+	if api.Async != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIAsync)
+		return false
+	}
+
+	async := &types.Async{}
+	for i := 0; i < 2 && !t.atEOF(); i++ {
+		word := t.readWord()
+		fields := strings.SplitN(word, ":", 2)
+		if len(fields) != 2 || len(fields[1]) == 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIAsync, word)
+			return false
+		}
+
+		switch fields[0] {
+		case "pollUrl":
+			if async.PollURL != "" {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APIAsync, fields[0])
+				return false
+			}
+			async.PollURL = fields[1]
+		case "callbackHeader":
+			if async.CallbackHeader != "" {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APIAsync, fields[0])
+				return false
+			}
+			async.CallbackHeader = fields[1]
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIAsync, word)
+			return false
+		}
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIAsync)
+		return false
+	}
+
+	api.Async = async
+	return true
+}
+
+// 异步接口应该通过 @apiSuccess 或是 @apiError 说明 202 的返回内容。
+func (l *lexer) checkAsync(api *types.API) {
+	if api.Async == nil {
+		return
+	}
+
+	if (api.Success == nil || api.Success.Code != "202") && (api.Error == nil || api.Error.Code != "202") {
+		l.syntaxWarn(locale.ErrAsyncMissing202, strings.ToUpper(api.Method), api.URL)
+	}
+}
+
+// GET 请求本身就是幂等的，使用 @apiIdempotencyKey 时给出警告。
+func (l *lexer) checkIdempotencyKey(api *types.API) {
+	if api.IdempotencyKey == nil {
+		return
+	}
+
+	if method := strings.ToUpper(api.Method); method == "GET" {
+		l.syntaxWarn(locale.ErrIdempotencyKeyOnGet, method, vars.APIIdempotencyKey)
+	}
+}
+
+// 批量接口通常用于提交数据，GET 请求使用 @apiBatch 时给出警告。
+func (l *lexer) checkBatch(api *types.API) {
+	if api.Batch == nil {
+		return
+	}
+
+	if method := strings.ToUpper(api.Method); method == "GET" {
+		l.syntaxWarn(locale.ErrBatchOnGet, method, vars.APIBatch)
+	}
+}
+
+// GET 和 HEAD 请求没有请求内容，使用 @apiContentLength 时给出警告。
+func (l *lexer) checkContentLength(api *types.API) {
+	if api.MaxRequestBodyBytes <= 0 {
+		return
+	}
+
+	if method := strings.ToUpper(api.Method); method == "GET" || method == "HEAD" {
+		l.syntaxWarn(locale.ErrContentLengthNoBody, method, vars.APIContentLength)
+	}
+}
+
+// 使用 @apiMultipart 时，请求参数都以表单字段的形式提交，
+// 若 @apiRequest 指定了 multipart/form-data 之外的类型，则给出警告。
+func (l *lexer) checkMultipart(api *types.API) {
+	if !api.Multipart || api.Request == nil || len(api.Request.Type) == 0 {
+		return
+	}
+
+	for _, typ := range strings.Split(api.Request.Type, ",") {
+		if !strings.EqualFold(strings.TrimSpace(typ), types.MultipartFormData) {
+			l.syntaxWarn(locale.ErrMultipartConflict, vars.APIMultipart, vars.APIRequest, api.Request.Type)
+			return
+		}
+	}
+}
+
+// 解析 @apiMediaType mimetype [extension]，表示返回的是二进制内容。
+func (l *lexer) scanMediaType(api *types.API) bool {
+	t := l.readTag()
+
+	if api.MediaType != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIMediaType)
+		return false
+	}
+
+	mimetype := t.readWord()
+	if len(mimetype) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIMediaType)
+		return false
+	}
+	ext := strings.TrimPrefix(t.readWord(), ".")
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIMediaType)
+		return false
+	}
+
+	if _, _, err := mime.ParseMediaType(mimetype); err != nil || strings.IndexByte(mimetype, '/') <= 0 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIMediaType, mimetype)
+		return false
+	}
+
+	if strings.ContainsAny(ext, "/\\") {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIMediaType, ext)
+		return false
+	}
+
+	api.MediaType = &types.MediaType{Type: mimetype, Extension: ext}
+	return true
+}
+
+// 使用 @apiMediaType 时，返回的是二进制内容，
+// 若 @apiSuccess 中还指定了参数，则给出警告。
+func (l *lexer) checkMediaType(api *types.API) {
+	if api.MediaType != nil && api.Success != nil && len(api.Success.Params) > 0 {
+		l.syntaxWarn(locale.ErrMediaTypeConflict, vars.APIMediaType, vars.APISuccess)
+	}
+}
+
+// 解析 @apiCacheControl directive [vary:header1,header2]
+//
+// directive 可以是 max-age:seconds、no-cache、no-store、private 或是 public，
+// 可以指定多个 @apiCacheControl，每个指定一条指令。
+func (l *lexer) scanCacheControl(api *types.API) bool {
+	t := l.readTag()
+
+	directive := t.readWord()
+	if len(directive) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APICacheControl)
+		return false
+	}
+
+	c := types.CachePolicy{}
+	if api.CachePolicy != nil {
+		c = *api.CachePolicy
+	}
+
+	var dup bool
+	switch {
+	case strings.HasPrefix(directive, "max-age:"):
+		n, err := strconv.Atoi(directive[len("max-age:"):])
+		if err != nil || n <= 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APICacheControl, directive)
+			return false
+		}
+		dup = c.MaxAge > 0
+		c.MaxAge = n
+	case directive == "no-cache":
+		dup, c.NoCache = c.NoCache, true
+	case directive == "no-store":
+		dup, c.NoStore = c.NoStore, true
+	case directive == "private":
+		dup, c.Private = c.Private, true
+	case directive == "public":
+		dup, c.Public = c.Public, true
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APICacheControl, directive)
+		return false
+	}
+	if dup {
+		t.syntaxError(locale.ErrDuplicateTagValue, vars.APICacheControl, directive)
+		return false
+	}
+
+	if vary := t.readWord(); len(vary) > 0 {
+		if !strings.HasPrefix(vary, "vary:") || len(vary) == len("vary:") {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APICacheControl, vary)
+			return false
+		}
+
+		headers := strings.Split(vary[len("vary:"):], ",")
+		for _, header := range headers {
+			if len(header) == 0 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APICacheControl, vary)
+				return false
+			}
+		}
+		c.Vary = append(c.Vary[:len(c.Vary):len(c.Vary)], headers...) // 出错时不能影响原来的值
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APICacheControl)
+		return false
+	}
+
+	api.CachePolicy = &c
+	return true
+}
+
+// 检测 @apiCacheControl 中是否有相互矛盾的指令，有则给出警告。
+func (l *lexer) checkCacheControl(api *types.API) {
+	c := api.CachePolicy
+	if c == nil {
+		return
+	}
+
+	if c.NoStore && c.MaxAge > 0 {
+		l.syntaxWarn(locale.ErrCacheControlConflict, vars.APICacheControl, "no-store", "max-age")
+	}
+	if c.Private && c.Public {
+		l.syntaxWarn(locale.ErrCacheControlConflict, vars.APICacheControl, "private", "public")
+	}
+}
+
+// 解析 @apiCORS [origin:pattern] [methods:m1,m2] [headers:h1,h2] [maxAge:seconds]，
+// 各参数都是可选的，且不分先后。
+func (l *lexer) scanCORS(api *types.API) bool {
+	t := l.readTag()
+
+	if api.CORSPolicy != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APICORS)
+		return false
+	}
+
+	c := &types.CORSPolicy{}
+	seen := make(map[string]bool, 4)
+	for !t.atEOF() {
+		word := t.readWord()
+		index := strings.IndexByte(word, ':')
+		if index <= 0 || index == len(word)-1 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APICORS, word)
+			return false
+		}
+
+		key, value := word[:index], word[index+1:]
+		if seen[key] {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APICORS, key)
+			return false
+		}
+		seen[key] = true
+
+		switch key {
+		case "origin":
+			c.Origin = value
+		case "methods", "headers":
+			items := strings.Split(value, ",")
+			for _, item := range items {
+				if len(item) == 0 {
+					t.syntaxError(locale.ErrInvalidTagValue, vars.APICORS, word)
+					return false
+				}
+			}
+
+			if key == "headers" {
+				c.Headers = items
+			} else {
+				c.Methods = strings.Split(strings.ToUpper(value), ",")
+			}
+		case "maxAge":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				t.syntaxError(locale.ErrInvalidTagValue, vars.APICORS, word)
+				return false
+			}
+			c.MaxAge = n
+		default:
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APICORS, word)
+			return false
+		}
+	}
+
+	api.CORSPolicy = c
+	return true
+}
+
+// 浏览器不会向 Access-Control-Allow-Origin 为 * 的地址发送认证信息，
+// 同时使用 @apiCORS origin:* 和 @apiAuth 时给出警告。
+func (l *lexer) checkCORS(api *types.API) {
+	if api.CORSPolicy != nil && api.CORSPolicy.Origin == "*" && len(api.Auth) > 0 {
+		l.syntaxWarn(locale.ErrCORSWildcardWithAuth, vars.APICORS, vars.APIAuth)
+	}
+}
+
+// 解析 @apiTryIt baseURL [token:apiKey]，为 API 开启在线调试的功能。
+func (l *lexer) scanTryIt(api *types.API) bool {
+	t := l.readTag()
+
+	tryIt := &types.TryIt{BaseURL: t.readWord()}
+	if len(tryIt.BaseURL) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APITryIt)
+		return false
+	}
+	if !is.URL(tryIt.BaseURL) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APITryIt, tryIt.BaseURL)
+		return false
+	}
+
+	if token := t.readWord(); len(token) > 0 {
+		if !strings.HasPrefix(token, "token:") || len(token) == len("token:") {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APITryIt, token)
+			return false
+		}
+		tryIt.Token = token[len("token:"):]
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APITryIt)
+		return false
+	}
+
+	if api.TryIt != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APITryIt)
+		return false
+	}
+
+	api.TryIt = tryIt
+	return true
+}
+
+// 解析 @apiAuth 标签，其值为 @apiSecurity 中定义的名称。
+// 可以指定多个 @apiAuth，表示支持多种认证方式。
+func (l *lexer) scanAuth(api *types.API) bool {
+	t := l.readTag()
+
+	name := t.readWord()
+	if len(name) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIAuth)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIAuth)
+		return false
+	}
+
+	api.Auth = append(api.Auth, name)
+	return true
+}
+
+// 解析 @apiCalls method path，可以指定多个。
+func (l *lexer) scanCalls(api *types.API) bool {
+	t := l.readTag()
+
+	method := t.readWord()
+	path := t.readWord()
+	if len(method) == 0 || len(path) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APICalls)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APICalls)
+		return false
+	}
+
+	e := (&types.API{Method: method, URL: path}).Endpoint()
+	for _, c := range api.Calls {
+		if c == e {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APICalls, e)
+			return false
+		}
+	}
+
+	api.Calls = append(api.Calls, e)
+	return true
+}
+
+// 解析 @apiNullable name
+func (l *lexer) scanNullable(names *[]string) bool {
+	t := l.readTag()
+
+	name := t.readWord()
+	if len(name) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APINullable)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APINullable)
+		return false
+	}
+
+	for _, n := range *names {
+		if n == name {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APINullable, name)
+			return false
+		}
+	}
+
+	*names = append(*names, name)
+	return true
+}
+
+// 将 names 中的参数都标记为可以为 null，包括 URL 参数、查询参数以及请求和返回内容中的参数。
+//
+// URL 参数总是必须的，标记为 null 是矛盾的，与找不到参数的情况一样，只给出警告。
+func (l *lexer) setNullable(api *types.API, names []string) {
+	lists := [][]*types.Param{api.Params, api.Queries}
+	if api.Request != nil {
+		lists = append(lists, api.Request.Params)
+	}
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp != nil {
+			lists = append(lists, resp.Params)
+		}
+	}
+
+	for _, name := range names {
+		found := false
+		for i, params := range lists {
+			for _, p := range params {
+				if p.Name != name {
+					continue
+				}
+
+				p.Nullable = true
+				found = true
+				if i == 0 {
+					l.syntaxWarn(locale.ErrNullableURLParam, vars.APINullable, name)
+				}
+			}
+		}
+
+		if !found {
+			l.syntaxWarn(locale.ErrNullableParamNotFound, vars.APINullable, name)
+		}
+	}
+}
+
+// 解析 @apiFormat name format
+func (l *lexer) scanFormat(api *types.API) bool {
+	t := l.readTag()
+
+	name := t.readWord()
+	format := t.readWord()
+	if len(name) == 0 || len(format) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIFormat)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIFormat)
+		return false
+	}
+
+	if !paramFormats[format] && !strings.HasPrefix(format, "x-") {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIFormat, format)
+		return false
+	}
+
+	if _, found := api.Formats[name]; found {
+		t.syntaxError(locale.ErrDuplicateTagValue, vars.APIFormat, name)
+		return false
+	}
+
+	if api.Formats == nil {
+		api.Formats = make(map[string]string, 5)
+	}
+	api.Formats[name] = format
+	return true
+}
+
+// 解析 @apiAccess role1[,role2...] [description]
+func (l *lexer) scanAccess(api *types.API) bool {
+	t := l.readTag()
+
+	if len(api.AccessRoles) > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIAccess)
+		return false
+	}
+
+	roles := t.readWord()
+	if len(roles) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIAccess)
+		return false
+	}
+
+	for _, role := range strings.Split(roles, ",") {
+		if len(role) == 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIAccess, roles)
+			return false
+		}
+
+		for _, v := range api.AccessRoles {
+			if v == role {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APIAccess, role)
+				return false
+			}
+		}
+		api.AccessRoles = append(api.AccessRoles, role)
+	}
+
+	api.AccessSummary = t.readEnd()
+	return true
+}
+
+// 解析 @apiScope scope1[,scope2...] [all|any]，未指定关系时为 all。
+func (l *lexer) scanScope(api *types.API) bool {
+	t := l.readTag()
+
+	if len(api.RequiredScopes) > 0 {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIScope)
+		return false
+	}
+
+	scopes := t.readWord()
+	logic := t.readWord()
+	if len(scopes) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIScope)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIScope)
+		return false
+	}
+
+	switch logic {
+	case "":
+		logic = types.ScopeLogicAll
+	case types.ScopeLogicAll, types.ScopeLogicAny:
+	default:
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIScope, logic)
+		return false
+	}
+
+	list := strings.Split(scopes, ",")
+	for i, scope := range list {
+		if len(scope) == 0 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIScope, scopes)
+			return false
+		}
+		if inStrings(list[:i], scope) {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIScope, scope)
+			return false
+		}
+	}
+
+	api.RequiredScopes = list
+	api.ScopeLogic = logic
+	return true
+}
+
+// 访问控制以认证为前提，使用 @apiAccess 时，若未指定 @apiAuth，则给出警告。
+func (l *lexer) checkAccess(api *types.API) {
+	if len(api.AccessRoles) > 0 && len(api.Auth) == 0 {
+		l.syntaxWarn(locale.ErrAccessWithoutAuth, vars.APIAccess, vars.APIAuth)
+	}
+}
+
+// 解析 @apiContentType code mimetype，指定某一状态码下的返回内容类型。
+func (l *lexer) scanContentType(api *types.API) bool {
+	t := l.readTag()
+
+	code := t.readWord()
+	mimetype := t.readWord()
+	if len(code) == 0 || len(mimetype) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIContentType)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIContentType)
+		return false
+	}
+
+	if _, err := strconv.Atoi(code); err != nil {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIContentType, code)
+		return false
+	}
+
+	if strings.IndexByte(mimetype, '/') <= 0 {
+		t.syntaxWarn(locale.ErrInvalidMimetype, mimetype)
+	}
+
+	if api.ContentTypes == nil {
+		api.ContentTypes = make(map[string][]string, 2)
+	}
+	api.ContentTypes[code] = append(api.ContentTypes[code], mimetype)
+	return true
+}
+
+// 解析 @apiExtension x-name value，value 必须为合法的 JSON 内容，可以跨行。
+func (l *lexer) scanExtension(api *types.API) bool {
+	t := l.readTag()
+
+	name := t.readWord()
+	val := t.readEnd()
+	if len(name) == 0 || len(val) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIExtension)
+		return false
+	}
+
+	if !strings.HasPrefix(name, "x-") || len(name) == 2 {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIExtension, name)
+		return false
+	}
+
+	if !json.Valid([]byte(val)) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APIExtension, val)
+		return false
+	}
+
+	if api.Extensions == nil {
+		api.Extensions = make(map[string]json.RawMessage, 2)
+	} else if _, found := api.Extensions[name]; found {
+		t.syntaxError(locale.ErrDuplicateTagValue, vars.APIExtension, name)
+		return false
+	}
+
+	api.Extensions[name] = json.RawMessage(val)
+	return true
+}
+
+// 解析 @apiCodegen lang key value，可以指定多个，同一语言下的 key 不能重复。
+func (l *lexer) scanCodegen(api *types.API) bool {
+	t := l.readTag()
+
+	lang := t.readWord()
+	key := t.readWord()
+	val := t.readLine()
+	if len(lang) == 0 || len(key) == 0 || len(val) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APICodegen)
+		return false
+	}
+
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APICodegen)
+		return false
+	}
+
+	if l.input.LangIsSupported != nil && !l.input.LangIsSupported(lang) {
+		t.syntaxError(locale.ErrInvalidTagValue, vars.APICodegen, lang)
+		return false
+	}
+
+	if api.Codegen == nil {
+		api.Codegen = make(map[string]map[string]string, 2)
+	}
+	hints := api.Codegen[lang]
+	if hints == nil {
+		hints = make(map[string]string, 2)
+		api.Codegen[lang] = hints
+	} else if _, found := hints[key]; found {
+		t.syntaxError(locale.ErrDuplicateTagValue, vars.APICodegen, lang+" "+key)
+		return false
+	}
+
+	hints[key] = val
+	return true
+}
+
+func (l *lexer) scanProduces(api *types.API) bool {
+	cts, ok := l.scanContentTypes(vars.APIProduces)
+	if !ok {
+		return false
+	}
+
+	api.Produces = append(api.Produces, cts...)
+	return true
+}
+
+func (l *lexer) scanConsumes(api *types.API) bool {
+	cts, ok := l.scanContentTypes(vars.APIConsumes)
+	if !ok {
+		return false
+	}
+
+	api.Consumes = append(api.Consumes, cts...)
+	return true
+}
+
+// 解析 @apiContentNegotiation mimetype1 [mimetype2] ...
+//
+// 多个值之间以空白字符分隔，多个 @apiContentNegotiation 的内容会累加。
+func (l *lexer) scanContentNegotiation(api *types.API) bool {
+	t := l.readTag()
+
+	line := t.readLine()
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIContentNegotiation)
+		return false
+	}
+
+	cts := strings.Fields(line)
+	if len(cts) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIContentNegotiation)
+		return false
+	}
+
+	for _, ct := range cts {
+		if !isMimetype(ct) {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIContentNegotiation, ct)
+			return false
+		}
+
+		for _, v := range api.Negotiation {
+			if strings.EqualFold(v, ct) {
+				t.syntaxError(locale.ErrDuplicateTagValue, vars.APIContentNegotiation, ct)
+				return false
+			}
+		}
+		api.Negotiation = append(api.Negotiation, ct)
+	}
+
+	return true
+}
+
+// @apiFormat 可用的格式，与 OpenAPI 中的 format 相同，
+// 另外以 x- 开头的自定义格式也是允许的。
+var paramFormats = map[string]bool{
+	"int32":     true,
+	"int64":     true,
+	"float":     true,
+	"double":    true,
+	"byte":      true,
+	"binary":    true,
+	"date":      true,
+	"date-time": true,
+	"time":      true,
+	"password":  true,
+	"email":     true,
+	"hostname":  true,
+	"ipv4":      true,
+	"ipv6":      true,
+	"uri":       true,
+	"uuid":      true,
+}
+
+// v 是否为一个完整的内容类型，比如 application/json，
+// 不能是简写的形式，也不能包含通配符。
+func isMimetype(v string) bool {
+	mt, _, err := mime.ParseMediaType(v)
+	if err != nil || strings.IndexByte(mt, '*') >= 0 {
+		return false
+	}
+
+	index := strings.IndexByte(mt, '/')
+	return index > 0 && index < len(mt)-1
+}
+
+// 解析 @apiProduces 和 @apiConsumes 的内容，多个值之间以逗号分隔。
+//
+// @apiProduces application/json,application/xml
+func (l *lexer) scanContentTypes(tagName string) ([]string, bool) {
+	t := l.readTag()
+
+	line := t.readLine()
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, tagName)
+		return nil, false
+	}
+
+	ret := make([]string, 0, 2)
+	for _, typ := range strings.Split(line, ",") {
+		typ = strings.TrimSpace(typ)
+		if len(typ) > 0 {
+			ret = append(ret, typ)
+		}
+	}
+
+	if len(ret) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, tagName)
+		return nil, false
+	}
+
+	return ret, true
+}
+
+// 检测 @apiSuccess 和 @apiError 中的示例类型是否都在 @apiProduces 中有声明。
+// 未指定 @apiProduces 时，不作检测。
+func (l *lexer) checkProduces(api *types.API) {
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp == nil {
+			continue
+		}
+
+		// @apiContentType 指定的值优先于 @apiProduces
+		cts, tagName := api.Produces, vars.APIProduces
+		if v, found := api.ContentTypes[resp.Code]; found {
+			cts, tagName = v, vars.APIContentType
+		}
+		if len(cts) == 0 {
+			continue
+		}
+
+		for _, e := range resp.Examples {
+			if !contentTypeMatch(cts, e.Type) {
+				l.syntaxWarn(locale.ErrContentTypeNotDeclared, e.Type, tagName)
+			}
+		}
+	}
+}
+
+// typ 是否与 contentTypes 中的某一项匹配。
+//
+// typ 可以是完整的类型，比如 application/json，
+// 也可以是简写的形式，比如 json，会与 application/json 或是 application/vnd+json 相匹配。
+func contentTypeMatch(contentTypes []string, typ string) bool {
+	typ = strings.ToLower(typ)
+	for _, ct := range contentTypes {
+		ct = strings.ToLower(ct)
+		if index := strings.IndexByte(ct, ';'); index > 0 { // 去掉 charset 等参数
+			ct = strings.TrimSpace(ct[:index])
+		}
+
+		if ct == typ {
+			return true
+		}
+
+		index := strings.IndexByte(ct, '/')
+		if index < 0 {
+			continue
+		}
+		sub := ct[index+1:]
+		if sub == typ || strings.HasSuffix(sub, "+"+typ) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (l *lexer) scanAPIQueries(api *types.API) bool {
+	if api.Queries == nil {
+		api.Queries = make([]*types.Param, 0, 1)
+	}
+
+	if p, ok := l.scanAPIParam(vars.APIQuery); ok {
+		api.Queries = append(api.Queries, p)
+		return true
+	}
+	return false
+}
+
+func (l *lexer) scanAPIParams(api *types.API) bool {
+	if api.Params == nil {
+		api.Params = make([]*types.Param, 0, 1)
+	}
+
+	if p, ok := l.scanAPIParam(vars.APIParam); ok {
+		api.Params = append(api.Params, p)
+		return true
+	}
+	return false
+}
+
+// 解析 @apiRequest 及其子标签
+func (l *lexer) scanAPIRequest() (*types.Request, bool) {
+	t := l.readTag()
+	r := &types.Request{
+		Type:     t.readLine(),
+		Headers:  map[string]string{},
+		Params:   []*types.Param{},
+		Examples: []*types.Example{},
+	}
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIRequest)
+		return nil, false
+	}
+
+LOOP:
+	for {
 		switch {
 		case l.matchTag(vars.APIHeader):
 			t := l.readTag()
@@ -289,6 +3212,10 @@ LOOP:
 				return nil, false
 			}
 			r.Params = append(r.Params, p)
+		case l.matchTag(vars.APIDiscriminator):
+			if !l.scanDiscriminator(&r.Discriminator) {
+				return nil, false
+			}
 		case l.matchTag(vars.APIExample):
 			e, ok := l.scanAPIExample()
 			if !ok {
@@ -310,6 +3237,58 @@ LOOP:
 	return r, true
 }
 
+// 解析 @apiDiscriminator property [value:type,...]
+//
+// 作为 @apiRequest、@apiSuccess 和 @apiError 的子标签，
+// 可选的映射关系以逗号分隔，每一项的值与类型之间以第一个冒号分隔。
+func (l *lexer) scanDiscriminator(d **types.Discriminator) bool {
+	t := l.readTag()
+
+	if *d != nil {
+		t.syntaxError(locale.ErrDuplicateTag, vars.APIDiscriminator)
+		return false
+	}
+
+	property := t.readWord()
+	if len(property) == 0 {
+		t.syntaxError(locale.ErrTagArgNotEnough, vars.APIDiscriminator)
+		return false
+	}
+
+	discriminator := &types.Discriminator{Property: property}
+	mapping := t.readLine()
+	if !t.atEOF() {
+		t.syntaxError(locale.ErrTagArgTooMuch, vars.APIDiscriminator)
+		return false
+	}
+
+	if len(mapping) > 0 {
+		discriminator.Mapping = make(map[string]string, 5)
+	}
+	for _, item := range strings.Split(mapping, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+
+		index := strings.IndexByte(item, ':')
+		if index <= 0 || index == len(item)-1 {
+			t.syntaxError(locale.ErrInvalidTagValue, vars.APIDiscriminator, item)
+			return false
+		}
+
+		value := strings.TrimSpace(item[:index])
+		if _, found := discriminator.Mapping[value]; found {
+			t.syntaxError(locale.ErrDuplicateTagValue, vars.APIDiscriminator, value)
+			return false
+		}
+		discriminator.Mapping[value] = strings.TrimSpace(item[index+1:])
+	}
+
+	*d = discriminator
+	return true
+}
+
 // 解析 @apiSuccess 或是 @apiError 及其子标签。
 func (l *lexer) scanResponse(tagName string) (*types.Response, bool) {
 	tag := l.readTag()
@@ -352,6 +3331,10 @@ LOOP:
 				return nil, false
 			}
 			resp.Params = append(resp.Params, p)
+		case l.matchTag(vars.APIDiscriminator):
+			if !l.scanDiscriminator(&resp.Discriminator) {
+				return nil, false
+			}
 		case l.matchTag(vars.APIExample):
 			e, ok := l.scanAPIExample()
 			if !ok {
@@ -373,11 +3356,30 @@ LOOP:
 }
 
 // 解析 @apiExample 标签
+//
+// @apiExample application/json json
+// {"id":1}
+//
+// 类型之后若在同一行只有一个单词，且之后的行中还有示例代码，
+// 则该单词表示代码高亮时所使用的语言，未指定时，根据类型推导，比如 application/json 对应 json。
 func (l *lexer) scanAPIExample() (*types.Example, bool) {
 	tag := l.readTag()
 	example := &types.Example{
 		Type: tag.readWord(),
-		Code: tag.readEnd(),
+	}
+
+	// 只有之后的行中还有示例代码时，才将该单词当作语言名称，
+	// 否则该单词即为示例代码，比如 @apiExample text/plain OK。
+	pos := tag.pos
+	if lang := tag.readRestLine(); isHighlightLang(lang) {
+		if code := tag.readEnd(); len(code) > 0 {
+			example.Lang = lang
+			example.Code = code
+		}
+	}
+	if len(example.Code) == 0 { // 非语言名称，则属于示例代码的一部分
+		tag.pos = pos
+		example.Code = tag.readEnd()
 	}
 
 	if len(example.Type) == 0 || len(example.Code) == 0 {
@@ -385,9 +3387,54 @@ func (l *lexer) scanAPIExample() (*types.Example, bool) {
 		return nil, false
 	}
 
+	if len(example.Lang) == 0 {
+		example.Lang = highlightLang(example.Type)
+	}
+
 	return example, true
 }
 
+// lang 是否为一个合法的代码高亮语言名称，比如 json、c++、c#、objective-c 等。
+func isHighlightLang(lang string) bool {
+	if len(lang) == 0 {
+		return false
+	}
+
+	for index, r := range lang {
+		if unicode.IsLetter(r) {
+			continue
+		}
+
+		if index == 0 || !(unicode.IsDigit(r) || strings.ContainsRune("-+#_", r)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// 根据内容类型推导代码高亮所使用的语言。
+//
+// application/json、application/vnd.api+json 等均为 json，
+// 不包含 / 的则直接返回其本身。
+func highlightLang(typ string) string {
+	typ = strings.ToLower(typ)
+	if index := strings.IndexByte(typ, ';'); index > 0 { // 去掉 charset 等参数
+		typ = strings.TrimSpace(typ[:index])
+	}
+
+	index := strings.IndexByte(typ, '/')
+	if index < 0 {
+		return typ
+	}
+
+	typ = typ[index+1:]
+	if index = strings.LastIndexByte(typ, '+'); index >= 0 {
+		typ = typ[index+1:]
+	}
+	return strings.TrimPrefix(typ, "x-")
+}
+
 // 解析 @apiParam 标签
 func (l *lexer) scanAPIParam(tagName string) (*types.Param, bool) {
 	p := &types.Param{}
@@ -5,7 +5,9 @@
 package syntax
 
 import (
+	"bytes"
 	"log"
+	"strings"
 	"testing"
 
 	"github.com/caixw/apidoc/types"
@@ -60,121 +62,2874 @@ func TestScanAPIExample(t *testing.T) {
 	e, err = l.scanAPIExample()
 	a.NotError(err).
 		Equal(e.Type, "xml").
+		Equal(e.Lang, "xml").
 		Equal(len(e.Code), len(matchCode)).
 		Equal(e.Code, matchCode)
+
+	// 指定了高亮语言
+	code = ` application/vnd.api+json javascript
+{"id":1}`
+	l = newLexerString(code)
+	e, ok := l.scanAPIExample()
+	a.True(ok).
+		Equal(e.Type, "application/vnd.api+json").
+		Equal(e.Lang, "javascript").
+		Equal(e.Code, `{"id":1}`)
+
+	// 未指定高亮语言，由类型推导
+	code = ` application/json
+{"id":1}`
+	l = newLexerString(code)
+	e, ok = l.scanAPIExample()
+	a.True(ok).
+		Equal(e.Type, "application/json").
+		Equal(e.Lang, "json").
+		Equal(e.Code, `{"id":1}`)
+
+	// 下一行的单词属于代码内容
+	code = ` text/plain
+plain`
+	l = newLexerString(code)
+	e, ok = l.scanAPIExample()
+	a.True(ok).
+		Equal(e.Lang, "plain").
+		Equal(e.Code, "plain")
+
+	// 单行的示例，该单词为代码而不是高亮语言
+	l = newLexerString(" text/plain OK\n")
+	e, ok = l.scanAPIExample()
+	a.True(ok).
+		Equal(e.Type, "text/plain").
+		Equal(e.Lang, "plain").
+		Equal(e.Code, "OK")
+
+	l = newLexerString(" json success\n")
+	e, ok = l.scanAPIExample()
+	a.True(ok).
+		Equal(e.Type, "json").
+		Equal(e.Lang, "json").
+		Equal(e.Code, "success")
+
+	// 没有代码
+	l = newLexerString(" application/json\n")
+	e, ok = l.scanAPIExample()
+	a.False(ok).Nil(e)
+}
+
+func TestIsHighlightLang(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isHighlightLang("json"))
+	a.True(isHighlightLang("c++"))
+	a.True(isHighlightLang("c#"))
+	a.True(isHighlightLang("objective-c"))
+	a.True(isHighlightLang("html5"))
+
+	a.False(isHighlightLang(""))
+	a.False(isHighlightLang("5html"))
+	a.False(isHighlightLang("<root>"))
+	a.False(isHighlightLang(`{"id":1}`))
+	a.False(isHighlightLang("json xml"))
+}
+
+func TestHighlightLang(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(highlightLang("json"), "json")
+	a.Equal(highlightLang("application/json"), "json")
+	a.Equal(highlightLang("Application/JSON; charset=utf-8"), "json")
+	a.Equal(highlightLang("application/vnd.api+json"), "json")
+	a.Equal(highlightLang("text/xml"), "xml")
+	a.Equal(highlightLang("application/x-yaml"), "yaml")
+}
+
+func TestScanAPIParam(t *testing.T) {
+	a := assert.New(t)
+
+	// 正常语法测试
+	l := newLexerString("id int optional 用户 id号\n")
+	p, ok := l.scanAPIParam(vars.APIQuery)
+	a.True(ok).NotNil(p)
+	a.Equal(p.Name, "id").
+		Equal(p.Type, "int").
+		Equal(p.Summary, "optional 用户 id号")
+
+	// 缺少参数
+	l = newLexerString("id int \n")
+	p, ok = l.scanAPIParam(vars.APIQuery)
+	a.False(ok).Nil(p)
+
+	// 缺少参数
+	l = newLexerString("id  \n")
+	p, ok = l.scanAPIParam(vars.APIQuery)
+	a.False(ok).Nil(p)
+}
+
+func TestScanAPI(t *testing.T) {
+	a := assert.New(t)
+
+	// 正常情况
+	l := newLexerString(" get test.com/api.json?k=1 summary summary\n api description\n@apiSuccess 200 OK")
+
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Method, "get").
+		Equal(api.URL, "test.com/api.json?k=1").
+		Equal(api.Summary, "summary summary").
+		Equal(api.Description, "api description")
+
+	// 多行description
+	l = newLexerString(" post test.com/api.json?K=1  summary summary\n api \ndescription\n@apiSuccess 400 summary")
+	api, ok = l.scanAPI()
+	a.True(ok)
+	a.Equal(api.URL, "test.com/api.json?K=1").
+		Equal(api.Method, "post").
+		Equal(api.Summary, "summary summary").
+		Equal(api.Description, "api \ndescription")
+
+	// 缺少description参数
+	l = newLexerString("get test.com/api.json summary summary \n@apiSuccess 400 error")
+	api, ok = l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Method, "get").
+		Equal(api.URL, "test.com/api.json").
+		Equal(api.Summary, "summary summary").
+		Equal(api.Description, "")
+
+	// 缺少description参数
+	l = newLexerString("get test.com/api.json summary summary\n \n@apiSuccess 400 error")
+	api, ok = l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Method, "get").
+		Equal(api.URL, "test.com/api.json").
+		Equal(api.Summary, "summary summary").
+		Equal(api.Description, "")
+
+	// 没有任何参数
+	l = newLexerString("  ")
+	api, ok = l.scanAPI()
+	a.False(ok).Nil(api)
+}
+
+func TestScanAPI_summaryLength(t *testing.T) {
+	a := assert.New(t)
+
+	// 按字符计算长度，而不是字节
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := "@api get /users " + strings.Repeat("用", maxSummaryLength) + "\n@apiSuccess 200 OK\n"
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+
+	// 超出长度只是警告，依然正常解析
+	warn.Reset()
+	doc = types.NewDoc()
+	code = "@api get /users " + strings.Repeat("a", maxSummaryLength+1) + "\n description\n@apiSuccess 200 OK\n"
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).
+		Equal(doc.Apis[0].Summary, strings.Repeat("a", maxSummaryLength+1)).
+		Equal(doc.Apis[0].Description, "description")
+	a.True(strings.Contains(warn.String(), vars.API)).
+		True(strings.Contains(warn.String(), "121"))
+}
+
+func TestScanAPIDoc(t *testing.T) {
+	a := assert.New(t)
+
+	code := ` title of apidoc
+@apiVersion 2.0.1
+@apiBaseURL https://api.caixw.io
+@apiBasePath /v1
+@apiLicense MIT https://opensource.org/licenses/MIT
+@apiContent
+line1
+line2`
+
+	l := newLexerString(code)
+	a.NotNil(l)
+	d := &types.Doc{}
+
+	a.NotError(l.scanAPIDoc(d))
+	a.Equal(d.Version, "2.0.1").
+		Equal(d.Title, "title of apidoc").
+		Equal(d.BaseURL, "https://api.caixw.io").
+		Equal(d.BasePath, "/v1").
+		Equal(d.LicenseName, "MIT").
+		Equal(d.LicenseURL, "https://opensource.org/licenses/MIT").
+		Equal(d.Content, "\nline1\nline2")
+
+		// 重复内容，报错
+	code = `title of apidoc2
+		@apiBaseURL http://api.caixw.io
+line1
+line2`
+	l = newLexerString(code)
+	a.False(l.scanAPIDoc(d))
+
+	// @apiBasePath 参数太多
+	code = `title of apidoc
+@apiBasePath /v1 /v2
+`
+	l = newLexerString(code)
+	a.False(l.scanAPIDoc(&types.Doc{}))
+
+	// 检测各个都是空值的情况
+	code = `2.9 title of apidoc
+`
+	l = newLexerString(code)
+	a.NotNil(l)
+	d = &types.Doc{}
+	a.NotError(l.scanAPIDoc(d))
+	a.Equal(d.Title, "2.9 title of apidoc").
+		Equal(d.Version, "").
+		Equal(d.BaseURL, "").
+		Equal(d.LicenseName, "").
+		Equal(d.LicenseURL, "")
+}
+
+func TestScanContentTypes(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(" application/json\n")
+	cts, ok := l.scanContentTypes(vars.APIProduces)
+	a.True(ok).Equal(cts, []string{"application/json"})
+
+	l = newLexerString(" application/json, application/xml,,text/plain\n")
+	cts, ok = l.scanContentTypes(vars.APIProduces)
+	a.True(ok).Equal(cts, []string{"application/json", "application/xml", "text/plain"})
+
+	// 缺少参数
+	l = newLexerString(" , \n")
+	cts, ok = l.scanContentTypes(vars.APIConsumes)
+	a.False(ok).Nil(cts)
+
+	// 多行内容
+	l = newLexerString(" application/json\napplication/xml\n")
+	cts, ok = l.scanContentTypes(vars.APIConsumes)
+	a.False(ok).Nil(cts)
+}
+
+func TestContentTypeMatch(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(contentTypeMatch([]string{"application/json"}, "json"))
+	a.True(contentTypeMatch([]string{"application/json"}, "application/json"))
+	a.True(contentTypeMatch([]string{"application/xml", "Application/JSON"}, "JSON"))
+	a.True(contentTypeMatch([]string{"application/json;charset=utf-8"}, "json"))
+	a.True(contentTypeMatch([]string{"application/vnd.api+json"}, "json"))
+
+	a.False(contentTypeMatch([]string{"application/xml"}, "json"))
+	a.False(contentTypeMatch([]string{"json"}, "xml"))
+	a.False(contentTypeMatch(nil, "json"))
+}
+
+func TestScanSecurity(t *testing.T) {
+	a := assert.New(t)
+	d := &types.Doc{}
+
+	l := newLexerString(" token apiKey header X-API-Key\n")
+	a.True(l.scanSecurity(d))
+	a.Equal(d.SecuritySchemes["token"], &types.Security{
+		Name: "token",
+		Type: types.SecurityTypeAPIKey,
+		In:   "header",
+		Key:  "X-API-Key",
+	})
+
+	l = newLexerString(" basic http basic\n")
+	a.True(l.scanSecurity(d))
+	a.Equal(d.SecuritySchemes["basic"].Scheme, "basic")
+
+	l = newLexerString(" oauth oauth2 https://example.com/oauth/token\n")
+	a.True(l.scanSecurity(d))
+	a.Equal(d.SecuritySchemes["oauth"].URL, "https://example.com/oauth/token")
+
+	l = newLexerString(" scoped oauth2 https://example.com/oauth/token users:read,users:write\n")
+	a.True(l.scanSecurity(d))
+	a.Equal(d.SecuritySchemes["scoped"].Scopes, []string{"users:read", "users:write"})
+
+	l = newLexerString(" oidc openIdConnect https://example.com/.well-known/openid-configuration\n")
+	a.True(l.scanSecurity(d))
+	a.Equal(d.SecuritySchemes["oidc"].Type, types.SecurityTypeOpenIDConnect)
+	a.Equal(len(d.SecuritySchemes), 5)
+
+	// 重复或是空的权限范围
+	l = newLexerString(" scoped2 oauth2 https://example.com/oauth/token users:read,users:read\n")
+	a.False(l.scanSecurity(d))
+	l = newLexerString(" scoped2 oauth2 https://example.com/oauth/token users:read,\n")
+	a.False(l.scanSecurity(d))
+
+	// 只有 oauth2 可以指定权限范围
+	l = newLexerString(" oidc2 openIdConnect https://example.com/.well-known/openid-configuration openid\n")
+	a.False(l.scanSecurity(d))
+
+	// 重复的名称
+	l = newLexerString(" token http bearer\n")
+	a.False(l.scanSecurity(d))
+	a.Equal(d.SecuritySchemes["token"].Type, types.SecurityTypeAPIKey)
+
+	// 无效的 apiKey 位置
+	l = newLexerString(" key apiKey body X-API-Key\n")
+	a.False(l.scanSecurity(d))
+
+	// apiKey 缺少名称
+	l = newLexerString(" key apiKey header\n")
+	a.False(l.scanSecurity(d))
+
+	// oauth2 的地址不是 URL
+	l = newLexerString(" oauth2 oauth2 /oauth/token\n")
+	a.False(l.scanSecurity(d))
+
+	// 无效的类型
+	l = newLexerString(" digest digest\n")
+	a.False(l.scanSecurity(d))
+
+	// 参数太多
+	l = newLexerString(" bearer http bearer jwt\n")
+	a.False(l.scanSecurity(d))
+
+	// 缺少类型
+	l = newLexerString(" bearer\n")
+	a.False(l.scanSecurity(d))
+	a.Equal(len(d.SecuritySchemes), 5)
+}
+
+func TestScanSDK(t *testing.T) {
+	a := assert.New(t)
+	d := &types.Doc{}
+
+	l := newLexerString(" go github.com/caixw/apidoc-go https://github.com/caixw/apidoc-go\n")
+	a.True(l.scanSDK(d))
+	a.Equal(len(d.SDKs), 1).
+		Equal(d.SDKs[0], &types.SDK{
+			Language: "go",
+			Package:  "github.com/caixw/apidoc-go",
+			URL:      "https://github.com/caixw/apidoc-go",
+		})
+
+	// 多个 @apiSDK 累加
+	l = newLexerString(" javascript apidoc-js https://www.npmjs.com/package/apidoc-js\n")
+	a.True(l.scanSDK(d))
+	a.Equal(len(d.SDKs), 2).
+		Equal(d.SDKs[1].Language, "javascript").
+		Equal(d.SDKs[1].Package, "apidoc-js")
+
+	// 重复的语言
+	l = newLexerString(" go github.com/caixw/apidoc-go2 https://github.com/caixw/apidoc-go2\n")
+	a.False(l.scanSDK(d))
+
+	// 无效的 URL
+	l = newLexerString(" php caixw/apidoc-php /packages/apidoc-php\n")
+	a.False(l.scanSDK(d))
+	l = newLexerString(" php caixw/apidoc-php packagist.org\n")
+	a.False(l.scanSDK(d))
+
+	// 缺少参数
+	l = newLexerString(" php caixw/apidoc-php\n")
+	a.False(l.scanSDK(d))
+
+	// 参数太多
+	l = newLexerString(" php caixw/apidoc-php https://packagist.org/packages/caixw/apidoc-php v1\n")
+	a.False(l.scanSDK(d))
+	a.Equal(len(d.SDKs), 2)
+
+	// 通过 @apidoc 指定
+	l = newLexerString(` title of apidoc
+@apiSDK go github.com/caixw/apidoc-go https://github.com/caixw/apidoc-go
+@apiSDK java io.caixw.apidoc https://search.maven.org/artifact/io.caixw/apidoc
+`)
+	d = &types.Doc{}
+	a.True(l.scanAPIDoc(d))
+	a.Equal(len(d.SDKs), 2).
+		Equal(d.SDKs[0].Language, "go").
+		Equal(d.SDKs[1].URL, "https://search.maven.org/artifact/io.caixw/apidoc")
+}
+
+func TestScanAuth(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" token\n")
+	a.True(l.scanAuth(api))
+	l = newLexerString(" basic\n")
+	a.True(l.scanAuth(api))
+	a.Equal(api.Auth, []string{"token", "basic"})
+
+	l = newLexerString(" \n")
+	a.False(l.scanAuth(api))
+
+	l = newLexerString(" token basic\n")
+	a.False(l.scanAuth(api))
+	a.Equal(len(api.Auth), 2)
+}
+
+func TestScanResponseHeader(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" * X-Request-ID string 请求的 ID\n")
+	a.True(l.scanResponseHeader(api))
+	l = newLexerString(" 201 Location string 新资源的地址\n")
+	a.True(l.scanResponseHeader(api))
+	l = newLexerString(" 429 Retry-After int 重试的等待时间\n")
+	a.True(l.scanResponseHeader(api))
+	a.Equal(api.ResponseHeaders, map[string][]*types.ResponseHeader{
+		"*":   {{Name: "X-Request-ID", Type: "string", Summary: "请求的 ID"}},
+		"201": {{Name: "Location", Type: "string", Summary: "新资源的地址"}},
+		"429": {{Name: "Retry-After", Type: "int", Summary: "重试的等待时间"}},
+	})
+
+	// 参数不够
+	l = newLexerString(" 200 Location string\n")
+	a.False(l.scanResponseHeader(api))
+
+	// 无效的状态码
+	l = newLexerString(" ok Location string summary\n")
+	a.False(l.scanResponseHeader(api))
+
+	// 重复的报头
+	l = newLexerString(" 201 location string summary\n")
+	a.False(l.scanResponseHeader(api))
+	a.Equal(len(api.ResponseHeaders["201"]), 1)
+}
+
+func TestScanFlag(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString("\n")
+	a.True(l.scanFlag(vars.APISafe, &api.Safe))
+	a.True(api.Safe).False(api.Idempotent)
+
+	// 重复的标签
+	l = newLexerString("\n")
+	a.False(l.scanFlag(vars.APISafe, &api.Safe))
+
+	// 带参数
+	l = newLexerString(" true\n")
+	a.False(l.scanFlag(vars.APIIdempotent, &api.Idempotent))
+	a.False(api.Idempotent)
+}
+
+// 不带参数的标签位于注释块的最后
+func TestParse_flagAtEOF(t *testing.T) {
+	a := assert.New(t)
+
+	tags := []string{
+		vars.APISafe,
+		vars.APIIdempotent,
+		vars.APIMultipart,
+		vars.APISSE,
+		vars.APIWebSocket,
+		vars.APICORS,
+		vars.APITenant,
+	}
+	for _, tag := range tags {
+		doc := types.NewDoc()
+		code := "@api get /users users\n@apiGroup users\n@apiSuccess 200 OK\n" + tag
+		a.NotPanic(func() {
+			Parse(&Input{Data: []rune(code), Warn: log.New(new(bytes.Buffer), "", 0), Error: log.New(new(bytes.Buffer), "", 0)}, doc)
+		}, tag)
+		a.Equal(len(doc.Apis), 1, tag)
+	}
+}
+
+func TestScanNote(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" info info text\n")
+	a.True(l.scanNote(api))
+	l = newLexerString(" warning line1\nline2\n")
+	a.True(l.scanNote(api))
+	l = newLexerString(" danger danger text\n")
+	a.True(l.scanNote(api))
+	l = newLexerString(" tip tip text\n")
+	a.True(l.scanNote(api))
+	l = newLexerString(" default text\n") // 未指定类型
+	a.True(l.scanNote(api))
+	a.Equal(api.Notes, []*types.Note{
+		{Type: types.NoteTypeInfo, Text: "info text"},
+		{Type: types.NoteTypeWarning, Text: "line1\nline2"},
+		{Type: types.NoteTypeDanger, Text: "danger text"},
+		{Type: types.NoteTypeTip, Text: "tip text"},
+		{Type: types.NoteTypeInfo, Text: "default text"},
+	})
+
+	l = newLexerString(" \n")
+	a.False(l.scanNote(api))
+
+	l = newLexerString(" warning\n")
+	a.False(l.scanNote(api))
+	a.Equal(len(api.Notes), 5)
+}
+
+func TestScanEnvironment(t *testing.T) {
+	a := assert.New(t)
+
+	// 单个环境
+	l := newLexerString(` get /users 获取用户列表
+@apiEnvironment prod 每分钟最多 100 次请求
+@apiSuccess 200 OK
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Environments, []*types.EnvironmentNote{
+		{Environment: types.EnvironmentProd, Text: "每分钟最多 100 次请求"},
+	})
+
+	// 多个环境累加
+	l = newLexerString(` get /users 获取用户列表
+@apiEnvironment prod 每分钟最多 100 次请求
+@apiEnvironment staging 不限制请求次数
+@apiEnvironment dev 返回内容包含调试信息
+@apiEnvironment prod line1
+line2
+@apiEnvironment all 需要登录
+@apiSuccess 200 OK
+`)
+	api, ok = l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Environments, []*types.EnvironmentNote{
+		{Environment: types.EnvironmentProd, Text: "每分钟最多 100 次请求"},
+		{Environment: types.EnvironmentStaging, Text: "不限制请求次数"},
+		{Environment: types.EnvironmentDev, Text: "返回内容包含调试信息"},
+		{Environment: types.EnvironmentProd, Text: "line1\nline2"},
+		{Environment: types.EnvironmentAll, Text: "需要登录"},
+	})
+
+	// 无效的环境名称
+	l = newLexerString(" test 测试环境\n")
+	a.False(l.scanEnvironment(&types.API{}))
+
+	// 缺少说明内容
+	l = newLexerString(" prod\n")
+	a.False(l.scanEnvironment(&types.API{}))
+
+	l = newLexerString(" \n")
+	a.False(l.scanEnvironment(&types.API{}))
+}
+
+func TestScanContentNegotiation(t *testing.T) {
+	a := assert.New(t)
+
+	// 单个类型
+	l := newLexerString(` get /users 获取用户列表
+@apiProduces application/json,application/xml,text/csv
+@apiContentNegotiation application/json
+@apiSuccess 200 OK
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Negotiation, []string{"application/json"})
+
+	// 多个类型，多个标签的内容累加
+	api = &types.API{}
+	l = newLexerString(" application/json \tapplication/xml\n")
+	a.True(l.scanContentNegotiation(api))
+	l = newLexerString(" application/vnd.api+json;charset=utf-8\n")
+	a.True(l.scanContentNegotiation(api))
+	a.Equal(api.Negotiation, []string{"application/json", "application/xml", "application/vnd.api+json;charset=utf-8"})
+
+	// 重复的值
+	l = newLexerString(" text/csv application/JSON\n")
+	a.False(l.scanContentNegotiation(api))
+
+	// 无效的类型
+	for _, v := range []string{"json", "application/", "/json", "*/*", "application/*", "text/plain;;"} {
+		l = newLexerString(" " + v + "\n")
+		a.False(l.scanContentNegotiation(&types.API{}), v)
+	}
+
+	// 缺少参数
+	l = newLexerString(" \n")
+	a.False(l.scanContentNegotiation(&types.API{}))
+
+	// 多行
+	l = newLexerString(" application/json\nline2\n")
+	a.False(l.scanContentNegotiation(&types.API{}))
+}
+
+func TestIsMimetype(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(isMimetype("application/json"))
+	a.True(isMimetype("application/vnd.api+json"))
+	a.True(isMimetype("text/plain;charset=utf-8"))
+
+	a.False(isMimetype(""))
+	a.False(isMimetype("json"))
+	a.False(isMimetype("text/*"))
+	a.False(isMimetype("text/"))
+}
+
+func TestScanIdempotencyKey(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(` post /payments 创建支付
+@apiIdempotencyKey Idempotency-Key 客户端生成的唯一值，24 小时内有效
+@apiSuccess 201 OK
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.IdempotencyKey, &types.IdempotencyKey{Header: "Idempotency-Key", Summary: "客户端生成的唯一值，24 小时内有效"})
+
+	// 重复的标签
+	l = newLexerString(" X-Request-ID 请求的唯一值\n")
+	a.False(l.scanIdempotencyKey(api))
+
+	// 缺少描述
+	l = newLexerString(" Idempotency-Key\n")
+	a.False(l.scanIdempotencyKey(&types.API{}))
+
+	l = newLexerString(" \n")
+	a.False(l.scanIdempotencyKey(&types.API{}))
+}
+
+func TestScanRequestID(t *testing.T) {
+	a := assert.New(t)
+
+	for _, mode := range []string{types.RequestIDAuto, types.RequestIDRequired, types.RequestIDOptional} {
+		api := &types.API{}
+		l := newLexerString(" X-Request-ID " + mode + "\n")
+		a.True(l.scanRequestID(api), mode)
+		a.Equal(api.RequestID, &types.RequestID{Header: "X-Request-ID", Mode: mode})
+	}
+
+	// 默认为 auto
+	api := &types.API{}
+	l := newLexerString(" X-Trace-ID\n")
+	a.True(l.scanRequestID(api))
+	a.Equal(api.RequestID, &types.RequestID{Header: "X-Trace-ID", Mode: types.RequestIDAuto})
+
+	// 重复的标签
+	l = newLexerString(" X-Request-ID required\n")
+	a.False(l.scanRequestID(api))
+	a.Equal(api.RequestID.Header, "X-Trace-ID")
+
+	// 参数不正确
+	for _, v := range []string{" \n", " X-Request-ID always\n", " X-Request-ID auto desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanRequestID(&types.API{}), v)
+	}
+}
+
+func TestScanEvent(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" created object 用户已创建\n")
+	a.True(l.scanEvent(vars.APIEvent, &api.Events, true))
+	l = newLexerString(" deleted string 用户已删除\n")
+	a.True(l.scanEvent(vars.APIEvent, &api.Events, true))
+	a.Equal(api.Events, []*types.Event{
+		{Name: "created", Type: "object", Summary: "用户已创建"},
+		{Name: "deleted", Type: "string", Summary: "用户已删除"},
+	})
+
+	// 重复的事件名称
+	l = newLexerString(" created object desc\n")
+	a.False(l.scanEvent(vars.APIEvent, &api.Events, true))
+	a.Equal(len(api.Events), 2)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " created\n", " created object\n", " created object desc\n line2\n"} {
+		l = newLexerString(v)
+		a.False(l.scanEvent(vars.APIEvent, &[]*types.Event{}, true), v)
+	}
+}
+
+func TestParse_sse(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api get /users/events user events
+@apiGroup users
+@apiSSE
+@apiEvent created object 用户已创建
+@apiEvent updated object 用户已更新
+@apiEvent deleted string 用户已删除，数据为用户 ID
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	api := doc.Apis[0]
+	a.True(api.SSE).Equal(len(api.Events), 3)
+	a.Equal(api.Events[2], &types.Event{Name: "deleted", Type: "string", Summary: "用户已删除，数据为用户 ID"})
+
+	// 非 GET 请求
+	doc = types.NewDoc()
+	code = `
+@api post /users/events user events
+@apiGroup users
+@apiSSE
+@apiEvent created object 用户已创建
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APISSE)).
+		True(strings.Contains(warn.String(), "POST"))
+
+	// 未指定 @apiSSE
+	warn.Reset()
+	doc = types.NewDoc()
+	code = `
+@api get /users/events user events
+@apiGroup users
+@apiEvent created object 用户已创建
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APIEvent))
+
+	// 重复的 @apiSSE
+	doc = types.NewDoc()
+	code = `
+@api get /users/events user events
+@apiGroup users
+@apiSSE
+@apiSSE
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Error: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 0)
+}
+
+func TestParse_domainEvents(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api post /orders create order
+@apiProducesEvent OrderCreated #/definitions/OrderCreated 订单已创建
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	a.Equal(doc.Apis[0].ProducedEvents, []*types.Event{{Name: "OrderCreated", Type: "#/definitions/OrderCreated", Summary: "订单已创建"}}).
+		Nil(doc.Apis[0].ConsumedEvents)
+
+	// 多个事件
+	doc = types.NewDoc()
+	code = `
+@api post /orders/{id}/pay pay order
+@apiConsumesEvent OrderCreated OrderCreated
+@apiConsumesEvent PaymentAuthorized PaymentAuthorized 支付已授权
+@apiProducesEvent OrderPaid OrderPaid
+@apiProducesEvent InvoiceIssued Invoice 已开具发票
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	api := doc.Apis[0]
+	a.Equal(api.ConsumedEvents, []*types.Event{
+		{Name: "OrderCreated", Type: "OrderCreated"},
+		{Name: "PaymentAuthorized", Type: "PaymentAuthorized", Summary: "支付已授权"},
+	})
+	a.Equal(api.ProducedEvents, []*types.Event{
+		{Name: "OrderPaid", Type: "OrderPaid"},
+		{Name: "InvoiceIssued", Type: "Invoice", Summary: "已开具发票"},
+	})
+
+	// 同名的事件可以同时出现在产生和消费中，但不能在同一类型中重复
+	errLog := new(bytes.Buffer)
+	doc = types.NewDoc()
+	code = `
+@api post /orders/{id}/retry retry
+@apiConsumesEvent OrderFailed OrderFailed
+@apiProducesEvent OrderFailed OrderFailed
+@apiProducesEvent OrderFailed OrderFailed
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Error: log.New(errLog, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 0)
+	a.True(strings.Contains(errLog.String(), vars.APIProducesEvent))
+
+	// 缺少结构的引用
+	errLog.Reset()
+	doc = types.NewDoc()
+	code = `
+@api post /orders create order
+@apiConsumesEvent OrderCreated
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Error: log.New(errLog, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 0)
+	a.True(strings.Contains(errLog.String(), vars.APIConsumesEvent))
+}
+
+func TestParse_websocket(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api get /chat chat
+@apiGroup chat
+@apiWebSocket
+@apiWSSend message object 新的聊天消息
+@apiWSSend typing string
+@apiWSReceive send object 发送聊天消息
+@apiSuccess 101 Switching Protocols
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	api := doc.Apis[0]
+	a.True(api.WebSocket)
+	a.Equal(api.WSSend, []*types.Event{
+		{Name: "message", Type: "object", Summary: "新的聊天消息"},
+		{Name: "typing", Type: "string"},
+	})
+	a.Equal(api.WSReceive, []*types.Event{{Name: "send", Type: "object", Summary: "发送聊天消息"}})
+
+	// 非 GET 请求
+	doc = types.NewDoc()
+	code = `
+@api post /chat chat
+@apiGroup chat
+@apiWebSocket
+@apiSuccess 101 Switching Protocols
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APIWebSocket)).
+		True(strings.Contains(warn.String(), "POST"))
+
+	// 未指定 @apiWebSocket
+	warn.Reset()
+	doc = types.NewDoc()
+	code = `
+@api get /chat chat
+@apiGroup chat
+@apiWSReceive send object
+@apiSuccess 101 Switching Protocols
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APIWSReceive)).
+		False(strings.Contains(warn.String(), vars.APIWSSend))
+
+	// 同类型中重复的消息名称
+	errLog := new(bytes.Buffer)
+	doc = types.NewDoc()
+	code = `
+@api get /chat chat
+@apiGroup chat
+@apiWebSocket
+@apiWSSend message object
+@apiWSReceive message object
+@apiWSReceive message string
+@apiSuccess 101 Switching Protocols
+`
+	Parse(&Input{Data: []rune(code), Error: log.New(errLog, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 0)
+	a.True(strings.Contains(errLog.String(), vars.APIWSReceive))
+}
+
+func TestScanSocketIO(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" /chat message emit #/definitions/Message 新的聊天消息\n")
+	a.True(l.scanSocketIO(api))
+	l = newLexerString(" /chat message on #/definitions/Message 发送聊天消息\n")
+	a.True(l.scanSocketIO(api))
+	l = newLexerString(" /admin kick emit string 被管理员踢出\n")
+	a.True(l.scanSocketIO(api))
+	a.Equal(api.SocketIO, []*types.SocketIONamespace{
+		{
+			Namespace: "/chat",
+			Events: []*types.SocketIOEvent{
+				{Event: "message", Direction: types.SocketIOEmit, Type: "#/definitions/Message", Summary: "新的聊天消息"},
+				{Event: "message", Direction: types.SocketIOOn, Type: "#/definitions/Message", Summary: "发送聊天消息"},
+			},
+		},
+		{
+			Namespace: "/admin",
+			Events:    []*types.SocketIOEvent{{Event: "kick", Direction: types.SocketIOEmit, Type: "string", Summary: "被管理员踢出"}},
+		},
+	})
+
+	// 同一命名空间中相同方向的重复事件
+	l = newLexerString(" /chat message on object desc\n")
+	a.False(l.scanSocketIO(api))
+	a.Equal(len(api.SocketIO[0].Events), 2)
+
+	// 参数不正确
+	for _, v := range []string{
+		" \n",
+		" /chat message emit object\n",
+		" chat message emit object desc\n",
+		" /chat message send object desc\n",
+		" /chat message emit object desc\n line2\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanSocketIO(&types.API{}), v)
+	}
+}
+
+func TestParse_socketIO(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api get /socket.io chat
+@apiGroup chat
+@apiWebSocket
+@apiSocketIO /chat message emit object 新的聊天消息
+@apiSocketIO /chat send on object 发送聊天消息
+@apiSuccess 101 Switching Protocols
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	a.Equal(len(doc.Apis[0].SocketIO), 1).Equal(len(doc.Apis[0].SocketIO[0].Events), 2)
+
+	// 未指定 @apiWebSocket
+	doc = types.NewDoc()
+	code = `
+@api get /socket.io chat
+@apiGroup chat
+@apiSocketIO /chat message emit object 新的聊天消息
+@apiSuccess 101 Switching Protocols
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APISocketIO))
+}
+
+func TestScanChangelog(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 1.0.0 2017-10-01 初始版本\n")
+	a.True(l.scanChangelog(api))
+	a.Equal(api.Changelog, []*types.ChangelogEntry{{Version: "1.0.0", Date: "2017-10-01", Description: "初始版本"}})
+
+	// 重复的版本号
+	l = newLexerString(" v1.0.0 2017-10-02 desc\n")
+	a.False(l.scanChangelog(api))
+	a.Equal(len(api.Changelog), 1)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 1.0.0\n", " 1.0.0 2017-10-01\n", " 1.0.0 2017-10-01 desc\n line2\n"} {
+		l = newLexerString(v)
+		a.False(l.scanChangelog(&types.API{}), v)
+	}
+
+	// 无效的日期
+	for _, date := range []string{"2017/10/01", "2017-13-01", "2017-02-30", "17-10-01", "2017-1-1", "20171001", "today"} {
+		l = newLexerString(" 1.0.0 " + date + " desc\n")
+		a.False(l.scanChangelog(&types.API{}), date)
+	}
+}
+
+func TestParse_changelog(t *testing.T) {
+	a := assert.New(t)
+
+	doc := types.NewDoc()
+	code := `
+@api get /users users
+@apiGroup users
+@apiChangelog 1.2.0 2017-11-01 添加 email 字段
+@apiChangelog 1.10.0 2018-03-01 添加分页参数
+@apiChangelog 1.0.0 2017-09-01 初始版本
+@apiChangelog 1.2.1 2017-11-15 修正 email 的格式
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code)}, doc)
+	a.Equal(len(doc.Apis), 1)
+
+	versions := make([]string, 0, 4)
+	for _, e := range doc.Apis[0].Changelog {
+		versions = append(versions, e.Version)
+	}
+	a.Equal(versions, []string{"1.10.0", "1.2.1", "1.2.0", "1.0.0"})
+	a.Equal(doc.Apis[0].Changelog[0], &types.ChangelogEntry{Version: "1.10.0", Date: "2018-03-01", Description: "添加分页参数"})
+}
+
+func TestScanBreakingChange(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" v2.0.0 删除了 email 字段\n")
+	a.True(l.scanBreakingChange(api))
+	l = newLexerString(" 3.0 不再支持分页参数\n")
+	a.True(l.scanBreakingChange(api))
+	a.Equal(api.BreakingChanges, []*types.BreakingChange{
+		{Version: "2.0.0", Description: "删除了 email 字段"},
+		{Version: "3.0", Description: "不再支持分页参数"},
+	})
+
+	// 重复的版本号
+	l = newLexerString(" 2.0.0 desc\n")
+	a.False(l.scanBreakingChange(api))
+	a.Equal(len(api.BreakingChanges), 2)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 2.0.0\n", " v\n", " 2.0.0 desc\n line2\n"} {
+		l = newLexerString(v)
+		a.False(l.scanBreakingChange(&types.API{}), v)
+	}
+}
+
+func TestScanScope(t *testing.T) {
+	a := assert.New(t)
+
+	// 默认为 all
+	api := &types.API{}
+	l := newLexerString(" users:read,users:write\n")
+	a.True(l.scanScope(api))
+	a.Equal(api.RequiredScopes, []string{"users:read", "users:write"}).
+		Equal(api.ScopeLogic, types.ScopeLogicAll)
+
+	api = &types.API{}
+	l = newLexerString(" users:read,admin any\n")
+	a.True(l.scanScope(api))
+	a.Equal(api.RequiredScopes, []string{"users:read", "admin"}).
+		Equal(api.ScopeLogic, types.ScopeLogicAny)
+
+	api = &types.API{}
+	l = newLexerString(" admin all\n")
+	a.True(l.scanScope(api))
+	a.Equal(api.RequiredScopes, []string{"admin"}).
+		Equal(api.ScopeLogic, types.ScopeLogicAll)
+
+	// 重复的标签
+	l = newLexerString(" users:read\n")
+	a.False(l.scanScope(api))
+	a.Equal(api.RequiredScopes, []string{"admin"})
+
+	for _, v := range []string{
+		" \n",
+		" users:read one\n",
+		" users:read any all\n",
+		" users:read,,admin\n",
+		" users:read,users:read\n",
+	} {
+		api = &types.API{}
+		l = newLexerString(v)
+		a.False(l.scanScope(api), v)
+		a.Empty(api.RequiredScopes)
+	}
+}
+
+func TestScanRegion(t *testing.T) {
+	a := assert.New(t)
+
+	// 单个区域
+	api := &types.API{}
+	l := newLexerString(" available:cn-north-1\n")
+	a.True(l.scanRegion(api))
+	a.Equal(api.Region, &types.RegionPolicy{Available: []string{"cn-north-1"}})
+
+	api = &types.API{}
+	l = newLexerString(" unavailable:eu-west-1\n")
+	a.True(l.scanRegion(api))
+	a.Equal(api.Region, &types.RegionPolicy{Unavailable: []string{"eu-west-1"}})
+
+	// 多个区域
+	api = &types.API{}
+	l = newLexerString(" unavailable:eu-west-1 available:us-east-1,us-west-2,ap-east-1\n")
+	a.True(l.scanRegion(api))
+	a.Equal(api.Region, &types.RegionPolicy{
+		Available:   []string{"us-east-1", "us-west-2", "ap-east-1"},
+		Unavailable: []string{"eu-west-1"},
+	})
+
+	// 相互矛盾的内容由 -lint 检测
+	api = &types.API{}
+	l = newLexerString(" available:us-east-1 unavailable:us-east-1\n")
+	a.True(l.scanRegion(api))
+
+	// 重复的标签
+	l = newLexerString(" available:cn-north-1\n")
+	a.False(l.scanRegion(api))
+	a.Equal(api.Region.Available, []string{"us-east-1"})
+
+	for _, v := range []string{
+		"\n",
+		" us-east-1\n",
+		" :us-east-1\n",
+		" region:us-east-1\n",
+		" available:\n",
+		" available:us-east-1,\n",
+		" available:us-east-1,us-east-1\n",
+		" available:us-east-1 available:us-west-2\n",
+		" available:us-east-1 unavailable:eu-west-1 x\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanRegion(&types.API{}), v)
+	}
+}
+
+func TestScanTenant(t *testing.T) {
+	a := assert.New(t)
+
+	data := []*struct {
+		code   string
+		tenant *types.TenantPolicy
+	}{
+		{
+			code:   " isolation:shared scoping:header\n",
+			tenant: &types.TenantPolicy{Isolation: types.TenantIsolationShared, Scoping: types.TenantScopingHeader, Header: types.DefaultTenantHeader},
+		},
+		{
+			code:   " scoping:header:X-Org isolation:dedicated\n",
+			tenant: &types.TenantPolicy{Isolation: types.TenantIsolationDedicated, Scoping: types.TenantScopingHeader, Header: "X-Org"},
+		},
+		{
+			code:   " scoping:path\n",
+			tenant: &types.TenantPolicy{Scoping: types.TenantScopingPath},
+		},
+		{
+			code:   " isolation:dedicated scoping:subdomain\n",
+			tenant: &types.TenantPolicy{Isolation: types.TenantIsolationDedicated, Scoping: types.TenantScopingSubdomain},
+		},
+		{
+			code:   "\n",
+			tenant: &types.TenantPolicy{},
+		},
+	}
+	for _, item := range data {
+		api := &types.API{}
+		l := newLexerString(item.code)
+		a.True(l.scanTenant(api), item.code)
+		a.Equal(api.Tenant, item.tenant, item.code)
+	}
+
+	// 重复的标签
+	api := &types.API{Tenant: &types.TenantPolicy{}}
+	l := newLexerString(" scoping:path\n")
+	a.False(l.scanTenant(api))
+	a.Equal(api.Tenant, &types.TenantPolicy{})
+
+	for _, v := range []string{
+		" shared\n",
+		" isolation:private\n",
+		" isolation:shared:x\n",
+		" scoping:query\n",
+		" scoping:header:\n",
+		" scoping:path:tenant\n",
+		" scoping:subdomain:x\n",
+		" region:cn\n",
+		" isolation:shared isolation:dedicated\n",
+		" scoping:path scoping:subdomain\n",
+		" isolation:shared scoping:path x\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanTenant(&types.API{}), v)
+	}
+}
+
+func TestScanAsync(t *testing.T) {
+	a := assert.New(t)
+
+	// 轮询
+	api := &types.API{}
+	l := newLexerString(" pollUrl:/jobs/{jobId}\n")
+	a.True(l.scanAsync(api))
+	a.Equal(api.Async, &types.Async{PollURL: "/jobs/{jobId}"})
+
+	// 重复的标签
+	l = newLexerString(" callbackHeader:X-Callback-URL\n")
+	a.False(l.scanAsync(api))
+	a.Equal(api.Async.CallbackHeader, "")
+
+	// 推送
+	api = &types.API{}
+	l = newLexerString(" callbackHeader:X-Callback-URL\n")
+	a.True(l.scanAsync(api))
+	a.Equal(api.Async, &types.Async{CallbackHeader: "X-Callback-URL"})
+
+	// 两者同时指定，且不分先后；地址中可以包含 :
+	api = &types.API{}
+	l = newLexerString(" callbackHeader:X-Callback-URL pollUrl:https://example.com/jobs/{jobId}\n")
+	a.True(l.scanAsync(api))
+	a.Equal(api.Async, &types.Async{PollURL: "https://example.com/jobs/{jobId}", CallbackHeader: "X-Callback-URL"})
+
+	// 都不指定
+	api = &types.API{}
+	l = newLexerString("\n")
+	a.True(l.scanAsync(api))
+	a.Equal(api.Async, &types.Async{})
+
+	// 参数不正确
+	for _, v := range []string{
+		" pollUrl\n", " pollUrl:\n", " poll:/jobs\n",
+		" pollUrl:/jobs pollUrl:/tasks\n",
+		" callbackHeader:X-A callbackHeader:X-B\n",
+		" pollUrl:/jobs callbackHeader:X-A desc\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanAsync(&types.API{}), v)
+	}
+}
+
+func TestScanCircuitBreaker(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" threshold:50% timeout:30s halfOpenRequests:5\n")
+	a.True(l.scanCircuitBreaker(api))
+	a.Equal(api.CircuitBreaker, &types.CircuitBreaker{Threshold: 50, Timeout: "30s", HalfOpenRequests: 5})
+
+	// 顺序无关，且可以省略部分参数
+	api = &types.API{}
+	l = newLexerString(" halfOpenRequests:1 threshold:25\n")
+	a.True(l.scanCircuitBreaker(api))
+	a.Equal(api.CircuitBreaker, &types.CircuitBreaker{Threshold: 25, HalfOpenRequests: 1})
+
+	// 没有参数
+	api = &types.API{}
+	l = newLexerString("\n")
+	a.True(l.scanCircuitBreaker(api))
+	a.Equal(api.CircuitBreaker, &types.CircuitBreaker{})
+
+	// 重复的标签
+	l = newLexerString(" threshold:10\n")
+	a.False(l.scanCircuitBreaker(api))
+	a.Equal(api.CircuitBreaker, &types.CircuitBreaker{})
+
+	for _, v := range []string{
+		" 50\n",
+		" :50\n",
+		" threshold:0\n",
+		" threshold:101%\n",
+		" threshold:half\n",
+		" timeout:30\n",
+		" timeout:-1s\n",
+		" halfOpenRequests:0\n",
+		" halfOpenRequests:x\n",
+		" errors:50\n",
+		" threshold:50 threshold:60\n",
+		" timeout:1s timeout:2s\n",
+		" threshold:50 timeout:1s halfOpenRequests:1 halfOpenRequests:2\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanCircuitBreaker(&types.API{}), v)
+	}
+}
+
+func TestScanTimeBudget(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 200 upstream:users:50, upstream:orders:80\n")
+	a.True(l.scanTimeBudget(api))
+	a.Equal(api.TimeBudget, &types.TimeBudget{Total: 200, Upstreams: []*types.UpstreamBudget{
+		{Service: "users", Value: 50},
+		{Service: "orders", Value: 80},
+	}})
+
+	// 仅以空格分隔
+	api = &types.API{}
+	l = newLexerString(" 100 upstream:users:50 upstream:orders:50\n")
+	a.True(l.scanTimeBudget(api))
+	a.Equal(api.TimeBudget.Allocated(), 100)
+
+	// 未指定上游服务
+	api = &types.API{}
+	l = newLexerString(" 100\n")
+	a.True(l.scanTimeBudget(api))
+	a.Equal(api.TimeBudget, &types.TimeBudget{Total: 100})
+
+	// 重复的标签
+	l = newLexerString(" 300\n")
+	a.False(l.scanTimeBudget(api))
+	a.Equal(api.TimeBudget.Total, 100)
+
+	// 参数不正确
+	for _, v := range []string{
+		" \n",
+		" 0\n",
+		" fast\n",
+		" 100 users:50\n",
+		" 100 upstream:users\n",
+		" 100 upstream::50\n",
+		" 100 upstream:users:0\n",
+		" 100 upstream:users:fast\n",
+		" 100 upstream:users:20, upstream:users:30\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanTimeBudget(&types.API{}), v)
+	}
+}
+
+func TestParse_timeBudget(t *testing.T) {
+	a := assert.New(t)
+
+	// 刚好用完所有的时间
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api get /orders orders
+@apiTimeBudget 200 upstream:users:120, upstream:inventory:80
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+
+	// 上游服务的时间之和超过了总时间
+	doc = types.NewDoc()
+	code = `
+@api get /orders orders
+@apiTimeBudget 200 upstream:users:120, upstream:inventory:81
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.Equal(doc.Apis[0].TimeBudget.Allocated(), 201)
+	a.True(strings.Contains(warn.String(), vars.APITimeBudget)).
+		True(strings.Contains(warn.String(), "201"))
+}
+
+func TestScanGRPC(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" UserService.GetUser\n")
+	a.True(l.scanGRPC(api))
+	a.Equal(api.GRPCMethod, "UserService.GetUser")
+
+	// 带包名
+	api = &types.API{}
+	l = newLexerString(" users.v1.UserService.GetUser\n")
+	a.True(l.scanGRPC(api))
+	a.Equal(api.GRPCMethod, "users.v1.UserService.GetUser")
+
+	// 重复的标签
+	l = newLexerString(" UserService.ListUsers\n")
+	a.False(l.scanGRPC(api))
+	a.Equal(api.GRPCMethod, "users.v1.UserService.GetUser")
+
+	// 参数不正确
+	for _, v := range []string{" \n", " GetUser\n", " UserService.\n", " .GetUser\n", " .UserService.GetUser\n", " users..GetUser\n", " UserService.GetUser desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanGRPC(&types.API{}), v)
+	}
+}
+
+func TestScanOperationID(t *testing.T) {
+	a := assert.New(t)
+
+	for _, v := range []string{"getUser", "get_user", "_getUser", "GetUser2", "x"} {
+		api := &types.API{}
+		l := newLexerString(" " + v + "\n")
+		a.True(l.scanOperationID(api), v)
+		a.Equal(api.OperationID, v)
+	}
+
+	// 重复的标签
+	api := &types.API{OperationID: "getUser"}
+	l := newLexerString(" listUsers\n")
+	a.False(l.scanOperationID(api))
+	a.Equal(api.OperationID, "getUser")
+
+	// 参数数量不正确
+	for _, v := range []string{" \n", " getUser desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanOperationID(&types.API{}), v)
+	}
+
+	// 无效的标识符
+	for _, v := range []string{"2getUser", "get-user", "get.user", "getUser()", "获取用户", "$get"} {
+		api := &types.API{}
+		l = newLexerString(" " + v + "\n")
+		a.False(l.scanOperationID(api), v)
+		a.Empty(api.OperationID)
+	}
+}
+
+func TestScanMediaType(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" image/png\n")
+	a.True(l.scanMediaType(api))
+	a.Equal(api.MediaType, &types.MediaType{Type: "image/png"})
+
+	// 带扩展名，可以有 . 前缀
+	api = &types.API{}
+	l = newLexerString(" application/pdf .pdf\n")
+	a.True(l.scanMediaType(api))
+	a.Equal(api.MediaType, &types.MediaType{Type: "application/pdf", Extension: "pdf"})
+
+	api = &types.API{}
+	l = newLexerString(" application/zip zip\n")
+	a.True(l.scanMediaType(api))
+	a.Equal(api.MediaType, &types.MediaType{Type: "application/zip", Extension: "zip"})
+
+	// 重复的标签
+	l = newLexerString(" image/jpeg\n")
+	a.False(l.scanMediaType(api))
+	a.Equal(api.MediaType.Type, "application/zip")
+
+	// 参数不正确
+	for _, v := range []string{" \n", " image/png png desc\n", " png\n", " /png\n", " image/\n", " application/zip a/zip\n"} {
+		l = newLexerString(v)
+		a.False(l.scanMediaType(&types.API{}), v)
+	}
+}
+
+func TestParse_mediaType(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api get /avatar avatar
+@apiMediaType image/png png
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	a.Equal(doc.Apis[0].MediaType, &types.MediaType{Type: "image/png", Extension: "png"})
+
+	// 二进制内容与参数同时存在
+	code = `
+@api get /archive.zip archive
+@apiMediaType application/zip zip
+@apiSuccess 200 OK
+@apiParam name string 文件名
+`
+	doc = types.NewDoc()
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APIMediaType)).
+		True(strings.Contains(warn.String(), vars.APISuccess))
+}
+
+func TestScanBatch(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 100 parallel\n")
+	a.True(l.scanBatch(api))
+	a.Equal(api.Batch, &types.Batch{MaxItems: 100, Mode: types.BatchParallel})
+
+	api = &types.API{}
+	l = newLexerString(" 20 sequential\n")
+	a.True(l.scanBatch(api))
+	a.Equal(api.Batch, &types.Batch{MaxItems: 20, Mode: types.BatchSequential})
+
+	// 默认为 sequential
+	api = &types.API{}
+	l = newLexerString(" 50\n")
+	a.True(l.scanBatch(api))
+	a.Equal(api.Batch, &types.Batch{MaxItems: 50, Mode: types.BatchSequential})
+
+	// 重复的标签
+	l = newLexerString(" 10 parallel\n")
+	a.False(l.scanBatch(api))
+	a.Equal(api.Batch.MaxItems, 50)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 0\n", " -1\n", " many\n", " 10 async\n", " 10 parallel desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanBatch(&types.API{}), v)
+	}
+}
+
+func TestParse_batch(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api post /users/batch batch create users
+@apiBatch 100 parallel
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+
+	// GET 请求使用 @apiBatch
+	code = `
+@api get /users/batch batch get users
+@apiBatch 100
+@apiSuccess 200 OK
+`
+	doc = types.NewDoc()
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	a.True(strings.Contains(warn.String(), vars.APIBatch)).
+		True(strings.Contains(warn.String(), "GET"))
+}
+
+func TestScanContentLength(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 1048576\n")
+	a.True(l.scanContentLength(api))
+	a.Equal(api.MaxRequestBodyBytes, int64(1048576))
+
+	// 超过 int32 的值
+	api = &types.API{}
+	l = newLexerString(" 10737418240\n")
+	a.True(l.scanContentLength(api))
+	a.Equal(api.MaxRequestBodyBytes, int64(10737418240))
+
+	// 重复的标签
+	l = newLexerString(" 1024\n")
+	a.False(l.scanContentLength(api))
+	a.Equal(api.MaxRequestBodyBytes, int64(10737418240))
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 0\n", " -1\n", " 1.5\n", " 1MB\n", " 1024 bytes\n"} {
+		l = newLexerString(v)
+		a.False(l.scanContentLength(&types.API{}), v)
+	}
+}
+
+func TestParse_contentLength(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api post /files upload
+@apiContentLength 10485760
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	a.Equal(doc.Apis[0].MaxRequestBodyBytes, int64(10485760))
+
+	// 没有请求内容的请求使用 @apiContentLength
+	for _, method := range []string{"GET", "HEAD"} {
+		warn.Reset()
+		code = `
+@api ` + method + ` /files files
+@apiContentLength 1024
+@apiSuccess 200 OK
+`
+		doc = types.NewDoc()
+		Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+		a.Equal(len(doc.Apis), 1)
+		a.True(strings.Contains(warn.String(), vars.APIContentLength), method).
+			True(strings.Contains(warn.String(), method), method)
+	}
+}
+
+// 各标签之间相互矛盾时，解析时即给出警告，-lint 中不再另行检测
+func TestParse_consistency(t *testing.T) {
+	a := assert.New(t)
+
+	data := []*struct {
+		code string
+		warn []string // 警告信息中应该包含的内容，为空表示没有警告
+	}{
+		{code: "@apiCircuitBreaker threshold:50%\n", warn: []string{vars.APICircuitBreaker, vars.APIRetry}},
+		{code: "@apiCircuitBreaker threshold:50%\n@apiIdempotent\n@apiRetry always maxAttempts:3\n"},
+		{code: "@apiTenant scoping:header:X-Org\n", warn: []string{"X-Org", vars.APIHeader}},
+		{code: "@apiTenant scoping:header:X-Org\n@apiRequest json\n@apiHeader x-org 租户\n"},
+		{code: "@apiRegion available:us-east-1,eu-west-1 unavailable:eu-west-1\n", warn: []string{"eu-west-1"}},
+		{code: "@apiRegion available:us-east-1 unavailable:eu-west-1\n"},
+		{code: "@apiThrottle 600 per hour\n@apiQuota 500 per year\n", warn: []string{"500", "600"}},
+		{code: "@apiThrottle 10 per second\n@apiQuota 10000 per day\n"},
+		{code: "@apiAsync pollUrl:/jobs/{jobId}\n", warn: []string{"GET /users"}},
+	}
+
+	for _, item := range data {
+		warn := new(bytes.Buffer)
+		doc := types.NewDoc()
+		code := "@api get /users users\n" + item.code + "@apiSuccess 200 OK\n"
+		Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+		a.Equal(len(doc.Apis), 1, item.code)
+
+		if len(item.warn) == 0 {
+			a.Equal(warn.Len(), 0, item.code, warn.String())
+			continue
+		}
+		for _, w := range item.warn {
+			a.True(strings.Contains(warn.String(), w), item.code, warn.String())
+		}
+	}
+}
+
+func TestScanGraphQL(t *testing.T) {
+	a := assert.New(t)
+
+	for _, typ := range []string{types.GraphQLQuery, types.GraphQLMutation, types.GraphQLSubscription} {
+		api := &types.API{}
+		l := newLexerString(" userChanged " + typ + "\n")
+		a.True(l.scanGraphQL(api), typ)
+		a.Equal(api.GraphQL, &types.GraphQL{Operation: "userChanged", Type: typ})
+	}
+
+	// 默认为 query
+	api := &types.API{}
+	l := newLexerString(" getUser\n")
+	a.True(l.scanGraphQL(api))
+	a.Equal(api.GraphQL, &types.GraphQL{Operation: "getUser", Type: types.GraphQLQuery})
+
+	// 重复的标签
+	l = newLexerString(" listUsers\n")
+	a.False(l.scanGraphQL(api))
+	a.Equal(api.GraphQL.Operation, "getUser")
+
+	// 参数不正确
+	for _, v := range []string{" \n", " getUser fragment\n", " getUser query desc\n", " get-user\n", " 1user query\n"} {
+		l = newLexerString(v)
+		a.False(l.scanGraphQL(&types.API{}), v)
+	}
+}
+
+func TestParse_graphql(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api post /users create user
+@apiGraphQL createUser mutation
+@apiGroup users
+@apiRequest json
+@apiParam name string 用户名
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+
+	api := doc.Apis[0]
+	a.Equal(api.Method, "post").
+		Equal(api.URL, "/users").
+		Equal(api.Group, "users").
+		Equal(api.Request.Params[0].Name, "name").
+		Equal(api.Success.Code, "201").
+		Equal(api.GraphQL, &types.GraphQL{Operation: "createUser", Type: types.GraphQLMutation})
+}
+
+func TestScanCodegen(t *testing.T) {
+	a := assert.New(t)
+
+	supported := func(lang string) bool { return lang == "java" || lang == "go" || lang == "python" }
+	codegenLexer := func(data string) *lexer {
+		l := newLexerString(data)
+		l.input.LangIsSupported = supported
+		return l
+	}
+
+	api := &types.API{}
+	for _, v := range []string{
+		" java methodName getUserById\n",
+		" java packageName com.example.users\n",
+		" go methodName GetUser\n",
+		" python methodName get_user_by_id\n",
+		" go package users v2\n", // 值中可以包含空格
+	} {
+		a.True(codegenLexer(v).scanCodegen(api), v)
+	}
+	a.Equal(api.Codegen, map[string]map[string]string{
+		"java":   {"methodName": "getUserById", "packageName": "com.example.users"},
+		"go":     {"methodName": "GetUser", "package": "users v2"},
+		"python": {"methodName": "get_user_by_id"},
+	})
+
+	// 同一语言下重复的选项
+	a.False(codegenLexer(" java methodName getUser\n").scanCodegen(api))
+	a.Equal(api.Codegen["java"]["methodName"], "getUserById")
+
+	// 不支持的语言
+	a.False(codegenLexer(" cobol methodName GETUSER\n").scanCodegen(api))
+	a.Nil(api.Codegen["cobol"])
+
+	// 未指定 LangIsSupported 时，不检测语言
+	api = &types.API{}
+	a.True(newLexerString(" cobol methodName GETUSER\n").scanCodegen(api))
+	a.Equal(api.Codegen["cobol"]["methodName"], "GETUSER")
+
+	for _, v := range []string{
+		"\n",
+		" java\n",
+		" java methodName\n",
+		" java methodName getUser\n next line\n",
+	} {
+		a.False(codegenLexer(v).scanCodegen(&types.API{}), v)
+	}
+}
+
+func TestScanDataClassification(t *testing.T) {
+	a := assert.New(t)
+
+	for _, c := range types.DataClassifications {
+		api := &types.API{}
+		l := newLexerString(" " + c + "\n")
+		a.True(l.scanDataClassification(api), c)
+		a.Equal(api.DataClassification, c)
+	}
+
+	// 重复的标签
+	api := &types.API{DataClassification: types.DataClassificationPII}
+	l := newLexerString(" public\n")
+	a.False(l.scanDataClassification(api))
+	a.Equal(api.DataClassification, types.DataClassificationPII)
+
+	for _, v := range []string{
+		"\n",
+		" secret\n",
+		" PII\n",
+		" pii,public\n",
+		" pii public\n",
+	} {
+		api = &types.API{}
+		l = newLexerString(v)
+		a.False(l.scanDataClassification(api), v)
+		a.Empty(api.DataClassification)
+	}
+}
+
+func TestScanAudience(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" partner\n")
+	a.True(l.scanAudience(api))
+	a.Equal(api.Audiences, []string{types.AudiencePartner})
+
+	api = &types.API{}
+	l = newLexerString(" public,partner,internal\n")
+	a.True(l.scanAudience(api))
+	a.Equal(api.Audiences, []string{types.AudiencePublic, types.AudiencePartner, types.AudienceInternal})
+
+	// 重复的标签
+	l = newLexerString(" internal\n")
+	a.False(l.scanAudience(api))
+	a.Equal(len(api.Audiences), 3)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " vip\n", " public,\n", " public,public\n", " public, partner\n"} {
+		l = newLexerString(v)
+		a.False(l.scanAudience(&types.API{}), v)
+	}
+}
+
+func TestScanFeatureFlag(t *testing.T) {
+	a := assert.New(t)
+
+	for _, provider := range []string{types.FeatureFlagLaunchDarkly, types.FeatureFlagStatsig, types.FeatureFlagCustom} {
+		api := &types.API{}
+		l := newLexerString(" new-checkout provider:" + provider + "\n")
+		a.True(l.scanFeatureFlag(api), provider)
+		a.Equal(api.FeatureFlag, &types.FeatureFlag{Name: "new-checkout", Provider: provider})
+	}
+
+	// 未指定提供方
+	api := &types.API{}
+	l := newLexerString(" new-checkout\n")
+	a.True(l.scanFeatureFlag(api))
+	a.Equal(api.FeatureFlag, &types.FeatureFlag{Name: "new-checkout"})
+
+	// 重复的标签
+	l = newLexerString(" other\n")
+	a.False(l.scanFeatureFlag(api))
+	a.Equal(api.FeatureFlag.Name, "new-checkout")
+
+	// 参数不正确
+	for _, v := range []string{" \n", " flag statsig\n", " flag provider:unleash\n", " flag provider:\n", " flag provider:custom desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanFeatureFlag(&types.API{}), v)
+	}
+}
+
+func TestParse_grpc(t *testing.T) {
+	a := assert.New(t)
+
+	warn := new(bytes.Buffer)
+	doc := types.NewDoc()
+	code := `
+@api get /users/{id} get user
+@apiGRPC UserService.GetUser
+@apiGroup users
+@apiParam id int 用户 ID
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Equal(warn.Len(), 0)
+	a.Equal(doc.Apis[0].GRPCMethod, "UserService.GetUser")
+
+	// 不在 @api 中的 @apiGRPC
+	doc = types.NewDoc()
+	code = `
+@apiGRPC UserService.DeleteUser
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 0)
+	a.True(strings.Contains(warn.String(), vars.APIGRPC)).
+		True(strings.Contains(warn.String(), vars.API+" "))
+}
+
+func TestScanTimeout(t *testing.T) {
+	a := assert.New(t)
+
+	for _, p := range []string{types.PercentileP50, types.PercentileP90, types.PercentileP99, types.PercentileMax} {
+		api := &types.API{}
+		l := newLexerString(" 500 " + p + "\n")
+		a.True(l.scanTimeout(api), p)
+		a.Equal(api.Timeout, &types.Timeout{Value: 500, Percentile: p})
+	}
+
+	// 默认为 max
+	api := &types.API{}
+	l := newLexerString(" 1000\n")
+	a.True(l.scanTimeout(api))
+	a.Equal(api.Timeout, &types.Timeout{Value: 1000, Percentile: types.PercentileMax})
+
+	// 重复的标签
+	l = newLexerString(" 200 p50\n")
+	a.False(l.scanTimeout(api))
+	a.Equal(api.Timeout.Value, 1000)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 0\n", " -1 p50\n", " 1.5\n", " 500ms\n", " 500 p95\n", " 500 p50 desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanTimeout(&types.API{}), v)
+	}
+
+	// 超过 maxTimeout 的值给出警告
+	warn := new(bytes.Buffer)
+	api = &types.API{}
+	l = newLexer(newInput([]rune(" 30000\n"), nil, log.New(warn, "", 0)))
+	a.True(l.scanTimeout(api))
+	a.Equal(warn.Len(), 0)
+
+	api = &types.API{}
+	l = newLexer(newInput([]rune(" 30001 p99\n"), nil, log.New(warn, "", 0)))
+	a.True(l.scanTimeout(api))
+	a.Equal(api.Timeout, &types.Timeout{Value: 30001, Percentile: types.PercentileP99})
+	a.True(strings.Contains(warn.String(), vars.APITimeout))
+}
+
+func TestScanMockDelay(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 50\n")
+	a.True(l.scanMockDelay(api))
+	a.Equal(api.MockDelay, 50)
+
+	// 重复的标签
+	l = newLexerString(" 100\n")
+	a.False(l.scanMockDelay(api))
+	a.Equal(api.MockDelay, 50)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 0\n", " -1\n", " 1.5\n", " 50ms\n", " 50 100\n"} {
+		l = newLexerString(v)
+		a.False(l.scanMockDelay(&types.API{}), v)
+	}
+}
+
+func TestScanThrottle(t *testing.T) {
+	a := assert.New(t)
+
+	for _, w := range []string{types.ThrottleWindowSecond, types.ThrottleWindowMinute, types.ThrottleWindowHour} {
+		api := &types.API{}
+		l := newLexerString(" 10 per " + w + "\n")
+		a.True(l.scanThrottle(api), w)
+		a.Equal(api.Throttle, &types.Throttle{Requests: 10, Window: w})
+	}
+
+	// 重复的标签
+	api := &types.API{Throttle: &types.Throttle{Requests: 10, Window: types.ThrottleWindowSecond}}
+	l := newLexerString(" 5 per minute\n")
+	a.False(l.scanThrottle(api))
+	a.Equal(api.Throttle.Requests, 10)
+
+	// 参数不正确
+	for _, v := range []string{
+		" \n", " 10\n", " 10 per\n", " 10 per second desc\n",
+		" 0 per second\n", " -1 per second\n", " 1.5 per second\n",
+		" 10 / second\n", " 10 per day\n", " 10 per seconds\n", " 10 per 1s\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanThrottle(&types.API{}), v)
+	}
+}
+
+func TestScanQuota(t *testing.T) {
+	a := assert.New(t)
+
+	for _, w := range []string{types.QuotaWindowDay, types.QuotaWindowMonth, types.QuotaWindowYear} {
+		api := &types.API{}
+		l := newLexerString(" 1000 per " + w + "\n")
+		a.True(l.scanQuota(api), w)
+		a.Equal(api.Quota, &types.Quota{Limit: 1000, Window: w})
+	}
+
+	for _, scope := range []string{types.QuotaScopeOrg, types.QuotaScopeUser, types.QuotaScopeKey} {
+		api := &types.API{}
+		l := newLexerString(" 1000 per month scope:" + scope + "\n")
+		a.True(l.scanQuota(api), scope)
+		a.Equal(api.Quota, &types.Quota{Limit: 1000, Window: types.QuotaWindowMonth, Scope: scope})
+	}
+
+	// 重复的标签
+	api := &types.API{Quota: &types.Quota{Limit: 1000, Window: types.QuotaWindowDay}}
+	l := newLexerString(" 5 per month\n")
+	a.False(l.scanQuota(api))
+	a.Equal(api.Quota.Limit, 1000)
+
+	// 参数不正确
+	for _, v := range []string{
+		" \n", " 1000\n", " 1000 per\n", " 0 per day\n", " 1.5 per day\n",
+		" 1000 / day\n", " 1000 per hour\n", " 1000 per days\n",
+		" 1000 per day org\n", " 1000 per day scope:team\n", " 1000 per day scope:\n",
+		" 1000 per day scope:org desc\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanQuota(&types.API{}), v)
+	}
+}
+
+func TestScanSLA(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 99.95 rpo:5m rto:1h30m\n")
+	a.True(l.scanSLA(api))
+	a.Equal(api.SLA, &types.SLA{Availability: 99.95, RPO: "5m", RTO: "1h30m"})
+
+	// 重复的标签
+	l = newLexerString(" 99.9\n")
+	a.False(l.scanSLA(api))
+	a.Equal(api.SLA.Availability, 99.95)
+
+	// rpo 和 rto 都是可选的，且不分先后
+	api = &types.API{}
+	l = newLexerString(" 100\n")
+	a.True(l.scanSLA(api))
+	a.Equal(api.SLA, &types.SLA{Availability: 100})
+
+	api = &types.API{}
+	l = newLexerString(" 99.9 rto:30s\n")
+	a.True(l.scanSLA(api))
+	a.Equal(api.SLA, &types.SLA{Availability: 99.9, RTO: "30s"})
+
+	api = &types.API{}
+	l = newLexerString(" 99.9 rto:30s rpo:0.5h\n")
+	a.True(l.scanSLA(api))
+	a.Equal(api.SLA, &types.SLA{Availability: 99.9, RPO: "0.5h", RTO: "30s"})
+
+	// 无效的百分比
+	for _, v := range []string{"0", "-1", "100.01", "101", "99.9%", "abc", "NaN"} {
+		l = newLexerString(" " + v + "\n")
+		a.False(l.scanSLA(&types.API{}), v)
+	}
+
+	// 无效的时间段
+	for _, v := range []string{"rpo:5", "rpo:", "rto:1d", "rto:-1m", "rto:0s", "rpo:abc", "rpo5m", ":5m", "mttr:5m"} {
+		l = newLexerString(" 99.9 " + v + "\n")
+		a.False(l.scanSLA(&types.API{}), v)
+	}
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 99.9 rpo:5m rpo:10m\n", " 99.9 rpo:5m rto:1h desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanSLA(&types.API{}), v)
+	}
+
+	// 低于 minSLAAvailability 的值给出警告
+	warn := new(bytes.Buffer)
+	l = newLexer(newInput([]rune(" 99\n"), nil, log.New(warn, "", 0)))
+	a.True(l.scanSLA(&types.API{}))
+	a.Equal(warn.Len(), 0)
+
+	l = newLexer(newInput([]rune(" 9.99\n"), nil, log.New(warn, "", 0)))
+	a.True(l.scanSLA(&types.API{}))
+	a.True(strings.Contains(warn.String(), vars.APISLA))
+}
+
+func TestScanMultipart(t *testing.T) {
+	a := assert.New(t)
+	doc := types.NewDoc()
+
+	warn := new(bytes.Buffer)
+	code := `
+@api post /avatars upload avatar
+@apiMultipart
+@apiRequest
+@apiParam title string 标题
+@apiParam avatar file 头像
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Empty(warn.String())
+	api := doc.Apis[0]
+	a.True(api.Multipart).
+		Equal(api.Request.Params, []*types.Param{
+			{Name: "title", Type: "string", Summary: "标题"},
+			{Name: "avatar", Type: "file", Summary: "头像"},
+		})
+
+	// 指定了 multipart/form-data
+	code = `
+@api post /avatars upload avatar
+@apiMultipart
+@apiRequest multipart/form-data
+@apiParam avatar file 头像
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 2).Empty(warn.String())
+
+	// 与 @apiRequest json 冲突，仅输出警告
+	code = `
+@api post /avatars upload avatar
+@apiMultipart
+@apiRequest application/json
+@apiParam avatar file 头像
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 3).
+		True(strings.Contains(warn.String(), vars.APIMultipart)).
+		True(strings.Contains(warn.String(), "application/json"))
+
+	// 重复的标签
+	l := newLexerString(" \n")
+	a.False(l.scanFlag(vars.APIMultipart, &api.Multipart))
+}
+
+func TestScanAccess(t *testing.T) {
+	a := assert.New(t)
+	doc := types.NewDoc()
+	warn := new(bytes.Buffer)
+
+	// 单个角色
+	code := `
+@api get /users get users
+@apiAccess admin
+@apiAuth token
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Empty(warn.String())
+	a.Equal(doc.Apis[0].AccessRoles, []string{"admin"}).
+		Empty(doc.Apis[0].AccessSummary)
+
+	// 多个角色，带描述
+	code = `
+@api delete /users/{id} delete user
+@apiAccess admin,editor,users:write 只读用户无法访问
+@apiAuth token
+@apiSuccess 204 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 2).Empty(warn.String())
+	a.Equal(doc.Apis[1].AccessRoles, []string{"admin", "editor", "users:write"}).
+		Equal(doc.Apis[1].AccessSummary, "只读用户无法访问")
+
+	// 只有 @apiAuth，没有 @apiAccess
+	code = `
+@api get /users/{id} get user
+@apiAuth token
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 3).Empty(warn.String())
+	a.Empty(doc.Apis[2].AccessRoles)
+
+	// 没有 @apiAuth，仅输出警告
+	code = `
+@api post /users create user
+@apiAccess admin
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 4).
+		True(strings.Contains(warn.String(), vars.APIAccess)).
+		True(strings.Contains(warn.String(), vars.APIAuth))
+
+	// 无效的值
+	for _, v := range []string{" \n", " admin,,editor\n", " admin,\n", " admin,admin\n"} {
+		l := newLexerString(v)
+		a.False(l.scanAccess(&types.API{}), v)
+	}
+
+	// 重复的标签
+	l := newLexerString(" editor\n")
+	a.False(l.scanAccess(doc.Apis[0]))
+}
+
+func TestScanCalls(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(` post /orders 创建订单
+@apiCalls get /users/{id}
+@apiCalls POST /payments?async=true
+@apiSuccess 201 OK
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Calls, []types.Endpoint{
+		{Method: "GET", URL: "/users/{id}"},
+		{Method: "POST", URL: "/payments"},
+	})
+
+	// 重复的值
+	l = newLexerString(" GET /users/{id}\n")
+	a.False(l.scanCalls(api))
+
+	// 参数不正确
+	for _, v := range []string{" \n", " GET\n", " GET /users /orders\n"} {
+		l = newLexerString(v)
+		a.False(l.scanCalls(&types.API{}), v)
+	}
+}
+
+func TestScanDiscriminator(t *testing.T) {
+	a := assert.New(t)
+	doc := types.NewDoc()
+
+	code := `
+@api post /pets create pet
+@apiRequest json
+@apiParam kind string 类型
+@apiParam name string 名称
+@apiDiscriminator kind dog:Dog, cat:#/types/Cat
+@apiSuccess 200 OK
+@apiParam kind string 类型
+@apiDiscriminator kind
+`
+	Parse(&Input{Data: []rune(code)}, doc)
+	a.Equal(len(doc.Apis), 1)
+	api := doc.Apis[0]
+	a.Equal(api.Request.Discriminator, &types.Discriminator{
+		Property: "kind",
+		Mapping:  map[string]string{"dog": "Dog", "cat": "#/types/Cat"},
+	})
+	a.Equal(api.Success.Discriminator, &types.Discriminator{Property: "kind"})
+
+	// 重复的标签
+	d := &types.Discriminator{Property: "kind"}
+	l := newLexerString(" type\n")
+	a.False(l.scanDiscriminator(&d))
+	a.Equal(d.Property, "kind")
+
+	// 重复的映射关系
+	d = nil
+	l = newLexerString(" kind dog:Dog,dog:Cat\n")
+	a.False(l.scanDiscriminator(&d))
+	a.Nil(d)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " kind dog\n", " kind :Dog\n", " kind dog:\n", " kind dog:Dog\ncat:Cat\n"} {
+		d = nil
+		l = newLexerString(v)
+		a.False(l.scanDiscriminator(&d), v)
+	}
+}
+
+func TestScanNullable(t *testing.T) {
+	a := assert.New(t)
+	doc := types.NewDoc()
+	warn := new(bytes.Buffer)
+
+	code := `
+@api put /users/{id} update user
+@apiParam id int 用户 ID
+@apiQuery fields string 返回的字段
+@apiNullable nickname
+@apiNullable fields
+@apiRequest json
+@apiParam nickname string 昵称
+@apiParam name string 名称
+@apiSuccess 200 OK
+@apiParam nickname string 昵称
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Empty(warn.String())
+	api := doc.Apis[0]
+	a.False(api.Params[0].Nullable).
+		True(api.Queries[0].Nullable).
+		True(api.Request.Params[0].Nullable).
+		False(api.Request.Params[1].Nullable).
+		True(api.Success.Params[0].Nullable)
+
+	// URL 参数，仅输出警告
+	code = `
+@api get /users/{id} get user
+@apiParam id int 用户 ID
+@apiNullable id
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 2).
+		True(strings.Contains(warn.String(), vars.APINullable)).
+		True(doc.Apis[1].Params[0].Nullable)
+
+	// 不存在的参数，仅输出警告
+	warn.Reset()
+	code = `
+@api get /users get users
+@apiNullable not-exists
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 3).
+		True(strings.Contains(warn.String(), "not-exists"))
+
+	// 重复的参数
+	names := []string{"name"}
+	l := newLexerString(" name\n")
+	a.False(l.scanNullable(&names))
+
+	// 参数不正确
+	for _, v := range []string{" \n", " name nickname\n"} {
+		l = newLexerString(v)
+		a.False(l.scanNullable(&[]string{}), v)
+	}
+}
+
+func TestScanFormat(t *testing.T) {
+	a := assert.New(t)
+
+	formats := []string{"int32", "int64", "float", "double", "byte", "binary", "date", "date-time", "time", "password", "email", "hostname", "ipv4", "ipv6", "uri", "uuid", "x-phone"}
+	api := &types.API{}
+	for _, format := range formats {
+		l := newLexerString(" " + format + " " + format + "\n")
+		a.True(l.scanFormat(api), format)
+		a.Equal(api.Formats[format], format)
+	}
+	a.Equal(len(api.Formats), len(formats))
+
+	// 重复的参数
+	l := newLexerString(" email uri\n")
+	a.False(l.scanFormat(api))
+	a.Equal(api.Formats["email"], "email")
+
+	// 无效的格式
+	for _, v := range []string{" id integer\n", " id Email\n", " id x\n"} {
+		l = newLexerString(v)
+		a.False(l.scanFormat(&types.API{}), v)
+	}
+
+	// 参数不正确
+	for _, v := range []string{" \n", " id\n", " id uuid uuid\n"} {
+		l = newLexerString(v)
+		a.False(l.scanFormat(&types.API{}), v)
+	}
+
+	l = newLexerString(` get /users/{id} 获取用户
+@apiParam id string 用户 ID
+@apiFormat id uuid
+@apiSuccess 200 OK
+@apiParam email string 邮箱
+@apiFormat email email
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Formats, map[string]string{"id": "uuid", "email": "email"})
+}
+
+func TestScanOrder(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" 5\n")
+	a.True(l.scanOrder(api))
+	a.Equal(api.Order, 5)
+
+	// 重复的标签
+	l = newLexerString(" 6\n")
+	a.False(l.scanOrder(api))
+	a.Equal(api.Order, 5)
+
+	api = &types.API{}
+	l = newLexerString(" \n")
+	a.False(l.scanOrder(api))
+
+	l = newLexerString(" 1 2\n")
+	a.False(l.scanOrder(api))
+
+	l = newLexerString(" 0\n")
+	a.False(l.scanOrder(api))
+
+	l = newLexerString(" -1\n")
+	a.False(l.scanOrder(api))
+
+	l = newLexerString(" first\n")
+	a.False(l.scanOrder(api))
+	a.Equal(api.Order, 0)
+}
+
+func TestScanOwner(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(` get /users 获取用户列表
+@apiOwner Platform Engineering platform@example.com
+@apiSuccess 200 OK
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Owner, &types.Owner{Team: "Platform Engineering", Email: "platform@example.com"})
+
+	// 重复的 @apiOwner
+	l = newLexerString(" platform\n")
+	a.False(l.scanOwner(api))
+
+	// 不带 email
+	api = &types.API{}
+	l = newLexerString(" Platform Engineering\n")
+	a.True(l.scanOwner(api))
+	a.Equal(api.Owner, &types.Owner{Team: "Platform Engineering"})
+
+	// 合法的 email
+	for _, email := range []string{"a@b", "first.last+tag@sub.example.com", "o'neil@example.com"} {
+		api = &types.API{}
+		l = newLexerString(" platform " + email + "\n")
+		a.True(l.scanOwner(api), email).Equal(api.Owner.Email, email)
+	}
+
+	// 无效的 email
+	for _, email := range []string{"@example.com", "platform@", "a@b@c", "a@-example.com", "a@example..com"} {
+		l = newLexerString(" platform " + email + "\n")
+		a.False(l.scanOwner(&types.API{}), email)
+	}
+
+	// 缺少团队名称
+	l = newLexerString(" \n")
+	a.False(l.scanOwner(&types.API{}))
+}
+
+func TestScanMetric(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(` get /users 获取用户列表
+@apiMetric p99-latency 200 ms
+@apiMetric   error-budget	0.1   %
+@apiSuccess 200 OK
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Metrics, []*types.Metric{
+		{Name: "p99-latency", Value: 200, Unit: "ms"},
+		{Name: "error-budget", Value: 0.1, Unit: "%"},
+	})
+
+	// 重复的名称
+	l = newLexerString(" p99-latency 100 ms\n")
+	a.False(l.scanMetric(api))
+	a.Equal(len(api.Metrics), 2)
+
+	// 值不是数值
+	l = newLexerString(" throughput fast rps\n")
+	a.False(l.scanMetric(&types.API{}))
+
+	l = newLexerString(" throughput 1,000 rps\n")
+	a.False(l.scanMetric(&types.API{}))
+
+	// 缺少单位
+	l = newLexerString(" throughput 1000\n")
+	a.False(l.scanMetric(&types.API{}))
+
+	l = newLexerString(" throughput 1000 rps more\n")
+	a.False(l.scanMetric(&types.API{}))
 }
 
-func TestScanAPIParam(t *testing.T) {
+func TestScanContract(t *testing.T) {
 	a := assert.New(t)
 
-	// 正常语法测试
-	l := newLexerString("id int optional 用户 id号\n")
-	p, ok := l.scanAPIParam(vars.APIQuery)
-	a.True(ok).NotNil(p)
-	a.Equal(p.Name, "id").
-		Equal(p.Type, "int").
-		Equal(p.Summary, "optional 用户 id号")
+	api := &types.API{}
+	l := newLexerString(" users https://pact.example.com/pacts/provider/users/latest\n")
+	a.True(l.scanContract(api))
+	l = newLexerString(" orders http://localhost:9292/pacts/orders\n")
+	a.True(l.scanContract(api))
+	a.Equal(api.Contracts, []*types.Contract{
+		{Suite: "users", URL: "https://pact.example.com/pacts/provider/users/latest"},
+		{Suite: "orders", URL: "http://localhost:9292/pacts/orders"},
+	})
 
-	// 缺少参数
-	l = newLexerString("id int \n")
-	p, ok = l.scanAPIParam(vars.APIQuery)
-	a.False(ok).Nil(p)
+	// 重复的测试套件
+	l = newLexerString(" users https://pact.example.com/users\n")
+	a.False(l.scanContract(api))
+	a.Equal(len(api.Contracts), 2)
 
-	// 缺少参数
-	l = newLexerString("id  \n")
-	p, ok = l.scanAPIParam(vars.APIQuery)
-	a.False(ok).Nil(p)
+	// 无效的地址
+	for _, v := range []string{"pact.example.com/users", "/pacts/users", "https://", "://example.com", "users"} {
+		l = newLexerString(" users " + v + "\n")
+		a.False(l.scanContract(&types.API{}), v)
+	}
+
+	// 参数不正确
+	for _, v := range []string{" \n", " users\n", " users https://example.com desc\n"} {
+		l = newLexerString(v)
+		a.False(l.scanContract(&types.API{}), v)
+	}
 }
 
-func TestScanAPI(t *testing.T) {
+func TestScanLinkTo(t *testing.T) {
 	a := assert.New(t)
 
-	// 正常情况
-	l := newLexerString(" get test.com/api.json?k=1 summary summary\n api description\n@apiSuccess 200 OK")
-
+	l := newLexerString(` get /users 获取用户列表
+@apiLinkTo https://tools.ietf.org/html/rfc7231 RFC 7231
+@apiLinkTo https://example.com/issues/1
+@apiSuccess 200 OK
+`)
 	api, ok := l.scanAPI()
 	a.True(ok).NotNil(api)
-	a.Equal(api.Method, "get").
-		Equal(api.URL, "test.com/api.json?k=1").
-		Equal(api.Summary, "summary summary").
-		Equal(api.Description, "api description")
+	a.Equal(api.Links, []*types.Link{
+		{URL: "https://tools.ietf.org/html/rfc7231", Label: "RFC 7231"},
+		{URL: "https://example.com/issues/1"},
+	})
 
-	// 多行description
-	l = newLexerString(" post test.com/api.json?K=1  summary summary\n api \ndescription\n@apiSuccess 400 summary")
-	api, ok = l.scanAPI()
-	a.True(ok)
-	a.Equal(api.URL, "test.com/api.json?K=1").
-		Equal(api.Method, "post").
-		Equal(api.Summary, "summary summary").
-		Equal(api.Description, "api \ndescription")
+	// 缺少 url
+	l = newLexerString(" \n")
+	a.False(l.scanLinkTo(&types.API{}))
 
-	// 缺少description参数
-	l = newLexerString("get test.com/api.json summary summary \n@apiSuccess 400 error")
-	api, ok = l.scanAPI()
+	// 无法解析的 url
+	l = newLexerString(" http://[::1 ipv6\n")
+	a.False(l.scanLinkTo(&types.API{}))
+
+	l = newLexerString(" https://example.com/%zz label\n")
+	a.False(l.scanLinkTo(&types.API{}))
+}
+
+func TestScanTodo(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(` get /users 获取用户列表
+@apiTodo 补充查询参数
+@apiTodo 补充返回值的示例
+@apiSuccess 200 OK
+`)
+	api, ok := l.scanAPI()
 	a.True(ok).NotNil(api)
-	a.Equal(api.Method, "get").
-		Equal(api.URL, "test.com/api.json").
-		Equal(api.Summary, "summary summary").
-		Equal(api.Description, "")
+	a.Equal(api.Todos, []string{"补充查询参数", "补充返回值的示例"})
 
-	// 缺少description参数
-	l = newLexerString("get test.com/api.json summary summary\n \n@apiSuccess 400 error")
+	// 缺少内容
+	l = newLexerString(` get /users 获取用户列表
+@apiTodo
+@apiSuccess 200 OK
+`)
 	api, ok = l.scanAPI()
+	a.False(ok).Nil(api)
+}
+
+func TestScanThrows(t *testing.T) {
+	a := assert.New(t)
+
+	l := newLexerString(` get /users/{id} 获取用户
+@apiThrows NOT_FOUND 用户不存在
+@apiThrows 40301 用户已被禁用
+@apiSuccess 200 OK
+`)
+	api, ok := l.scanAPI()
 	a.True(ok).NotNil(api)
-	a.Equal(api.Method, "get").
-		Equal(api.URL, "test.com/api.json").
-		Equal(api.Summary, "summary summary").
-		Equal(api.Description, "")
+	a.Equal(api.ErrorCodes, []*types.ErrorCode{
+		{Code: "NOT_FOUND", Summary: "用户不存在"},
+		{Code: "40301", Summary: "用户已被禁用"},
+	})
 
-	// 没有任何参数
-	l = newLexerString("  ")
+	// 重复的错误代码
+	l = newLexerString(" NOT_FOUND 找不到\n")
+	a.False(l.scanThrows(api))
+	a.Equal(len(api.ErrorCodes), 2)
+
+	// 空的错误代码
+	l = newLexerString(` get /users/{id} 获取用户
+@apiThrows
+@apiSuccess 200 OK
+`)
 	api, ok = l.scanAPI()
 	a.False(ok).Nil(api)
+
+	// 缺少描述
+	l = newLexerString(" NOT_FOUND\n")
+	a.False(l.scanThrows(&types.API{}))
+
+	// 包含非法字符
+	l = newLexerString(" not-found 用户不存在\n")
+	a.False(l.scanThrows(&types.API{}))
 }
 
-func TestScanAPIDoc(t *testing.T) {
+func TestScanCallback(t *testing.T) {
 	a := assert.New(t)
 
-	code := ` title of apidoc
-@apiVersion 2.0.1
-@apiBaseURL https://api.caixw.io
-@apiLicense MIT https://opensource.org/licenses/MIT
-@apiContent
-line1
-line2`
+	// 注册 webhook 的 API，服务端在事件发生时回调 callbackUrl
+	l := newLexerString(` post /webhooks 注册 webhook
+@apiCallback onEvent {$request.body#/callbackUrl} post /events
+@apiSuccess 201 created
+`)
+	api, ok := l.scanAPI()
+	a.True(ok).NotNil(api)
+	a.Equal(api.Callbacks, []*types.Callback{
+		{Name: "onEvent", Expression: "{$request.body#/callbackUrl}", Method: "post", Path: "/events"},
+	})
 
-	l := newLexerString(code)
-	a.NotNil(l)
-	d := &types.Doc{}
+	l = newLexerString(" onError https://example.com?id={$request.query.id} post /errors\n")
+	a.True(l.scanCallback(api))
+	a.Equal(len(api.Callbacks), 2)
 
-	a.NotError(l.scanAPIDoc(d))
-	a.Equal(d.Version, "2.0.1").
-		Equal(d.Title, "title of apidoc").
-		Equal(d.BaseURL, "https://api.caixw.io").
-		Equal(d.LicenseName, "MIT").
-		Equal(d.LicenseURL, "https://opensource.org/licenses/MIT").
-		Equal(d.Content, "\nline1\nline2")
+	// 重复的名称
+	l = newLexerString(" onEvent {$url} post /events\n")
+	a.False(l.scanCallback(api))
 
-		// 重复内容，报错
-	code = `title of apidoc2
-		@apiBaseURL http://api.caixw.io
-line1
-line2`
-	l = newLexerString(code)
-	a.False(l.scanAPIDoc(d))
+	l = newLexerString(" onEvent {$request.body#/callbackUrl} post\n")
+	a.False(l.scanCallback(api))
 
-	// 检测各个都是空值的情况
-	code = `2.9 title of apidoc
+	l = newLexerString(" onEvent {$request.body#/callbackUrl} post /events more\n")
+	a.False(l.scanCallback(api))
+
+	// 无效的运行时表达式
+	l = newLexerString(" onOther {$request.cookie.url} post /events\n")
+	a.False(l.scanCallback(api))
+	l = newLexerString(" onOther https://example.com post /events\n")
+	a.False(l.scanCallback(api))
+	a.Equal(len(api.Callbacks), 2)
+}
+
+func TestScanAuthError(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" 401 AuthError 未登录或是 token 已经过期\n")
+	a.True(l.scanAuthError(api))
+	l = newLexerString(" 403 PermissionError 没有访问权限\n")
+	a.True(l.scanAuthError(api))
+	a.Equal(api.AuthErrors, []*types.AuthError{
+		{Code: "401", Schema: "AuthError", Summary: "未登录或是 token 已经过期"},
+		{Code: "403", Schema: "PermissionError", Summary: "没有访问权限"},
+	})
+
+	// 重复的状态码
+	l = newLexerString(" 401 Error desc\n")
+	a.False(l.scanAuthError(api))
+	a.Equal(len(api.AuthErrors), 2)
+
+	// 只能是 401 和 403
+	for _, code := range []string{"400", "404", "500", "4xx", "abc"} {
+		l = newLexerString(" " + code + " Error desc\n")
+		a.False(l.scanAuthError(&types.API{}), code)
+	}
+
+	// 参数不正确
+	for _, v := range []string{" \n", " 401\n", " 401 Error\n", " 401 Error desc\n line2\n"} {
+		l = newLexerString(v)
+		a.False(l.scanAuthError(&types.API{}), v)
+	}
+}
+
+func TestScanConflict(t *testing.T) {
+	a := assert.New(t)
+
+	// 单个冲突
+	api := &types.API{}
+	l := newLexerString(" version-mismatch ConflictError 数据已经被其它请求修改\n")
+	a.True(l.scanConflict(api))
+	a.Equal(api.Conflicts, []*types.Conflict{
+		{Condition: "version-mismatch", Schema: "ConflictError", Summary: "数据已经被其它请求修改"},
+	})
+
+	// 多个冲突
+	l = newLexerString(" duplicate-email UniqueError 邮箱已经被注册\n")
+	a.True(l.scanConflict(api))
+	a.Equal(len(api.Conflicts), 2)
+	a.Equal(api.Conflicts[1], &types.Conflict{Condition: "duplicate-email", Schema: "UniqueError", Summary: "邮箱已经被注册"})
+
+	// 重复的 condition
+	l = newLexerString(" version-mismatch Error desc\n")
+	a.False(l.scanConflict(api))
+	a.Equal(len(api.Conflicts), 2)
+
+	// 参数不正确
+	for _, v := range []string{" \n", " version-mismatch\n", " version-mismatch Error\n", " version-mismatch Error desc\n line2\n"} {
+		l = newLexerString(v)
+		a.False(l.scanConflict(&types.API{}), v)
+	}
+}
+
+func TestScanRetry(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" exponential-backoff maxAttempts:3 backoff:exponential retryOn:502,503\n")
+	a.True(l.scanRetry(api))
+	a.Equal(api.Retry, &types.Retry{
+		Strategy:    "exponential-backoff",
+		MaxAttempts: 3,
+		Backoff:     types.BackoffExponential,
+		RetryOn:     []int{502, 503},
+	})
+
+	// 重复的标签
+	l = newLexerString(" always\n")
+	a.False(l.scanRetry(api))
+
+	// 选项顺序无关，且可以省略
+	api = &types.API{}
+	l = newLexerString(" always backoff:linear maxAttempts:5\n")
+	a.True(l.scanRetry(api))
+	a.Equal(api.Retry, &types.Retry{Strategy: "always", MaxAttempts: 5, Backoff: types.BackoffLinear})
+
+	api = &types.API{}
+	l = newLexerString(" never\n")
+	a.True(l.scanRetry(api))
+	a.Equal(api.Retry, &types.Retry{Strategy: "never"})
+
+	for _, v := range []string{
+		" \n",
+		" always maxAttempts\n",
+		" always maxAttempts:\n",
+		" always maxAttempts:0\n",
+		" always maxAttempts:x\n",
+		" always backoff:random\n",
+		" always retryOn:50x\n",
+		" always retryOn:200,\n",
+		" always retryOn:600\n",
+		" always timeout:5\n",
+		" always maxAttempts:3 maxAttempts:4\n",
+	} {
+		api = &types.API{}
+		l = newLexerString(v)
+		a.False(l.scanRetry(api), v)
+		a.Nil(api.Retry)
+	}
+}
+
+func TestScanCORS(t *testing.T) {
+	a := assert.New(t)
+
+	api := &types.API{}
+	l := newLexerString(" origin:https://*.example.com methods:get,Post headers:Content-Type,X-Request-ID maxAge:600\n")
+	a.True(l.scanCORS(api))
+	a.Equal(api.CORSPolicy, &types.CORSPolicy{
+		Origin:  "https://*.example.com",
+		Methods: []string{"GET", "POST"},
+		Headers: []string{"Content-Type", "X-Request-ID"},
+		MaxAge:  600,
+	})
+
+	// 重复的标签
+	l = newLexerString(" origin:*\n")
+	a.False(l.scanCORS(api))
+	a.Equal(api.CORSPolicy.Origin, "https://*.example.com")
+
+	// 各参数都是可选的
+	for v, policy := range map[string]*types.CORSPolicy{
+		" \n":                      {},
+		" origin:*\n":              {Origin: "*"},
+		" methods:PUT\n":           {Methods: []string{"PUT"}},
+		" headers:Authorization\n": {Headers: []string{"Authorization"}},
+		" maxAge:86400\n":          {MaxAge: 86400},
+		" maxAge:1 origin:*\n":     {Origin: "*", MaxAge: 1},
+	} {
+		api = &types.API{}
+		l = newLexerString(v)
+		a.True(l.scanCORS(api), v)
+		a.Equal(api.CORSPolicy, policy, v)
+	}
+
+	// 无效的值
+	for _, v := range []string{
+		" origin:\n", " origin\n", " :*\n", " credentials:true\n",
+		" methods:GET,,POST\n", " methods:GET,\n", " headers:,X-Token\n",
+		" maxAge:0\n", " maxAge:-1\n", " maxAge:1h\n",
+		" origin:* origin:https://example.com\n",
+	} {
+		l = newLexerString(v)
+		a.False(l.scanCORS(&types.API{}), v)
+	}
+}
+
+func TestCheckCORS(t *testing.T) {
+	a := assert.New(t)
+	doc := types.NewDoc()
+	warn := new(bytes.Buffer)
+
+	// 限定来源时可以与 @apiAuth 同时使用
+	code := `
+@api get /users get users
+@apiCORS origin:https://example.com
+@apiAuth token
+@apiSuccess 200 OK
 `
-	l = newLexerString(code)
-	a.NotNil(l)
-	d = &types.Doc{}
-	a.NotError(l.scanAPIDoc(d))
-	a.Equal(d.Title, "2.9 title of apidoc").
-		Equal(d.Version, "").
-		Equal(d.BaseURL, "").
-		Equal(d.LicenseName, "").
-		Equal(d.LicenseURL, "")
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 1).Empty(warn.String())
+
+	// 没有 @apiAuth 时可以允许任意来源
+	code = `
+@api get /articles get articles
+@apiCORS origin:*
+@apiSuccess 200 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 2).Empty(warn.String())
+
+	// 同时使用，输出警告
+	code = `
+@api post /users create user
+@apiCORS origin:* methods:POST
+@apiAuth token
+@apiSuccess 201 OK
+`
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(len(doc.Apis), 3).
+		True(strings.Contains(warn.String(), vars.APICORS)).
+		True(strings.Contains(warn.String(), vars.APIAuth))
+}
+
+func TestScanCacheControl(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" max-age:3600 vary:Accept,Accept-Encoding\n")
+	a.True(l.scanCacheControl(api))
+	a.Equal(api.CachePolicy, &types.CachePolicy{MaxAge: 3600, Vary: []string{"Accept", "Accept-Encoding"}})
+
+	l = newLexerString(" no-cache\n")
+	a.True(l.scanCacheControl(api))
+	l = newLexerString(" no-store\n")
+	a.True(l.scanCacheControl(api))
+	l = newLexerString(" private vary:Cookie\n")
+	a.True(l.scanCacheControl(api))
+	l = newLexerString(" public\n")
+	a.True(l.scanCacheControl(api))
+	a.Equal(api.CachePolicy, &types.CachePolicy{
+		MaxAge:  3600,
+		NoCache: true,
+		NoStore: true,
+		Private: true,
+		Public:  true,
+		Vary:    []string{"Accept", "Accept-Encoding", "Cookie"},
+	})
+
+	// 重复的指令
+	for _, v := range []string{" max-age:60\n", " no-cache\n", " no-store\n", " private\n", " public\n"} {
+		l = newLexerString(v)
+		a.False(l.scanCacheControl(api), v)
+	}
+
+	for _, v := range []string{
+		" \n",
+		" max-age\n",
+		" max-age:0\n",
+		" max-age:x\n",
+		" must-revalidate\n",
+		" no-cache Accept\n",
+		" no-cache vary:\n",
+		" no-cache vary:Accept,\n",
+		" no-cache vary:Accept more\n",
+	} {
+		api = &types.API{}
+		l = newLexerString(v)
+		a.False(l.scanCacheControl(api), v)
+		a.Nil(api.CachePolicy)
+	}
+}
+
+func TestScanTryIt(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" https://api.caixw.io token:abc\n")
+	a.True(l.scanTryIt(api))
+	a.Equal(api.TryIt, &types.TryIt{BaseURL: "https://api.caixw.io", Token: "abc"})
+
+	// 重复的标签
+	l = newLexerString(" https://api.caixw.io\n")
+	a.False(l.scanTryIt(api))
+
+	api = &types.API{}
+	l = newLexerString(" http://localhost:8080\n")
+	a.True(l.scanTryIt(api))
+	a.Equal(api.TryIt, &types.TryIt{BaseURL: "http://localhost:8080"})
+
+	api = &types.API{}
+	l = newLexerString(" \n")
+	a.False(l.scanTryIt(api))
+
+	l = newLexerString(" not-url\n")
+	a.False(l.scanTryIt(api))
+
+	l = newLexerString(" https://api.caixw.io abc\n")
+	a.False(l.scanTryIt(api))
+
+	l = newLexerString(" https://api.caixw.io token:\n")
+	a.False(l.scanTryIt(api))
+
+	l = newLexerString(" https://api.caixw.io token:abc def\n")
+	a.False(l.scanTryIt(api))
+	a.Nil(api.TryIt)
+}
+
+func TestScanContentType(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" 200 application/json\n")
+	a.True(l.scanContentType(api))
+	l = newLexerString(" 200 application/xml\n")
+	a.True(l.scanContentType(api))
+	l = newLexerString(" 404 text/plain\n")
+	a.True(l.scanContentType(api))
+	a.Equal(api.ContentTypes, map[string][]string{
+		"200": {"application/json", "application/xml"},
+		"404": {"text/plain"},
+	})
+
+	// 无效的内容类型，仅作警告
+	warn := new(bytes.Buffer)
+	l = newLexer(newInput([]rune(" 500 json\n"), nil, log.New(warn, "", 0)))
+	a.True(l.scanContentType(api))
+	a.Equal(api.ContentTypes["500"], []string{"json"}).
+		True(strings.Contains(warn.String(), "json"))
+
+	// 参数不够
+	l = newLexerString(" 200\n")
+	a.False(l.scanContentType(api))
+
+	// 参数太多
+	l = newLexerString(" 200 application/json text/plain\n")
+	a.False(l.scanContentType(api))
+
+	// 无效的状态码
+	l = newLexerString(" ok application/json\n")
+	a.False(l.scanContentType(api))
+}
+
+func TestScanExtension(t *testing.T) {
+	a := assert.New(t)
+	api := &types.API{}
+
+	l := newLexerString(" x-string \"abc\"\n")
+	a.True(l.scanExtension(api))
+	l = newLexerString(" x-number 100\n")
+	a.True(l.scanExtension(api))
+	l = newLexerString(" x-bool true\n")
+	a.True(l.scanExtension(api))
+	l = newLexerString(" x-object {\n\"lang\": \"go\",\n\"source\": \"\"\n}\n")
+	a.True(l.scanExtension(api))
+	a.Equal(len(api.Extensions), 4).
+		Equal(string(api.Extensions["x-string"]), `"abc"`).
+		Equal(string(api.Extensions["x-number"]), "100").
+		Equal(string(api.Extensions["x-bool"]), "true").
+		Equal(string(api.Extensions["x-object"]), "{\n\"lang\": \"go\",\n\"source\": \"\"\n}")
+
+	// 缺少参数
+	l = newLexerString(" x-empty\n")
+	a.False(l.scanExtension(api))
+
+	// 名称不以 x- 开头
+	l = newLexerString(" name 100\n")
+	a.False(l.scanExtension(api))
+
+	// 非 JSON 内容
+	l = newLexerString(" x-invalid abc\n")
+	a.False(l.scanExtension(api))
+
+	// 重复的名称
+	l = newLexerString(" x-bool false\n")
+	a.False(l.scanExtension(api))
+	a.Equal(string(api.Extensions["x-bool"]), "true")
 }
 
 func TestScanAPIRequest(t *testing.T) {
@@ -418,6 +3173,164 @@ ab @apiIgnore
 	Parse(&Input{Data: []rune(code)}, doc)
 	a.Equal(l+1, len(doc.Apis))
 
+	// @apiProduces 和 @apiConsumes
+	code = `
+@api post /users create user
+@apiProduces application/json
+@apiProduces application/xml,text/plain
+@apiConsumes application/json
+@apiSuccess 200 OK
+@apiExample json
+{"id":1}
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code)}, doc)
+	a.Equal(l+1, len(doc.Apis))
+	api := doc.Apis[l]
+	a.Equal(api.Produces, []string{"application/json", "application/xml", "text/plain"}).
+		Equal(api.Consumes, []string{"application/json"})
+
+	// @apiProduces 与示例类型不一致，仅输出警告
+	warn := new(bytes.Buffer)
+	code = `
+@api post /users create user
+@apiProduces application/xml
+@apiSuccess 200 OK
+@apiExample json
+{"id":1}
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		True(strings.Contains(warn.String(), vars.APIProduces))
+
+	// @apiContentType 为不同的状态码指定不同的内容类型
+	warn.Reset()
+	code = `
+@api get /users/{id} get user
+@apiProduces application/json
+@apiContentType 200 application/json
+@apiContentType 404 text/plain
+@apiSuccess 200 OK
+@apiExample json
+{"id":1}
+@apiError 404 not found
+@apiExample text/plain
+not found
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		Empty(warn.String())
+	api = doc.Apis[l]
+	a.Equal(api.ContentTypes["200"], []string{"application/json"}).
+		Equal(api.ContentTypes["404"], []string{"text/plain"})
+
+	// @apiResponseHeader 不会影响请求的报头和参数
+	code = `
+@api post /users create user
+@apiRequest json
+@apiHeader Content-Type application/json
+@apiParam name string 用户名
+@apiResponseHeader 201 Location string 新资源的地址
+@apiResponseHeader * X-Request-ID string 请求的 ID
+@apiSuccess 201 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code)}, doc)
+	a.Equal(l+1, len(doc.Apis))
+	api = doc.Apis[l]
+	a.Equal(len(api.Request.Params), 1).
+		Equal(len(api.Request.Headers), 1).
+		Equal(len(api.ResponseHeadersOf("201")), 2).
+		Equal(len(api.ResponseHeadersOf("400")), 1)
+
+	// @apiSafe 和 @apiIdempotent
+	warn.Reset()
+	code = `
+@api get /users get users
+@apiSafe
+@apiIdempotent
+@apiSuccess 200 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		Empty(warn.String())
+	api = doc.Apis[l]
+	a.True(api.Safe).True(api.Idempotent)
+
+	// POST 使用 @apiSafe，仅输出警告
+	code = `
+@api post /users create user
+@apiSafe
+@apiSuccess 201 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		True(strings.Contains(warn.String(), vars.APISafe))
+
+	// DELETE 使用 @apiIdempotent 不会有警告
+	warn.Reset()
+	code = `
+@api delete /users/1 delete user
+@apiIdempotent
+@apiSuccess 204 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		Empty(warn.String())
+
+	// 未标记 @apiIdempotent 的 API 使用 @apiRetry，仅输出警告
+	code = `
+@api post /orders create order
+@apiRetry always maxAttempts:3
+@apiSuccess 201 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		True(strings.Contains(warn.String(), vars.APIRetry))
+
+	warn.Reset()
+	code = `
+@api put /orders/1 update order
+@apiIdempotent
+@apiRetry always backoff:exponential retryOn:503
+@apiSuccess 200 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		Empty(warn.String())
+
+	// @apiCacheControl 中相互矛盾的指令，仅输出警告
+	warn.Reset()
+	code = `
+@api get /news list news
+@apiCacheControl no-store
+@apiCacheControl max-age:60
+@apiSuccess 200 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		True(strings.Contains(warn.String(), vars.APICacheControl))
+
+	warn.Reset()
+	code = `
+@api get /news/1 get news
+@apiCacheControl max-age:60 vary:Accept
+@apiCacheControl public
+@apiSuccess 200 OK
+`
+	l = len(doc.Apis)
+	Parse(&Input{Data: []rune(code), Warn: log.New(warn, "", 0)}, doc)
+	a.Equal(l+1, len(doc.Apis)).
+		Empty(warn.String())
+
 	// @apiIgno 不认识的标签，会被过滤
 	code = `
 @api delete /admin/users/{id} delete users
@@ -28,7 +28,7 @@ function login(w, r) {
 	// TODO
 }
 
-/* @api DELETE /users/login 注销登录
+/** @api DELETE /users/login 注销登录
 @apiGroup users
 
 @apiRequest json
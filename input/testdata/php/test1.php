@@ -27,7 +27,7 @@ function login(ResponseWriter $w, Request $r) {
 	// TODO
 }
 
-/* @api DELETE /users/login 注销登录
+/** @api DELETE /users/login 注销登录
 @apiGroup users
 
 @apiRequest json
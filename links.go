@@ -0,0 +1,124 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/types"
+)
+
+// 检测 wd 中配置的文档中的链接是否都可以访问，返回是否全部可以访问。
+//
+// 每个地址只检测一次，以 HEAD 请求访问，超过 timeout 或是返回值不为 2xx 的都视为无法访问；
+// ignore 不为空时，与该正则表达式匹配的地址不作检测。无法访问的地址及原因以表格的形式输出到 w。
+func checkLinks(w io.Writer, wd string, timeout time.Duration, ignore string) bool {
+	if timeout <= 0 {
+		erro.Println(locale.Sprintf(locale.FlagInvalidLinkTimeout, timeout))
+		return false
+	}
+
+	var pattern *regexp.Regexp
+	if len(ignore) > 0 {
+		var err error
+		if pattern, err = regexp.Compile(ignore); err != nil {
+			erro.Println(locale.Sprintf(locale.FlagInvalidIgnoreLinks, err))
+			return false
+		}
+	}
+
+	cfg, err := load(wd)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	docs, _ := input.Parse(cfg.Inputs...)
+	links := make([]string, 0, 10)
+	for _, link := range collectLinks(docs) {
+		if pattern == nil || !pattern.MatchString(link) {
+			links = append(links, link)
+		}
+	}
+
+	failed, err := writeUnreachableLinks(w, &http.Client{Timeout: timeout}, links)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	if failed > 0 {
+		erro.Println(locale.Sprintf(locale.ErrLinksUnreachable, len(links), failed))
+		return false
+	}
+
+	info.Println(locale.Sprintf(locale.FlagLinksPassed, len(links)))
+	return true
+}
+
+// 返回文档中所有的链接，包括 @apiBaseURL、@apiLicense、@apiLinkTo 和 @apiContract 中的地址，
+// 已经去重并按字母顺序排序。
+func collectLinks(docs *types.Doc) []string {
+	found := make(map[string]struct{}, 10)
+	add := func(url string) {
+		if len(url) > 0 {
+			found[url] = struct{}{}
+		}
+	}
+
+	add(docs.BaseURL)
+	add(docs.LicenseURL)
+	for _, api := range docs.Apis {
+		for _, link := range api.Links {
+			add(link.URL)
+		}
+		for _, c := range api.Contracts {
+			add(c.URL)
+		}
+	}
+
+	links := make([]string, 0, len(found))
+	for url := range found {
+		links = append(links, url)
+	}
+	sort.Strings(links)
+	return links
+}
+
+// 依次访问 links 中的地址，将无法访问的地址及原因写入 w，返回无法访问的数量。
+func writeUnreachableLinks(w io.Writer, client *http.Client, links []string) (int, error) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	failed := 0
+	for _, link := range links {
+		reason := ""
+		resp, err := client.Head(link)
+		if err != nil {
+			reason = err.Error()
+		} else {
+			resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+				reason = resp.Status
+			}
+		}
+
+		if len(reason) > 0 {
+			failed++
+			if _, err := fmt.Fprintf(tw, "%s\t%s\n", link, reason); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return failed, tw.Flush()
+}
@@ -0,0 +1,125 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+)
+
+func TestCollectLinks(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.BaseURL = "https://api.example.com"
+	docs.NewAPI(&types.API{
+		Links:     []*types.Link{{URL: "https://example.com/issues/1"}, {URL: "https://api.example.com"}},
+		Contracts: []*types.Contract{{Suite: "users", URL: "https://pact.example.com/users"}},
+	})
+	docs.NewAPI(&types.API{Links: []*types.Link{{URL: "https://example.com/issues/1"}}})
+
+	a.Equal(collectLinks(docs), []string{
+		"https://api.example.com",
+		"https://example.com/issues/1",
+		"https://pact.example.com/users",
+	})
+
+	a.Empty(collectLinks(types.NewDoc()))
+}
+
+func TestWriteUnreachableLinks(t *testing.T) {
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/created":
+			w.WriteHeader(http.StatusNoContent)
+		case "/slow":
+			time.Sleep(500 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close() // 已经关闭的服务，无法连接
+
+	client := &http.Client{Timeout: 100 * time.Millisecond}
+
+	buf := new(bytes.Buffer)
+	failed, err := writeUnreachableLinks(buf, client, []string{srv.URL + "/ok", srv.URL + "/created"})
+	a.NotError(err).Equal(failed, 0).Empty(buf.String())
+
+	buf.Reset()
+	failed, err = writeUnreachableLinks(buf, client, []string{
+		srv.URL + "/ok",
+		srv.URL + "/missing",
+		srv.URL + "/slow",
+		closed.URL + "/users",
+	})
+	a.NotError(err).Equal(failed, 3)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	a.Equal(len(lines), 3)
+	a.True(strings.HasPrefix(lines[0], srv.URL+"/missing")).
+		True(strings.Contains(lines[0], "404")).
+		True(strings.HasPrefix(lines[1], srv.URL+"/slow")).
+		True(strings.HasPrefix(lines[2], closed.URL+"/users"))
+}
+
+func TestCheckLinks(t *testing.T) {
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir := coverageFixture(a, `package main
+
+// @apidoc test
+// @apiBaseURL `+srv.URL+`
+func doc() {}
+
+// @api get /users users
+// @apiLinkTo `+srv.URL+`/issues/1 需求
+// @apiContract users `+srv.URL+`/missing
+// @apiSuccess 200 OK
+func users() {}
+`)
+	defer os.RemoveAll(dir)
+
+	buf := new(bytes.Buffer)
+	a.False(checkLinks(buf, dir, time.Second, ""))
+	a.True(strings.Contains(buf.String(), srv.URL+"/missing")).
+		False(strings.Contains(buf.String(), "/issues/1"))
+
+	// 忽略无法访问的地址
+	buf.Reset()
+	a.True(checkLinks(buf, dir, time.Second, "/missing$"))
+	a.Empty(buf.String())
+
+	// 无效的参数
+	a.False(checkLinks(buf, dir, 0, ""))
+	a.False(checkLinks(buf, dir, time.Second, "(missing"))
+}
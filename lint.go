@@ -0,0 +1,207 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 收集 log.Logger 输出的信息。
+// log.Logger 每输出一条信息，都会调用一次 Write。
+type collector struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (c *collector) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	c.msgs = append(c.msgs, strings.TrimSpace(string(p)))
+	c.mu.Unlock()
+	return len(p), nil
+}
+
+// 可以在配置文件中启用的检测规则，
+// 这些规则只在 -lint 中生效，且只产生警告信息。
+type lintRuleSet struct {
+	RequireOwner     bool `yaml:"requireOwner,omitempty"`     // 每个 API 都必须指定 @apiOwner
+	RequireRequestID bool `yaml:"requireRequestID,omitempty"` // 指定了 @apiOwner 的 API 必须指定 @apiRequestID
+	RequireAuthError bool `yaml:"requireAuthError,omitempty"` // 指定了 @apiAuth 的 API 必须指定 @apiAuthError
+}
+
+// 检测 docs 是否符合 rules 中的规则，不符合的以警告信息输出到 l。
+func (rules *lintRuleSet) check(docs *types.Doc, l *log.Logger) {
+	if rules == nil {
+		return
+	}
+
+	for _, api := range docs.Apis {
+		if rules.RequireOwner && api.Owner == nil {
+			l.Println(locale.Sprintf(locale.ErrOwnerMissing, strings.ToUpper(api.Method), api.URL, vars.APIOwner))
+		}
+
+		if rules.RequireRequestID && api.Owner != nil && api.RequestID == nil {
+			l.Println(locale.Sprintf(locale.ErrRequestIDMissing, strings.ToUpper(api.Method), api.URL, vars.APIOwner, vars.APIRequestID))
+		}
+
+		if rules.RequireAuthError && len(api.Auth) > 0 && len(api.AuthErrors) == 0 {
+			l.Println(locale.Sprintf(locale.ErrAuthErrorMissing, strings.ToUpper(api.Method), api.URL, vars.APIAuth, vars.APIAuthError))
+		}
+	}
+}
+
+// 检测 @apiFeatureFlag 是否已经过时：API 在之前的版本中就已存在，
+// 其引入的版本以 @apiChangelog 中最早的版本为准，说明该功能开关应该被移除了。
+//
+// 需要与 @apiVersion 指定的文档版本比较，所以无法在解析单个 API 时进行。
+func checkFeatureFlags(docs *types.Doc, l *log.Logger) {
+	if len(docs.Version) == 0 {
+		return
+	}
+
+	for _, api := range docs.Apis {
+		if api.FeatureFlag == nil || len(api.Changelog) == 0 {
+			continue
+		}
+
+		since := api.Changelog[len(api.Changelog)-1].Version // 已按版本从新到旧排序
+		if types.CompareVersion(since, docs.Version) < 0 {
+			l.Println(locale.Sprintf(locale.ErrStaleFeatureFlag, strings.ToUpper(api.Method), api.URL, since, api.FeatureFlag.Name, docs.Version))
+		}
+	}
+}
+
+// 严格模式下，所有 API 都需要通过 @apiDataClassification 指定数据分级。
+func checkClassifications(docs *types.Doc, l *log.Logger) {
+	for _, api := range docs.Apis {
+		if len(api.DataClassification) == 0 {
+			l.Println(locale.Sprintf(locale.ErrClassificationMissing, strings.ToUpper(api.Method), api.URL, vars.APIDataClassification))
+		}
+	}
+}
+
+// 用于访问 @apiContract 地址的客户端
+var contractClient = &http.Client{Timeout: 10 * time.Second}
+
+// 访问 docs 中 @apiContract 指定的地址，无法访问或是返回值不为 200 的以警告信息输出到 l。
+func checkContracts(docs *types.Doc, l *log.Logger) {
+	for _, api := range docs.Apis {
+		for _, c := range api.Contracts {
+			resp, err := contractClient.Get(c.URL)
+			if err != nil {
+				l.Println(locale.Sprintf(locale.ErrContractCheckFailed, strings.ToUpper(api.Method), api.URL, c.Suite, err))
+				continue
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				l.Println(locale.Sprintf(locale.ErrContractCheckFailed, strings.ToUpper(api.Method), api.URL, c.Suite, resp.Status))
+			}
+		}
+	}
+}
+
+// 文档的检测结果
+type lintResult struct {
+	Passed   bool     `json:"passed"`
+	Errors   []string `json:"errors"`
+	Warnings []string `json:"warnings"`
+}
+
+// 检测 wd 中配置的文档内容，并以 format 格式输出检测结果，返回是否通过检测。
+//
+// strict 为 true 时，警告信息也会被当作错误处理；
+// failOnTodo 为 true 时，@apiTodo 会被当作错误处理；
+// contracts 为 true 时，会访问 @apiContract 指定的地址。
+func runLint(wd, format string, strict, failOnTodo, contracts bool) bool {
+	format = strings.ToLower(format)
+	if format != vars.FormatText && format != vars.FormatJSON {
+		erro.Println(locale.Sprintf(locale.FlagInvalidFormat))
+		return false
+	}
+
+	cfg, err := load(wd)
+	if err != nil {
+		erro.Println(err)
+		return false
+	}
+
+	ret := lint(cfg, strict, failOnTodo, contracts)
+
+	if format == vars.FormatJSON {
+		data, err := json.MarshalIndent(ret, "", strings.Repeat(" ", vars.JSONIndent))
+		if err != nil {
+			erro.Println(err)
+			return false
+		}
+		fmt.Println(string(data))
+		return ret.Passed
+	}
+
+	for _, msg := range ret.Errors {
+		erro.Println(msg)
+	}
+	for _, msg := range ret.Warnings {
+		warn.Println(msg)
+	}
+	info.Println(locale.Sprintf(locale.FlagLintResult, len(ret.Errors), len(ret.Warnings)))
+
+	return ret.Passed
+}
+
+// 分析 cfg 中的文档内容，返回所有的错误和警告信息。
+//
+// strict 为 true 时，有警告信息也会被当作未通过检测，且所有 API 都需要指定 @apiDataClassification。
+// @apiTodo 本身只产生警告信息，failOnTodo 为 true 时，会额外产生一条错误信息。
+// contracts 为 true 时，检测 @apiContract 指定的地址是否可以正常访问。
+//
+// 只涉及单个 API 中各标签之间一致性的检测，在解析时就会以警告信息输出，
+// 与是否使用 -lint 无关，这里只包含需要整个文档或是需要额外开启的检测。
+func lint(cfg *config, strict, failOnTodo, contracts bool) *lintResult {
+	errs := &collector{}
+	warns := &collector{}
+	errLog := log.New(errs, "", 0)
+	warnLog := log.New(warns, "", 0)
+
+	for _, o := range cfg.Inputs {
+		o.ErrorLog = errLog
+		o.WarnLog = warnLog
+	}
+	docs, _ := input.Parse(cfg.Inputs...)
+
+	for _, err := range docs.Validate() {
+		errLog.Println(err)
+	}
+	cfg.Lint.check(docs, warnLog)
+	checkFeatureFlags(docs, warnLog)
+	if strict {
+		checkClassifications(docs, warnLog)
+	}
+	if contracts {
+		checkContracts(docs, warnLog)
+	}
+
+	if failOnTodo {
+		if todos := docs.Stats().Todos; todos > 0 {
+			errLog.Println(locale.Sprintf(locale.ErrHasTodo, todos))
+		}
+	}
+
+	return &lintResult{
+		Passed:   len(errs.msgs) == 0 && (!strict || len(warns.msgs) == 0),
+		Errors:   errs.msgs,
+		Warnings: warns.msgs,
+	}
+}
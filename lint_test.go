@@ -0,0 +1,915 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func TestLint(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiGroup users
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /users/{id} user
+// @apiUnknownTag unknown
+// @apiSuccess 200 OK
+func user() {}
+
+// @api delete /users/{id} delete user
+func deleteUser() {}
+
+// @api post /users create user
+// @apiAuth token
+// @apiSuccess 201 created
+func createUser() {}
+
+// @api put /users/{id} update user
+// @apiRetry always maxAttempts:3
+// @apiSuccess 200 OK
+func updateUser() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	ret := lint(cfg, false, false, false)
+	a.False(ret.Passed).
+		Equal(len(ret.Errors), 2).  // 缺少 @apiSuccess，以及 @apiAuth 引用了未定义的认证方式
+		Equal(len(ret.Warnings), 2) // 不认识的标签，以及未标记 @apiIdempotent 的 @apiRetry
+}
+
+func TestLint_strict(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	// 仅有警告信息
+	code := `package main
+
+// @api get /users users
+// @apiUnknownTag unknown
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Errors).Equal(len(ret.Warnings), 1)
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+func TestLint_todo(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiTodo 补充查询参数
+// @apiTodo 补充返回值
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 每个 @apiTodo 产生一条警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Errors).Equal(len(ret.Warnings), 2)
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+
+	ret = lint(cfg, false, true, false)
+	a.False(ret.Passed).Equal(len(ret.Errors), 1).Equal(len(ret.Warnings), 2)
+}
+
+func TestLint_requireOwner(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiOwner Platform Engineering platform@example.com
+// @apiSuccess 200 OK
+func users() {}
+
+// @api delete /users/{id} delete user
+// @apiSuccess 204 OK
+func deleteUser() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 默认不启用
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Warnings)
+
+	cfg.Lint = &lintRuleSet{RequireOwner: true}
+	ret = lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], "DELETE /users/{id}"))
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+func TestLint_requireRequestID(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiOwner Platform Engineering platform@example.com
+// @apiRequestID X-Request-ID
+// @apiSuccess 200 OK
+func users() {}
+
+// @api delete /users/{id} delete user
+// @apiOwner Platform Engineering platform@example.com
+// @apiSuccess 204 OK
+func deleteUser() {}
+
+// @api get /health health
+// @apiSuccess 200 OK
+func health() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 默认不启用
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Warnings)
+
+	// 只检测指定了 @apiOwner 的 API
+	cfg.Lint = &lintRuleSet{RequireRequestID: true}
+	ret = lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], "DELETE /users/{id}")).
+		True(strings.Contains(ret.Warnings[0], vars.APIRequestID))
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+func TestLint_requireAuthError(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @apidoc test
+// @apiSecurity token apiKey header Authorization
+
+// @api get /users users
+// @apiAuth token
+// @apiAuthError 401 AuthError 未登录
+// @apiAuthError 403 AuthError 没有访问权限
+// @apiSuccess 200 OK
+func users() {}
+
+// @api delete /users/{id} delete user
+// @apiAuth token
+// @apiSuccess 204 OK
+func deleteUser() {}
+
+// @api get /health health
+// @apiSuccess 200 OK
+func health() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 默认不启用
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Warnings)
+
+	// 只检测指定了 @apiAuth 的 API
+	cfg.Lint = &lintRuleSet{RequireAuthError: true}
+	ret = lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], "DELETE /users/{id}")).
+		True(strings.Contains(ret.Warnings[0], vars.APIAuthError))
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+func TestLint_idempotencyKey(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api post /payments create payment
+// @apiIdempotencyKey Idempotency-Key 唯一值
+// @apiSuccess 201 OK
+func createPayment() {}
+
+// @api get /payments payments
+// @apiIdempotencyKey Idempotency-Key 唯一值
+// @apiSuccess 200 OK
+func payments() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有 GET 请求产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], vars.APIIdempotencyKey)).
+		True(strings.Contains(ret.Warnings[0], "GET"))
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+func TestLint_nullable(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users/{id} user
+// @apiParam id int 用户 ID
+// @apiNullable id
+// @apiSuccess 200 OK
+// @apiParam nickname string 昵称
+func user() {}
+
+// @api get /users users
+// @apiNullable nickname
+// @apiSuccess 200 OK
+// @apiParam nickname string 昵称
+func users() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有 URL 参数产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], vars.APINullable)).
+		True(strings.Contains(ret.Warnings[0], "id"))
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+// 以子进程的方式运行 main()，参数为 -- 之后的内容。
+// 供 runMain 调用，不会直接执行。
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("APIDOC_HELPER_PROCESS") != "1" {
+		return
+	}
+
+	args := os.Args
+	for i, arg := range args {
+		if arg == "--" {
+			args = args[i+1:]
+			break
+		}
+	}
+	os.Args = append([]string{vars.Name}, args...)
+
+	main()
+	os.Exit(0)
+}
+
+// 在子进程中执行 main()，返回标准输出的内容和退出码。
+func runMain(a *assert.Assertion, args ...string) (string, int) {
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=^TestHelperProcess$", "--"}, args...)...)
+	cmd.Env = append(os.Environ(), "APIDOC_HELPER_PROCESS=1")
+	stdout := new(bytes.Buffer)
+	cmd.Stdout = stdout
+
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return stdout.String(), exitErr.ExitCode()
+	}
+	a.NotError(err)
+	return stdout.String(), 0
+}
+
+func TestRunLint(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "main.go")
+	code := `package main
+
+// @api get /users users
+// @apiUnknownTag unknown
+// @apiDataClassification public
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(src, []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	data, err := yaml.Marshal(cfg)
+	a.NotError(err)
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.ConfigFilename), data, os.ModePerm))
+
+	// 只有警告
+	_, code0 := runMain(a, "-wd", dir, "-lint")
+	a.Equal(code0, 0)
+
+	out, code1 := runMain(a, "-wd", dir, "-lint", "-strict", "-format", "json")
+	a.Equal(code1, 1)
+	ret := &lintResult{}
+	a.NotError(json.Unmarshal([]byte(out), ret))
+	a.False(ret.Passed).Empty(ret.Errors).Equal(len(ret.Warnings), 1)
+
+	// 有错误
+	a.NotError(os.WriteFile(src, []byte("package main\n\n// @api get /users users\nfunc users() {}\n"), os.ModePerm))
+	out, code1 = runMain(a, "-wd", dir, "-lint", "-format", "json")
+	a.Equal(code1, 1)
+	ret = &lintResult{}
+	a.NotError(json.Unmarshal([]byte(out), ret))
+	a.False(ret.Passed).Equal(len(ret.Errors), 1)
+
+	// 无效的格式
+	_, code1 = runMain(a, "-wd", dir, "-lint", "-format", "xml")
+	a.Equal(code1, 1)
+}
+
+func TestLint_timeout(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiTimeout 30000
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiTimeout 60000 p99
+// @apiSuccess 200 OK
+func reports() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有超过 30000 毫秒的产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], vars.APITimeout)).
+		True(strings.Contains(ret.Warnings[0], "main.go:8"))
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed)
+}
+
+func TestLint_sla(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiSLA 99.9 rpo:5m rto:1h
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiSLA 9.99
+// @apiSuccess 200 OK
+func reports() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有可用性低于 99% 的产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], vars.APISLA)).
+		True(strings.Contains(ret.Warnings[0], "main.go:8"))
+}
+
+func TestLint_summaryLength(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users ` + strings.Repeat("x", 121) + `
+// 详细的描述内容不受长度限制
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], vars.API)).
+		True(strings.Contains(ret.Warnings[0], "120"))
+
+	// strict 模式下警告也视为错误
+	a.False(lint(cfg, true, false, false).Passed)
+}
+
+func TestLint_featureFlag(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @apiDoc test
+// @apiVersion 2.0.0
+func doc() {}
+
+// @api get /users users
+// @apiFeatureFlag new-users provider:launchDarkly
+// @apiChangelog 1.1.0 2017-11-01 添加分页
+// @apiChangelog 1.0.0 2017-10-01 初始版本
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiFeatureFlag reports provider:statsig
+// @apiChangelog 2.0.0 2017-12-01 初始版本
+// @apiSuccess 200 OK
+func reports() {}
+
+// @api get /orders orders
+// @apiFeatureFlag orders
+// @apiSuccess 200 OK
+func orders() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有在之前版本中就已存在的 API 产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1, ret.Warnings)
+	a.True(strings.Contains(ret.Warnings[0], "/users")).
+		True(strings.Contains(ret.Warnings[0], "new-users")).
+		True(strings.Contains(ret.Warnings[0], "1.0.0"))
+}
+
+func TestLint_circuitBreaker(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiCircuitBreaker threshold:50% timeout:30s
+// @apiIdempotent
+// @apiRetry always maxAttempts:3
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiCircuitBreaker threshold:50%
+// @apiSuccess 200 OK
+func reports() {}
+
+// @api get /orders orders
+// @apiIdempotent
+// @apiRetry always maxAttempts:3
+// @apiSuccess 200 OK
+func orders() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "1.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有未指定 @apiRetry 的熔断器产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1, ret.Warnings)
+	a.True(strings.Contains(ret.Warnings[0], "/reports")).
+		True(strings.Contains(ret.Warnings[0], vars.APICircuitBreaker)).
+		True(strings.Contains(ret.Warnings[0], vars.APIRetry))
+}
+
+func TestLint_tenant(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiTenant isolation:shared scoping:header
+// @apiRequest json
+// @apiHeader x-tenant-id 租户 ID
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiTenant scoping:header:X-Org
+// @apiRequest json
+// @apiHeader X-Tenant-ID 租户 ID
+// @apiSuccess 200 OK
+func reports() {}
+
+// @api get /orders orders
+// @apiTenant scoping:header
+// @apiSuccess 200 OK
+func orders() {}
+
+// @api get /{tenant}/items items
+// @apiTenant isolation:dedicated scoping:path
+// @apiParam tenant string 租户
+// @apiSuccess 200 OK
+func items() {}
+
+// @api get /files files
+// @apiTenant scoping:subdomain
+// @apiSuccess 200 OK
+func files() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "1.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有通过报头区分租户，且未声明该报头的产生警告，报头名称不区分大小写
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 2, ret.Warnings)
+	sort.Strings(ret.Warnings) // 各注释块并行解析，警告信息的顺序不固定
+	a.True(strings.Contains(ret.Warnings[0], "/reports")).
+		True(strings.Contains(ret.Warnings[0], "X-Org"))
+	a.True(strings.Contains(ret.Warnings[1], "/orders")).
+		True(strings.Contains(ret.Warnings[1], types.DefaultTenantHeader))
+}
+
+func TestLint_region(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiRegion available:cn-north-1
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiRegion available:us-east-1,eu-west-1 unavailable:cn-north-1
+// @apiSuccess 200 OK
+func reports() {}
+
+// @api get /orders orders
+// @apiRegion available:us-east-1,eu-west-1,ap-east-1 unavailable:ap-east-1,eu-west-1
+// @apiSuccess 200 OK
+func orders() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "1.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 每个相互矛盾的区域都产生一条警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 2, ret.Warnings)
+	sort.Strings(ret.Warnings)
+	a.True(strings.Contains(ret.Warnings[0], "/orders")).
+		True(strings.Contains(ret.Warnings[0], "ap-east-1"))
+	a.True(strings.Contains(ret.Warnings[1], "/orders")).
+		True(strings.Contains(ret.Warnings[1], "eu-west-1"))
+}
+
+func TestLint_quota(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiThrottle 10 per second
+// @apiQuota 10000 per day scope:user
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiQuota 100 per month
+// @apiSuccess 200 OK
+func reports() {}
+
+// @api get /orders orders
+// @apiThrottle 600 per hour
+// @apiQuota 500 per year scope:org
+// @apiSuccess 200 OK
+func orders() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "1.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有配额少于频率限制的产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1, ret.Warnings)
+	a.True(strings.Contains(ret.Warnings[0], "/orders")).
+		True(strings.Contains(ret.Warnings[0], "500")).
+		True(strings.Contains(ret.Warnings[0], "600"))
+}
+
+func TestLint_async(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api post /reports reports
+// @apiAsync pollUrl:/jobs/{jobId}
+// @apiSuccess 202 Accepted
+// @apiParam jobId string 任务 ID
+func reports() {}
+
+// @api post /exports exports
+// @apiAsync callbackHeader:X-Callback-URL
+// @apiSuccess 200 OK
+// @apiError 202 Accepted
+func exports() {}
+
+// @api post /imports imports
+// @apiAsync pollUrl:/jobs/{jobId} callbackHeader:X-Callback-URL
+// @apiSuccess 200 OK
+func imports() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "1.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 只有未声明 202 的产生警告
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1, ret.Warnings)
+	a.True(strings.Contains(ret.Warnings[0], "/imports"))
+}
+
+func TestLint_dataClassification(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiDataClassification pii
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /news news
+// @apiSuccess 200 OK
+func news() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "1.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 非严格模式下不作要求
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Warnings)
+
+	ret = lint(cfg, true, false, false)
+	a.False(ret.Passed).Equal(len(ret.Warnings), 1, ret.Warnings)
+	a.True(strings.Contains(ret.Warnings[0], "/news")).
+		True(strings.Contains(ret.Warnings[0], vars.APIDataClassification))
+}
+
+func TestLint_checkContracts(t *testing.T) {
+	a := assert.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/pacts/users" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiContract users ` + srv.URL + `/pacts/users
+// @apiDataClassification confidential
+// @apiSuccess 200 OK
+func users() {}
+
+// @api get /reports reports
+// @apiContract reports ` + srv.URL + `/pacts/reports
+// @apiDataClassification confidential
+// @apiSuccess 200 OK
+func reports() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: "4.0.0",
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	a.NotError(cfg.sanitize())
+
+	// 未指定 contracts 时不访问
+	ret := lint(cfg, false, false, false)
+	a.True(ret.Passed).Empty(ret.Warnings)
+
+	// 只有返回 404 的产生警告
+	ret = lint(cfg, false, false, true)
+	a.True(ret.Passed).Equal(len(ret.Warnings), 1)
+	a.True(strings.Contains(ret.Warnings[0], "/reports")).
+		True(strings.Contains(ret.Warnings[0], "404"))
+
+	// 无法访问的地址
+	srv.Close()
+	ret = lint(cfg, true, false, true)
+	a.False(ret.Passed).Equal(len(ret.Warnings), 2)
+}
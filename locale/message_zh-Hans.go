@@ -24,9 +24,35 @@ func init() {
 		FlagVUsage:              "显示版本信息",
 		FlagLanguagesUsage:      "显示所有支持的语言",
 		FlagGUsage:              "创建一个默认的配置文件",
+		FlagYesUsage:            "创建配置文件时不作询问，全部采用默认值",
+		FlagForceUsage:          "创建配置文件时，覆盖已经存在的文件",
 		FlagEncodingsUsage:      "显示支持的编码方式",
 		FlagWDUsage:             "指定工作目录，默认为当前目录",
 		FlagPprofUsage:          "指定一种调试输出类型，可以为 %s 或是 %s",
+		FlagStatsUsage:          "显示文档的统计信息",
+		FlagFormatUsage:         "指定统计信息等内容的输出格式，可以为 %s 或是 %s",
+		FlagOutputUsage:         "指定输出的类型和目录，格式为 type:dir，可以指定多个，会覆盖配置文件中的 output",
+		FlagBasePathUsage:       "指定所有 API 地址的前缀，会覆盖配置文件和 @apiBasePath 中的值",
+		FlagTryItBaseURLUsage:   "指定在线调试的基地址，未指定 @apiTryIt 的 API 都会使用该地址",
+		FlagLintUsage:           "检测文档中的语法错误，有错误时以非零值退出",
+		FlagStrictUsage:         "与 -lint 一起使用，将警告也当作错误处理，并要求所有 API 都指定 @apiDataClassification",
+		FlagInstallHookUsage:    "在当前 git 仓库中安装 pre-commit 钩子，提交前执行 -lint -strict",
+		FlagServeUsage:          "启动文档服务，源文件有变化时会自动重新生成文档",
+		FlagPortUsage:           "与 -serve 或 -mock 一起使用，指定监听的地址",
+		FlagCompletionUsage:     "输出指定 shell 的自动补全脚本，可以是 bash、zsh、fish 或是 powershell",
+		FlagParallelUsage:       "同时生成所有的输出内容，在指定了多个 -output 时可以减少用时",
+		FlagFailOnTodoUsage:     "文档中包含 @apiTodo 时返回错误，与 -lint 一起使用时，将其当作错误而不是警告",
+		FlagCheckContractsUsage: "与 -lint 一起使用，访问 @apiContract 指定的地址，返回值不为 200 时给出警告",
+		FlagMockUsage:           "在指定的目录中生成模拟服务的代码，返回内容来自文档中的示例",
+		FlagMaxMockDelayUsage:   "与 -mock 一起使用，指定 @apiMockDelay 等待时间的上限，单位为毫秒",
+		FlagEnvironmentUsage:    "只输出指定环境的 @apiEnvironment 内容，可以是 prod、staging 或是 dev",
+		FlagAudienceUsage:       "只输出面向指定受众的 API，可以是 public、partner 或是 internal",
+		FlagMinCoverageUsage:    "检测文档的覆盖率是否达到指定的百分比，未达到时以非零值退出",
+		FlagIgnoreLinksUsage:    "与 -check-links 一起使用，不检测与该正则表达式匹配的链接",
+		FlagLinkTimeoutUsage:    "与 -check-links 一起使用，指定每个链接的超时时间",
+		FlagCheckLinksUsage:     "检测文档中的链接是否可以访问，有无法访问的链接时以非零值退出",
+		FlagDiffUsage:           "与指定目录中的文档比较，列出新增、删除和修改的 API 以及新增的不兼容变更，有删除的 API 或是不兼容的变更时以非零值退出",
+		FlagExportLangDefsUsage: "以 JSON 格式输出指定语言的定义，多个语言以逗号分隔",
 		FlagVersionBuildWith:    "%v %v build with %v\n",
 		FlagVersionCommitHash:   "commit hash %v\n",
 		FlagSupportedLanguages:  "目前支持以下语言 %v\n",
@@ -34,6 +60,35 @@ func init() {
 		FlagConfigWritedSuccess: "配置内容成功写入 %v",
 		FlagPprofWritedSuccess:  "pprof 的相关数据已经写入到 %v",
 		FlagInvalidPprrof:       "无效的 pprof 参数",
+		FlagInvalidFormat:       "无效的 format 参数",
+		FlagInvalidOutput:       "无效的 output 参数：%v",
+		FlagOutputDirOverlap:    "输出目录 %v 与 %v 重叠",
+		FlagInvalidCompletion:   "不支持的 shell：%v，可用的值为：%v",
+		FlagInvalidEnvironment:  "不支持的环境：%v，可用的值为：%v",
+		FlagInvalidAudience:     "不支持的受众：%v，可用的值为：%v",
+		FlagInvalidMinCoverage:  "无效的 min-coverage 参数：%v，应该在 0 到 100 之间",
+		FlagInvalidLinkTimeout:  "无效的 link-timeout 参数：%v，应该大于 0",
+		FlagInvalidIgnoreLinks:  "无效的 ignore-links 参数：%v",
+		FlagInvalidMaxMockDelay: "无效的 max-mock-delay 参数：%v，不能小于 0",
+		FlagLintResult:          "共有 %v 个错误，%v 个警告",
+		FlagHookWritedSuccess:   "pre-commit 钩子成功写入 %v",
+		FlagMockWritedSuccess:   "模拟服务的代码成功写入 %v",
+		FlagServeListening:      "文档服务已经启动，监听地址：%v",
+		FlagServeRebuild:        "源文件有变化，已经重新生成文档",
+		FlagStats:               "API 总数：%d\n带详细描述：%d\n带参数描述：%d\n带返回描述：%d\n覆盖率：%.2f%%\n待完成：%d\n",
+		FlagStatsClassification: "数据分级为 %v 的 API：%d\n",
+		FlagCoverageHeader:      "方法\t地址\t详细描述\t参数描述",
+		FlagCoveragePassed:      "覆盖率 %.2f%% 达到要求的 %.2f%%",
+		FlagDiffAdded:           "新增的 API",
+		FlagDiffRemoved:         "删除的 API",
+		FlagDiffModified:        "修改的 API",
+		FlagDiffBreakingChange:  "不兼容的变更",
+		FlagLinksPassed:         "共检测了 %v 个链接，全部可以访问",
+		FlagConfigFileExists:    "配置文件 %v 已经存在，可以使用 -force 参数覆盖",
+		FlagPromptLang:          "源代码的语言 [%v]：",
+		FlagPromptInputDirs:     "源代码目录，多个目录以逗号分隔 [%v]：",
+		FlagPromptOutputDir:     "文档的输出目录 [%v]：",
+		FlagPromptOutputType:    "文档的输出类型 [%v]：",
 
 		VersionInCompatible: "当前程序与配置文件中指定的版本号不兼容",
 		Complete:            "完成！文档保存在：%v，总用时：%v",
@@ -41,12 +96,18 @@ func init() {
 		// 错误信息，可能在地方用到
 		ErrRequired:              "不能为空",
 		ErrInvalidFormat:         "格式不正确",
+		ErrInvalidValue:          "无效的值",
 		ErrDirNotExists:          "目录不存在",
 		ErrMkdirError:            "创建目录时发生以下错误：%v",
-		ErrInvalidBlockType:      "无效的 block.Type 值：%v",
+		ErrInvalidBlockType:      "无效的 Block.Type 值：%v",
 		ErrUnsupportedInputLang:  "无效的输入语言：%v",
 		ErrNotFoundEndFlag:       "找不到结束符号",
+		ErrScriptNotFound:        "找不到 <script> 标签",
 		ErrNotFoundSupportedLang: "该目录下没有支持的语言文件",
+		ErrLangExists:            "语言 %v 已经存在",
+		ErrInvalidLangDef:        "语言定义文件 %v 中的 %v %v",
+		ErrInvalidLangRegister:   "注册语言 %v 时 %v %v",
+		ErrLangNotExportable:     "语言 %v 包含无法导出的代码块定义",
 		ErrUnknownTag:            "不认识的标签：%v",
 		ErrDuplicateTag:          "重复的标签：%v",
 		ErrSuccessNotEmpty:       vars.APISuccess + " 不能为空",
@@ -55,6 +116,56 @@ func init() {
 		ErrSecondArgMustURL:      vars.APILicense + " 第二个参数必须为 URL",
 		ErrUnsupportedEncoding:   "不支持的编码方式：%v",
 
+		// 标签内容检测时的警告信息
+		ErrContentTypeNotDeclared: "示例类型：%v 未在 %v 中声明",
+		ErrFileTooLarge:           "文件 %v 的大小超过了 %v 字节，将被忽略",
+		ErrInvalidTagValue:        "标签：%v 的值 %v 无效",
+		ErrDuplicateTagValue:      "标签：%v 的值 %v 重复",
+		ErrSecurityNotFound:       "%v %v 引用的认证方式 %v 未定义",
+		ErrScopeNotFound:          "%v %v 要求的权限范围 %v 未在其使用的 oauth2 认证方式中定义",
+		ErrInvalidExtension:       "%v %v 的扩展字段 %v 不是合法的 JSON",
+		ErrFormatParamNotFound:    "%v %v 的 @apiFormat 引用的参数 %v 未定义",
+		ErrDiscriminatorNotFound:  "%v %v 的 @apiDiscriminator 引用的字段 %v 未定义",
+		ErrDuplicateOperationID:   "%v %v 的 @apiOperationID %v 与 %v %v 重复",
+		ErrInvalidMimetype:        "%v 不是一个有效的内容类型",
+		ErrUnsafeMethod:           "%v 请求通常会修改服务端的数据，不应该使用 %v",
+		ErrIdempotencyKeyOnGet:    "%v 请求本身是幂等的，不需要使用 %v",
+		ErrBatchOnGet:             "%v 请求很少需要批量处理，请确认 %v 是否正确",
+		ErrContentLengthNoBody:    "%v 请求没有请求内容，%v 不会生效",
+		ErrMultipartConflict:      "使用了 %v，但 %v 指定的内容类型为 %v",
+		ErrMediaTypeConflict:      "%v 表示返回二进制内容，不应该同时在 %v 中指定参数",
+		ErrAccessWithoutAuth:      "使用了 %v，但未通过 %v 指定认证方式",
+		ErrRetryNotIdempotent:     "未标记为 %v 的 API 使用了 %v，重试可能会产生副作用",
+		ErrCacheControlConflict:   "%v 中同时指定了 %v 和 %v",
+		ErrCORSWildcardWithAuth:   "%v 允许任意来源时，浏览器不会发送认证信息，与 %v 无法同时使用",
+		ErrTimeoutTooLarge:        "%v 指定的响应时间 %d 毫秒超过了 %d 毫秒，可能是错误的配置",
+		ErrTimeBudgetExceeded:     "%v 中上游服务的时间之和 %v 毫秒超过了总时间 %v 毫秒",
+		ErrSLAAvailabilityTooLow:  "%v 指定的可用性 %v%% 低于 %v%%，可能是输入错误",
+		ErrSummaryTooLong:         "%v 的简要描述长度为 %v 个字符，超过了 %v 个字符，详细内容应该放在描述中",
+		ErrTagOutsideAPI:          "%v 只能在 %v 中使用",
+		ErrSSENotGet:              "%v 通常只用于 GET 请求，当前请求方法为 %v",
+		ErrMissingDependentTag:    "使用了 %v，但未指定 %v",
+		ErrWebSocketNotGet:        "%v 需要通过 GET 请求升级协议，当前请求方法为 %v",
+		ErrTodo:                   "未完成的文档：%v",
+		ErrHasTodo:                "文档中还有 %d 项未完成的内容",
+		ErrCoverageTooLow:         "文档覆盖率 %.2f%% 低于要求的 %.2f%%",
+		ErrLinksUnreachable:       "共检测了 %v 个链接，其中 %v 个无法访问",
+		ErrDiffRemoved:            "与之前的文档相比，删除了 %d 个 API",
+		ErrDiffBreakingChanges:    "与之前的文档相比，新增了 %d 个不兼容的变更",
+		ErrOwnerMissing:           "%v %v 未指定 %v",
+		ErrRequestIDMissing:       "%v %v 指定了 %v，但未指定 %v",
+		ErrAuthErrorMissing:       "%v %v 使用了 %v，但未通过 %v 说明认证失败时的返回内容",
+		ErrStaleFeatureFlag:       "%v %v 在 %v 版本中就已存在，但依然受功能开关 %v 控制，当前版本为 %v",
+		ErrRetryMissing:           "%v %v 指定了 %v，但未指定与之配合使用的 %v",
+		ErrTenantHeaderMissing:    "%v %v 通过报头 %v 区分租户，但请求中未通过 %v 声明该报头",
+		ErrRegionConflict:         "%v %v 的区域 %v 同时被声明为可用和不可用",
+		ErrQuotaConflict:          "%v %v 的配额 %v 次/%v 少于频率限制 %v 次/%v",
+		ErrAsyncMissing202:        "%v %v 为异步接口，但没有声明状态码为 202 的返回内容",
+		ErrClassificationMissing:  "%v %v 未指定 %v，严格模式下所有 API 都需要指定数据分级",
+		ErrContractCheckFailed:    "%v %v 的契约测试 %v 检测失败：%v",
+		ErrNullableURLParam:       "%v 指定的 %v 为 URL 参数，URL 参数总是必须的，不应该为空",
+		ErrNullableParamNotFound:  "%v 指定的参数 %v 不存在",
+
 		// logs
 		InfoPrefix:  "[信息] ",
 		WarnPrefix:  "[警告] ",
@@ -24,9 +24,35 @@ func init() {
 		FlagVUsage:              "顯示版本信息",
 		FlagLanguagesUsage:      "顯示所有支持的語言",
 		FlagGUsage:              "創建壹個默認的配置文件",
+		FlagYesUsage:            "創建配置文件時不作詢問，全部采用默認值",
+		FlagForceUsage:          "創建配置文件時，覆蓋已經存在的文件",
 		FlagEncodingsUsage:      "顯示支持的編碼方式",
 		FlagWDUsage:             "指定工作目錄，默認為當前目錄",
 		FlagPprofUsage:          "指定壹種調試輸出類型，可以為 %s 或是 %s",
+		FlagStatsUsage:          "顯示文檔的統計信息",
+		FlagFormatUsage:         "指定統計信息等內容的輸出格式，可以為 %s 或是 %s",
+		FlagOutputUsage:         "指定輸出的類型和目錄，格式為 type:dir，可以指定多個，會覆蓋配置文件中的 output",
+		FlagBasePathUsage:       "指定所有 API 地址的前綴，會覆蓋配置文件和 @apiBasePath 中的值",
+		FlagTryItBaseURLUsage:   "指定在線調試的基地址，未指定 @apiTryIt 的 API 都會使用該地址",
+		FlagLintUsage:           "檢測文檔中的語法錯誤，有錯誤時以非零值退出",
+		FlagStrictUsage:         "與 -lint 壹起使用，將警告也當作錯誤處理，並要求所有 API 都指定 @apiDataClassification",
+		FlagInstallHookUsage:    "在當前 git 倉庫中安裝 pre-commit 鉤子，提交前執行 -lint -strict",
+		FlagServeUsage:          "啟動文檔服務，源文件有變化時會自動重新生成文檔",
+		FlagPortUsage:           "與 -serve 或 -mock 壹起使用，指定監聽的地址",
+		FlagCompletionUsage:     "輸出指定 shell 的自動補全腳本，可以是 bash、zsh、fish 或是 powershell",
+		FlagParallelUsage:       "同時生成所有的輸出內容，在指定了多個 -output 時可以減少用時",
+		FlagFailOnTodoUsage:     "文檔中包含 @apiTodo 時返回錯誤，與 -lint 壹起使用時，將其當作錯誤而不是警告",
+		FlagCheckContractsUsage: "與 -lint 壹起使用，訪問 @apiContract 指定的地址，返回值不為 200 時給出警告",
+		FlagMockUsage:           "在指定的目錄中生成模擬服務的代碼，返回內容來自文檔中的示例",
+		FlagMaxMockDelayUsage:   "與 -mock 壹起使用，指定 @apiMockDelay 等待時間的上限，單位為毫秒",
+		FlagEnvironmentUsage:    "只輸出指定環境的 @apiEnvironment 內容，可以是 prod、staging 或是 dev",
+		FlagAudienceUsage:       "只輸出面向指定受眾的 API，可以是 public、partner 或是 internal",
+		FlagMinCoverageUsage:    "檢測文檔的覆蓋率是否達到指定的百分比，未達到時以非零值退出",
+		FlagIgnoreLinksUsage:    "與 -check-links 一起使用，不檢測與該正則表達式匹配的鏈接",
+		FlagLinkTimeoutUsage:    "與 -check-links 一起使用，指定每個鏈接的超時時間",
+		FlagCheckLinksUsage:     "檢測文檔中的鏈接是否可以訪問，有無法訪問的鏈接時以非零值退出",
+		FlagDiffUsage:           "與指定目錄中的文檔比較，列出新增、刪除和修改的 API 以及新增的不兼容變更，有刪除的 API 或是不兼容的變更時以非零值退出",
+		FlagExportLangDefsUsage: "以 JSON 格式輸出指定語言的定義，多個語言以逗號分隔",
 		FlagVersionBuildWith:    "%v %v build with %v\n",
 		FlagVersionCommitHash:   "commit hash %v\n",
 		FlagSupportedLanguages:  "目前支持以下語言 %v\n",
@@ -34,6 +60,35 @@ func init() {
 		FlagConfigWritedSuccess: "配置內容成功寫入 %v",
 		FlagPprofWritedSuccess:  "pprof 的相關數據已經寫入到 %v",
 		FlagInvalidPprrof:       "無效的 pprof 參數",
+		FlagInvalidFormat:       "無效的 format 參數",
+		FlagInvalidOutput:       "無效的 output 參數：%v",
+		FlagOutputDirOverlap:    "輸出目錄 %v 與 %v 重疊",
+		FlagInvalidCompletion:   "不支持的 shell：%v，可用的值為：%v",
+		FlagInvalidEnvironment:  "不支持的環境：%v，可用的值為：%v",
+		FlagInvalidAudience:     "不支持的受眾：%v，可用的值為：%v",
+		FlagInvalidMinCoverage:  "無效的 min-coverage 參數：%v，應該在 0 到 100 之間",
+		FlagInvalidLinkTimeout:  "無效的 link-timeout 參數：%v，應該大於 0",
+		FlagInvalidIgnoreLinks:  "無效的 ignore-links 參數：%v",
+		FlagInvalidMaxMockDelay: "無效的 max-mock-delay 參數：%v，不能小於 0",
+		FlagLintResult:          "共有 %v 個錯誤，%v 個警告",
+		FlagHookWritedSuccess:   "pre-commit 鉤子成功寫入 %v",
+		FlagMockWritedSuccess:   "模擬服務的代碼成功寫入 %v",
+		FlagServeListening:      "文檔服務已經啟動，監聽地址：%v",
+		FlagServeRebuild:        "源文件有變化，已經重新生成文檔",
+		FlagStats:               "API 總數：%d\n帶詳細描述：%d\n帶參數描述：%d\n帶返回描述：%d\n覆蓋率：%.2f%%\n待完成：%d\n",
+		FlagStatsClassification: "數據分級為 %v 的 API：%d\n",
+		FlagCoverageHeader:      "方法\t地址\t詳細描述\t參數描述",
+		FlagCoveragePassed:      "覆蓋率 %.2f%% 達到要求的 %.2f%%",
+		FlagDiffAdded:           "新增的 API",
+		FlagDiffRemoved:         "刪除的 API",
+		FlagDiffModified:        "修改的 API",
+		FlagDiffBreakingChange:  "不兼容的變更",
+		FlagLinksPassed:         "共檢測了 %v 個鏈接，全部可以訪問",
+		FlagConfigFileExists:    "配置文件 %v 已經存在，可以使用 -force 參數覆蓋",
+		FlagPromptLang:          "源代碼的語言 [%v]：",
+		FlagPromptInputDirs:     "源代碼目錄，多個目錄以逗號分隔 [%v]：",
+		FlagPromptOutputDir:     "文檔的輸出目錄 [%v]：",
+		FlagPromptOutputType:    "文檔的輸出類型 [%v]：",
 
 		VersionInCompatible: "當前程序與配置文件中指定的版本號不兼容",
 		Complete:            "完成！文檔保存在：%v，總用時：%v",
@@ -41,12 +96,18 @@ func init() {
 		// 錯誤信息，可能在地方用到
 		ErrRequired:              "不能為空",
 		ErrInvalidFormat:         "格式不正確",
+		ErrInvalidValue:          "無效的值",
 		ErrDirNotExists:          "目錄不存在",
 		ErrMkdirError:            "創建目錄時發生以下錯誤：%v",
-		ErrInvalidBlockType:      "無效的 block.Type 值：%v",
+		ErrInvalidBlockType:      "無效的 Block.Type 值：%v",
 		ErrUnsupportedInputLang:  "無效的輸入語言：%v",
 		ErrNotFoundEndFlag:       "找不到結束符號",
+		ErrScriptNotFound:        "找不到 <script> 標簽",
 		ErrNotFoundSupportedLang: "該目錄下沒有支持的語言文件",
+		ErrLangExists:            "語言 %v 已經存在",
+		ErrInvalidLangDef:        "語言定義文件 %v 中的 %v %v",
+		ErrInvalidLangRegister:   "註冊語言 %v 時 %v %v",
+		ErrLangNotExportable:     "語言 %v 包含無法導出的代碼塊定義",
 		ErrUnknownTag:            "不認識的標簽：%v",
 		ErrDuplicateTag:          "重復的標簽：%v",
 		ErrSuccessNotEmpty:       vars.APISuccess + " 不能为空",
@@ -55,6 +116,56 @@ func init() {
 		ErrSecondArgMustURL:      vars.APILicense + " 第二個參數必須為 URL",
 		ErrUnsupportedEncoding:   "不支持的編碼方式：%v",
 
+		// 標簽內容檢測時的警告信息
+		ErrContentTypeNotDeclared: "示例類型：%v 未在 %v 中聲明",
+		ErrFileTooLarge:           "文件 %v 的大小超過了 %v 字節，將被忽略",
+		ErrInvalidTagValue:        "標簽：%v 的值 %v 無效",
+		ErrDuplicateTagValue:      "標簽：%v 的值 %v 重復",
+		ErrSecurityNotFound:       "%v %v 引用的認證方式 %v 未定義",
+		ErrScopeNotFound:          "%v %v 要求的權限範圍 %v 未在其使用的 oauth2 認證方式中定義",
+		ErrInvalidExtension:       "%v %v 的擴展字段 %v 不是合法的 JSON",
+		ErrFormatParamNotFound:    "%v %v 的 @apiFormat 引用的參數 %v 未定義",
+		ErrDiscriminatorNotFound:  "%v %v 的 @apiDiscriminator 引用的字段 %v 未定義",
+		ErrDuplicateOperationID:   "%v %v 的 @apiOperationID %v 與 %v %v 重複",
+		ErrInvalidMimetype:        "%v 不是一個有效的內容類型",
+		ErrUnsafeMethod:           "%v 請求通常會修改服務端的數據，不應該使用 %v",
+		ErrIdempotencyKeyOnGet:    "%v 請求本身是冪等的，不需要使用 %v",
+		ErrBatchOnGet:             "%v 請求很少需要批量處理，請確認 %v 是否正確",
+		ErrContentLengthNoBody:    "%v 請求沒有請求內容，%v 不會生效",
+		ErrMultipartConflict:      "使用了 %v，但 %v 指定的內容類型為 %v",
+		ErrMediaTypeConflict:      "%v 表示返回二進制內容，不應該同時在 %v 中指定參數",
+		ErrAccessWithoutAuth:      "使用了 %v，但未通過 %v 指定認證方式",
+		ErrRetryNotIdempotent:     "未標記為 %v 的 API 使用了 %v，重試可能會產生副作用",
+		ErrCacheControlConflict:   "%v 中同時指定了 %v 和 %v",
+		ErrCORSWildcardWithAuth:   "%v 允許任意來源時，瀏覽器不會發送認證信息，與 %v 無法同時使用",
+		ErrTimeoutTooLarge:        "%v 指定的響應時間 %d 毫秒超過了 %d 毫秒，可能是錯誤的配置",
+		ErrTimeBudgetExceeded:     "%v 中上游服務的時間之和 %v 毫秒超過了總時間 %v 毫秒",
+		ErrSLAAvailabilityTooLow:  "%v 指定的可用性 %v%% 低於 %v%%，可能是輸入錯誤",
+		ErrSummaryTooLong:         "%v 的簡要描述長度為 %v 個字符，超過了 %v 個字符，詳細內容應該放在描述中",
+		ErrTagOutsideAPI:          "%v 只能在 %v 中使用",
+		ErrSSENotGet:              "%v 通常只用於 GET 請求，當前請求方法為 %v",
+		ErrMissingDependentTag:    "使用了 %v，但未指定 %v",
+		ErrWebSocketNotGet:        "%v 需要通過 GET 請求升級協議，當前請求方法為 %v",
+		ErrTodo:                   "未完成的文檔：%v",
+		ErrHasTodo:                "文檔中還有 %d 項未完成的內容",
+		ErrCoverageTooLow:         "文檔覆蓋率 %.2f%% 低於要求的 %.2f%%",
+		ErrLinksUnreachable:       "共檢測了 %v 個鏈接，其中 %v 個無法訪問",
+		ErrDiffRemoved:            "與之前的文檔相比，刪除了 %d 個 API",
+		ErrDiffBreakingChanges:    "與之前的文檔相比，新增了 %d 個不兼容的變更",
+		ErrOwnerMissing:           "%v %v 未指定 %v",
+		ErrRequestIDMissing:       "%v %v 指定了 %v，但未指定 %v",
+		ErrAuthErrorMissing:       "%v %v 使用了 %v，但未通過 %v 說明認證失敗時的返回內容",
+		ErrStaleFeatureFlag:       "%v %v 在 %v 版本中就已存在，但依然受功能開關 %v 控制，當前版本為 %v",
+		ErrRetryMissing:           "%v %v 指定了 %v，但未指定與之配合使用的 %v",
+		ErrTenantHeaderMissing:    "%v %v 通過報頭 %v 區分租戶，但請求中未通過 %v 聲明該報頭",
+		ErrRegionConflict:         "%v %v 的區域 %v 同時被聲明為可用和不可用",
+		ErrQuotaConflict:          "%v %v 的配額 %v 次/%v 少於頻率限制 %v 次/%v",
+		ErrAsyncMissing202:        "%v %v 為異步接口，但沒有聲明狀態碼為 202 的返回內容",
+		ErrClassificationMissing:  "%v %v 未指定 %v，嚴格模式下所有 API 都需要指定數據分級",
+		ErrContractCheckFailed:    "%v %v 的契約測試 %v 檢測失敗：%v",
+		ErrNullableURLParam:       "%v 指定的 %v 為 URL 參數，URL 參數總是必須的，不應該為空",
+		ErrNullableParamNotFound:  "%v 指定的參數 %v 不存在",
+
 		// logs
 		InfoPrefix:  "[信息] ",
 		WarnPrefix:  "[警告] ",
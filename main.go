@@ -7,23 +7,27 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
-	"io/ioutil"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/issue9/logs/writers"
 	"github.com/issue9/term/colors"
 	"github.com/issue9/version"
-	yaml "gopkg.in/yaml.v2"
 
 	"github.com/caixw/apidoc/input"
 	"github.com/caixw/apidoc/locale"
 	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/types"
 	"github.com/caixw/apidoc/vars"
 )
 
@@ -50,10 +54,37 @@ func main() {
 	h := flag.Bool("h", false, locale.Sprintf(locale.FlagHUsage))
 	v := flag.Bool("v", false, locale.Sprintf(locale.FlagVUsage))
 	g := flag.Bool("g", false, locale.Sprintf(locale.FlagGUsage))
+	yes := flag.Bool("yes", false, locale.Sprintf(locale.FlagYesUsage))
+	force := flag.Bool("force", false, locale.Sprintf(locale.FlagForceUsage))
 	wd := flag.String("wd", "./", locale.Sprintf(locale.FlagWDUsage))
 	languages := flag.Bool("languages", false, locale.Sprintf(locale.FlagLanguagesUsage))
 	encodings := flag.Bool("encodings", false, locale.Sprintf(locale.FlagEncodingsUsage))
 	pprofType := flag.String("pprof", "", locale.Sprintf(locale.FlagPprofUsage, vars.PprofCPU, vars.PprofMem))
+	stats := flag.Bool("stats", false, locale.Sprintf(locale.FlagStatsUsage))
+	format := flag.String("format", vars.FormatText, locale.Sprintf(locale.FlagFormatUsage, vars.FormatText, vars.FormatJSON))
+	outputs := outputFlags{}
+	flag.Var(&outputs, "output", locale.Sprintf(locale.FlagOutputUsage))
+	basePath := flag.String("base-path", "", locale.Sprintf(locale.FlagBasePathUsage))
+	tryItBaseURL := flag.String("try-it-base-url", "", locale.Sprintf(locale.FlagTryItBaseURLUsage))
+	lintFlag := flag.Bool("lint", false, locale.Sprintf(locale.FlagLintUsage))
+	strict := flag.Bool("strict", false, locale.Sprintf(locale.FlagStrictUsage))
+	hook := flag.Bool("install-hook", false, locale.Sprintf(locale.FlagInstallHookUsage))
+	serve := flag.Bool("serve", false, locale.Sprintf(locale.FlagServeUsage))
+	port := flag.String("port", ":8080", locale.Sprintf(locale.FlagPortUsage))
+	completion := flag.String("completion", "", locale.Sprintf(locale.FlagCompletionUsage))
+	parallel := flag.Bool("parallel", false, locale.Sprintf(locale.FlagParallelUsage))
+	failOnTodo := flag.Bool("fail-on-todo", false, locale.Sprintf(locale.FlagFailOnTodoUsage))
+	mock := flag.String("mock", "", locale.Sprintf(locale.FlagMockUsage))
+	maxMockDelay := flag.Int("max-mock-delay", vars.DefaultMaxMockDelay, locale.Sprintf(locale.FlagMaxMockDelayUsage))
+	environment := flag.String("environment", "", locale.Sprintf(locale.FlagEnvironmentUsage))
+	audience := flag.String("audience", "", locale.Sprintf(locale.FlagAudienceUsage))
+	minCoverage := flag.Float64("min-coverage", 0, locale.Sprintf(locale.FlagMinCoverageUsage))
+	checkLinksFlag := flag.Bool("check-links", false, locale.Sprintf(locale.FlagCheckLinksUsage))
+	linkTimeout := flag.Duration("link-timeout", 10*time.Second, locale.Sprintf(locale.FlagLinkTimeoutUsage))
+	ignoreLinks := flag.String("ignore-links", "", locale.Sprintf(locale.FlagIgnoreLinksUsage))
+	diff := flag.String("diff", "", locale.Sprintf(locale.FlagDiffUsage))
+	checkContracts := flag.Bool("check-contracts", false, locale.Sprintf(locale.FlagCheckContractsUsage))
+	exportLangDefs := flag.String("export-lang-defs", "", locale.Sprintf(locale.FlagExportLangDefsUsage))
 	flag.Usage = usage
 	flag.Parse()
 
@@ -70,8 +101,69 @@ func main() {
 	case *encodings:
 		locale.Printf(locale.FlagSupportedEncodings, input.Encodings())
 		return
+	case len(*exportLangDefs) > 0:
+		if err := input.ExportLangDefs(os.Stdout, strings.Split(*exportLangDefs, ",")...); err != nil {
+			erro.Println(err)
+			os.Exit(1)
+		}
+		return
 	case *g:
-		genConfigFile(*wd)
+		path, err := genConfigFile(*wd, os.Stdin, os.Stdout, *yes, *force)
+		if err != nil {
+			erro.Println(err)
+			return
+		}
+		info.Println(locale.Sprintf(locale.FlagConfigWritedSuccess, path))
+		return
+	case *stats:
+		printStats(*wd, *format)
+		return
+	case *minCoverage != 0:
+		if !checkCoverage(os.Stdout, *wd, *minCoverage) {
+			os.Exit(1)
+		}
+		return
+	case *checkLinksFlag:
+		if !checkLinks(os.Stdout, *wd, *linkTimeout, *ignoreLinks) {
+			os.Exit(1)
+		}
+		return
+	case len(*diff) > 0:
+		if !runDiff(os.Stdout, *wd, *diff, *format) {
+			os.Exit(1)
+		}
+		return
+	case *lintFlag:
+		if !runLint(*wd, *format, *strict, *failOnTodo, *checkContracts) {
+			os.Exit(1)
+		}
+		return
+	case *hook:
+		path, err := installHook(*wd)
+		if err != nil {
+			erro.Println(err)
+			return
+		}
+		info.Println(locale.Sprintf(locale.FlagHookWritedSuccess, path))
+		return
+	case len(*completion) > 0:
+		if err := writeCompletion(os.Stdout, *completion, flag.CommandLine); err != nil {
+			erro.Println(err)
+			os.Exit(1)
+		}
+		return
+	case len(*mock) > 0:
+		path, err := writeMock(*wd, *mock, *port, *maxMockDelay)
+		if err != nil {
+			erro.Println(err)
+			os.Exit(1)
+		}
+		info.Println(locale.Sprintf(locale.FlagMockWritedSuccess, path))
+		return
+	case *serve:
+		if err := runServe(*wd, *port, *basePath, *tryItBaseURL); err != nil {
+			erro.Println(err)
+		}
 		return
 	}
 
@@ -79,7 +171,7 @@ func main() {
 		buf := new(bytes.Buffer)
 		defer func() { // 在程序结束时，将内容写入到文件
 			profile := filepath.Join(*wd, *pprofType+".prof")
-			if err := ioutil.WriteFile(profile, buf.Bytes(), os.ModePerm); err != nil {
+			if err := os.WriteFile(profile, buf.Bytes(), os.ModePerm); err != nil {
 				erro.Println(err)
 			}
 		}()
@@ -103,76 +195,245 @@ func main() {
 		}
 	}
 
-	run(*wd)
+	if !run(*wd, outputs, *basePath, *tryItBaseURL, *environment, *audience, *parallel, *failOnTodo) {
+		os.Exit(1)
+	}
 }
 
 // 真正的程序入口，main 主要是作参数的处理。
-func run(wd string) {
-	cfg, err := loadConfig(filepath.Join(wd, vars.ConfigFilename))
-	if err != nil {
-		erro.Println(err)
-		return
+//
+// outputs 若不为空，则替代配置文件中的 output 配置项；
+// basePath 若不为空，则替代所有输出中的 basePath 配置项；
+// tryItBaseURL 若不为空，则替代所有输出中的 tryItBaseURL 配置项；
+// environment 若不为空，则只输出该环境以及 all 的 @apiEnvironment 内容；
+// audience 若不为空，则只输出面向该受众的 API；
+// parallel 表示是否同时生成各个输出；
+// failOnTodo 为 true 时，文档中包含 @apiTodo 则不生成文档。
+//
+// 返回值表示是否成功生成了文档。
+func run(wd string, outputs outputFlags, basePath, tryItBaseURL, environment, audience string, parallel, failOnTodo bool) bool {
+	switch environment {
+	case "", types.EnvironmentProd, types.EnvironmentStaging, types.EnvironmentDev:
+	default:
+		envs := []string{types.EnvironmentProd, types.EnvironmentStaging, types.EnvironmentDev}
+		erro.Println(locale.Sprintf(locale.FlagInvalidEnvironment, environment, strings.Join(envs, ",")))
+		return false
 	}
 
-	// 比较版本号兼容问题
-	compatible, err := version.SemVerCompatible(vars.Version(), cfg.Version)
+	switch audience {
+	case "", types.AudiencePublic, types.AudiencePartner, types.AudienceInternal:
+	default:
+		audiences := []string{types.AudiencePublic, types.AudiencePartner, types.AudienceInternal}
+		erro.Println(locale.Sprintf(locale.FlagInvalidAudience, audience, strings.Join(audiences, ",")))
+		return false
+	}
+
+	cfg, err := load(wd)
 	if err != nil {
 		erro.Println(err)
-		return
+		return false
 	}
-	if !compatible {
-		erro.Println(locale.Sprintf(locale.VersionInCompatible))
-		return
+
+	if len(outputs) == 0 {
+		outputs = outputFlags{cfg.Output}
+	}
+
+	for _, o := range outputs {
+		if len(basePath) > 0 {
+			o.BasePath = basePath
+		}
+		if len(tryItBaseURL) > 0 {
+			o.TryItBaseURL = tryItBaseURL
+		}
 	}
 
 	docs, elapsed := input.Parse(cfg.Inputs...)
+	for _, err := range docs.Validate() {
+		erro.Println(err)
+	}
 
-	cfg.Output.Elapsed = elapsed
-	if err := output.Render(docs, cfg.Output); err != nil {
+	if failOnTodo {
+		if todos := docs.Stats().Todos; todos > 0 {
+			erro.Println(locale.Sprintf(locale.ErrHasTodo, todos))
+			return false
+		}
+	}
+
+	if len(environment) > 0 {
+		docs.FilterEnvironment(environment)
+	}
+	if len(audience) > 0 {
+		docs = docs.ForAudience(audience)
+	}
+
+	if err := render(docs, elapsed, outputs, parallel); err != nil {
 		erro.Println(err)
-		return
+		return false
 	}
 
-	info.Println(locale.Sprintf(locale.Complete, cfg.Output.Dir, elapsed))
+	info.Println(locale.Sprintf(locale.Complete, outputs.dirs(), elapsed))
+	return true
 }
 
-func usage() {
-	buf := new(bytes.Buffer)
-	flag.CommandLine.SetOutput(buf)
-	flag.PrintDefaults()
+// 将 docs 输出到 outputs 指定的各个位置，
+// 文档只需要解析一次，各个输出之间互不干扰。
+//
+// parallel 为 true 时，每个输出在单独的协程中生成。
+// output.Render 不会修改 docs，多个协程可以同时读取。
+func render(docs *types.Doc, elapsed time.Duration, outputs []*output.Options, parallel bool) error {
+	if !parallel {
+		for _, o := range outputs {
+			o.Elapsed = elapsed
+			if err := output.Render(docs, o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-	locale.Printf(locale.FlagUsage, vars.Name, buf.String(), vars.RepoURL, vars.OfficialURL)
+	errs := make(chan error, len(outputs))
+	wg := &sync.WaitGroup{}
+	for _, o := range outputs {
+		wg.Add(1)
+		go func(o *output.Options) {
+			defer wg.Done()
+			o.Elapsed = elapsed
+			errs <- output.Render(docs, o)
+		}(o)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 通过 -output 参数指定的输出内容，可以指定多个，
+// 各个输出的目录不能相同或是相互嵌套，
+// 每个的格式均为 type:dir，比如 html:./doc。
+type outputFlags []*output.Options
+
+func (o *outputFlags) String() string {
+	items := make([]string, 0, len(*o))
+	for _, opt := range *o {
+		items = append(items, opt.Type+":"+opt.Dir)
+	}
+	return strings.Join(items, ",")
+}
+
+func (o *outputFlags) Set(v string) error {
+	index := strings.IndexByte(v, ':')
+	if index <= 0 || index == len(v)-1 {
+		return errors.New(locale.Sprintf(locale.FlagInvalidOutput, v))
+	}
+
+	opt := &output.Options{Type: v[:index], Dir: v[index+1:]}
+	if err := opt.Sanitize(); err != nil {
+		return err
+	}
+
+	// 每个输出在生成之前都会清空其目录，不能相同或是相互嵌套。
+	for _, item := range *o {
+		if dirOverlap(item.Dir, opt.Dir) {
+			return errors.New(locale.Sprintf(locale.FlagOutputDirOverlap, opt.Dir, item.Dir))
+		}
+	}
+
+	*o = append(*o, opt)
+	return nil
+}
+
+// 判断 d1 和 d2 是否为同一目录，或是其中一个包含另一个。
+func dirOverlap(d1, d2 string) bool {
+	if abs, err := filepath.Abs(d1); err == nil {
+		d1 = abs
+	}
+	if abs, err := filepath.Abs(d2); err == nil {
+		d2 = abs
+	}
+
+	if len(d1) > len(d2) {
+		d1, d2 = d2, d1
+	}
+	if d1 == d2 {
+		return true
+	}
+
+	if !strings.HasSuffix(d1, string(filepath.Separator)) {
+		d1 += string(filepath.Separator)
+	}
+	return strings.HasPrefix(d2, d1)
 }
 
-// 根据 wd 所在目录的内容生成一个配置文件，并写入到 wd 目录下的 .apidoc.yaml 中
-func genConfigFile(wd string) {
-	o, err := input.Detect(wd, true)
+// 所有的输出目录，以逗号分隔
+func (o outputFlags) dirs() string {
+	dirs := make([]string, 0, len(o))
+	for _, opt := range o {
+		dirs = append(dirs, opt.Dir)
+	}
+	return strings.Join(dirs, ",")
+}
+
+// 加载 wd 目录下的配置文件，并检测其版本号是否与当前程序兼容。
+func load(wd string) (*config, error) {
+	cfg, err := loadConfig(filepath.Join(wd, vars.ConfigFilename))
 	if err != nil {
-		erro.Println(err)
-		return
+		return nil, err
 	}
 
-	cfg := &config{
-		Version: vars.Version(),
-		Inputs:  []*input.Options{o},
-		Output: &output.Options{
-			Dir: filepath.Join(o.Dir, "doc"),
-		},
+	// 比较版本号兼容问题
+	compatible, err := version.SemVerCompatible(vars.Version(), cfg.Version)
+	if err != nil {
+		return nil, err
+	}
+	if !compatible {
+		return nil, errors.New(locale.Sprintf(locale.VersionInCompatible))
 	}
 
-	data, err := yaml.Marshal(cfg)
+	return cfg, nil
+}
+
+// 输出文档的统计信息，format 指定了输出的格式。
+func printStats(wd, format string) {
+	cfg, err := load(wd)
 	if err != nil {
 		erro.Println(err)
 		return
 	}
 
-	path := filepath.Join(wd, vars.ConfigFilename)
-	if err = ioutil.WriteFile(path, data, os.ModePerm); err != nil {
-		erro.Println(err)
-		return
+	docs, _ := input.Parse(cfg.Inputs...)
+	stats := docs.Stats()
+
+	switch strings.ToLower(format) {
+	case vars.FormatText:
+		locale.Printf(locale.FlagStats, stats.Total, stats.Description, stats.Params, stats.Responses, stats.Coverage, stats.Todos)
+		for _, c := range types.DataClassifications {
+			if n, found := stats.Classifications[c]; found {
+				locale.Printf(locale.FlagStatsClassification, c, n)
+			}
+		}
+	case vars.FormatJSON:
+		data, err := json.MarshalIndent(stats, "", strings.Repeat(" ", vars.JSONIndent))
+		if err != nil {
+			erro.Println(err)
+			return
+		}
+		fmt.Println(string(data))
+	default:
+		erro.Println(locale.Sprintf(locale.FlagInvalidFormat))
 	}
+}
 
-	info.Println(locale.Sprintf(locale.FlagConfigWritedSuccess, path))
+func usage() {
+	buf := new(bytes.Buffer)
+	flag.CommandLine.SetOutput(buf)
+	flag.PrintDefaults()
+
+	locale.Printf(locale.FlagUsage, vars.Name, buf.String(), vars.RepoURL, vars.OfficialURL)
 }
 
 func printVersion() {
@@ -0,0 +1,311 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/utils"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+var _ flag.Value = &outputFlags{}
+
+func TestOutputFlags(t *testing.T) {
+	a := assert.New(t)
+
+	o := outputFlags{}
+	a.NotError(o.Set("html:./doc"))
+	a.NotError(o.Set("json:./doc-data"))
+	a.Equal(len(o), 2).
+		Equal(o[0].Type, output.TypeHTML).
+		Equal(o[1].Dir, "./doc-data").
+		Equal(o.String(), "html:./doc,json:./doc-data").
+		Equal(o.dirs(), "./doc,./doc-data")
+
+	a.Error(o.Set("./doc"))
+	a.Error(o.Set(":./doc"))
+	a.Error(o.Set("html:"))
+	a.Error(o.Set("xml:./doc"))
+
+	// 相同或是相互嵌套的目录
+	a.Error(o.Set("raml:./doc"))
+	a.Error(o.Set("raml:doc/"))
+	a.Error(o.Set("raml:./doc/raml"))
+	a.Error(o.Set("raml:."))
+	a.Equal(len(o), 2)
+}
+
+func TestDirOverlap(t *testing.T) {
+	a := assert.New(t)
+
+	a.True(dirOverlap("./doc", "./doc")).
+		True(dirOverlap("./doc", "doc/")).
+		True(dirOverlap("./doc", "./doc/data")).
+		True(dirOverlap("./doc/data", "./doc")).
+		True(dirOverlap("./doc", "./data/../doc/raml")).
+		True(dirOverlap("/", "/doc"))
+
+	a.False(dirOverlap("./doc", "./doc-data")).
+		False(dirOverlap("./doc/html", "./doc/raml")).
+		False(dirOverlap("./html", "./raml"))
+}
+
+func TestRender(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := types.NewDoc()
+	docs.Title = "test"
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "users", Group: "users"})
+
+	htmlDir := filepath.Join(dir, "html")
+	jsonDir := filepath.Join(dir, "json")
+	o := outputFlags{}
+	a.NotError(o.Set(output.TypeHTML + ":" + htmlDir))
+	a.NotError(o.Set(output.TypeJSON + ":" + jsonDir))
+	a.NotError(render(docs, 0, o, true))
+
+	a.True(utils.FileExists(filepath.Join(htmlDir, "index.html")))
+	assertJSONFile(a, filepath.Join(htmlDir, vars.JSONDataDirName, vars.PageFileName+".json"))
+	assertJSONFile(a, filepath.Join(htmlDir, vars.JSONDataDirName, vars.GroupFilePrefix+"users.json"))
+
+	a.False(utils.FileExists(filepath.Join(jsonDir, "index.html")))
+	assertJSONFile(a, filepath.Join(jsonDir, vars.PageFileName+".json"))
+	assertJSONFile(a, filepath.Join(jsonDir, vars.GroupFilePrefix+"users.json"))
+}
+
+func TestRun_failOnTodo(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiTodo 补充返回值
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	docDir := filepath.Join(dir, "doc")
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Type: output.TypeJSON, Dir: docDir},
+	}
+	data, err := yaml.Marshal(cfg)
+	a.NotError(err)
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.ConfigFilename), data, os.ModePerm))
+
+	_, code1 := runMain(a, "-wd", dir, "-fail-on-todo")
+	a.Equal(code1, 1).False(utils.FileExists(docDir))
+
+	_, code0 := runMain(a, "-wd", dir)
+	a.Equal(code0, 0).True(utils.FileExists(docDir))
+}
+
+func TestRun_environment(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	code := `package main
+
+// @api get /users users
+// @apiEnvironment prod prod-note
+// @apiEnvironment staging staging-note
+// @apiEnvironment all all-note
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(dir, "main.go"), []byte(code), os.ModePerm))
+
+	docDir := filepath.Join(dir, "doc")
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{{Lang: "go", Dir: dir}},
+		Output:  &output.Options{Type: output.TypeJSON, Dir: docDir},
+	}
+	data, err := yaml.Marshal(cfg)
+	a.NotError(err)
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.ConfigFilename), data, os.ModePerm))
+	group := filepath.Join(docDir, vars.GroupFilePrefix+vars.DefaultGroupName+".json")
+
+	// 未指定环境，输出所有内容
+	_, exitCode := runMain(a, "-wd", dir)
+	a.Equal(exitCode, 0)
+	data, err = os.ReadFile(group)
+	a.NotError(err)
+	a.True(strings.Contains(string(data), "prod-note")).
+		True(strings.Contains(string(data), "staging-note")).
+		True(strings.Contains(string(data), "all-note"))
+
+	_, exitCode = runMain(a, "-wd", dir, "-environment", "prod")
+	a.Equal(exitCode, 0)
+	data, err = os.ReadFile(group)
+	a.NotError(err)
+	a.True(strings.Contains(string(data), "prod-note")).
+		False(strings.Contains(string(data), "staging-note")).
+		True(strings.Contains(string(data), "all-note"))
+
+	_, exitCode = runMain(a, "-wd", dir, "-environment", "dev")
+	a.Equal(exitCode, 0)
+	data, err = os.ReadFile(group)
+	a.NotError(err)
+	a.False(strings.Contains(string(data), "prod-note")).
+		False(strings.Contains(string(data), "staging-note")).
+		True(strings.Contains(string(data), "all-note"))
+
+	// 无效的环境
+	_, exitCode = runMain(a, "-wd", dir, "-environment", "test")
+	a.Equal(exitCode, 1)
+}
+
+// 顺序生成和同时生成的内容应该是相同的
+func TestRender_parallel(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := newBenchDoc(50)
+	formats := []string{output.TypeJSON, output.TypeRAML, output.TypeBlueprint, output.TypeWord}
+	files := []string{
+		filepath.Join(output.TypeJSON, vars.GroupFilePrefix+"group0.json"),
+		filepath.Join(output.TypeJSON, vars.GroupFilePrefix+"group4.json"),
+		filepath.Join(output.TypeRAML, vars.RAMLFileName),
+		filepath.Join(output.TypeBlueprint, vars.BlueprintFileName),
+		filepath.Join(output.TypeWord, vars.WordFileName),
+	}
+
+	sequential := filepath.Join(dir, "sequential")
+	a.NotError(render(docs, 0, newBenchOutputs(a, sequential, formats...), false))
+
+	parallel := filepath.Join(dir, "parallel")
+	a.NotError(render(docs, 0, newBenchOutputs(a, parallel, formats...), true))
+
+	for _, file := range files {
+		s, err := os.ReadFile(filepath.Join(sequential, file))
+		a.NotError(err).NotEmpty(s)
+		p, err := os.ReadFile(filepath.Join(parallel, file))
+		a.NotError(err)
+		a.Equal(s, p, "%s 的内容不相同", file)
+	}
+}
+
+// go1.27 BenchmarkRender_sequential 	      20	  29093560 ns/op
+func BenchmarkRender_sequential(b *testing.B) {
+	benchmarkRender(b, false)
+}
+
+// 以下结果为单核环境，此时两者没有差别，多核环境下用时会随着输出的数量减少。
+//
+// go1.27 BenchmarkRender_parallel   	      20	  29477480 ns/op
+func BenchmarkRender_parallel(b *testing.B) {
+	benchmarkRender(b, true)
+}
+
+// 以 500 个 API 生成 raml、blueprint 和 word 三种格式的文档
+func benchmarkRender(b *testing.B, parallel bool) {
+	a := assert.New(b)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := newBenchDoc(500)
+	outputs := newBenchOutputs(a, dir, output.TypeRAML, output.TypeBlueprint, output.TypeWord)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := render(docs, 0, outputs, parallel); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// 生成包含 size 个 API 的文档，平均分布在 10 个分组中
+func newBenchDoc(size int) *types.Doc {
+	docs := types.NewDoc()
+	docs.Title = "bench"
+	docs.BaseURL = "https://example.com"
+
+	for i := 0; i < size; i++ {
+		id := strconv.Itoa(i)
+		docs.NewAPI(&types.API{
+			Method:  "POST",
+			URL:     "/users/" + id + "/{id}",
+			Summary: "summary " + id,
+			Group:   "group" + strconv.Itoa(i%10),
+			Params:  []*types.Param{{Name: "id", Type: "int", Summary: "id"}},
+			Request: &types.Request{
+				Type:   "application/json",
+				Params: []*types.Param{{Name: "name", Type: "string", Summary: "name"}},
+			},
+			Success: &types.Response{
+				Code:    "200",
+				Summary: "OK",
+				Params:  []*types.Param{{Name: "id", Type: "int", Summary: "id"}},
+			},
+		})
+	}
+
+	return docs
+}
+
+// 为每种 formats 生成一个输出，输出目录为 dir 下与类型同名的目录
+func newBenchOutputs(a *assert.Assertion, dir string, formats ...string) []*output.Options {
+	o := outputFlags{}
+	for _, typ := range formats {
+		a.NotError(o.Set(typ + ":" + filepath.Join(dir, typ)))
+	}
+	return o
+}
+
+// path 指向的文件是否为合法的 JSON 文件
+func assertJSONFile(a *assert.Assertion, path string) {
+	data, err := os.ReadFile(path)
+	a.NotError(err)
+
+	v := map[string]interface{}{}
+	a.NotError(json.Unmarshal(data, &v)).NotEmpty(v)
+}
+
+func TestMain_exportLangDefs(t *testing.T) {
+	a := assert.New(t)
+
+	out, code := runMain(a, "-export-lang-defs", "go,python")
+	a.Equal(code, 0)
+	defs := []map[string]interface{}{}
+	a.NotError(json.Unmarshal([]byte(out), &defs))
+	a.Equal(len(defs), 2).
+		Equal(defs[0]["name"], "go").
+		Equal(defs[1]["name"], "python")
+
+	_, code = runMain(a, "-export-lang-defs", "pascal")
+	a.Equal(code, 1)
+}
@@ -0,0 +1,51 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+)
+
+// 根据 wd 中配置的文档内容，在 dir 目录下生成模拟服务的 main.go，返回该文件的路径。
+//
+// addr 为模拟服务默认的监听地址，运行时可以通过 -port 参数修改；
+// maxDelay 为 @apiMockDelay 默认的上限，运行时可以通过 -max-delay 参数修改。
+func writeMock(wd, dir, addr string, maxDelay int) (string, error) {
+	if maxDelay < 0 {
+		return "", errors.New(locale.Sprintf(locale.FlagInvalidMaxMockDelay, maxDelay))
+	}
+
+	cfg, err := load(wd)
+	if err != nil {
+		return "", err
+	}
+
+	docs, _ := input.Parse(cfg.Inputs...)
+	for _, err := range docs.Validate() {
+		erro.Println(err)
+	}
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, "main.go")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err = docs.WriteMockServer(file, addr, maxDelay); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 生成模拟服务的代码，编译并运行，再访问其中的 API。
+func TestWriteMock(t *testing.T) {
+	if testing.Short() {
+		t.Skip("需要编译生成的代码")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("未找到 go 命令")
+	}
+
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	src := filepath.Join(dir, "src")
+	a.NotError(os.MkdirAll(src, os.ModePerm))
+	code := `package main
+
+// @api get /users/{id} user
+// @apiSuccess 200 OK
+// @apiExample json
+// {"id":1,"name":"caixw"}
+func user() {}
+
+// @api get /slow slow
+// @apiMockDelay 50
+// @apiSuccess 204 OK
+func slow() {}
+
+// @api get /slower slower
+// @apiMockDelay 60000
+// @apiSuccess 204 OK
+func slower() {}
+
+// @api get /limited limited
+// @apiThrottle 10 per second
+// @apiSuccess 204 OK
+func limited() {}
+`
+	a.NotError(os.WriteFile(filepath.Join(src, "main.go"), []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{{Lang: "go", Dir: src}},
+		Output:  &output.Options{Dir: filepath.Join(dir, "doc")},
+	}
+	data, err := yaml.Marshal(cfg)
+	a.NotError(err)
+	a.NotError(os.WriteFile(filepath.Join(dir, vars.ConfigFilename), data, os.ModePerm))
+
+	mockDir := filepath.Join(dir, "mock-server")
+	_, exitCode := runMain(a, "-wd", dir, "-mock", mockDir, "-port", ":8081", "-max-mock-delay", "100")
+	a.Equal(exitCode, 0)
+
+	// 编译
+	bin := filepath.Join(mockDir, "mock-server")
+	build := exec.Command(goBin, "build", "-o", bin, "main.go")
+	build.Dir = mockDir
+	build.Env = append(os.Environ(), "GOFLAGS=", "GO111MODULE=off")
+	out, err := build.CombinedOutput()
+	a.NotError(err, string(out))
+
+	// 运行，端口由 -port 指定，覆盖生成时的默认值
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	a.NotError(err)
+	addr := l.Addr().String()
+	a.NotError(l.Close())
+
+	server := exec.Command(bin, "-port", addr)
+	a.NotError(server.Start())
+	defer server.Process.Kill()
+
+	var resp *http.Response
+	for i := 0; i < 50; i++ {
+		if resp, err = http.Get("http://" + addr + "/users/1"); err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	a.NotError(err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	a.NotError(err)
+	a.Equal(resp.StatusCode, http.StatusOK).
+		Equal(resp.Header.Get("Content-Type"), "application/json").
+		Equal(string(body), `{"id":1,"name":"caixw"}`)
+
+	resp, err = http.Get("http://" + addr + "/not-exists")
+	a.NotError(err)
+	resp.Body.Close()
+	a.Equal(resp.StatusCode, http.StatusNotFound)
+
+	// @apiMockDelay
+	start := time.Now()
+	resp, err = http.Get("http://" + addr + "/slow")
+	a.NotError(err)
+	resp.Body.Close()
+	a.Equal(resp.StatusCode, http.StatusNoContent).
+		True(time.Since(start) >= 50*time.Millisecond)
+
+	// 超过 -max-mock-delay 的，只等待 100 毫秒
+	start = time.Now()
+	resp, err = http.Get("http://" + addr + "/slower")
+	a.NotError(err)
+	resp.Body.Close()
+	elapsed := time.Since(start)
+	a.Equal(resp.StatusCode, http.StatusNoContent).
+		True(elapsed >= 100*time.Millisecond).
+		True(elapsed < 10*time.Second)
+
+	// @apiThrottle，第 11 个请求返回 429
+	for i := 0; i < 10; i++ {
+		resp, err = http.Get("http://" + addr + "/limited")
+		a.NotError(err)
+		resp.Body.Close()
+		a.Equal(resp.StatusCode, http.StatusNoContent, i)
+	}
+	resp, err = http.Get("http://" + addr + "/limited")
+	a.NotError(err)
+	resp.Body.Close()
+	a.Equal(resp.StatusCode, http.StatusTooManyRequests).
+		Equal(resp.Header.Get("Retry-After"), "1")
+
+	// 下一个时间窗口重新计数
+	time.Sleep(time.Second)
+	resp, err = http.Get("http://" + addr + "/limited")
+	a.NotError(err)
+	resp.Body.Close()
+	a.Equal(resp.StatusCode, http.StatusNoContent)
+}
+
+func TestWriteMock_invalidMaxDelay(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	path, err := writeMock(dir, filepath.Join(dir, "mock-server"), ":8080", -1)
+	a.Error(err).Empty(path)
+}
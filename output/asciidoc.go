@@ -0,0 +1,191 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// @apiNote 的类型与 AsciiDoc 中 admonition 的对应关系
+var asciidocAdmonitions = map[string]string{
+	types.NoteTypeInfo:    "NOTE",
+	types.NoteTypeTip:     "TIP",
+	types.NoteTypeWarning: "WARNING",
+	types.NoteTypeDanger:  "CAUTION",
+}
+
+// 将 docs 以 AsciiDoc 的格式输出到 o.Dir 目录下。
+func renderAsciidoc(docs *types.Doc, o *Options) error {
+	file, err := os.Create(filepath.Join(o.Dir, vars.AsciidocFileName))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WriteAsciidoc(file, docs, o)
+}
+
+// WriteAsciidoc 将 docs 转换成 AsciiDoc 格式的内容，写入到 w 中。
+//
+// 章节的层级与 html 的标题相同：文档标题为 =，每个组为 ==，
+// 每个 API 为 ===，请求和各个响应为 ====。
+// 参数和报头以表格的形式输出，@apiNote 以 admonition 的形式输出。
+func WriteAsciidoc(w io.Writer, docs *types.Doc, o *Options) error {
+	basePath := docs.BasePath
+	if len(o.BasePath) > 0 {
+		basePath = o.BasePath
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("= " + docs.Title + "\n")
+	if len(docs.Version) > 0 {
+		buf.WriteString(":revnumber: " + docs.Version + "\n")
+	}
+	buf.WriteString(":toc:\n")
+
+	if len(docs.BaseURL) > 0 {
+		buf.WriteString("\n" + joinPath(docs.BaseURL, basePath) + "\n")
+		basePath = ""
+	}
+	if len(docs.Content) > 0 {
+		buf.WriteString("\n" + strings.TrimSpace(docs.Content) + "\n")
+	}
+
+	groups := make(map[string][]*types.API, 10)
+	for _, api := range docs.Apis {
+		if o.groupIsEnable(api.Group) {
+			groups[api.Group] = append(groups[api.Group], api)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		apis := groups[name]
+		sortAPIs(apis)
+
+		buf.WriteString("\n== " + name + "\n")
+		for _, api := range apis {
+			writeAsciidocAPI(buf, api, basePath)
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func writeAsciidocAPI(buf *bytes.Buffer, api *types.API, basePath string) {
+	buf.WriteString("\n=== " + strings.ToUpper(api.Method) + " " + joinPath(basePath, api.URL) + " " + api.Summary + "\n")
+	if len(api.Description) > 0 {
+		buf.WriteString("\n" + strings.TrimSpace(api.Description) + "\n")
+	}
+
+	for _, note := range api.Notes {
+		admonition, found := asciidocAdmonitions[note.Type]
+		if !found {
+			admonition = asciidocAdmonitions[types.NoteTypeInfo]
+		}
+		buf.WriteString("\n[" + admonition + "]\n====\n" + note.Text + "\n====\n")
+	}
+
+	if len(api.Links) > 0 {
+		buf.WriteString("\n.参考\n")
+		for _, link := range api.Links {
+			buf.WriteString("* " + link.URL + "[" + strings.Replace(link.Label, "]", `\]`, -1) + "]\n")
+		}
+	}
+
+	writeAsciidocParams(buf, "参数", api.Params)
+	writeAsciidocParams(buf, "查询参数", api.Queries)
+
+	if req := api.Request; req != nil {
+		title := "请求"
+		if len(req.Type) > 0 {
+			title += " (" + mediaType(strings.Split(req.Type, ",")[0]) + ")"
+		}
+		buf.WriteString("\n==== " + title + "\n")
+		writeAsciidocHeaders(buf, req.Headers)
+		writeAsciidocParams(buf, "参数", req.Params)
+		writeAsciidocExamples(buf, req.Examples)
+	}
+
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp == nil {
+			continue
+		}
+
+		buf.WriteString("\n==== " + resp.Code + " " + resp.Summary + "\n")
+		writeAsciidocHeaders(buf, resp.Headers)
+		writeAsciidocParams(buf, "参数", resp.Params)
+		writeAsciidocExamples(buf, resp.Examples)
+	}
+}
+
+func writeAsciidocParams(buf *bytes.Buffer, title string, params []*types.Param) {
+	if len(params) == 0 {
+		return
+	}
+
+	buf.WriteString("\n." + title + "\n")
+	buf.WriteString("[cols=\"1,1,3\",options=\"header\"]\n|===\n")
+	buf.WriteString("|名称 |类型 |描述\n")
+	for _, p := range params {
+		writeAsciidocRow(buf, p.Name, p.Type, p.Summary)
+	}
+	buf.WriteString("|===\n")
+}
+
+func writeAsciidocHeaders(buf *bytes.Buffer, headers map[string]string) {
+	if len(headers) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf.WriteString("\n.报头\n")
+	buf.WriteString("[cols=\"1,3\",options=\"header\"]\n|===\n")
+	buf.WriteString("|名称 |描述\n")
+	for _, k := range keys {
+		writeAsciidocRow(buf, k, headers[k])
+	}
+	buf.WriteString("|===\n")
+}
+
+func writeAsciidocExamples(buf *bytes.Buffer, examples []*types.Example) {
+	for _, e := range examples {
+		buf.WriteString("\n.示例\n")
+		if len(e.Lang) > 0 {
+			buf.WriteString("[source," + e.Lang + "]\n")
+		}
+		buf.WriteString("----\n" + strings.Trim(e.Code, "\n") + "\n----\n")
+	}
+}
+
+// 输出表格中的一行，单元格中的 | 需要转义。
+func writeAsciidocRow(buf *bytes.Buffer, cells ...string) {
+	for i, cell := range cells {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString("|" + strings.Replace(cell, "|", `\|`, -1))
+	}
+	buf.WriteByte('\n')
+}
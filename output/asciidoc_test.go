@@ -0,0 +1,108 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/utils"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func newAsciidocDoc() *types.Doc {
+	docs := newBlueprintDoc()
+	docs.Version = "1.0.0"
+
+	api := docs.Apis[0]
+	api.Notes = append(api.Notes,
+		&types.Note{Type: types.NoteTypeInfo, Text: "line1\nline2"},
+		&types.Note{Type: types.NoteTypeDanger, Text: "会删除缓存"},
+	)
+	api.Links = append(api.Links, &types.Link{URL: "https://example.com/wiki"})
+	api.Success.Params = append(api.Success.Params, &types.Param{Name: "status", Type: "string", Summary: "active|blocked"})
+
+	return docs
+}
+
+func TestWriteAsciidoc(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(WriteAsciidoc(buf, newAsciidocDoc(), &Options{}))
+
+	golden, err := os.ReadFile("./testdata/asciidoc.adoc")
+	a.NotError(err)
+	a.Equal(buf.String(), string(golden))
+}
+
+// 检测输出内容的结构是否正确
+func TestWriteAsciidoc_structure(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(WriteAsciidoc(buf, newAsciidocDoc(), &Options{Groups: []string{"users"}}))
+	lines := strings.Split(buf.String(), "\n")
+
+	sections := map[string][]string{}
+	var tables, blocks, listings int
+	for i, line := range lines {
+		for _, prefix := range []string{"= ", "== ", "=== ", "==== "} {
+			if strings.HasPrefix(line, prefix) {
+				sections[prefix] = append(sections[prefix], line[len(prefix):])
+				if prefix != "= " { // 章节之前必须是空行
+					a.Empty(lines[i-1], line)
+				}
+			}
+		}
+
+		switch line {
+		case "|===":
+			tables++
+		case "====":
+			blocks++
+		case "----":
+			listings++
+		}
+	}
+
+	a.Equal(sections["= "], []string{"test"}).
+		Equal(sections["== "], []string{"users"}).
+		Equal(sections["=== "], []string{"GET /users/{id} 获取用户"}).
+		Equal(sections["==== "], []string{"200 OK", "404 不存在"})
+
+	// 分隔符都是成对出现的
+	a.Equal(tables, 8).Equal(blocks, 6).Equal(listings, 2)
+
+	s := buf.String()
+	a.True(strings.Contains(s, "\n:revnumber: 1.0.0\n")).
+		True(strings.Contains(s, "\nhttps://api.caixw.io/v1\n")).
+		True(strings.Contains(s, "\n[WARNING]\n====\n需要登录\n====\n")).
+		True(strings.Contains(s, "\n[NOTE]\n====\nline1\nline2\n====\n")).
+		True(strings.Contains(s, "\n[CAUTION]\n====\n会删除缓存\n====\n")).
+		True(strings.Contains(s, "\n* https://example.com/issues/1[需求文档]\n")).
+		True(strings.Contains(s, "\n* https://example.com/wiki[]\n")).
+		True(strings.Contains(s, "\n|status |string |active\\|blocked\n")).
+		False(strings.Contains(s, "/login")) // 未指定的分组
+}
+
+func TestRender_asciidoc(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	o := &Options{Type: TypeAsciidoc, Dir: dir}
+	a.NotError(o.Sanitize())
+	a.NotError(Render(newAsciidocDoc(), o))
+	a.True(utils.FileExists(filepath.Join(dir, vars.AsciidocFileName)))
+}
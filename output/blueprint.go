@@ -0,0 +1,239 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 将 docs 以 API Blueprint 的格式输出到 o.Dir 目录下。
+func renderBlueprint(docs *types.Doc, o *Options) error {
+	file, err := os.Create(filepath.Join(o.Dir, vars.BlueprintFileName))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return writeBlueprint(file, docs, o)
+}
+
+// 将 docs 转换成 API Blueprint 格式的内容，写入到 w 中。
+//
+// 每个组对应一个 # Group，
+// 每个 API 对应一个 ## summary [METHOD path] 的资源。
+func writeBlueprint(w io.Writer, docs *types.Doc, o *Options) error {
+	basePath := docs.BasePath
+	if len(o.BasePath) > 0 {
+		basePath = o.BasePath
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("FORMAT: 1A\n")
+	if len(docs.BaseURL) > 0 {
+		buf.WriteString("HOST: " + joinPath(docs.BaseURL, basePath) + "\n")
+		basePath = ""
+	}
+
+	buf.WriteString("\n# " + docs.Title + "\n")
+	if len(docs.Content) > 0 {
+		buf.WriteString("\n" + strings.TrimSpace(docs.Content) + "\n")
+	}
+
+	groups := make(map[string][]*types.API, 10)
+	for _, api := range docs.Apis {
+		if o.groupIsEnable(api.Group) {
+			groups[api.Group] = append(groups[api.Group], api)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		apis := groups[name]
+		sortAPIs(apis)
+
+		buf.WriteString("\n# Group " + name + "\n")
+		for _, api := range apis {
+			writeBlueprintAPI(buf, api, basePath)
+		}
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func writeBlueprintAPI(buf *bytes.Buffer, api *types.API, basePath string) {
+	path := joinPath(basePath, api.URL)
+	if len(api.Queries) > 0 {
+		queries := make([]string, 0, len(api.Queries))
+		for _, q := range api.Queries {
+			queries = append(queries, q.Name)
+		}
+		path += "{?" + strings.Join(queries, ",") + "}"
+	}
+
+	buf.WriteString("\n## " + api.Summary + " [" + strings.ToUpper(api.Method) + " " + path + "]\n")
+	if len(api.Description) > 0 {
+		buf.WriteString("\n" + strings.TrimSpace(api.Description) + "\n")
+	}
+
+	if len(api.Notes) > 0 {
+		buf.WriteString("\n" + notesMarkdown(api.Notes, "> ") + "\n")
+	}
+
+	if len(api.Links) > 0 {
+		buf.WriteString("\n### References\n\n" + linksMarkdown(api.Links) + "\n")
+	}
+
+	if len(api.Changelog) > 0 {
+		buf.WriteString("\n### Changelog\n\n" + changelogMarkdown(api.Changelog) + "\n")
+	}
+
+	if len(api.Params) > 0 || len(api.Queries) > 0 {
+		buf.WriteString("\n+ Parameters\n")
+		for _, p := range api.Params {
+			writeBlueprintParam(buf, 1, p, true)
+		}
+		for _, p := range api.Queries {
+			writeBlueprintParam(buf, 1, p, false)
+		}
+	}
+
+	if req := api.Request; req != nil {
+		typ := ""
+		if api.Multipart {
+			typ = types.MultipartFormData
+		} else if len(req.Type) > 0 {
+			typ = strings.Split(req.Type, ",")[0]
+		} else if len(api.Consumes) > 0 {
+			typ = api.Consumes[0]
+		}
+
+		var body string
+		if len(req.Examples) > 0 {
+			body = req.Examples[0].Code
+		}
+		writeBlueprintPayload(buf, "Request", typ, req.Headers, req.Params, body)
+	}
+
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp == nil {
+			continue
+		}
+
+		typ := ""
+		if cts, found := api.ContentTypes[resp.Code]; found {
+			typ = cts[0]
+		} else if resp == api.Success && len(api.Negotiation) > 0 {
+			typ = api.Negotiation[0]
+		} else if len(api.Produces) > 0 {
+			typ = api.Produces[0]
+		}
+
+		// 合并 @apiResponseHeader 和 @apiCacheControl 指定的报头
+		rh := api.ResponseHeadersOf(resp.Code)
+		if resp == api.Success {
+			rh = append(rh, api.CacheHeaders()...)
+		}
+		headers := resp.Headers
+		if len(rh) > 0 {
+			headers = make(map[string]string, len(resp.Headers)+len(rh))
+			for _, h := range rh {
+				headers[h.Name] = h.Summary
+			}
+			for k, v := range resp.Headers {
+				headers[k] = v
+			}
+		}
+
+		if len(resp.Examples) == 0 {
+			writeBlueprintPayload(buf, "Response "+resp.Code, typ, headers, resp.Params, "")
+			continue
+		}
+
+		// 每个示例代码对应一个 Response 区块
+		for _, e := range resp.Examples {
+			writeBlueprintPayload(buf, "Response "+resp.Code, e.Type, headers, resp.Params, e.Code)
+		}
+	}
+}
+
+// 输出 + Request 或是 + Response 区块
+func writeBlueprintPayload(buf *bytes.Buffer, title, typ string, headers map[string]string, params []*types.Param, body string) {
+	buf.WriteString("\n+ " + title)
+	if len(typ) > 0 {
+		buf.WriteString(" (" + mediaType(typ) + ")")
+	}
+	buf.WriteString("\n")
+
+	if len(headers) > 0 {
+		keys := make([]string, 0, len(headers))
+		for k := range headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("\n    + Headers\n\n")
+		for _, k := range keys {
+			buf.WriteString(strings.Repeat(" ", 12) + k + ": " + headers[k] + "\n")
+		}
+	}
+
+	if len(params) > 0 {
+		buf.WriteString("\n    + Attributes\n")
+		for _, p := range params {
+			writeBlueprintParam(buf, 2, p, false)
+		}
+	}
+
+	if body = strings.Trim(body, "\n"); len(body) > 0 {
+		buf.WriteString("\n    + Body\n\n")
+		for _, line := range strings.Split(body, "\n") {
+			if len(strings.TrimSpace(line)) == 0 {
+				buf.WriteString("\n")
+				continue
+			}
+			buf.WriteString(strings.Repeat(" ", 12) + line + "\n")
+		}
+	}
+}
+
+// 输出一个参数，depth 表示列表的层级，从 1 开始。
+func writeBlueprintParam(buf *bytes.Buffer, depth int, p *types.Param, required bool) {
+	buf.WriteString(strings.Repeat(" ", depth*4) + "+ " + p.Name + " (" + blueprintType(p.Type))
+	if required {
+		buf.WriteString(", required")
+	}
+	buf.WriteString(")")
+
+	if len(p.Summary) > 0 {
+		buf.WriteString(" - " + p.Summary)
+	}
+	buf.WriteString("\n")
+}
+
+// 将参数类型转换成 MSON 的基本类型，无法识别的类型统一为 string。
+func blueprintType(typ string) string {
+	switch t := ramlType(typ); t {
+	case "integer":
+		return "number"
+	case "number", "boolean", "array", "object", "string":
+		return t
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/utils"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func newBlueprintDoc() *types.Doc {
+	docs := types.NewDoc()
+	docs.Title = "test"
+	docs.BaseURL = "https://api.caixw.io"
+	docs.BasePath = "/v1"
+	docs.Content = "line1\nline2"
+
+	docs.NewAPI(&types.API{
+		Method:      "GET",
+		URL:         "/users/{id}",
+		Summary:     "获取用户",
+		Description: "获取指定用户的信息",
+		Notes:       []*types.Note{{Type: types.NoteTypeWarning, Text: "需要登录"}},
+		Links:       []*types.Link{{URL: "https://example.com/issues/1", Label: "需求文档"}},
+		Changelog: []*types.ChangelogEntry{
+			{Version: "1.1.0", Date: "2017-11-01", Description: "返回 email 字段"},
+			{Version: "1.0.0", Date: "2017-10-01", Description: "初始版本"},
+		},
+		Group:    "users",
+		Produces: []string{"application/json"},
+		Params:   []*types.Param{{Name: "id", Type: "int", Summary: "用户 ID"}},
+		Queries:  []*types.Param{{Name: "fields", Type: "string", Summary: "返回的字段"}},
+		Success: &types.Response{
+			Code:     "200",
+			Summary:  "OK",
+			Headers:  map[string]string{"ETag": "版本"},
+			Params:   []*types.Param{{Name: "name", Type: "string", Summary: "用户名"}},
+			Examples: []*types.Example{{Type: "json", Code: "{\n    \"name\": \"caixw\"\n}"}},
+		},
+		Error: &types.Response{Code: "404", Summary: "不存在"},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/login",
+		Summary: "登录",
+		Group:   "auth",
+		Request: &types.Request{
+			Type:     "json",
+			Headers:  map[string]string{"Accept-Language": "语言"},
+			Params:   []*types.Param{{Name: "password", Type: "string", Summary: "密码"}},
+			Examples: []*types.Example{{Type: "json", Code: `{"password":"123"}`}},
+		},
+		Success: &types.Response{Code: "201", Summary: "OK"},
+	})
+
+	return docs
+}
+
+func TestWriteBlueprint(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeBlueprint(buf, newBlueprintDoc(), &Options{}))
+
+	golden, err := os.ReadFile("./testdata/blueprint.apib")
+	a.NotError(err)
+	a.Equal(buf.String(), string(golden))
+}
+
+func TestRender_blueprint(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	o := &Options{Type: TypeBlueprint, Dir: dir}
+	a.NotError(o.Sanitize())
+	a.NotError(Render(newBlueprintDoc(), o))
+	a.True(utils.FileExists(filepath.Join(dir, vars.BlueprintFileName)))
+}
@@ -18,11 +18,32 @@ import (
 	"github.com/issue9/utils"
 )
 
+// 支持的输出类型
+const (
+	TypeHTML      = "html"      // 输出静态页面及其所需要的 JSON 数据
+	TypeJSON      = "json"      // 仅输出 JSON 数据
+	TypeRAML      = "raml"      // 输出 RAML 1.0 格式的文档
+	TypeBlueprint = "blueprint" // 输出 API Blueprint 格式的文档
+	TypeSingle    = "single"    // 输出不依赖其它文件的单个 html 文件
+	TypeWord      = "word"      // 输出 docx 格式的 Word 文档
+	TypePDF       = "pdf"       // 输出 PDF 文档
+	TypeAsciidoc  = "asciidoc"  // 输出 AsciiDoc 格式的文档
+)
+
 // Options 指定了渲染输出的相关设置项。
 type Options struct {
-	Dir     string        `yaml:"dir"`              // 文档的保存目录
-	Groups  []string      `yaml:"groups,omitempty"` // 仅输出这些组，为空表示输出所有
-	Elapsed time.Duration `yaml:"-"`                // 编译用时
+	Type     string        `yaml:"type,omitempty"`     // 输出的类型，默认为 html
+	Dir      string        `yaml:"dir"`                // 文档的保存目录
+	Groups   []string      `yaml:"groups,omitempty"`   // 仅输出这些组，为空表示输出所有
+	BasePath string        `yaml:"basePath,omitempty"` // 所有 API 地址的前缀，会覆盖文档中的 @apiBasePath
+	Elapsed  time.Duration `yaml:"-"`                  // 编译用时
+
+	// 在线调试的基地址，未指定 @apiTryIt 的 API 都会使用该地址开启在线调试。
+	// 仅对 html 和 json 有效。
+	TryItBaseURL string `yaml:"tryItBaseURL,omitempty"`
+
+	// PDF 文档的设置，仅对 pdf 有效。为空表示使用默认设置，并输出目录。
+	PDF *PDFOptions `yaml:"pdf,omitempty"`
 
 	dataDir string // json 数据保存的目录
 }
@@ -33,6 +54,18 @@ func (o *Options) Sanitize() *types.OptionsError {
 		return &types.OptionsError{Field: "dir", Message: locale.Sprintf(locale.ErrRequired)}
 	}
 
+	switch o.Type {
+	case "":
+		o.Type = TypeHTML
+	case TypeHTML, TypeJSON, TypeRAML, TypeBlueprint, TypeSingle, TypeWord, TypePDF, TypeAsciidoc:
+	default:
+		return &types.OptionsError{Field: "type", Message: locale.Sprintf(locale.ErrInvalidValue)}
+	}
+
+	if o.PDF != nil {
+		return o.PDF.Sanitize()
+	}
+
 	return nil
 }
 
@@ -52,6 +85,8 @@ func (o *Options) groupIsEnable(group string) bool {
 }
 
 // Render 渲染 docs 的内容，具体的渲染参数由 o 指定。
+//
+// Render 不会修改 docs 的内容，只要 o 不同，可以在多个协程中同时调用。
 func Render(docs *types.Doc, o *Options) error {
 	// 文档目录下的文件名可能改变，先清除目录下的所有文件。
 	if err := os.RemoveAll(o.Dir); err != nil {
@@ -64,6 +99,26 @@ func Render(docs *types.Doc, o *Options) error {
 		}
 	}
 
+	switch o.Type {
+	case TypeRAML:
+		return renderRAML(docs, o)
+	case TypeBlueprint:
+		return renderBlueprint(docs, o)
+	case TypeSingle:
+		return renderSinglePage(docs, o)
+	case TypeWord:
+		return renderWord(docs, o)
+	case TypePDF:
+		return renderPDF(docs, o)
+	case TypeAsciidoc:
+		return renderAsciidoc(docs, o)
+	}
+
+	if o.Type == TypeJSON { // 仅输出数据，直接保存在 Dir 下
+		o.dataDir = o.Dir
+		return render(docs, o)
+	}
+
 	o.dataDir = filepath.Join(o.Dir, vars.JSONDataDirName)
 	if !utils.FileExists(o.dataDir) {
 		if err := os.MkdirAll(o.dataDir, os.ModePerm); err != nil {
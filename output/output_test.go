@@ -4,6 +4,32 @@
 
 package output
 
-import "github.com/caixw/apidoc/types"
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+)
 
 var _ types.Sanitizer = &Options{}
+
+func TestOptions_Sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	o := &Options{}
+	a.Error(o.Sanitize())
+
+	// 默认为 html
+	o.Dir = "./doc"
+	a.NotError(o.Sanitize())
+	a.Equal(o.Type, TypeHTML)
+
+	o.Type = TypeJSON
+	a.NotError(o.Sanitize())
+	a.Equal(o.Type, TypeJSON)
+
+	o.Type = "xml"
+	err := o.Sanitize()
+	a.Error(err).Equal(err.Field, "type")
+}
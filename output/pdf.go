@@ -0,0 +1,478 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 页面的边距，单位为 pt
+const pdfMargin = 50
+
+// 支持的页面大小，单位为 pt
+var pdfPageSizes = map[string][2]float64{
+	"A4":     {595, 842},
+	"Letter": {612, 792},
+}
+
+// 支持的字体。
+//
+// 均为 Adobe 预定义的 CJK 字体，不需要嵌入到文件中，由阅读器使用系统中的字体代替。
+// 字体中同时包含了拉丁字符，所以不需要再为英文指定其它字体。
+var pdfFonts = map[string]struct{ ordering, encoding string }{
+	"STSong-Light": {ordering: "GB1", encoding: "UniGB-UCS2-H"},   // 简体中文
+	"MSung-Light":  {ordering: "CNS1", encoding: "UniCNS-UCS2-H"}, // 繁体中文
+}
+
+// PDFOptions 指定了 PDF 输出的相关设置项。
+type PDFOptions struct {
+	PageSize string  `yaml:"pageSize,omitempty"` // 页面大小，可以是 A4 和 Letter，默认为 A4
+	Font     string  `yaml:"font,omitempty"`     // 字体，可以是 STSong-Light 和 MSung-Light，默认为 STSong-Light
+	FontSize float64 `yaml:"fontSize,omitempty"` // 正文的字号，默认为 10
+	TOC      bool    `yaml:"toc,omitempty"`      // 是否在标题页之后输出目录
+}
+
+// Sanitize 对 PDFOptions 作一些初始化操作。
+func (opts *PDFOptions) Sanitize() *types.OptionsError {
+	if len(opts.PageSize) == 0 {
+		opts.PageSize = "A4"
+	}
+	if _, found := pdfPageSizes[opts.PageSize]; !found {
+		return &types.OptionsError{Field: "pdf.pageSize", Message: locale.Sprintf(locale.ErrInvalidValue)}
+	}
+
+	if len(opts.Font) == 0 {
+		opts.Font = "STSong-Light"
+	}
+	if _, found := pdfFonts[opts.Font]; !found {
+		return &types.OptionsError{Field: "pdf.font", Message: locale.Sprintf(locale.ErrInvalidValue)}
+	}
+
+	if opts.FontSize == 0 {
+		opts.FontSize = 10
+	}
+	if opts.FontSize < 0 {
+		return &types.OptionsError{Field: "pdf.fontSize", Message: locale.Sprintf(locale.ErrInvalidValue)}
+	}
+
+	return nil
+}
+
+// 将 docs 以 PDF 的格式输出到 o.Dir 目录下。
+func renderPDF(docs *types.Doc, o *Options) error {
+	opts := o.PDF
+	if opts == nil {
+		opts = &PDFOptions{TOC: true}
+	}
+
+	file, err := os.Create(filepath.Join(o.Dir, vars.PDFFileName))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WritePDF(file, docs, o, opts)
+}
+
+// WritePDF 将 docs 转换成 PDF 文档，写入到 w 中。
+//
+// 第一页为标题页，opts.TOC 为 true 时，之后为目录页，目录中的各项都可以点击跳转；
+// 之后每个组从新的一页开始，API 的内容依次排列，参数以表格的形式输出。
+func WritePDF(w io.Writer, docs *types.Doc, o *Options, opts *PDFOptions) error {
+	if err := opts.Sanitize(); err != nil {
+		return err
+	}
+
+	basePath := docs.BasePath
+	if len(o.BasePath) > 0 {
+		basePath = o.BasePath
+	}
+
+	size := opts.FontSize
+
+	// 标题页
+	front := newPDFLayout(opts)
+	front.newPage()
+	front.y -= 150
+	front.text(size*2.4, 0, docs.Title)
+	if len(docs.Version) > 0 {
+		front.text(size*1.2, 0, docs.Version)
+	}
+	if len(docs.BaseURL) > 0 {
+		front.text(size*1.2, 0, joinPath(docs.BaseURL, basePath))
+		basePath = ""
+	}
+	front.space(size)
+	front.paragraphs(size, 0, docs.Content)
+
+	// 内容，同时记录各个组和 API 的位置，供目录使用。
+	body := newPDFLayout(opts)
+	var toc []*pdfTOCItem
+
+	groups := make(map[string][]*types.API, 10)
+	for _, api := range docs.Apis {
+		if o.groupIsEnable(api.Group) {
+			groups[api.Group] = append(groups[api.Group], api)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		apis := groups[name]
+		sortAPIs(apis)
+
+		body.newPage()
+		toc = append(toc, &pdfTOCItem{title: name, dest: body.dest()})
+		body.text(size*1.8, 0, name)
+		body.space(size)
+
+		for _, api := range apis {
+			// 标题至少与之后的两行内容在同一页
+			title := strings.ToUpper(api.Method) + " " + joinPath(basePath, api.URL) + " " + api.Summary
+			body.space(size)
+			body.ensure(size * 1.4 * 1.5 * 3)
+			toc = append(toc, &pdfTOCItem{title: title, level: 1, dest: body.dest()})
+			pdfAPI(body, size, title, api)
+		}
+	}
+
+	// 目录
+	contents := newPDFLayout(opts)
+	if opts.TOC && len(toc) > 0 {
+		contents.newPage()
+		contents.text(size*1.8, 0, "目录")
+		contents.space(size)
+		for _, item := range toc {
+			contents.link(size, float64(item.level)*size*2, item.title, item.dest)
+		}
+	}
+
+	// 链接的目标都在内容部分，需要加上之前的页数。
+	offset := len(front.pages) + len(contents.pages)
+	for _, item := range toc {
+		item.dest.page += offset
+	}
+
+	pages := make([]*pdfPage, 0, offset+len(body.pages))
+	pages = append(pages, front.pages...)
+	pages = append(pages, contents.pages...)
+	pages = append(pages, body.pages...)
+
+	_, err := writePDFFile(docs.Title, opts, pages).WriteTo(w)
+	return err
+}
+
+func pdfAPI(l *pdfLayout, size float64, title string, api *types.API) {
+	l.text(size*1.4, 0, title)
+	l.paragraphs(size, 0, api.Description)
+	for _, note := range api.Notes {
+		l.paragraphs(size, 0, notesMarkdown([]*types.Note{note}, ""))
+	}
+
+	pdfParams(l, size, "参数", api.Params)
+	pdfParams(l, size, "查询参数", api.Queries)
+	if api.Request != nil {
+		pdfParams(l, size, "请求", api.Request.Params)
+	}
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp == nil {
+			continue
+		}
+		l.text(size, 0, resp.Code+" "+resp.Summary)
+		pdfParams(l, size, "", resp.Params)
+	}
+}
+
+// 以表格的形式输出参数，title 不为空时，在表格之前输出 title。
+func pdfParams(l *pdfLayout, size float64, title string, params []*types.Param) {
+	if len(params) == 0 {
+		return
+	}
+
+	if len(title) > 0 {
+		l.text(size, 0, title)
+	}
+
+	l.row(size, "名称", "类型", "描述")
+	for _, p := range params {
+		l.row(size, p.Name, p.Type, p.Summary)
+	}
+}
+
+// 目录中的一项
+type pdfTOCItem struct {
+	title string
+	level int // 缩进的层级，从 0 开始
+	dest  *pdfDest
+}
+
+// 链接的目标位置
+type pdfDest struct {
+	page int // 页面的索引，从 0 开始
+	y    float64
+}
+
+// 页面中的一个链接
+type pdfLink struct {
+	rect [4]float64
+	dest *pdfDest
+}
+
+type pdfPage struct {
+	content *bytes.Buffer
+	links   []*pdfLink
+}
+
+// 将内容按页面进行排版
+type pdfLayout struct {
+	width, height float64
+	pages         []*pdfPage
+	y             float64 // 当前的纵坐标，PDF 的原点在左下角
+}
+
+func newPDFLayout(opts *PDFOptions) *pdfLayout {
+	size := pdfPageSizes[opts.PageSize]
+	return &pdfLayout{width: size[0], height: size[1]}
+}
+
+func (l *pdfLayout) newPage() {
+	l.pages = append(l.pages, &pdfPage{content: new(bytes.Buffer)})
+	l.y = l.height - pdfMargin
+}
+
+// 当前页面的剩余空间不足 h 时，换到新的一页。
+func (l *pdfLayout) ensure(h float64) {
+	if len(l.pages) == 0 || l.y-h < pdfMargin {
+		l.newPage()
+	}
+}
+
+// 换行，当前页面的剩余空间不足 h 时，换到新的一页。
+func (l *pdfLayout) lineBreak(h float64) {
+	l.ensure(h)
+	l.y -= h
+}
+
+// 留出 h 高度的空白，不会跨页。
+func (l *pdfLayout) space(h float64) {
+	if l.y-h >= pdfMargin {
+		l.y -= h
+	}
+}
+
+// 当前位置
+func (l *pdfLayout) dest() *pdfDest {
+	return &pdfDest{page: len(l.pages) - 1, y: l.y}
+}
+
+func (l *pdfLayout) write(size, x float64, text string) {
+	fmt.Fprintf(l.pages[len(l.pages)-1].content, "BT /F1 %.2f Tf %.2f %.2f Td <%s> Tj ET\n", size, x, l.y, pdfText(text))
+}
+
+// 输出 text，超出宽度的内容自动换行。
+func (l *pdfLayout) text(size, indent float64, text string) {
+	for _, line := range pdfWrap(text, size, l.width-2*pdfMargin-indent) {
+		l.lineBreak(size * 1.5)
+		l.write(size, pdfMargin+indent, line)
+	}
+}
+
+// 将多行的 text 按行输出，忽略空行。
+func (l *pdfLayout) paragraphs(size, indent float64, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			l.text(size, indent, line)
+		}
+	}
+}
+
+// 输出指向 dest 的链接，仅占一行，超出的内容会被截断。
+func (l *pdfLayout) link(size, indent float64, text string, dest *pdfDest) {
+	lines := pdfWrap(text, size, l.width-2*pdfMargin-indent)
+
+	l.lineBreak(size * 1.5)
+	l.write(size, pdfMargin+indent, lines[0])
+
+	page := l.pages[len(l.pages)-1]
+	page.links = append(page.links, &pdfLink{
+		rect: [4]float64{pdfMargin + indent, l.y - size*0.3, l.width - pdfMargin, l.y + size},
+		dest: dest,
+	})
+}
+
+// 输出表格中的一行，三列的宽度分别为 30%、20% 和 50%。
+func (l *pdfLayout) row(size float64, name, typ, summary string) {
+	width := l.width - 2*pdfMargin
+	xs := []float64{pdfMargin, pdfMargin + width*0.3, pdfMargin + width*0.5}
+	cells := [][]string{
+		pdfWrap(name, size, width*0.3-size),
+		pdfWrap(typ, size, width*0.2-size),
+		pdfWrap(summary, size, width*0.5),
+	}
+
+	rows := 0
+	for _, lines := range cells {
+		if len(lines) > rows {
+			rows = len(lines)
+		}
+	}
+
+	for index := 0; index < rows; index++ {
+		l.lineBreak(size * 1.5)
+		for i, lines := range cells {
+			if index < len(lines) {
+				l.write(size, xs[i], lines[index])
+			}
+		}
+	}
+}
+
+// 按 width 对 text 进行折行，至少返回一行。
+//
+// 字符的宽度只作估算：ASCII 为半个字宽，其它为一个字宽，与字体中 /W 的定义相同。
+// 拉丁字符优先在空格处折行，其它字符可以在任意位置折行。
+func pdfWrap(text string, size, width float64) []string {
+	text = strings.Replace(text, "\n", " ", -1)
+
+	var lines []string
+	line := []rune{}
+	w := 0.0
+	space := -1 // 当前行中最后一个空格的位置
+	for _, r := range text {
+		rw := size
+		if r < 0x80 {
+			rw = size / 2
+		}
+
+		if w+rw > width && len(line) > 0 {
+			if space > 0 && r < 0x80 && line[len(line)-1] < 0x80 {
+				lines = append(lines, string(line[:space]))
+				line = append([]rune{}, line[space+1:]...)
+			} else {
+				lines = append(lines, string(line))
+				line = line[:0]
+			}
+			space = -1
+			w = pdfWidth(line, size)
+		}
+
+		if r == ' ' {
+			space = len(line)
+		}
+		line = append(line, r)
+		w += rw
+	}
+
+	return append(lines, string(line))
+}
+
+func pdfWidth(line []rune, size float64) float64 {
+	w := 0.0
+	for _, r := range line {
+		if r < 0x80 {
+			w += size / 2
+		} else {
+			w += size
+		}
+	}
+	return w
+}
+
+// 将 text 转换成 UCS-2 编码的十六进制字符串，超出范围的字符以 ? 代替。
+func pdfText(text string) string {
+	buf := new(bytes.Buffer)
+	for _, r := range text {
+		if r > 0xffff {
+			r = '?'
+		}
+		fmt.Fprintf(buf, "%04X", r)
+	}
+	return buf.String()
+}
+
+// 将 pages 组装成完整的 PDF 文件。
+//
+// 对象的编号依次为：1 目录、2 页面树、3 字体、4 CID 字体、5 字体描述、6 文档信息，
+// 之后每个页面占用两个编号，分别为页面和其内容。
+func writePDFFile(title string, opts *PDFOptions, pages []*pdfPage) *bytes.Buffer {
+	const firstPage = 7
+	pageID := func(index int) int { return firstPage + index*2 }
+	size := pdfPageSizes[opts.PageSize]
+	font := pdfFonts[opts.Font]
+
+	buf := new(bytes.Buffer)
+	offsets := make([]int, firstPage+len(pages)*2)
+	object := func(id int, format string, v ...interface{}) {
+		offsets[id] = buf.Len()
+		fmt.Fprintf(buf, "%d 0 obj\n", id)
+		fmt.Fprintf(buf, format, v...)
+		buf.WriteString("\nendobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageID(i)))
+	}
+
+	object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	object(2, "<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+	object(3, "<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /%s /DescendantFonts [4 0 R] >>", opts.Font, font.encoding)
+	object(4, "<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (%s) /Supplement 0 >> /FontDescriptor 5 0 R /DW 1000 /W [1 95 500] >>", opts.Font, font.ordering)
+	object(5, "<< /Type /FontDescriptor /FontName /%s /Flags 6 /FontBBox [-25 -254 1000 880] /ItalicAngle 0 /Ascent 880 /Descent -120 /CapHeight 880 /StemV 93 >>", opts.Font)
+	object(6, "<< /Title <FEFF%s> /Producer (%s) >>", pdfUTF16(title), vars.Name)
+
+	for i, page := range pages {
+		annots := ""
+		if len(page.links) > 0 {
+			items := make([]string, 0, len(page.links))
+			for _, link := range page.links {
+				items = append(items, fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [%.2f %.2f %.2f %.2f] /Border [0 0 0] /Dest [%d 0 R /XYZ 0 %.2f 0] >>",
+					link.rect[0], link.rect[1], link.rect[2], link.rect[3], pageID(link.dest.page), link.dest.y))
+			}
+			annots = " /Annots [" + strings.Join(items, " ") + "]"
+		}
+
+		id := pageID(i)
+		object(id, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R%s >>",
+			size[0], size[1], id+1, annots)
+		object(id+1, "<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String())
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+
+	return buf
+}
+
+// 将 text 转换成 UTF-16BE 编码的十六进制字符串，用于文档信息等文本字符串。
+func pdfUTF16(text string) string {
+	buf := new(bytes.Buffer)
+	for _, u := range utf16.Encode([]rune(text)) {
+		fmt.Fprintf(buf, "%04X", u)
+	}
+	return buf.String()
+}
@@ -0,0 +1,172 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+var _ types.Sanitizer = &PDFOptions{}
+
+// 通过 xref 读取 PDF 中的所有对象，返回以编号为键名的对象内容。
+func readPDF(a *assert.Assertion, data []byte) map[int]string {
+	a.True(bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	a.True(bytes.HasSuffix(data, []byte("%%EOF\n")))
+
+	matches := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+	a.Equal(len(matches), 2)
+	xref, err := strconv.Atoi(string(matches[1]))
+	a.NotError(err)
+
+	lines := strings.Split(string(data[xref:]), "\n")
+	a.Equal(lines[0], "xref")
+	size, err := strconv.Atoi(strings.Fields(lines[1])[1])
+	a.NotError(err)
+
+	objects := make(map[int]string, size)
+	for id := 1; id < size; id++ {
+		entry := lines[2+id]
+		a.Equal(len(entry)+1, 20, "xref 中的记录长度不正确")
+		offset, err := strconv.Atoi(entry[:10])
+		a.NotError(err)
+
+		content := string(data[offset:])
+		prefix := strconv.Itoa(id) + " 0 obj\n"
+		a.True(strings.HasPrefix(content, prefix), "第 %d 个对象的位置不正确", id)
+		objects[id] = content[len(prefix):strings.Index(content, "\nendobj\n")]
+	}
+
+	return objects
+}
+
+func TestPDFOptions_Sanitize(t *testing.T) {
+	a := assert.New(t)
+
+	opts := &PDFOptions{}
+	a.NotError(opts.Sanitize())
+	a.Equal(opts.PageSize, "A4").
+		Equal(opts.Font, "STSong-Light").
+		Equal(opts.FontSize, 10)
+
+	a.Error((&PDFOptions{PageSize: "A5"}).Sanitize())
+	a.Error((&PDFOptions{Font: "Helvetica"}).Sanitize())
+	a.Error((&PDFOptions{FontSize: -1}).Sanitize())
+
+	o := &Options{Dir: "./testdir", Type: TypePDF, PDF: &PDFOptions{PageSize: "A5"}}
+	a.Error(o.Sanitize())
+}
+
+func TestWritePDF(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.Title = "测试文档"
+	docs.Version = "1.0.0"
+	docs.Content = "line1\nline2"
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Summary: "获取用户",
+		Group:   "users",
+		Params:  []*types.Param{{Name: "id", Type: "int", Summary: "用户 ID"}},
+		Success: &types.Response{Code: "200", Summary: "OK", Params: []*types.Param{{Name: "name", Type: "string", Summary: strings.Repeat("很长的描述", 100)}}},
+	})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/admin", Summary: "admin", Group: "admin"})
+
+	buf := new(bytes.Buffer)
+	a.NotError(WritePDF(buf, docs, &Options{}, &PDFOptions{PageSize: "Letter", TOC: true}))
+	objects := readPDF(a, buf.Bytes())
+
+	a.True(strings.Contains(objects[1], "/Type /Catalog"))
+	a.True(strings.Contains(objects[3], "/BaseFont /STSong-Light"))
+	a.True(strings.Contains(objects[6], "/Title <FEFF"+pdfUTF16(docs.Title)+">"))
+
+	count, err := strconv.Atoi(regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(objects[2])[1])
+	a.NotError(err)
+	a.True(count > 0)
+
+	pages := 0
+	for _, obj := range objects {
+		if strings.HasPrefix(obj, "<< /Type /Page ") {
+			pages++
+			a.True(strings.Contains(obj, "/MediaBox [0 0 612 792]"))
+		}
+	}
+	a.Equal(pages, count)
+
+	// 标题页、目录页，以及 admin 和 users 两个组
+	a.True(count >= 4)
+
+	// 目录中的链接指向各组所在的页面
+	toc := objects[pageObject(1)]
+	dests := regexp.MustCompile(`/Dest \[(\d+) 0 R`).FindAllStringSubmatch(toc, -1)
+	a.Equal(len(dests), 4)
+	for i, name := range []string{"admin", "users"} {
+		id, err := strconv.Atoi(dests[i*2][1])
+		a.NotError(err)
+		page := objects[id]
+		a.True(strings.HasPrefix(page, "<< /Type /Page "))
+
+		contentID, err := strconv.Atoi(regexp.MustCompile(`/Contents (\d+) 0 R`).FindStringSubmatch(page)[1])
+		a.NotError(err)
+		a.True(strings.Contains(objects[contentID], "<"+pdfText(name)+">"))
+	}
+
+	// 不输出目录
+	buf.Reset()
+	a.NotError(WritePDF(buf, docs, &Options{Groups: []string{"admin"}}, &PDFOptions{}))
+	objects = readPDF(a, buf.Bytes())
+	a.True(strings.Contains(objects[2], "/Count 2"))
+	for _, obj := range objects {
+		a.False(strings.Contains(obj, "/Annots"))
+	}
+}
+
+// 第 index 个页面的对象编号
+func pageObject(index int) int {
+	return 7 + index*2
+}
+
+func TestPDFWrap(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(pdfWrap("", 10, 100), []string{""})
+	a.Equal(pdfWrap("abc def", 10, 100), []string{"abc def"})
+	a.Equal(pdfWrap("abcd efgh ijkl", 10, 40), []string{"abcd", "efgh", "ijkl"})
+	a.Equal(pdfWrap("一二三四五六", 10, 30), []string{"一二三", "四五六"})
+	a.Equal(pdfWrap("abcdefghijkl", 10, 30), []string{"abcdef", "ghijkl"})
+}
+
+func TestRenderPDF(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := types.NewDoc()
+	docs.Title = "test"
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "users", Group: "users"})
+
+	o := &Options{Type: TypePDF, Dir: dir}
+	a.NotError(o.Sanitize())
+	a.NotError(Render(docs, o))
+
+	data, err := os.ReadFile(filepath.Join(dir, vars.PDFFileName))
+	a.NotError(err)
+	objects := readPDF(a, data)
+	a.True(strings.Contains(objects[2], "/Count 3"))
+}
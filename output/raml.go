@@ -0,0 +1,973 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// RAML 文件的首行声明
+const ramlHeader = "#%RAML 1.0\n"
+
+// 以 RAML 注解形式输出的标签，按声明的顺序输出到 annotationTypes 中。
+//
+// 新增以注解形式输出的标签时，只需要在此添加一项，
+// 并在 ramlMethod 中输出具体的注解内容。
+var ramlAnnotations = []*ramlAnnotation{
+	{name: "safe", typ: "boolean", present: func(api *types.API) bool { return api.Safe }},
+	{name: "idempotent", typ: "boolean", present: func(api *types.API) bool { return api.Idempotent }},
+	{name: "changelog", typ: "object[]", present: func(api *types.API) bool { return len(api.Changelog) > 0 }},
+	{name: "breakingChanges", typ: "object[]", present: func(api *types.API) bool { return len(api.BreakingChanges) > 0 }},
+	{name: "sseEvents", typ: "object[]", present: func(api *types.API) bool { return api.SSE }},
+	{name: "websocket", typ: "object", present: func(api *types.API) bool { return api.WebSocket }},
+	{name: "socketIO", typ: "object[]", present: func(api *types.API) bool { return len(api.SocketIO) > 0 }},
+	{name: "producesEvents", typ: "object[]", present: ramlHasDomainEvents},
+	{name: "consumesEvents", typ: "object[]", present: ramlHasDomainEvents},
+	{name: "grpcMethod", typ: "string", present: func(api *types.API) bool { return len(api.GRPCMethod) > 0 }},
+	{name: "operationId", typ: "string", present: func(api *types.API) bool { return len(api.OperationID) > 0 }},
+	{name: "graphqlOperation", typ: "object", present: func(api *types.API) bool { return api.GraphQL != nil }},
+	{name: "retry", typ: "object", present: func(api *types.API) bool { return api.Retry != nil }},
+	{name: "circuitBreaker", typ: "object", present: func(api *types.API) bool { return api.CircuitBreaker != nil }},
+	{name: "tenant", typ: "object", present: func(api *types.API) bool { return api.Tenant != nil }},
+	{name: "region", typ: "object", present: func(api *types.API) bool { return api.Region != nil }},
+	{name: "quota", typ: "object", present: func(api *types.API) bool { return api.Quota != nil }},
+	{name: "async", typ: "object", present: func(api *types.API) bool { return api.Async != nil }},
+	{name: "maxContentLength", typ: "integer", present: func(api *types.API) bool { return api.MaxRequestBodyBytes > 0 }},
+	{name: "batch", typ: "object", present: func(api *types.API) bool { return api.Batch != nil }},
+	{name: "timeout", typ: "object", present: func(api *types.API) bool { return api.Timeout != nil }},
+	{name: "timeBudget", typ: "object", present: func(api *types.API) bool { return api.TimeBudget != nil }},
+	{name: "sla", typ: "object", present: func(api *types.API) bool { return api.SLA != nil }},
+	{name: "featureFlag", typ: "object", present: func(api *types.API) bool { return api.FeatureFlag != nil }},
+	{name: "cors", typ: "object", present: func(api *types.API) bool { return api.CORSPolicy != nil }},
+	{name: "errorCodes", typ: "object", present: func(api *types.API) bool { return len(api.ErrorCodes) > 0 }},
+	{name: "metrics", typ: "object", present: func(api *types.API) bool { return len(api.Metrics) > 0 }},
+	{name: "environmentNotes", typ: "object[]", present: func(api *types.API) bool { return len(api.Environments) > 0 }},
+	{name: "audience", typ: "string[]", present: func(api *types.API) bool { return len(api.Audiences) > 0 }},
+	{name: "dataClassification", typ: "string", present: func(api *types.API) bool { return len(api.DataClassification) > 0 }},
+	{name: "owner", typ: "object", present: func(api *types.API) bool { return api.Owner != nil }},
+	{name: "idempotencyKey", typ: "boolean", present: func(api *types.API) bool { return api.IdempotencyKey != nil }},
+	{name: "requestID", typ: "string", present: func(api *types.API) bool { return api.RequestID != nil }},
+	{name: "accessRoles", typ: "string[]", present: func(api *types.API) bool { return len(api.AccessRoles) > 0 }},
+	{name: "conflicts", typ: "object[]", present: func(api *types.API) bool { return len(api.Conflicts) > 0 }},
+	{name: "scopeLogic", typ: "string", present: func(api *types.API) bool { return api.ScopeLogic == types.ScopeLogicAny }},
+	{name: "format", typ: "string", present: func(api *types.API) bool { return len(api.Formats) > 0 }},
+	{name: "discriminatorMapping", typ: "object", present: ramlHasDiscriminatorMapping},
+}
+
+// 注解类型的声明，present 为 true 时才会输出
+type ramlAnnotation struct {
+	name    string                // 注解名称
+	typ     string                // 注解的类型
+	present func(*types.API) bool // api 中是否包含该注解
+}
+
+// 是否包含 @apiProducesEvent 或是 @apiConsumesEvent
+func ramlHasDomainEvents(api *types.API) bool {
+	return len(api.ProducedEvents) > 0 || len(api.ConsumedEvents) > 0
+}
+
+// 将 docs 以 RAML 1.0 的格式输出到 o.Dir 目录下。
+func renderRAML(docs *types.Doc, o *Options) error {
+	file, err := os.Create(filepath.Join(o.Dir, vars.RAMLFileName))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return writeRAML(file, docs, o)
+}
+
+// 将 docs 转换成 RAML 1.0 格式的内容，写入到 w 中。
+//
+// 组名被转换成 resourceTypes，
+// 认证方式被转换成 securitySchemes，
+// 相同地址的 API 合并为同一资源下的不同方法。
+// 为了保证缩进的正确性，所有的内容都通过 yaml.MapSlice 构建。
+func writeRAML(w io.Writer, docs *types.Doc, o *Options) error {
+	basePath := docs.BasePath
+	if len(o.BasePath) > 0 {
+		basePath = o.BasePath
+	}
+
+	root := yaml.MapSlice{{Key: "title", Value: docs.Title}}
+	if len(docs.Version) > 0 {
+		root = append(root, yaml.MapItem{Key: "version", Value: docs.Version})
+	}
+	if len(docs.BaseURL) > 0 {
+		root = append(root, yaml.MapItem{Key: "baseUri", Value: joinPath(docs.BaseURL, basePath)})
+		basePath = ""
+	}
+	if len(docs.Content) > 0 {
+		root = append(root, yaml.MapItem{Key: "description", Value: docs.Content})
+	}
+
+	if len(docs.SecuritySchemes) > 0 {
+		root = append(root, yaml.MapItem{Key: "securitySchemes", Value: ramlSecuritySchemes(docs.SecuritySchemes)})
+	}
+
+	apis := make([]*types.API, 0, len(docs.Apis))
+	for _, api := range docs.Apis {
+		if o.groupIsEnable(api.Group) {
+			apis = append(apis, api)
+		}
+	}
+	sortAPIs(apis)
+
+	used := make([]bool, len(ramlAnnotations)) // ramlAnnotations 中的各项是否有 API 用到
+	codegenLangs := map[string]bool{}          // @apiCodegen 中出现的所有语言
+
+	// 资源按其第一个 API 的排序先后输出
+	groups := yaml.MapSlice{}
+	urls := make([]string, 0, len(apis))
+	resources := make(map[string]yaml.MapSlice, len(apis))
+	for _, api := range apis {
+		url := joinPath(basePath, api.URL)
+		res, found := resources[url]
+		if !found {
+			res = yaml.MapSlice{{Key: "type", Value: api.Group}}
+			if len(api.Params) > 0 {
+				res = append(res, yaml.MapItem{Key: "uriParameters", Value: ramlParams(api.Params, api.Formats)})
+			}
+
+			if !ramlHasKey(groups, api.Group) {
+				groups = append(groups, yaml.MapItem{Key: api.Group, Value: yaml.MapSlice{{Key: "usage", Value: api.Group}}})
+			}
+			urls = append(urls, url)
+		}
+		resources[url] = append(res, yaml.MapItem{Key: strings.ToLower(api.Method), Value: ramlMethod(api, docs.SecuritySchemes)})
+		for i, an := range ramlAnnotations {
+			used[i] = used[i] || an.present(api)
+		}
+		for lang := range api.Codegen {
+			codegenLangs[lang] = true
+		}
+	}
+
+	// 注解类型只声明文档中实际用到的部分，@apiCodegen 中的每一种语言对应一个注解
+	annotations := yaml.MapSlice{}
+	for i, an := range ramlAnnotations {
+		if used[i] {
+			annotations = append(annotations, yaml.MapItem{Key: an.name, Value: an.typ})
+		}
+	}
+	if len(docs.SDKs) > 0 {
+		annotations = append(annotations, yaml.MapItem{Key: "sdks", Value: "object[]"})
+	}
+	langs := make([]string, 0, len(codegenLangs))
+	for lang := range codegenLangs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		annotations = append(annotations, yaml.MapItem{Key: "codegen-" + lang, Value: "object"})
+	}
+	if len(annotations) > 0 {
+		root = append(root, yaml.MapItem{Key: "annotationTypes", Value: annotations})
+	}
+
+	if len(docs.SDKs) > 0 {
+		root = append(root, yaml.MapItem{Key: "(sdks)", Value: ramlSDKs(docs.SDKs)})
+	}
+
+	if len(groups) > 0 {
+		sort.SliceStable(groups, func(i, j int) bool {
+			return groups[i].Key.(string) < groups[j].Key.(string)
+		})
+		root = append(root, yaml.MapItem{Key: "resourceTypes", Value: groups})
+	}
+
+	for _, url := range urls {
+		root = append(root, yaml.MapItem{Key: url, Value: resources[url]})
+	}
+
+	data, err := yaml.Marshal(root)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.WriteString(w, ramlHeader); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// 将 @apiSDK 转换成 (sdks) 注解的值，保持声明时的顺序。
+func ramlSDKs(sdks []*types.SDK) []yaml.MapSlice {
+	items := make([]yaml.MapSlice, 0, len(sdks))
+	for _, sdk := range sdks {
+		items = append(items, yaml.MapSlice{
+			{Key: "language", Value: sdk.Language},
+			{Key: "package", Value: sdk.Package},
+			{Key: "url", Value: sdk.URL},
+		})
+	}
+	return items
+}
+
+func ramlSecuritySchemes(schemes map[string]*types.Security) yaml.MapSlice {
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make(yaml.MapSlice, 0, len(names))
+	for _, name := range names {
+		s := schemes[name]
+		scheme := yaml.MapSlice{}
+
+		switch s.Type {
+		case types.SecurityTypeAPIKey:
+			key := "headers"
+			if s.In == "query" {
+				key = "queryParameters"
+			}
+			scheme = append(scheme,
+				yaml.MapItem{Key: "type", Value: "Pass Through"},
+				yaml.MapItem{Key: "describedBy", Value: yaml.MapSlice{
+					{Key: key, Value: yaml.MapSlice{{Key: s.Key, Value: yaml.MapSlice{{Key: "type", Value: "string"}}}}},
+				}},
+			)
+		case types.SecurityTypeHTTP:
+			switch strings.ToLower(s.Scheme) {
+			case "basic":
+				scheme = append(scheme, yaml.MapItem{Key: "type", Value: "Basic Authentication"})
+			case "digest":
+				scheme = append(scheme, yaml.MapItem{Key: "type", Value: "Digest Authentication"})
+			default:
+				scheme = append(scheme, yaml.MapItem{Key: "type", Value: "x-" + s.Scheme})
+			}
+		case types.SecurityTypeOAuth2:
+			settings := yaml.MapSlice{
+				{Key: "accessTokenUri", Value: s.URL},
+				{Key: "authorizationGrants", Value: []string{"client_credentials"}},
+			}
+			if len(s.Scopes) > 0 {
+				settings = append(settings, yaml.MapItem{Key: "scopes", Value: s.Scopes})
+			}
+			scheme = append(scheme,
+				yaml.MapItem{Key: "type", Value: "OAuth 2.0"},
+				yaml.MapItem{Key: "settings", Value: settings},
+			)
+		default:
+			scheme = append(scheme, yaml.MapItem{Key: "type", Value: "x-" + s.Type})
+		}
+
+		ret = append(ret, yaml.MapItem{Key: name, Value: scheme})
+	}
+
+	return ret
+}
+
+func ramlMethod(api *types.API, schemes map[string]*types.Security) yaml.MapSlice {
+	m := yaml.MapSlice{{Key: "displayName", Value: api.Summary}}
+
+	// 提示信息和外部资源附加在 description 之后
+	desc := api.Description
+	if len(api.Notes) > 0 {
+		if len(desc) > 0 {
+			desc += "\n\n"
+		}
+		desc += notesMarkdown(api.Notes, "")
+	}
+	if len(api.Links) > 0 {
+		if len(desc) > 0 {
+			desc += "\n\n"
+		}
+		desc += linksMarkdown(api.Links)
+	}
+	if len(desc) > 0 {
+		m = append(m, yaml.MapItem{Key: "description", Value: desc})
+	}
+
+	if api.Safe {
+		m = append(m, yaml.MapItem{Key: "(safe)", Value: true})
+	}
+	if api.Idempotent {
+		m = append(m, yaml.MapItem{Key: "(idempotent)", Value: true})
+	}
+	if len(api.Changelog) > 0 {
+		changelog := make([]yaml.MapSlice, 0, len(api.Changelog))
+		for _, e := range api.Changelog {
+			changelog = append(changelog, yaml.MapSlice{
+				{Key: "version", Value: e.Version},
+				{Key: "date", Value: e.Date},
+				{Key: "description", Value: e.Description},
+			})
+		}
+		m = append(m, yaml.MapItem{Key: "(changelog)", Value: changelog})
+	}
+	if len(api.BreakingChanges) > 0 {
+		changes := make([]yaml.MapSlice, 0, len(api.BreakingChanges))
+		for _, c := range api.BreakingChanges {
+			changes = append(changes, yaml.MapSlice{
+				{Key: "version", Value: c.Version},
+				{Key: "description", Value: c.Description},
+			})
+		}
+		m = append(m, yaml.MapItem{Key: "(breakingChanges)", Value: changes})
+	}
+	if api.SSE {
+		m = append(m, yaml.MapItem{Key: "(sseEvents)", Value: ramlEvents(api.Events)})
+	}
+	if api.WebSocket {
+		m = append(m, yaml.MapItem{Key: "(websocket)", Value: yaml.MapSlice{
+			{Key: "send", Value: ramlEvents(api.WSSend)},
+			{Key: "receive", Value: ramlEvents(api.WSReceive)},
+		}})
+	}
+	if len(api.SocketIO) > 0 {
+		m = append(m, yaml.MapItem{Key: "(socketIO)", Value: ramlSocketIO(api.SocketIO)})
+	}
+	if len(api.ProducedEvents) > 0 {
+		m = append(m, yaml.MapItem{Key: "(producesEvents)", Value: ramlEvents(api.ProducedEvents)})
+	}
+	if len(api.ConsumedEvents) > 0 {
+		m = append(m, yaml.MapItem{Key: "(consumesEvents)", Value: ramlEvents(api.ConsumedEvents)})
+	}
+	if len(api.GRPCMethod) > 0 {
+		m = append(m, yaml.MapItem{Key: "(grpcMethod)", Value: api.GRPCMethod})
+	}
+	if len(api.OperationID) > 0 {
+		m = append(m, yaml.MapItem{Key: "(operationId)", Value: api.OperationID})
+	}
+	if g := api.GraphQL; g != nil {
+		m = append(m, yaml.MapItem{Key: "(graphqlOperation)", Value: yaml.MapSlice{
+			{Key: "name", Value: g.Operation},
+			{Key: "type", Value: g.Type},
+		}})
+	}
+	if api.Retry != nil {
+		m = append(m, yaml.MapItem{Key: "(retry)", Value: ramlRetry(api.Retry)})
+	}
+	if c := api.CircuitBreaker; c != nil {
+		cb := yaml.MapSlice{}
+		if c.Threshold > 0 {
+			cb = append(cb, yaml.MapItem{Key: "threshold", Value: c.Threshold})
+		}
+		if c.Timeout != "" {
+			cb = append(cb, yaml.MapItem{Key: "timeout", Value: c.Timeout})
+		}
+		if c.HalfOpenRequests > 0 {
+			cb = append(cb, yaml.MapItem{Key: "halfOpenRequests", Value: c.HalfOpenRequests})
+		}
+		m = append(m, yaml.MapItem{Key: "(circuitBreaker)", Value: cb})
+	}
+	if t := api.Tenant; t != nil {
+		tenant := yaml.MapSlice{}
+		if t.Isolation != "" {
+			tenant = append(tenant, yaml.MapItem{Key: "isolation", Value: t.Isolation})
+		}
+		if t.Scoping != "" {
+			tenant = append(tenant, yaml.MapItem{Key: "scoping", Value: t.Scoping})
+		}
+		if t.Header != "" {
+			tenant = append(tenant, yaml.MapItem{Key: "header", Value: t.Header})
+		}
+		m = append(m, yaml.MapItem{Key: "(tenant)", Value: tenant})
+	}
+	if r := api.Region; r != nil {
+		region := yaml.MapSlice{}
+		if len(r.Available) > 0 {
+			region = append(region, yaml.MapItem{Key: "available", Value: r.Available})
+		}
+		if len(r.Unavailable) > 0 {
+			region = append(region, yaml.MapItem{Key: "unavailable", Value: r.Unavailable})
+		}
+		m = append(m, yaml.MapItem{Key: "(region)", Value: region})
+	}
+	if q := api.Quota; q != nil {
+		quota := yaml.MapSlice{
+			{Key: "limit", Value: q.Limit},
+			{Key: "window", Value: q.Window},
+		}
+		if len(q.Scope) > 0 {
+			quota = append(quota, yaml.MapItem{Key: "scope", Value: q.Scope})
+		}
+		m = append(m, yaml.MapItem{Key: "(quota)", Value: quota})
+	}
+	if a := api.Async; a != nil {
+		async := yaml.MapSlice{}
+		if len(a.PollURL) > 0 {
+			async = append(async, yaml.MapItem{Key: "pollUrl", Value: a.PollURL})
+		}
+		if len(a.CallbackHeader) > 0 {
+			async = append(async, yaml.MapItem{Key: "callbackHeader", Value: a.CallbackHeader})
+		}
+		m = append(m, yaml.MapItem{Key: "(async)", Value: async})
+	}
+	if api.MaxRequestBodyBytes > 0 {
+		m = append(m, yaml.MapItem{Key: "(maxContentLength)", Value: api.MaxRequestBodyBytes})
+	}
+	if api.Batch != nil {
+		m = append(m, yaml.MapItem{Key: "(batch)", Value: yaml.MapSlice{
+			{Key: "maxItems", Value: api.Batch.MaxItems},
+			{Key: "mode", Value: api.Batch.Mode},
+		}})
+	}
+	if api.Timeout != nil {
+		m = append(m, yaml.MapItem{Key: "(timeout)", Value: yaml.MapSlice{
+			{Key: "value", Value: api.Timeout.Value},
+			{Key: "percentile", Value: api.Timeout.Percentile},
+		}})
+	}
+	if b := api.TimeBudget; b != nil {
+		budget := yaml.MapSlice{{Key: "total", Value: b.Total}}
+		if len(b.Upstreams) > 0 {
+			upstreams := make([]yaml.MapSlice, 0, len(b.Upstreams))
+			for _, u := range b.Upstreams {
+				upstreams = append(upstreams, yaml.MapSlice{
+					{Key: "service", Value: u.Service},
+					{Key: "value", Value: u.Value},
+				})
+			}
+			budget = append(budget, yaml.MapItem{Key: "upstreams", Value: upstreams})
+		}
+		m = append(m, yaml.MapItem{Key: "(timeBudget)", Value: budget})
+	}
+	if api.SLA != nil {
+		sla := yaml.MapSlice{{Key: "availability", Value: api.SLA.Availability}}
+		if api.SLA.RPO != "" {
+			sla = append(sla, yaml.MapItem{Key: "rpo", Value: api.SLA.RPO})
+		}
+		if api.SLA.RTO != "" {
+			sla = append(sla, yaml.MapItem{Key: "rto", Value: api.SLA.RTO})
+		}
+		m = append(m, yaml.MapItem{Key: "(sla)", Value: sla})
+	}
+	if f := api.FeatureFlag; f != nil {
+		flag := yaml.MapSlice{{Key: "name", Value: f.Name}}
+		if len(f.Provider) > 0 {
+			flag = append(flag, yaml.MapItem{Key: "provider", Value: f.Provider})
+		}
+		m = append(m, yaml.MapItem{Key: "(featureFlag)", Value: flag})
+	}
+	if c := api.CORSPolicy; c != nil {
+		cors := yaml.MapSlice{}
+		if c.Origin != "" {
+			cors = append(cors, yaml.MapItem{Key: "origin", Value: c.Origin})
+		}
+		if len(c.Methods) > 0 {
+			cors = append(cors, yaml.MapItem{Key: "methods", Value: c.Methods})
+		}
+		if len(c.Headers) > 0 {
+			cors = append(cors, yaml.MapItem{Key: "headers", Value: c.Headers})
+		}
+		if c.MaxAge > 0 {
+			cors = append(cors, yaml.MapItem{Key: "maxAge", Value: c.MaxAge})
+		}
+		m = append(m, yaml.MapItem{Key: "(cors)", Value: cors})
+	}
+	if len(api.ErrorCodes) > 0 {
+		codes := make(yaml.MapSlice, 0, len(api.ErrorCodes))
+		for _, e := range api.ErrorCodes {
+			codes = append(codes, yaml.MapItem{Key: e.Code, Value: e.Summary})
+		}
+		m = append(m, yaml.MapItem{Key: "(errorCodes)", Value: codes})
+	}
+	if len(api.Metrics) > 0 {
+		metrics := make(yaml.MapSlice, 0, len(api.Metrics))
+		for _, metric := range api.Metrics {
+			metrics = append(metrics, yaml.MapItem{Key: metric.Name, Value: yaml.MapSlice{
+				{Key: "value", Value: metric.Value},
+				{Key: "unit", Value: metric.Unit},
+			}})
+		}
+		m = append(m, yaml.MapItem{Key: "(metrics)", Value: metrics})
+	}
+	if len(api.Environments) > 0 {
+		notes := make([]yaml.MapSlice, 0, len(api.Environments))
+		for _, note := range api.Environments {
+			notes = append(notes, yaml.MapSlice{
+				{Key: "environment", Value: note.Environment},
+				{Key: "text", Value: note.Text},
+			})
+		}
+		m = append(m, yaml.MapItem{Key: "(environmentNotes)", Value: notes})
+	}
+	if len(api.Audiences) > 0 {
+		m = append(m, yaml.MapItem{Key: "(audience)", Value: api.Audiences})
+	}
+	if len(api.DataClassification) > 0 {
+		m = append(m, yaml.MapItem{Key: "(dataClassification)", Value: api.DataClassification})
+	}
+	if api.Owner != nil {
+		owner := yaml.MapSlice{{Key: "team", Value: api.Owner.Team}}
+		if len(api.Owner.Email) > 0 {
+			owner = append(owner, yaml.MapItem{Key: "email", Value: api.Owner.Email})
+		}
+		m = append(m, yaml.MapItem{Key: "(owner)", Value: owner})
+	}
+
+	if len(api.Auth) > 0 {
+		m = append(m, yaml.MapItem{Key: "securedBy", Value: ramlSecuredBy(api, schemes)})
+	}
+	if api.ScopeLogic == types.ScopeLogicAny {
+		m = append(m, yaml.MapItem{Key: "(scopeLogic)", Value: api.ScopeLogic})
+	}
+	if len(api.AccessRoles) > 0 {
+		m = append(m, yaml.MapItem{Key: "(accessRoles)", Value: api.AccessRoles})
+	}
+	m = append(m, ramlCodegen(api.Codegen)...)
+
+	if len(api.Queries) > 0 {
+		m = append(m, yaml.MapItem{Key: "queryParameters", Value: ramlParams(api.Queries, api.Formats)})
+	}
+
+	var headers yaml.MapSlice
+	if api.Request != nil {
+		headers = ramlHeaders(api.Request.Headers)
+	}
+	if key := api.IdempotencyKey; key != nil { // 覆盖 @apiRequest 中的同名报头
+		headers = ramlSetHeader(headers, yaml.MapItem{Key: key.Header, Value: yaml.MapSlice{
+			{Key: "type", Value: "string"},
+			{Key: "description", Value: key.Summary},
+			{Key: "required", Value: true},
+			{Key: "(idempotencyKey)", Value: true},
+		}})
+	}
+	if rid := api.RequestID; rid != nil {
+		headers = ramlSetHeader(headers, ramlRequestID(rid, true))
+	}
+	if len(headers) > 0 {
+		m = append(m, yaml.MapItem{Key: "headers", Value: headers})
+	}
+
+	if req := api.Request; req != nil {
+		mimetypes := api.Consumes
+		if api.Multipart {
+			mimetypes = []string{types.MultipartFormData}
+		} else if len(req.Type) > 0 {
+			mimetypes = strings.Split(req.Type, ",")
+		}
+		if typ := ramlObject(req.Params, req.Discriminator, api.Formats); len(typ) > 0 {
+			if api.Batch != nil {
+				typ = ramlBatch(typ, "items", api.Batch)
+			}
+			m = append(m, yaml.MapItem{Key: "body", Value: ramlBody(mimetypes, typ)})
+		}
+	}
+
+	responses := yaml.MapSlice{}
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp == nil || ramlHasCode(responses, resp.Code) {
+			continue
+		}
+
+		r := yaml.MapSlice{{Key: "description", Value: resp.Summary}}
+		if headers := ramlResponseHeaders(api, resp); len(headers) > 0 {
+			r = append(r, yaml.MapItem{Key: "headers", Value: headers})
+		}
+		mimetypes := api.Produces
+		if cts, found := api.ContentTypes[resp.Code]; found {
+			mimetypes = cts
+		} else if resp == api.Success && len(api.Negotiation) > 0 {
+			mimetypes = api.Negotiation
+		}
+		if resp == api.Success && api.MediaType != nil { // 二进制内容以 file 类型输出
+			r = append(r, yaml.MapItem{Key: "body", Value: yaml.MapSlice{{Key: api.MediaType.Type, Value: yaml.MapSlice{
+				{Key: "type", Value: "file"},
+				{Key: "fileTypes", Value: []string{api.MediaType.Type}},
+			}}}})
+		} else if typ := ramlObject(resp.Params, resp.Discriminator, api.Formats); len(typ) > 0 {
+			if resp == api.Success && api.Batch != nil {
+				typ = ramlBatch(typ, "results", api.Batch)
+			}
+			r = append(r, yaml.MapItem{Key: "body", Value: ramlBody(mimetypes, typ)})
+		}
+
+		// 状态码作为数值输出，否则会被当作字符串加上引号
+		var code interface{} = resp.Code
+		if c, err := strconv.Atoi(resp.Code); err == nil {
+			code = c
+		}
+		responses = append(responses, yaml.MapItem{Key: code, Value: r})
+	}
+	for _, e := range api.AuthErrors { // @apiAuthError 只补充 @apiSuccess 和 @apiError 中没有的状态码
+		if ramlHasCode(responses, e.Code) {
+			continue
+		}
+
+		typ := yaml.MapSlice{{Key: "type", Value: e.Schema}}
+		var body interface{} = typ
+		if len(api.Produces) > 0 {
+			mimetypes := make(yaml.MapSlice, 0, len(api.Produces))
+			for _, mimetype := range api.Produces {
+				mimetypes = append(mimetypes, yaml.MapItem{Key: mediaType(mimetype), Value: typ})
+			}
+			body = mimetypes
+		}
+
+		code, _ := strconv.Atoi(e.Code) // 只能是 401 和 403
+		responses = append(responses, yaml.MapItem{Key: code, Value: yaml.MapSlice{
+			{Key: "description", Value: e.Summary},
+			{Key: "body", Value: body},
+		}})
+	}
+	if len(api.Conflicts) > 0 && !ramlHasCode(responses, "409") { // @apiConflict 只在未声明 409 时组合成 409 的返回内容
+		responses = append(responses, yaml.MapItem{Key: 409, Value: ramlConflicts(api)})
+	}
+	if api.Async != nil && !ramlHasCode(responses, "202") { // 异步接口自动补充返回任务 ID 的 202
+		typ := yaml.MapSlice{
+			{Key: "type", Value: "object"},
+			{Key: "properties", Value: yaml.MapSlice{{Key: types.AsyncJobID, Value: "string"}}},
+		}
+		responses = append(responses, yaml.MapItem{Key: 202, Value: yaml.MapSlice{
+			{Key: "description", Value: "Accepted"},
+			{Key: "body", Value: ramlBody(api.Produces, typ)},
+		}})
+	}
+	if len(responses) > 0 {
+		m = append(m, yaml.MapItem{Key: "responses", Value: responses})
+	}
+
+	return m
+}
+
+// 将 @apiConflict 组合成 409 的返回内容，返回内容的类型为各冲突类型的联合类型，
+// 冲突的名称与类型的对应关系以 (conflicts) 注解的形式输出。
+func ramlConflicts(api *types.API) yaml.MapSlice {
+	schemas := make([]string, 0, len(api.Conflicts))
+	exists := make(map[string]bool, len(api.Conflicts))
+	summaries := make([]string, 0, len(api.Conflicts))
+	conflicts := make([]yaml.MapSlice, 0, len(api.Conflicts))
+	for _, c := range api.Conflicts {
+		if !exists[c.Schema] {
+			exists[c.Schema] = true
+			schemas = append(schemas, c.Schema)
+		}
+		summaries = append(summaries, c.Condition+": "+c.Summary)
+		conflicts = append(conflicts, yaml.MapSlice{
+			{Key: "condition", Value: c.Condition},
+			{Key: "type", Value: c.Schema},
+			{Key: "description", Value: c.Summary},
+		})
+	}
+
+	typ := yaml.MapSlice{{Key: "type", Value: strings.Join(schemas, " | ")}}
+	return yaml.MapSlice{
+		{Key: "description", Value: strings.Join(summaries, "\n")},
+		{Key: "(conflicts)", Value: conflicts},
+		{Key: "body", Value: ramlBody(api.Produces, typ)},
+	}
+}
+
+// 指定了 @apiScope 时，将权限范围作为 oauth2 认证方式的参数输出，其它认证方式保持不变。
+func ramlSecuredBy(api *types.API, schemes map[string]*types.Security) []interface{} {
+	ret := make([]interface{}, 0, len(api.Auth))
+	for _, name := range api.Auth {
+		if s, found := schemes[name]; found && s.Type == types.SecurityTypeOAuth2 && len(api.RequiredScopes) > 0 {
+			ret = append(ret, yaml.MapSlice{{Key: name, Value: yaml.MapSlice{{Key: "scopes", Value: api.RequiredScopes}}}})
+			continue
+		}
+		ret = append(ret, name)
+	}
+	return ret
+}
+
+// 将 @apiCodegen 转换成注解，每一种语言对应一个 (codegen-lang) 注解，语言和选项均按名称排序。
+func ramlCodegen(codegen map[string]map[string]string) yaml.MapSlice {
+	langs := make([]string, 0, len(codegen))
+	for lang := range codegen {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	ret := make(yaml.MapSlice, 0, len(langs))
+	for _, lang := range langs {
+		hints := codegen[lang]
+		keys := make([]string, 0, len(hints))
+		for key := range hints {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		m := make(yaml.MapSlice, 0, len(keys))
+		for _, key := range keys {
+			m = append(m, yaml.MapItem{Key: key, Value: hints[key]})
+		}
+		ret = append(ret, yaml.MapItem{Key: "(codegen-" + lang + ")", Value: m})
+	}
+	return ret
+}
+
+func ramlRetry(r *types.Retry) yaml.MapSlice {
+	ret := yaml.MapSlice{{Key: "strategy", Value: r.Strategy}}
+	if r.MaxAttempts > 0 {
+		ret = append(ret, yaml.MapItem{Key: "maxAttempts", Value: r.MaxAttempts})
+	}
+	if len(r.Backoff) > 0 {
+		ret = append(ret, yaml.MapItem{Key: "backoff", Value: r.Backoff})
+	}
+	if len(r.RetryOn) > 0 {
+		ret = append(ret, yaml.MapItem{Key: "retryOn", Value: r.RetryOn})
+	}
+	return ret
+}
+
+// 根据参数生成对象类型的声明，没有参数时返回 nil。
+//
+// RAML 的 discriminator 只有字段名称，映射关系以注解的形式输出。
+func ramlObject(params []*types.Param, d *types.Discriminator, formats map[string]string) yaml.MapSlice {
+	if len(params) == 0 {
+		return nil
+	}
+
+	typ := yaml.MapSlice{
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: ramlParams(params, formats)},
+	}
+	if d != nil {
+		typ = append(typ, yaml.MapItem{Key: "discriminator", Value: d.Property})
+		if len(d.Mapping) > 0 {
+			typ = append(typ, yaml.MapItem{Key: "(discriminatorMapping)", Value: ramlMapping(d.Mapping)})
+		}
+	}
+	return typ
+}
+
+// 将 typ 包装成批量接口的内容，field 为数组所在的字段名。
+func ramlBatch(typ yaml.MapSlice, field string, b *types.Batch) yaml.MapSlice {
+	return yaml.MapSlice{
+		{Key: "type", Value: "object"},
+		{Key: "properties", Value: yaml.MapSlice{{Key: field, Value: yaml.MapSlice{
+			{Key: "type", Value: "array"},
+			{Key: "maxItems", Value: b.MaxItems},
+			{Key: "items", Value: typ},
+		}}}},
+	}
+}
+
+// 生成 body 的内容，未指定 mimetypes 时，直接使用类型声明，
+// 由 RAML 的 mediaType 决定其类型。
+func ramlBody(mimetypes []string, typ yaml.MapSlice) yaml.MapSlice {
+	if len(mimetypes) == 0 {
+		return typ
+	}
+
+	body := make(yaml.MapSlice, 0, len(mimetypes))
+	for _, mimetype := range mimetypes {
+		body = append(body, yaml.MapItem{Key: mediaType(mimetype), Value: typ})
+	}
+	return body
+}
+
+// 将 m 转换成按键名排序的 yaml.MapSlice
+func ramlMapping(m map[string]string) yaml.MapSlice {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	ret := make(yaml.MapSlice, 0, len(keys))
+	for _, k := range keys {
+		ret = append(ret, yaml.MapItem{Key: k, Value: m[k]})
+	}
+	return ret
+}
+
+// formats 为 @apiFormat 指定的参数格式，以注解的形式输出；
+// @apiNullable 指定的参数，其类型为与 nil 的联合类型。
+func ramlParams(params []*types.Param, formats map[string]string) yaml.MapSlice {
+	ret := make(yaml.MapSlice, 0, len(params))
+	for _, p := range params {
+		typ := ramlType(p.Type)
+		if p.Nullable {
+			typ += " | nil"
+		}
+
+		param := yaml.MapSlice{
+			{Key: "type", Value: typ},
+			{Key: "description", Value: p.Summary},
+		}
+		if format, found := formats[p.Name]; found {
+			param = append(param, yaml.MapItem{Key: "(format)", Value: format})
+		}
+		ret = append(ret, yaml.MapItem{Key: p.Name, Value: param})
+	}
+	return ret
+}
+
+func ramlHeaders(headers map[string]string) yaml.MapSlice {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ret := make(yaml.MapSlice, 0, len(names))
+	for _, name := range names {
+		ret = append(ret, yaml.MapItem{Key: name, Value: yaml.MapSlice{
+			{Key: "type", Value: "string"},
+			{Key: "description", Value: headers[name]},
+		}})
+	}
+	return ret
+}
+
+// 合并 @apiSuccess 和 @apiError 中的报头与 @apiResponseHeader 指定的报头，
+// @apiCacheControl 产生的报头仅添加到 @apiSuccess 中，@apiRequestID 的报头则添加到所有的返回内容中。
+func ramlResponseHeaders(api *types.API, resp *types.Response) yaml.MapSlice {
+	rh := api.ResponseHeadersOf(resp.Code)
+	if resp == api.Success {
+		rh = append(rh, api.CacheHeaders()...)
+	}
+
+	headers := ramlHeaders(resp.Headers)
+	for _, h := range rh {
+		if ramlHasKey(headers, h.Name) {
+			continue
+		}
+
+		headers = append(headers, yaml.MapItem{Key: h.Name, Value: yaml.MapSlice{
+			{Key: "type", Value: ramlType(h.Type)},
+			{Key: "description", Value: h.Summary},
+		}})
+	}
+
+	if rid := api.RequestID; rid != nil && !ramlHasKey(headers, rid.Header) {
+		headers = append(headers, ramlRequestID(rid, false))
+	}
+	return headers
+}
+
+// @apiRequestID 在请求中的描述，键名为 types.RequestID.Mode
+var ramlRequestIDSummaries = map[string]string{
+	types.RequestIDAuto:     "用于关联请求的 ID，未提供时由服务端生成",
+	types.RequestIDRequired: "用于关联请求的 ID",
+	types.RequestIDOptional: "用于关联请求的 ID，可以不提供",
+}
+
+// 生成 @apiRequestID 对应的报头，request 表示是否为请求中的报头。
+//
+// RAML 中的报头默认是必须的，所以需要明确指定 required：
+// 请求中只有 required 是必须的；返回内容中，除了 optional 之外都会包含该报头。
+func ramlRequestID(rid *types.RequestID, request bool) yaml.MapItem {
+	desc := ramlRequestIDSummaries[rid.Mode]
+	required := rid.Mode == types.RequestIDRequired
+	if !request {
+		desc = "与请求中的 " + rid.Header + " 相同"
+		if rid.Mode == types.RequestIDAuto {
+			desc += "，请求中未提供时为服务端生成的值"
+		}
+		required = rid.Mode != types.RequestIDOptional
+	}
+
+	return yaml.MapItem{Key: rid.Header, Value: yaml.MapSlice{
+		{Key: "type", Value: "string"},
+		{Key: "description", Value: desc},
+		{Key: "required", Value: required},
+		{Key: "(requestID)", Value: rid.Mode},
+	}}
+}
+
+// 将 SSE 的事件或是 WebSocket 的消息转换成 RAML 注解的值
+func ramlEvents(events []*types.Event) []yaml.MapSlice {
+	ret := make([]yaml.MapSlice, 0, len(events))
+	for _, e := range events {
+		item := yaml.MapSlice{
+			{Key: "name", Value: e.Name},
+			{Key: "type", Value: e.Type},
+		}
+		if len(e.Summary) > 0 {
+			item = append(item, yaml.MapItem{Key: "description", Value: e.Summary})
+		}
+		ret = append(ret, item)
+	}
+	return ret
+}
+
+// 将 @apiSocketIO 转换成注解的值，每个命名空间为一个元素。
+func ramlSocketIO(namespaces []*types.SocketIONamespace) []yaml.MapSlice {
+	ret := make([]yaml.MapSlice, 0, len(namespaces))
+	for _, ns := range namespaces {
+		events := make([]yaml.MapSlice, 0, len(ns.Events))
+		for _, e := range ns.Events {
+			events = append(events, yaml.MapSlice{
+				{Key: "event", Value: e.Event},
+				{Key: "direction", Value: e.Direction},
+				{Key: "type", Value: e.Type},
+				{Key: "description", Value: e.Summary},
+			})
+		}
+		ret = append(ret, yaml.MapSlice{
+			{Key: "namespace", Value: ns.Namespace},
+			{Key: "events", Value: events},
+		})
+	}
+	return ret
+}
+
+// 将 item 添加到 headers 中，若已经存在同名的报头，则替换该报头。
+func ramlSetHeader(headers yaml.MapSlice, item yaml.MapItem) yaml.MapSlice {
+	for i := range headers {
+		if headers[i].Key == item.Key {
+			headers[i] = item
+			return headers
+		}
+	}
+	return append(headers, item)
+}
+
+// 将参数类型转换成 RAML 的内置类型，无法识别的类型统一为 any。
+func ramlType(typ string) string {
+	switch strings.ToLower(typ) {
+	case "int", "integer", "int32", "int64", "uint", "long":
+		return "integer"
+	case "float", "double", "number", "float32", "float64":
+		return "number"
+	case "bool", "boolean":
+		return "boolean"
+	case "string", "array", "object", "file":
+		return strings.ToLower(typ)
+	default:
+		return "any"
+	}
+}
+
+// 请求或是返回内容中是否有 @apiDiscriminator 指定的映射关系
+func ramlHasDiscriminatorMapping(api *types.API) bool {
+	if api.Request != nil && api.Request.Discriminator != nil && len(api.Request.Discriminator.Mapping) > 0 {
+		return true
+	}
+
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp != nil && resp.Discriminator != nil && len(resp.Discriminator.Mapping) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func ramlHasKey(items yaml.MapSlice, key string) bool {
+	for _, item := range items {
+		if item.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func ramlHasCode(items yaml.MapSlice, code string) bool {
+	for _, item := range items {
+		if fmt.Sprint(item.Key) == code {
+			return true
+		}
+	}
+	return false
+}
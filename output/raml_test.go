@@ -0,0 +1,1100 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+	"github.com/issue9/utils"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/input/syntax"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func newRAMLDoc() *types.Doc {
+	docs := types.NewDoc()
+	docs.Title = "test"
+	docs.Version = "1.0.0"
+	docs.BaseURL = "https://api.caixw.io"
+	docs.BasePath = "/v1"
+	docs.SecuritySchemes["token"] = &types.Security{Name: "token", Type: types.SecurityTypeAPIKey, In: "header", Key: "X-Token"}
+	docs.SecuritySchemes["basic"] = &types.Security{Name: "basic", Type: types.SecurityTypeHTTP, Scheme: "basic"}
+
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Summary: "获取用户",
+		Group:   "users",
+		Notes:   []*types.Note{{Type: types.NoteTypeTip, Text: "tip"}},
+		Links:   []*types.Link{{URL: "https://tools.ietf.org/html/rfc7231"}},
+		Auth:    []string{"token"},
+		Params:  []*types.Param{{Name: "id", Type: "int", Summary: "用户 ID"}},
+		Queries: []*types.Param{{Name: "fields", Type: "string", Summary: "返回的字段"}},
+		Success: &types.Response{
+			Code:    "200",
+			Summary: "OK",
+			Params:  []*types.Param{{Name: "name", Type: "string", Summary: "用户名"}},
+		},
+		Error: &types.Response{Code: "404", Summary: "不存在"},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "DELETE",
+		URL:     "/users/{id}",
+		Summary: "删除用户",
+		Group:   "users",
+		Auth:    []string{"basic"},
+		Params:  []*types.Param{{Name: "id", Type: "int", Summary: "用户 ID"}},
+		Success: &types.Response{Code: "204", Summary: "OK"},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/login",
+		Summary: "登录",
+		Group:   "auth",
+		Request: &types.Request{
+			Type:    "json",
+			Headers: map[string]string{"Accept-Language": "语言"},
+			Params:  []*types.Param{{Name: "password", Type: "string", Summary: "密码"}},
+		},
+		Success: &types.Response{Code: "201", Summary: "OK"},
+	})
+
+	return docs
+}
+
+func TestWriteRAML(t *testing.T) {
+	a := assert.New(t)
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, newRAMLDoc(), &Options{}))
+	a.True(strings.HasPrefix(buf.String(), ramlHeader))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	a.Equal(raml["title"], "test").
+		Equal(raml["version"], "1.0.0").
+		Equal(raml["baseUri"], "https://api.caixw.io/v1")
+
+	schemes := raml["securitySchemes"].(map[interface{}]interface{})
+	a.Equal(len(schemes), 2)
+	a.Equal(schemes["token"].(map[interface{}]interface{})["type"], "Pass Through").
+		Equal(schemes["basic"].(map[interface{}]interface{})["type"], "Basic Authentication")
+
+	resourceTypes := raml["resourceTypes"].(map[interface{}]interface{})
+	a.Equal(len(resourceTypes), 2)
+	a.NotNil(resourceTypes["users"]).NotNil(resourceTypes["auth"])
+
+	// 相同地址的 API 合并在同一资源之下
+	users := raml["/users/{id}"].(map[interface{}]interface{})
+	a.Equal(users["type"], "users")
+	a.NotNil(users["uriParameters"].(map[interface{}]interface{})["id"])
+	get := users["get"].(map[interface{}]interface{})
+	a.Equal(get["displayName"], "获取用户").
+		Equal(get["description"], "**Tip:** tip\n\n- [https://tools.ietf.org/html/rfc7231](https://tools.ietf.org/html/rfc7231)").
+		Equal(get["securedBy"], []interface{}{"token"})
+	a.NotNil(get["queryParameters"].(map[interface{}]interface{})["fields"])
+	responses := get["responses"].(map[interface{}]interface{})
+	a.Equal(len(responses), 2)
+	a.NotNil(responses[200]).NotNil(responses[404])
+	a.NotNil(users["delete"])
+
+	login := raml["/login"].(map[interface{}]interface{})
+	post := login["post"].(map[interface{}]interface{})
+	body := post["body"].(map[interface{}]interface{})
+	a.NotNil(body["application/json"])
+	a.NotNil(post["headers"].(map[interface{}]interface{})["Accept-Language"])
+}
+
+func TestWriteRAML_groups(t *testing.T) {
+	a := assert.New(t)
+
+	docs := newRAMLDoc()
+	docs.BaseURL = ""
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{Groups: []string{"auth"}, BasePath: "/v2"}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	a.Nil(raml["baseUri"]).
+		Nil(raml["/v2/users/{id}"]).
+		NotNil(raml["/v2/login"])
+}
+
+func TestWriteRAML_contentTypes(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:       "GET",
+		URL:          "/users",
+		Summary:      "获取用户",
+		Group:        "users",
+		Produces:     []string{"application/json"},
+		ContentTypes: map[string][]string{"400": {"text/plain"}},
+		Success:      &types.Response{Code: "200", Params: []*types.Param{{Name: "id", Type: "int"}}},
+		Error:        &types.Response{Code: "400", Params: []*types.Param{{Name: "message", Type: "string"}}},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	users := raml["/users"].(map[interface{}]interface{})
+	responses := users["get"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+
+	body := responses[200].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	a.NotNil(body["application/json"]).Nil(body["text/plain"])
+
+	body = responses[400].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	a.NotNil(body["text/plain"]).Nil(body["application/json"])
+
+	// @apiContentNegotiation 只作用于 Success
+	docs.Apis[0].Produces = []string{"application/json", "application/xml", "text/csv"}
+	docs.Apis[0].Negotiation = []string{"application/json", "application/xml"}
+	buf.Reset()
+	a.NotError(writeRAML(buf, docs, &Options{}))
+	raml = map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	users = raml["/users"].(map[interface{}]interface{})
+	responses = users["get"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+
+	body = responses[200].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	a.Equal(len(body), 2).
+		NotNil(body["application/json"]).
+		NotNil(body["application/xml"])
+
+	body = responses[400].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	a.Equal(len(body), 1).NotNil(body["text/plain"])
+}
+
+func TestWriteRAML_annotations(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "users", Group: "g", Safe: true, Idempotent: true})
+	docs.NewAPI(&types.API{
+		Method:              "PUT",
+		URL:                 "/users",
+		Summary:             "update",
+		Group:               "g",
+		Idempotent:          true,
+		Retry:               &types.Retry{Strategy: "always", MaxAttempts: 3, RetryOn: []int{503}},
+		Timeout:             &types.Timeout{Value: 500, Percentile: types.PercentileP99},
+		TimeBudget:          &types.TimeBudget{Total: 500, Upstreams: []*types.UpstreamBudget{{Service: "users", Value: 200}}},
+		SLA:                 &types.SLA{Availability: 99.95, RTO: "1h"},
+		CircuitBreaker:      &types.CircuitBreaker{Threshold: 50, Timeout: "30s"},
+		Tenant:              &types.TenantPolicy{Isolation: types.TenantIsolationShared, Scoping: types.TenantScopingHeader, Header: "X-Org"},
+		Region:              &types.RegionPolicy{Available: []string{"us-east-1", "eu-west-1"}},
+		Quota:               &types.Quota{Limit: 10000, Window: types.QuotaWindowMonth, Scope: types.QuotaScopeKey},
+		Async:               &types.Async{PollURL: "/jobs/{jobId}"},
+		MaxRequestBodyBytes: 1048576,
+		DataClassification:  types.DataClassificationSensitive,
+		FeatureFlag:         &types.FeatureFlag{Name: "new-users", Provider: types.FeatureFlagCustom},
+		Audiences:           []string{types.AudiencePartner, types.AudienceInternal},
+		CORSPolicy:          &types.CORSPolicy{Origin: "*", Methods: []string{"PUT"}, MaxAge: 600},
+		BreakingChanges: []*types.BreakingChange{
+			{Version: "2.0.0", Description: "删除 name 参数"},
+		},
+		GRPCMethod:  "users.v1.UserService.UpdateUser",
+		OperationID: "updateUser",
+		GraphQL:     &types.GraphQL{Operation: "updateUser", Type: types.GraphQLMutation},
+		Changelog: []*types.ChangelogEntry{
+			{Version: "1.1.0", Date: "2017-11-01", Description: "添加 email 字段"},
+			{Version: "1.0.0", Date: "2017-10-01", Description: "初始版本"},
+		},
+		ErrorCodes:  []*types.ErrorCode{{Code: "NOT_FOUND", Summary: "用户不存在"}},
+		Metrics:     []*types.Metric{{Name: "p99-latency", Value: 200, Unit: "ms"}},
+		Owner:       &types.Owner{Team: "Platform Engineering", Email: "platform@example.com"},
+		AccessRoles: []string{"admin", "editor"},
+		Environments: []*types.EnvironmentNote{
+			{Environment: types.EnvironmentProd, Text: "限流"},
+			{Environment: types.EnvironmentAll, Text: "需要登录"},
+		},
+	})
+	docs.NewAPI(&types.API{Method: "POST", URL: "/users", Summary: "create", Group: "g"})
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/events",
+		Summary: "events",
+		Group:   "g",
+		SSE:     true,
+		Events: []*types.Event{
+			{Name: "created", Type: "object", Summary: "用户已创建"},
+			{Name: "deleted", Type: "string", Summary: "用户已删除"},
+		},
+	})
+
+	docs.NewAPI(&types.API{
+		Method:    "GET",
+		URL:       "/chat",
+		Summary:   "chat",
+		Group:     "g",
+		WebSocket: true,
+		WSSend:    []*types.Event{{Name: "message", Type: "object", Summary: "新的聊天消息"}},
+		WSReceive: []*types.Event{{Name: "send", Type: "object"}},
+		SocketIO: []*types.SocketIONamespace{{
+			Namespace: "/chat",
+			Events:    []*types.SocketIOEvent{{Event: "typing", Direction: types.SocketIOEmit, Type: "string", Summary: "对方正在输入"}},
+		}},
+	})
+
+	docs.NewAPI(&types.API{
+		Method:         "POST",
+		URL:            "/orders",
+		Summary:        "create order",
+		Group:          "g",
+		ConsumedEvents: []*types.Event{{Name: "CartCheckedOut", Type: "Cart"}},
+		ProducedEvents: []*types.Event{
+			{Name: "OrderCreated", Type: "Order", Summary: "订单已创建"},
+			{Name: "StockReserved", Type: "Stock"},
+		},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(annotations["safe"], "boolean").
+		Equal(annotations["idempotent"], "boolean").
+		Equal(annotations["retry"], "object").
+		Equal(annotations["timeout"], "object").
+		Equal(annotations["sla"], "object").
+		Equal(annotations["timeBudget"], "object").
+		Equal(annotations["circuitBreaker"], "object").
+		Equal(annotations["tenant"], "object").
+		Equal(annotations["region"], "object").
+		Equal(annotations["quota"], "object").
+		Equal(annotations["async"], "object").
+		Equal(annotations["maxContentLength"], "integer").
+		Equal(annotations["dataClassification"], "string").
+		Equal(annotations["featureFlag"], "object").
+		Equal(annotations["audience"], "string[]").
+		Equal(annotations["cors"], "object").
+		Equal(annotations["breakingChanges"], "object[]").
+		Equal(annotations["grpcMethod"], "string").
+		Equal(annotations["operationId"], "string").
+		Equal(annotations["graphqlOperation"], "object").
+		Equal(annotations["sseEvents"], "object[]").
+		Equal(annotations["changelog"], "object[]").
+		Equal(annotations["websocket"], "object").
+		Equal(annotations["socketIO"], "object[]").
+		Equal(annotations["producesEvents"], "object[]").
+		Equal(annotations["consumesEvents"], "object[]").
+		Equal(annotations["errorCodes"], "object").
+		Equal(annotations["metrics"], "object").
+		Equal(annotations["owner"], "object").
+		Equal(annotations["environmentNotes"], "object[]").
+		Equal(annotations["accessRoles"], "string[]")
+
+	users := raml["/users"].(map[interface{}]interface{})
+	get := users["get"].(map[interface{}]interface{})
+	a.Equal(get["(safe)"], true).Equal(get["(idempotent)"], true)
+	put := users["put"].(map[interface{}]interface{})
+	a.Nil(put["(safe)"]).Equal(put["(idempotent)"], true)
+	a.Equal(put["(retry)"], map[interface{}]interface{}{
+		"strategy":    "always",
+		"maxAttempts": 3,
+		"retryOn":     []interface{}{503},
+	})
+	a.Equal(put["(changelog)"], []interface{}{
+		map[interface{}]interface{}{"version": "1.1.0", "date": "2017-11-01", "description": "添加 email 字段"},
+		map[interface{}]interface{}{"version": "1.0.0", "date": "2017-10-01", "description": "初始版本"},
+	})
+	a.Equal(put["(grpcMethod)"], "users.v1.UserService.UpdateUser").
+		Equal(put["(operationId)"], "updateUser").
+		Equal(put["(featureFlag)"], map[interface{}]interface{}{"name": "new-users", "provider": "custom"}).
+		Equal(put["(audience)"], []interface{}{"partner", "internal"}).
+		Equal(put["(dataClassification)"], "sensitive").
+		Equal(put["(region)"], map[interface{}]interface{}{"available": []interface{}{"us-east-1", "eu-west-1"}}).
+		Equal(put["(quota)"], map[interface{}]interface{}{"limit": 10000, "window": "month", "scope": "key"}).
+		Equal(put["(async)"], map[interface{}]interface{}{"pollUrl": "/jobs/{jobId}"}).
+		Equal(put["(maxContentLength)"], 1048576).
+		Equal(put["(tenant)"], map[interface{}]interface{}{"isolation": "shared", "scoping": "header", "header": "X-Org"}).
+		Equal(put["(circuitBreaker)"], map[interface{}]interface{}{"threshold": 50, "timeout": "30s"}).
+		Equal(put["(timeBudget)"], map[interface{}]interface{}{
+			"total":     500,
+			"upstreams": []interface{}{map[interface{}]interface{}{"service": "users", "value": 200}},
+		}).
+		Equal(put["(graphqlOperation)"], map[interface{}]interface{}{"name": "updateUser", "type": "mutation"})
+	a.Equal(put["(timeout)"], map[interface{}]interface{}{"value": 500, "percentile": "p99"})
+	a.Equal(put["(breakingChanges)"], []interface{}{
+		map[interface{}]interface{}{"version": "2.0.0", "description": "删除 name 参数"},
+	})
+	a.Equal(put["(cors)"], map[interface{}]interface{}{"origin": "*", "methods": []interface{}{"PUT"}, "maxAge": 600})
+	a.Equal(put["(sla)"], map[interface{}]interface{}{"availability": 99.95, "rto": "1h"})
+	a.Equal(put["(errorCodes)"], map[interface{}]interface{}{"NOT_FOUND": "用户不存在"})
+	a.Equal(put["(metrics)"], map[interface{}]interface{}{
+		"p99-latency": map[interface{}]interface{}{"value": 200.0, "unit": "ms"},
+	})
+	a.Equal(put["(owner)"], map[interface{}]interface{}{"team": "Platform Engineering", "email": "platform@example.com"})
+	a.Equal(put["(accessRoles)"], []interface{}{"admin", "editor"})
+	a.Equal(put["(environmentNotes)"], []interface{}{
+		map[interface{}]interface{}{"environment": "prod", "text": "限流"},
+		map[interface{}]interface{}{"environment": "all", "text": "需要登录"},
+	})
+	a.Nil(put["(sseEvents)"])
+	events := raml["/events"].(map[interface{}]interface{})["get"].(map[interface{}]interface{})
+	a.Equal(events["(sseEvents)"], []interface{}{
+		map[interface{}]interface{}{"name": "created", "type": "object", "description": "用户已创建"},
+		map[interface{}]interface{}{"name": "deleted", "type": "string", "description": "用户已删除"},
+	})
+	a.Nil(events["(websocket)"])
+	chat := raml["/chat"].(map[interface{}]interface{})["get"].(map[interface{}]interface{})
+	a.Nil(chat["(sseEvents)"])
+	a.Equal(chat["(websocket)"], map[interface{}]interface{}{
+		"send":    []interface{}{map[interface{}]interface{}{"name": "message", "type": "object", "description": "新的聊天消息"}},
+		"receive": []interface{}{map[interface{}]interface{}{"name": "send", "type": "object"}},
+	})
+	a.Equal(chat["(socketIO)"], []interface{}{
+		map[interface{}]interface{}{
+			"namespace": "/chat",
+			"events": []interface{}{
+				map[interface{}]interface{}{"event": "typing", "direction": "emit", "type": "string", "description": "对方正在输入"},
+			},
+		},
+	})
+	a.Nil(events["(socketIO)"])
+	orders := raml["/orders"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+	a.Equal(orders["(producesEvents)"], []interface{}{
+		map[interface{}]interface{}{"name": "OrderCreated", "type": "Order", "description": "订单已创建"},
+		map[interface{}]interface{}{"name": "StockReserved", "type": "Stock"},
+	})
+	a.Equal(orders["(consumesEvents)"], []interface{}{
+		map[interface{}]interface{}{"name": "CartCheckedOut", "type": "Cart"},
+	})
+	a.Nil(chat["(producesEvents)"]).Nil(chat["(consumesEvents)"])
+
+	post := users["post"].(map[interface{}]interface{})
+	a.Nil(post["(safe)"]).Nil(post["(idempotent)"]).Nil(post["(retry)"]).Nil(post["(timeout)"]).Nil(post["(timeBudget)"]).Nil(post["(circuitBreaker)"]).Nil(post["(tenant)"]).Nil(post["(region)"]).Nil(post["(quota)"]).Nil(post["(async)"]).Nil(post["(maxContentLength)"]).Nil(post["(dataClassification)"]).Nil(post["(sla)"]).Nil(post["(featureFlag)"]).Nil(post["(audience)"]).Nil(post["(cors)"]).Nil(post["(breakingChanges)"]).Nil(post["(grpcMethod)"]).Nil(post["(operationId)"]).Nil(post["(graphqlOperation)"]).Nil(post["(sseEvents)"]).Nil(post["(changelog)"]).Nil(post["(errorCodes)"]).Nil(post["(metrics)"]).Nil(post["(owner)"]).Nil(post["(environmentNotes)"]).Nil(post["(accessRoles)"])
+
+	// 未使用时，不输出 annotationTypes
+	buf.Reset()
+	a.NotError(writeRAML(buf, newRAMLDoc(), &Options{}))
+	raml = map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	a.Nil(raml["annotationTypes"])
+}
+
+func TestWriteRAML_idempotencyKey(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:         "POST",
+		URL:            "/payments",
+		Summary:        "创建支付",
+		Group:          "payments",
+		IdempotencyKey: &types.IdempotencyKey{Header: "Idempotency-Key", Summary: "唯一值"},
+	})
+	docs.NewAPI(&types.API{
+		Method:         "PUT",
+		URL:            "/payments",
+		Summary:        "修改支付",
+		Group:          "payments",
+		IdempotencyKey: &types.IdempotencyKey{Header: "Idempotency-Key", Summary: "唯一值"},
+		Request: &types.Request{Headers: map[string]string{
+			"Accept-Language": "语言",
+			"Idempotency-Key": "同名的报头",
+		}},
+	})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/payments", Summary: "支付列表", Group: "payments"})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(annotations["idempotencyKey"], "boolean")
+
+	key := map[interface{}]interface{}{
+		"type":             "string",
+		"description":      "唯一值",
+		"required":         true,
+		"(idempotencyKey)": true,
+	}
+	payments := raml["/payments"].(map[interface{}]interface{})
+	post := payments["post"].(map[interface{}]interface{})
+	a.Equal(post["headers"], map[interface{}]interface{}{"Idempotency-Key": key})
+
+	put := payments["put"].(map[interface{}]interface{})
+	headers := put["headers"].(map[interface{}]interface{})
+	a.Equal(len(headers), 2).
+		Equal(headers["Idempotency-Key"], key).
+		NotNil(headers["Accept-Language"])
+
+	get := payments["get"].(map[interface{}]interface{})
+	a.Nil(get["headers"])
+}
+
+// 从注释到 RAML 的完整过程
+func TestWriteRAML_multipart(t *testing.T) {
+	a := assert.New(t)
+
+	code := `
+@api post /avatars upload avatar
+@apiGroup users
+@apiMultipart
+@apiRequest
+@apiParam title string 标题
+@apiParam avatar file 头像
+@apiSuccess 201 OK
+`
+	docs := types.NewDoc()
+	syntax.Parse(&syntax.Input{Data: []rune(code)}, docs)
+	a.Equal(len(docs.Apis), 1)
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	post := raml["/avatars"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+	body := post["body"].(map[interface{}]interface{})
+	a.Equal(len(body), 1)
+
+	form := body[types.MultipartFormData].(map[interface{}]interface{})
+	a.Equal(form["type"], "object")
+	a.Equal(form["properties"], map[interface{}]interface{}{
+		"title":  map[interface{}]interface{}{"type": "string", "description": "标题"},
+		"avatar": map[interface{}]interface{}{"type": "file", "description": "头像"},
+	})
+}
+
+func TestWriteRAML_responseHeaders(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/users",
+		Summary: "create",
+		Group:   "g",
+		ResponseHeaders: map[string][]*types.ResponseHeader{
+			"*":   {{Name: "X-Request-ID", Type: "string", Summary: "请求的 ID"}},
+			"201": {{Name: "Location", Type: "string", Summary: "新资源的地址"}},
+		},
+		CachePolicy: &types.CachePolicy{MaxAge: 60, Private: true, Vary: []string{"Accept"}},
+		Success:     &types.Response{Code: "201", Summary: "OK", Headers: map[string]string{"ETag": "版本"}},
+		Error:       &types.Response{Code: "400", Summary: "ERROR"},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	post := raml["/users"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+	a.Nil(post["headers"]) // 不会出现在请求报头中
+
+	responses := post["responses"].(map[interface{}]interface{})
+	headers := responses[201].(map[interface{}]interface{})["headers"].(map[interface{}]interface{})
+	a.Equal(len(headers), 5)
+	a.NotNil(headers["ETag"]).NotNil(headers["Location"]).NotNil(headers["X-Request-ID"])
+	a.Equal(headers["Cache-Control"].(map[interface{}]interface{})["description"], "max-age=60, private").
+		Equal(headers["Vary"].(map[interface{}]interface{})["description"], "Accept")
+
+	headers = responses[400].(map[interface{}]interface{})["headers"].(map[interface{}]interface{})
+	a.Equal(len(headers), 1) // 缓存相关的报头仅出现在 @apiSuccess 中
+	a.NotNil(headers["X-Request-ID"])
+}
+
+func TestWriteRAML_authErrors(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:   "DELETE",
+		URL:      "/users/{id}",
+		Summary:  "delete",
+		Group:    "g",
+		Produces: []string{"application/json"},
+		Auth:     []string{"token"},
+		AuthErrors: []*types.AuthError{
+			{Code: "401", Schema: "AuthError", Summary: "未登录"},
+			{Code: "403", Schema: "PermissionError", Summary: "没有删除权限"},
+		},
+		Success: &types.Response{Code: "204", Summary: "OK"},
+		Error:   &types.Response{Code: "403", Summary: "ERROR"},
+	})
+	docs.NewAPI(&types.API{
+		Method:     "GET",
+		URL:        "/users/{id}",
+		Summary:    "get",
+		Group:      "g",
+		AuthErrors: []*types.AuthError{{Code: "401", Schema: "AuthError", Summary: "未登录"}},
+		Success:    &types.Response{Code: "200", Summary: "OK"},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	users := raml["/users/{id}"].(map[interface{}]interface{})
+
+	// 与 @apiError 相同的状态码，以 @apiError 为准
+	responses := users["delete"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+	a.Equal(len(responses), 3)
+	a.Equal(responses[401], map[interface{}]interface{}{
+		"description": "未登录",
+		"body":        map[interface{}]interface{}{"application/json": map[interface{}]interface{}{"type": "AuthError"}},
+	})
+	a.Equal(responses[403].(map[interface{}]interface{})["description"], "ERROR")
+
+	// 未指定 @apiProduces
+	responses = users["get"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+	a.Equal(len(responses), 2)
+	a.Equal(responses[401], map[interface{}]interface{}{
+		"description": "未登录",
+		"body":        map[interface{}]interface{}{"type": "AuthError"},
+	})
+}
+
+func TestWriteRAML_async(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:   "POST",
+		URL:      "/reports",
+		Summary:  "poll",
+		Group:    "g",
+		Produces: []string{"application/json"},
+		Async:    &types.Async{PollURL: "/jobs/{jobId}"},
+		Success:  &types.Response{Code: "200", Summary: "OK"},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/exports",
+		Summary: "callback",
+		Group:   "g",
+		Async:   &types.Async{CallbackHeader: "X-Callback-URL"},
+		Success: &types.Response{Code: "202", Summary: "已接受"},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+
+	// 轮询，自动补充 202
+	reports := raml["/reports"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+	a.Equal(reports["(async)"], map[interface{}]interface{}{"pollUrl": "/jobs/{jobId}"})
+	responses := reports["responses"].(map[interface{}]interface{})
+	a.Equal(len(responses), 2).NotNil(responses[200])
+	a.Equal(responses[202], map[interface{}]interface{}{
+		"description": "Accepted",
+		"body": map[interface{}]interface{}{"application/json": map[interface{}]interface{}{
+			"type":       "object",
+			"properties": map[interface{}]interface{}{"jobId": "string"},
+		}},
+	})
+
+	// 推送，已经声明了 202 的不再补充
+	exports := raml["/exports"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+	a.Equal(exports["(async)"], map[interface{}]interface{}{"callbackHeader": "X-Callback-URL"})
+	responses = exports["responses"].(map[interface{}]interface{})
+	a.Equal(len(responses), 1)
+	a.Equal(responses[202].(map[interface{}]interface{})["description"], "已接受")
+}
+
+func TestWriteRAML_conflicts(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:    "PUT",
+		URL:       "/users/{id}",
+		Summary:   "single",
+		Group:     "g",
+		Produces:  []string{"application/json"},
+		Conflicts: []*types.Conflict{{Condition: "version-mismatch", Schema: "ConflictError", Summary: "数据已经被修改"}},
+		Success:   &types.Response{Code: "200", Summary: "OK"},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/users",
+		Summary: "multiple",
+		Group:   "g",
+		Conflicts: []*types.Conflict{
+			{Condition: "duplicate-email", Schema: "UniqueError", Summary: "邮箱已经被注册"},
+			{Condition: "duplicate-name", Schema: "UniqueError", Summary: "用户名已经被注册"},
+			{Condition: "version-mismatch", Schema: "ConflictError", Summary: "数据已经被修改"},
+		},
+		Success: &types.Response{Code: "201", Summary: "OK"},
+	})
+	docs.NewAPI(&types.API{
+		Method:    "DELETE",
+		URL:       "/users/{id}",
+		Summary:   "declared",
+		Group:     "g",
+		Conflicts: []*types.Conflict{{Condition: "in-use", Schema: "ConflictError", Summary: "用户正在被使用"}},
+		Success:   &types.Response{Code: "204", Summary: "OK"},
+		Error:     &types.Response{Code: "409", Summary: "ERROR"},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(annotations["conflicts"], "object[]")
+
+	// 单个冲突
+	user := raml["/users/{id}"].(map[interface{}]interface{})
+	responses := user["put"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+	a.Equal(responses[409], map[interface{}]interface{}{
+		"description": "version-mismatch: 数据已经被修改",
+		"(conflicts)": []interface{}{
+			map[interface{}]interface{}{"condition": "version-mismatch", "type": "ConflictError", "description": "数据已经被修改"},
+		},
+		"body": map[interface{}]interface{}{"application/json": map[interface{}]interface{}{"type": "ConflictError"}},
+	})
+
+	// 多个冲突组成联合类型，相同的类型只出现一次
+	responses = raml["/users"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+	conflict := responses[409].(map[interface{}]interface{})
+	a.Equal(conflict["body"], map[interface{}]interface{}{"type": "UniqueError | ConflictError"}).
+		Equal(conflict["description"], "duplicate-email: 邮箱已经被注册\nduplicate-name: 用户名已经被注册\nversion-mismatch: 数据已经被修改").
+		Equal(len(conflict["(conflicts)"].([]interface{})), 3)
+
+	// 已经通过 @apiError 声明了 409
+	responses = user["delete"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+	a.Equal(responses[409], map[interface{}]interface{}{"description": "ERROR"})
+}
+
+func TestWriteRAML_mediaType(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	for _, typ := range []string{"image/png", "application/pdf", "application/zip"} {
+		docs.NewAPI(&types.API{
+			Method:    "GET",
+			URL:       "/files/" + strings.Replace(typ, "/", "-", 1),
+			Summary:   "download",
+			Group:     "g",
+			Produces:  []string{"application/json"},
+			MediaType: &types.MediaType{Type: typ},
+			Success:   &types.Response{Code: "200", Summary: "OK"},
+			Error:     &types.Response{Code: "404", Summary: "ERROR", Params: []*types.Param{{Name: "message", Type: "string"}}},
+		})
+	}
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	for _, typ := range []string{"image/png", "application/pdf", "application/zip"} {
+		res := raml["/files/"+strings.Replace(typ, "/", "-", 1)].(map[interface{}]interface{})
+		responses := res["get"].(map[interface{}]interface{})["responses"].(map[interface{}]interface{})
+
+		a.Equal(responses[200].(map[interface{}]interface{})["body"], map[interface{}]interface{}{
+			typ: map[interface{}]interface{}{"type": "file", "fileTypes": []interface{}{typ}},
+		})
+
+		// 只影响 @apiSuccess
+		body := responses[404].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+		a.NotNil(body["application/json"])
+	}
+}
+
+func TestWriteRAML_batch(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/users/batch",
+		Summary: "batch",
+		Group:   "g",
+		Batch:   &types.Batch{MaxItems: 100, Mode: types.BatchParallel},
+		Request: &types.Request{Type: "application/json", Params: []*types.Param{{Name: "name", Type: "string"}}},
+		Success: &types.Response{Code: "200", Summary: "OK", Params: []*types.Param{{Name: "id", Type: "int"}}},
+		Error:   &types.Response{Code: "400", Summary: "ERROR", Params: []*types.Param{{Name: "message", Type: "string"}}},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "PUT",
+		URL:     "/users/batch",
+		Summary: "batch",
+		Group:   "g",
+		Batch:   &types.Batch{MaxItems: 10, Mode: types.BatchSequential},
+		Success: &types.Response{Code: "204", Summary: "OK"},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	a.Equal(raml["annotationTypes"].(map[interface{}]interface{})["batch"], "object")
+	res := raml["/users/batch"].(map[interface{}]interface{})
+
+	post := res["post"].(map[interface{}]interface{})
+	a.Equal(post["(batch)"], map[interface{}]interface{}{"maxItems": 100, "mode": "parallel"})
+
+	// 请求内容包装在 items 中
+	body := post["body"].(map[interface{}]interface{})["application/json"].(map[interface{}]interface{})
+	items := body["properties"].(map[interface{}]interface{})["items"].(map[interface{}]interface{})
+	a.Equal(body["type"], "object").
+		Equal(items["type"], "array").
+		Equal(items["maxItems"], 100)
+	a.NotNil(items["items"].(map[interface{}]interface{})["properties"].(map[interface{}]interface{})["name"])
+
+	// 返回内容包装在 results 中，只影响 @apiSuccess
+	responses := post["responses"].(map[interface{}]interface{})
+	results := responses[200].(map[interface{}]interface{})["body"].(map[interface{}]interface{})["properties"].(map[interface{}]interface{})["results"].(map[interface{}]interface{})
+	a.Equal(results["type"], "array")
+	a.NotNil(results["items"].(map[interface{}]interface{})["properties"].(map[interface{}]interface{})["id"])
+	errBody := responses[400].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	a.NotNil(errBody["properties"].(map[interface{}]interface{})["message"])
+
+	// 没有参数时不输出 body
+	put := res["put"].(map[interface{}]interface{})
+	a.Equal(put["(batch)"], map[interface{}]interface{}{"maxItems": 10, "mode": "sequential"}).
+		Nil(put["body"])
+}
+
+func TestWriteRAML_order(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{Method: "GET", URL: "/a", Summary: "a", Group: "g"})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/c", Summary: "c", Group: "g", Order: 2})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/b", Summary: "b", Group: "g", Order: 1})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := yaml.MapSlice{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	urls := make([]string, 0, 3)
+	for _, item := range raml {
+		if key := item.Key.(string); strings.HasPrefix(key, "/") {
+			urls = append(urls, key)
+		}
+	}
+	a.Equal(urls, []string{"/b", "/c", "/a"})
+}
+
+func TestRender_raml(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	o := &Options{Type: TypeRAML, Dir: dir}
+	a.NotError(o.Sanitize())
+	a.NotError(Render(newRAMLDoc(), o))
+	a.True(utils.FileExists(filepath.Join(dir, vars.RAMLFileName)))
+	a.False(utils.FileExists(filepath.Join(dir, "index.html")))
+}
+
+func TestWriteRAML_formats(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Summary: "user",
+		Group:   "users",
+		Params:  []*types.Param{{Name: "id", Type: "string", Summary: "id"}},
+		Queries: []*types.Param{{Name: "since", Type: "string", Summary: "since"}, {Name: "page", Type: "int", Summary: "page"}},
+		Success: &types.Response{Code: "200", Params: []*types.Param{{Name: "email", Type: "string", Summary: "email"}}},
+		Formats: map[string]string{"id": "uuid", "since": "date", "email": "email"},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(annotations["format"], "string")
+
+	res := raml["/users/{id}"].(map[interface{}]interface{})
+	id := res["uriParameters"].(map[interface{}]interface{})["id"].(map[interface{}]interface{})
+	a.Equal(id["(format)"], "uuid")
+
+	get := res["get"].(map[interface{}]interface{})
+	queries := get["queryParameters"].(map[interface{}]interface{})
+	a.Equal(queries["since"].(map[interface{}]interface{})["(format)"], "date")
+	a.Nil(queries["page"].(map[interface{}]interface{})["(format)"])
+
+	body := get["responses"].(map[interface{}]interface{})[200].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	email := body["properties"].(map[interface{}]interface{})["email"].(map[interface{}]interface{})
+	a.Equal(email["(format)"], "email")
+
+	// 没有 @apiFormat 时，不输出注解的定义
+	buf.Reset()
+	docs = types.NewDoc()
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "users", Group: "users", Success: &types.Response{Code: "200"}})
+	a.NotError(writeRAML(buf, docs, &Options{}))
+	a.False(strings.Contains(buf.String(), "annotationTypes"))
+}
+
+func TestWriteRAML_nullable(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/users",
+		Summary: "users",
+		Group:   "users",
+		Queries: []*types.Param{{Name: "since", Type: "string", Summary: "since", Nullable: true}},
+		Success: &types.Response{Code: "200", Params: []*types.Param{
+			{Name: "nickname", Type: "string", Summary: "nickname", Nullable: true},
+			{Name: "age", Type: "int", Summary: "age"},
+		}},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	get := raml["/users"].(map[interface{}]interface{})["get"].(map[interface{}]interface{})
+	since := get["queryParameters"].(map[interface{}]interface{})["since"].(map[interface{}]interface{})
+	a.Equal(since["type"], "string | nil")
+
+	body := get["responses"].(map[interface{}]interface{})[200].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	props := body["properties"].(map[interface{}]interface{})
+	a.Equal(props["nickname"].(map[interface{}]interface{})["type"], "string | nil").
+		Equal(props["age"].(map[interface{}]interface{})["type"], "integer")
+}
+
+func TestWriteRAML_discriminator(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/pets",
+		Summary: "create pet",
+		Group:   "pets",
+		Request: &types.Request{
+			Type: "application/json",
+			Params: []*types.Param{
+				{Name: "kind", Type: "string", Summary: "类型"},
+				{Name: "name", Type: "string", Summary: "名称"},
+			},
+			Discriminator: &types.Discriminator{Property: "kind", Mapping: map[string]string{"dog": "Dog", "cat": "Cat"}},
+		},
+		Success: &types.Response{
+			Code:          "201",
+			Params:        []*types.Param{{Name: "kind", Type: "string", Summary: "类型"}},
+			Discriminator: &types.Discriminator{Property: "kind"},
+		},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(annotations["discriminatorMapping"], "object")
+
+	post := raml["/pets"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+	req := post["body"].(map[interface{}]interface{})["application/json"].(map[interface{}]interface{})
+	a.Equal(req["discriminator"], "kind").
+		Equal(req["(discriminatorMapping)"], map[interface{}]interface{}{"dog": "Dog", "cat": "Cat"})
+
+	resp := post["responses"].(map[interface{}]interface{})[201].(map[interface{}]interface{})["body"].(map[interface{}]interface{})
+	a.Equal(resp["discriminator"], "kind").
+		Nil(resp["(discriminatorMapping)"])
+
+	// 映射关系按值的顺序输出
+	a.True(strings.Index(buf.String(), "cat: Cat") < strings.Index(buf.String(), "dog: Dog"))
+}
+
+func TestWriteRAML_requestID(t *testing.T) {
+	a := assert.New(t)
+
+	data := []*struct {
+		mode              string
+		request, response bool // 请求和返回内容中的 required
+	}{
+		{mode: types.RequestIDAuto, request: false, response: true},
+		{mode: types.RequestIDRequired, request: true, response: true},
+		{mode: types.RequestIDOptional, request: false, response: false},
+	}
+
+	for _, item := range data {
+		docs := types.NewDoc()
+		docs.NewAPI(&types.API{
+			Method:    "POST",
+			URL:       "/orders",
+			Summary:   "create order",
+			Group:     "orders",
+			RequestID: &types.RequestID{Header: "X-Request-ID", Mode: item.mode},
+			Request:   &types.Request{Headers: map[string]string{"X-Request-ID": "被覆盖"}},
+			Success:   &types.Response{Code: "201", Summary: "OK"},
+			Error:     &types.Response{Code: "400", Summary: "ERR", Headers: map[string]string{"X-Request-ID": "自定义"}},
+		})
+
+		buf := new(bytes.Buffer)
+		a.NotError(writeRAML(buf, docs, &Options{}))
+
+		raml := map[string]interface{}{}
+		a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+		annotations := raml["annotationTypes"].(map[interface{}]interface{})
+		a.Equal(annotations["requestID"], "string")
+
+		post := raml["/orders"].(map[interface{}]interface{})["post"].(map[interface{}]interface{})
+		req := post["headers"].(map[interface{}]interface{})["X-Request-ID"].(map[interface{}]interface{})
+		a.Equal(req["required"], item.request, item.mode).
+			Equal(req["(requestID)"], item.mode).
+			NotEqual(req["description"], "被覆盖")
+
+		responses := post["responses"].(map[interface{}]interface{})
+		success := responses[201].(map[interface{}]interface{})["headers"].(map[interface{}]interface{})["X-Request-ID"].(map[interface{}]interface{})
+		a.Equal(success["required"], item.response, item.mode).
+			Equal(success["(requestID)"], item.mode)
+
+		// @apiError 中的同名报头优先
+		e := responses[400].(map[interface{}]interface{})["headers"].(map[interface{}]interface{})["X-Request-ID"].(map[interface{}]interface{})
+		a.Equal(e["description"], "自定义").Nil(e["(requestID)"])
+	}
+}
+
+func TestWriteRAML_scopes(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.SecuritySchemes["token"] = &types.Security{Name: "token", Type: types.SecurityTypeAPIKey, In: "header", Key: "X-Token"}
+	docs.SecuritySchemes["oauth"] = &types.Security{Name: "oauth", Type: types.SecurityTypeOAuth2, URL: "https://example.com/token", Scopes: []string{"users:read", "users:write", "admin"}}
+	docs.NewAPI(&types.API{
+		Method:         "GET",
+		URL:            "/users",
+		Summary:        "list",
+		Group:          "users",
+		Auth:           []string{"oauth", "token"},
+		RequiredScopes: []string{"users:read", "admin"},
+		ScopeLogic:     types.ScopeLogicAny,
+	})
+	docs.NewAPI(&types.API{
+		Method:         "DELETE",
+		URL:            "/users",
+		Summary:        "delete",
+		Group:          "users",
+		Auth:           []string{"oauth"},
+		RequiredScopes: []string{"users:write", "admin"},
+		ScopeLogic:     types.ScopeLogicAll,
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	settings := raml["securitySchemes"].(map[interface{}]interface{})["oauth"].(map[interface{}]interface{})["settings"].(map[interface{}]interface{})
+	a.Equal(settings["scopes"], []interface{}{"users:read", "users:write", "admin"})
+	a.Equal(raml["annotationTypes"].(map[interface{}]interface{})["scopeLogic"], "string")
+
+	// 只有 oauth2 认证方式带上权限范围
+	users := raml["/users"].(map[interface{}]interface{})
+	get := users["get"].(map[interface{}]interface{})
+	a.Equal(get["securedBy"], []interface{}{
+		map[interface{}]interface{}{"oauth": map[interface{}]interface{}{"scopes": []interface{}{"users:read", "admin"}}},
+		"token",
+	}).Equal(get["(scopeLogic)"], "any")
+
+	// all 为默认值，不输出注解
+	del := users["delete"].(map[interface{}]interface{})
+	a.Equal(del["securedBy"], []interface{}{
+		map[interface{}]interface{}{"oauth": map[interface{}]interface{}{"scopes": []interface{}{"users:write", "admin"}}},
+	}).Nil(del["(scopeLogic)"])
+}
+
+func TestWriteRAML_codegen(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Summary: "user",
+		Group:   "users",
+		Codegen: map[string]map[string]string{
+			"java": {"methodName": "getUserById", "packageName": "com.example"},
+			"go":   {"methodName": "GetUser"},
+		},
+	})
+	docs.NewAPI(&types.API{
+		Method:  "DELETE",
+		URL:     "/users/{id}",
+		Summary: "delete",
+		Group:   "users",
+		Codegen: map[string]map[string]string{"python": {"methodName": "delete_user"}},
+	})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "users", Group: "users"})
+
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+	// 语言按名称排序
+	a.True(strings.Index(buf.String(), "(codegen-go)") < strings.Index(buf.String(), "(codegen-java)"))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(len(annotations), 3).
+		Equal(annotations["codegen-go"], "object").
+		Equal(annotations["codegen-java"], "object").
+		Equal(annotations["codegen-python"], "object")
+
+	user := raml["/users/{id}"].(map[interface{}]interface{})
+	get := user["get"].(map[interface{}]interface{})
+	a.Equal(get["(codegen-java)"], map[interface{}]interface{}{"methodName": "getUserById", "packageName": "com.example"}).
+		Equal(get["(codegen-go)"], map[interface{}]interface{}{"methodName": "GetUser"}).
+		Nil(get["(codegen-python)"])
+	del := user["delete"].(map[interface{}]interface{})
+	a.Equal(del["(codegen-python)"], map[interface{}]interface{}{"methodName": "delete_user"})
+
+	users := raml["/users"].(map[interface{}]interface{})["get"].(map[interface{}]interface{})
+	a.Nil(users["(codegen-go)"]).Nil(users["(codegen-java)"])
+}
+
+func TestWriteRAML_sdks(t *testing.T) {
+	a := assert.New(t)
+
+	docs := newRAMLDoc()
+	buf := new(bytes.Buffer)
+	a.NotError(writeRAML(buf, docs, &Options{}))
+	a.False(strings.Contains(buf.String(), "sdks"))
+
+	docs.SDKs = []*types.SDK{
+		{Language: "go", Package: "github.com/caixw/apidoc-go", URL: "https://github.com/caixw/apidoc-go"},
+		{Language: "javascript", Package: "apidoc-js", URL: "https://www.npmjs.com/package/apidoc-js"},
+	}
+	buf.Reset()
+	a.NotError(writeRAML(buf, docs, &Options{}))
+
+	raml := map[string]interface{}{}
+	a.NotError(yaml.Unmarshal(buf.Bytes(), &raml))
+	annotations := raml["annotationTypes"].(map[interface{}]interface{})
+	a.Equal(annotations["sdks"], "object[]")
+	a.Equal(raml["(sdks)"], []interface{}{
+		map[interface{}]interface{}{"language": "go", "package": "github.com/caixw/apidoc-go", "url": "https://github.com/caixw/apidoc-go"},
+		map[interface{}]interface{}{"language": "javascript", "package": "apidoc-js", "url": "https://www.npmjs.com/package/apidoc-js"},
+	})
+}
@@ -6,11 +6,11 @@ package output
 
 import (
 	"encoding/json"
-	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -29,6 +29,9 @@ type page struct {
 	Elapsed     time.Duration     `json:"elapsed"`
 	Groups      map[string]string `json:"groups"` // 组名与文件名的对应关系
 
+	SecuritySchemes map[string]*types.Security `json:"securitySchemes,omitempty"`
+	SDKs            []*types.SDK               `json:"sdks,omitempty"`
+
 	AppName    string `json:"appName"`
 	AppURL     string `json:"appURL"`
 	AppVersion string `json:"appVersion"`
@@ -42,9 +45,42 @@ type group struct {
 }
 
 func render(docs *types.Doc, opt *Options) error {
+	page, groups := buildPage(docs, opt)
+
+	if err := renderPage(page, opt.dataDir); err != nil {
+		return err
+	}
+
+	return renderGroups(groups, opt)
+}
+
+// 将 docs 转换成页面信息和分组的 API 列表，键名为小写的组名。
+func buildPage(docs *types.Doc, opt *Options) (*page, map[string]*group) {
 	groups := make(map[string]*group, 100)
 
+	basePath := docs.BasePath
+	if len(opt.BasePath) > 0 {
+		basePath = opt.BasePath
+	}
+
+	// 指定了 BaseURL 时，basePath 附加在 BaseURL 之后，否则作为每个 API 地址的前缀。
+	baseURL := docs.BaseURL
+	if len(baseURL) > 0 {
+		baseURL = joinPath(baseURL, basePath)
+		basePath = ""
+	}
+
 	for _, api := range docs.Apis {
+		// docs 可能同时被多个输出使用，不能直接修改其内容。
+		if len(basePath) > 0 || (api.TryIt == nil && len(opt.TryItBaseURL) > 0) {
+			a := *api
+			a.URL = joinPath(basePath, a.URL)
+			if a.TryIt == nil && len(opt.TryItBaseURL) > 0 {
+				a.TryIt = &types.TryIt{BaseURL: opt.TryItBaseURL}
+			}
+			api = &a
+		}
+
 		name := strings.ToLower(api.Group)
 		path := filepath.Join(opt.dataDir, vars.GroupFilePrefix+name+".json")
 
@@ -58,37 +94,143 @@ func render(docs *types.Doc, opt *Options) error {
 		groups[name].Apis = append(groups[name].Apis, api)
 	}
 
+	// 组文件相对于文档目录的路径
+	dataDir := vars.JSONDataDirName
+	if opt.Type == TypeJSON {
+		dataDir = ""
+	}
+
 	names := make(map[string]string, len(groups))
 	for name, group := range groups {
-		names[group.Name] = path.Join(vars.JSONDataDirName, vars.GroupFilePrefix+name+".json")
+		names[group.Name] = path.Join(dataDir, vars.GroupFilePrefix+name+".json")
+
+		sortAPIs(group.Apis)
+	}
 
-		// 排序
-		sort.SliceStable(group.Apis, func(i, j int) bool {
-			return group.Apis[i].URL < group.Apis[j].URL
-		})
+	date, elapsed := time.Now(), opt.Elapsed
+	if epoch, ok := sourceDateEpoch(); ok {
+		date, elapsed = epoch, 0
 	}
 
 	page := &page{
 		Title:       docs.Title,
 		Version:     docs.Version,
-		BaseURL:     docs.BaseURL,
+		BaseURL:     baseURL,
 		LicenseName: docs.LicenseName,
 		LicenseURL:  docs.LicenseURL,
 		Content:     docs.Content,
-		Date:        time.Now(),
-		Elapsed:     opt.Elapsed,
+		Date:        date,
+		Elapsed:     elapsed,
 		Groups:      names,
 
+		SecuritySchemes: docs.SecuritySchemes,
+		SDKs:            docs.SDKs,
+
 		AppName:    vars.Name,
 		AppURL:     vars.OfficialURL,
 		AppVersion: vars.Version(),
 	}
 
-	if err := renderPage(page, opt.dataDir); err != nil {
-		return err
+	return page, groups
+}
+
+// 环境变量 SOURCE_DATE_EPOCH 指定的时间，未指定或是格式不正确时返回 false。
+//
+// 指定该变量之后，生成的 JSON 内容中不再包含与生成时间相关的内容，
+// 相同的文档多次生成的内容完全相同，方便 git diff 和根据校验值进行缓存。
+// 参考 https://reproducible-builds.org/specs/source-date-epoch/
+func sourceDateEpoch() (time.Time, bool) {
+	val := os.Getenv(vars.SourceDateEpochEnv)
+	if len(val) == 0 {
+		return time.Time{}, false
 	}
 
-	return renderGroups(groups, opt)
+	sec, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(sec, 0).UTC(), true
+}
+
+// 对 apis 进行排序。
+//
+// 指定了 Order 的排在前面，按 Order 从小到大排列；
+// 未指定 Order 的排在后面，按地址和请求方法排列。
+func sortAPIs(apis []*types.API) {
+	sort.SliceStable(apis, func(i, j int) bool {
+		ai, aj := apis[i], apis[j]
+
+		switch {
+		case ai.Order > 0 && aj.Order > 0 && ai.Order != aj.Order:
+			return ai.Order < aj.Order
+		case ai.Order > 0 && aj.Order == 0:
+			return true
+		case ai.Order == 0 && aj.Order > 0:
+			return false
+		case ai.URL != aj.URL:
+			return ai.URL < aj.URL
+		default:
+			return ai.Method < aj.Method
+		}
+	})
+}
+
+// 连接两段路径，保证连接处只有一个 / 符号。
+func joinPath(base, p string) string {
+	if len(base) == 0 {
+		return p
+	}
+	if len(p) == 0 {
+		return base
+	}
+
+	return strings.TrimRight(base, "/") + "/" + strings.TrimLeft(p, "/")
+}
+
+// 将提示信息转换成 **Warning:** text 形式的 markdown 内容，每条信息占一个段落。
+// prefix 为每一行内容的前缀。
+func notesMarkdown(notes []*types.Note, prefix string) string {
+	lines := make([]string, 0, len(notes))
+	for _, note := range notes {
+		typ := note.Type
+		if len(typ) == 0 {
+			typ = types.NoteTypeInfo
+		}
+		text := "**" + strings.ToUpper(typ[:1]) + typ[1:] + ":** " + note.Text
+		lines = append(lines, prefix+strings.Replace(text, "\n", "\n"+prefix, -1))
+	}
+	return strings.Join(lines, "\n\n")
+}
+
+// 将外部资源转换成 - [label](url) 形式的 markdown 列表，未指定 label 的直接使用 url。
+func linksMarkdown(links []*types.Link) string {
+	lines := make([]string, 0, len(links))
+	for _, link := range links {
+		label := link.Label
+		if len(label) == 0 {
+			label = link.URL
+		}
+		lines = append(lines, "- ["+label+"]("+link.URL+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 将变更记录转换成 - **version** (date) description 形式的 markdown 列表。
+func changelogMarkdown(changelog []*types.ChangelogEntry) string {
+	lines := make([]string, 0, len(changelog))
+	for _, e := range changelog {
+		lines = append(lines, "- **"+e.Version+"** ("+e.Date+") "+e.Description)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 将 @apiRequest json 之类的简写转换成完整的 mimetype
+func mediaType(typ string) string {
+	typ = strings.TrimSpace(typ)
+	if strings.IndexByte(typ, '/') < 0 {
+		return "application/" + typ
+	}
+	return typ
 }
 
 func renderPage(p *page, destDir string) error {
@@ -111,11 +253,14 @@ func renderGroups(groups map[string]*group, o *Options) error {
 	return nil
 }
 
+// 将 obj 以 JSON 格式写入 path。
+//
+// encoding/json 总是按键名排序输出 map 的内容，所以相同的 obj 输出的内容也是相同的。
 func renderJSON(obj interface{}, path string) error {
 	data, err := json.MarshalIndent(obj, "", strings.Repeat(" ", vars.JSONIndent))
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(path, data, os.ModePerm)
+	return os.WriteFile(path, data, os.ModePerm)
 }
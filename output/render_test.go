@@ -0,0 +1,271 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func TestJoinPath(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(joinPath("", "/users"), "/users")
+	a.Equal(joinPath("/v1", ""), "/v1")
+	a.Equal(joinPath("/v1", "/users"), "/v1/users")
+	a.Equal(joinPath("/v1/", "/users"), "/v1/users")
+	a.Equal(joinPath("/v1", "users"), "/v1/users")
+	a.Equal(joinPath("/v1/", "users"), "/v1/users")
+	a.Equal(joinPath("https://api.caixw.io/", "/v1/"), "https://api.caixw.io/v1/")
+}
+
+func TestSortAPIs(t *testing.T) {
+	a := assert.New(t)
+
+	apis := []*types.API{
+		{Method: "GET", URL: "/users"},
+		{Method: "POST", URL: "/login", Order: 2},
+		{Method: "DELETE", URL: "/users"},
+		{Method: "GET", URL: "/admin"},
+		{Method: "GET", URL: "/users/{id}", Order: 1},
+		{Method: "DELETE", URL: "/login", Order: 2},
+	}
+	sortAPIs(apis)
+
+	ids := make([]string, 0, len(apis))
+	for _, api := range apis {
+		ids = append(ids, api.Method+" "+api.URL)
+	}
+	a.Equal(ids, []string{
+		"GET /users/{id}",
+		"DELETE /login", // Order 相同，按地址和请求方法排序
+		"POST /login",
+		"GET /admin", // 未指定 Order 的按地址和请求方法排序
+		"DELETE /users",
+		"GET /users",
+	})
+}
+
+func TestNotesMarkdown(t *testing.T) {
+	a := assert.New(t)
+
+	notes := []*types.Note{
+		{Type: types.NoteTypeInfo, Text: "info"},
+		{Type: types.NoteTypeWarning, Text: "line1\nline2"},
+		{Type: types.NoteTypeDanger, Text: "danger"},
+		{Type: types.NoteTypeTip, Text: "tip"},
+		{Text: "default"},
+	}
+
+	a.Equal(notesMarkdown(notes, ""), "**Info:** info\n\n**Warning:** line1\nline2\n\n**Danger:** danger\n\n**Tip:** tip\n\n**Info:** default")
+	a.Equal(notesMarkdown(notes[1:2], "> "), "> **Warning:** line1\n> line2")
+	a.Empty(notesMarkdown(nil, "> "))
+}
+
+func TestLinksMarkdown(t *testing.T) {
+	a := assert.New(t)
+
+	links := []*types.Link{
+		{URL: "https://tools.ietf.org/html/rfc7231", Label: "RFC 7231"},
+		{URL: "https://example.com/issues/1"},
+	}
+	a.Equal(linksMarkdown(links), "- [RFC 7231](https://tools.ietf.org/html/rfc7231)\n- [https://example.com/issues/1](https://example.com/issues/1)")
+	a.Empty(linksMarkdown(nil))
+}
+
+func TestChangelogMarkdown(t *testing.T) {
+	a := assert.New(t)
+
+	changelog := []*types.ChangelogEntry{
+		{Version: "1.1.0", Date: "2017-11-01", Description: "添加 email 字段"},
+		{Version: "1.0.0", Date: "2017-10-01", Description: "初始版本"},
+	}
+	a.Equal(changelogMarkdown(changelog), "- **1.1.0** (2017-11-01) 添加 email 字段\n- **1.0.0** (2017-10-01) 初始版本")
+	a.Empty(changelogMarkdown(nil))
+}
+
+func TestRender_basePath(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := types.NewDoc()
+	docs.BasePath = "/v1/"
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Group: "users"})
+
+	// 未指定 BaseURL，作为 API 地址的前缀
+	o := &Options{Type: TypeJSON, Dir: dir}
+	a.NotError(Render(docs, o))
+	p, g := loadRendered(a, dir)
+	a.Equal(p.BaseURL, "").
+		Equal(g.Apis[0].URL, "/v1/users").
+		Equal(docs.Apis[0].URL, "/users") // 不会修改原始数据
+
+	// Options.BasePath 覆盖文档中的值
+	o.BasePath = "/v2"
+	a.NotError(Render(docs, o))
+	_, g = loadRendered(a, dir)
+	a.Equal(g.Apis[0].URL, "/v2/users")
+
+	// 指定了 BaseURL，附加在 BaseURL 之后
+	docs.BaseURL = "https://api.caixw.io/"
+	a.NotError(Render(docs, o))
+	p, g = loadRendered(a, dir)
+	a.Equal(p.BaseURL, "https://api.caixw.io/v2").
+		Equal(g.Apis[0].URL, "/users")
+}
+
+func TestRender_tryIt(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Group: "users"})
+	docs.NewAPI(&types.API{
+		Method: "POST",
+		URL:    "/users",
+		Group:  "users",
+		TryIt:  &types.TryIt{BaseURL: "https://api.caixw.io", Token: "abc"},
+	})
+
+	// 未指定 @apiTryIt 的不输出在线调试
+	o := &Options{Type: TypeJSON, Dir: dir}
+	a.NotError(Render(docs, o))
+	_, g := loadRendered(a, dir)
+	a.Nil(g.Apis[0].TryIt).
+		Equal(g.Apis[1].TryIt, &types.TryIt{BaseURL: "https://api.caixw.io", Token: "abc"})
+
+	// TryItBaseURL 不会覆盖 @apiTryIt 的值
+	o.TryItBaseURL = "http://localhost:8080"
+	a.NotError(Render(docs, o))
+	_, g = loadRendered(a, dir)
+	a.Equal(g.Apis[0].TryIt, &types.TryIt{BaseURL: "http://localhost:8080"}).
+		Equal(g.Apis[1].TryIt.BaseURL, "https://api.caixw.io").
+		Nil(docs.Apis[0].TryIt) // 不会修改原始数据
+
+	// html 的模板中包含在线调试的面板
+	o.Type = TypeHTML
+	a.NotError(Render(docs, o))
+	data, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	a.NotError(err)
+	a.True(bytes.Contains(data, []byte(`{{#if tryIt}}`))).
+		True(bytes.Contains(data, []byte(`<span class="badge todo"`)))
+	_, g = loadRendered(a, filepath.Join(dir, vars.JSONDataDirName))
+	a.Equal(g.Apis[0].TryIt.BaseURL, "http://localhost:8080")
+}
+
+// 加载 dir 目录下的 page.json 和 group_users.json
+func loadRendered(a *assert.Assertion, dir string) (*page, *group) {
+	p := &page{}
+	data, err := os.ReadFile(filepath.Join(dir, vars.PageFileName+".json"))
+	a.NotError(err).NotError(json.Unmarshal(data, p))
+
+	g := &group{}
+	data, err = os.ReadFile(filepath.Join(dir, vars.GroupFilePrefix+"users.json"))
+	a.NotError(err).NotError(json.Unmarshal(data, g))
+
+	return p, g
+}
+
+// 包含所有 map 类型字段的文档
+func newDeterministicDoc() *types.Doc {
+	docs := types.NewDoc()
+	docs.Title = "test"
+	docs.SecuritySchemes["token"] = &types.Security{Name: "token", Type: types.SecurityTypeAPIKey, In: "header", Key: "X-Token"}
+	docs.SecuritySchemes["basic"] = &types.Security{Name: "basic", Type: types.SecurityTypeHTTP, Scheme: "basic"}
+	docs.SecuritySchemes["oauth"] = &types.Security{Name: "oauth", Type: types.SecurityTypeOAuth2, URL: "https://example.com/token"}
+
+	docs.NewAPI(&types.API{
+		Method:  "POST",
+		URL:     "/users",
+		Summary: "create",
+		Group:   "users",
+		Formats: map[string]string{"id": "uuid", "email": "email", "birthday": "date"},
+		Request: &types.Request{
+			Type:          "json",
+			Headers:       map[string]string{"X-Trace": "trace", "Authorization": "token", "Accept": "json"},
+			Discriminator: &types.Discriminator{Property: "type", Mapping: map[string]string{"vip": "VIPUser", "admin": "Admin", "guest": "Guest"}},
+		},
+		Success: &types.Response{
+			Code:    "201",
+			Summary: "created",
+			Headers: map[string]string{"Location": "url", "ETag": "etag", "Cache-Control": "no-cache"},
+		},
+		ResponseHeaders: map[string][]*types.ResponseHeader{
+			"429": {{Name: "Retry-After", Type: "int", Summary: "retry"}},
+			"*":   {{Name: "X-Request-ID", Type: "string", Summary: "id"}},
+			"201": {{Name: "Location", Type: "string", Summary: "url"}},
+		},
+		ContentTypes: map[string][]string{"400": {"text/plain"}, "201": {"application/json"}, "500": {"text/html"}},
+		Extensions:   map[string]json.RawMessage{"x-rate": json.RawMessage(`{"limit":10}`), "x-beta": json.RawMessage("true"), "x-owner": json.RawMessage(`"team"`)},
+	})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "list", Group: "users"})
+
+	return docs
+}
+
+func TestRender_deterministic(t *testing.T) {
+	a := assert.New(t)
+
+	a.NotError(os.Setenv(vars.SourceDateEpochEnv, "1500000000"))
+	defer os.Unsetenv(vars.SourceDateEpochEnv)
+
+	render := func() (page, group []byte) {
+		dir, err := os.MkdirTemp("", "apidoc")
+		a.NotError(err)
+		defer os.RemoveAll(dir)
+
+		a.NotError(Render(newDeterministicDoc(), &Options{Type: TypeJSON, Dir: dir, Elapsed: time.Second}))
+
+		page, err = os.ReadFile(filepath.Join(dir, vars.PageFileName+".json"))
+		a.NotError(err)
+		group, err = os.ReadFile(filepath.Join(dir, vars.GroupFilePrefix+"users.json"))
+		a.NotError(err)
+		return page, group
+	}
+
+	page1, group1 := render()
+	page2, group2 := render()
+	a.Equal(page1, page2).Equal(group1, group2)
+
+	p := &page{}
+	a.NotError(json.Unmarshal(page1, p))
+	a.Equal(p.Date, time.Unix(1500000000, 0).UTC()).Equal(p.Elapsed, 0)
+
+	golden, err := os.ReadFile("./testdata/group_users.json")
+	a.NotError(err)
+	a.Equal(string(group1), string(golden))
+}
+
+func TestSourceDateEpoch(t *testing.T) {
+	a := assert.New(t)
+	defer os.Unsetenv(vars.SourceDateEpochEnv)
+
+	a.NotError(os.Unsetenv(vars.SourceDateEpochEnv))
+	_, ok := sourceDateEpoch()
+	a.False(ok)
+
+	a.NotError(os.Setenv(vars.SourceDateEpochEnv, "not-a-number"))
+	_, ok = sourceDateEpoch()
+	a.False(ok)
+
+	a.NotError(os.Setenv(vars.SourceDateEpochEnv, "0"))
+	date, ok := sourceDateEpoch()
+	a.True(ok).Equal(date, time.Unix(0, 0).UTC())
+}
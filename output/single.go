@@ -0,0 +1,570 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/caixw/apidoc/output/static"
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 单页面的模板。
+//
+// 与 html 输出不同，所有内容都在生成时渲染好，不依赖 CDN 上的 jQuery 和 Handlebars，
+// 样式和脚本均直接内嵌在页面中。目前的样式和脚本中没有字体和图片等二进制内容，
+// 若以后有添加，需要以 base64 的 data URI 的形式嵌入。
+const singlePageTemplate = `<!DOCTYPE html>
+<html lang="zh-cmn-Hans">
+    <head>
+        <meta charset="utf-8" />
+        <meta name="viewport" content="width=device-width, initial-scale=1" />
+        <title>{{.Page.Title}} | {{.Page.AppName}}</title>
+        <style>{{.Style}}
+main .group{display:none}
+main .group.active{display:block}
+        </style>
+    </head>
+    <body>
+        <aside>
+            <header><h1>{{.Page.Title}}</h1></header>
+
+            <menu>
+                <ul class="menu">
+                    <li class="menu-item content"><a href="#">home</a></li>
+                    {{range .Groups}}
+                    <li class="menu-item api"><a href="#{{.ID}}">{{.Name}}</a></li>
+                    {{end}}
+                </ul>
+            </menu>
+
+            <footer>
+                <p>内容由<a href="{{.Page.AppURL}}">{{.Page.AppName}}</a>编译于 <time>{{date .Page.Date}}</time>，用时{{elapsed .Page.Elapsed}}。</p>
+                {{if .Page.LicenseName}}
+                <p>内容采用<a href="{{.Page.LicenseURL}}">{{.Page.LicenseName}}</a>进行许可。</p>
+                {{end}}
+            </footer>
+        </aside>
+
+        <main id="main">
+            <div class="group active" id="content">{{.Content}}
+                {{if .Page.SDKs}}
+                <div class="sdks">
+                    <h4>官方 SDK</h4>
+                    <ul class="sdk-grid">
+                        {{range .Page.SDKs}}
+                        <li><span class="sdk-icon" data-lang="{{.Language}}">{{.Language}}</span><a href="{{.URL}}">{{.Package}}</a></li>
+                        {{end}}
+                    </ul>
+                </div>
+                {{end}}
+            </div>
+
+            {{range .Groups}}
+            <div class="group" id="{{.ID}}">
+                <h2>{{.Name}}</h2>
+                {{range $api := .Apis}}
+                <section class="api">
+                    <h3>
+                        <span class="method {{lower .Method}}">{{.Method}}</span>
+                        <span class="url">{{.URL}}</span>
+                        {{if .GRPCMethod}}<span class="grpc" title="gRPC">{{.GRPCMethod}}</span>{{end}}
+                        <span class="summary">{{.Summary}}</span>
+                        {{if .Safe}}<span class="badge">safe</span>{{end}}
+                        {{if .Idempotent}}<span class="badge">idempotent</span>{{end}}
+                        {{if .SSE}}<span class="badge">sse</span>{{end}}
+                        {{if .WebSocket}}<span class="badge">websocket</span>{{end}}
+                        {{with .Timeout}}<span class="badge timeout" title="预期的响应时间">{{.Percentile}} &le; {{.Value}}ms</span>{{end}}
+                        {{if .DataClassification}}<span class="badge classification {{.DataClassification}}" title="数据分级">{{.DataClassification}}</span>{{end}}
+                        {{if .AccessRoles}}<span class="badge access" title="{{.AccessSummary}}">需要角色：{{join .AccessRoles ","}}</span>{{end}}
+                        {{if .MaxRequestBodyBytes}}<span class="badge content-length" title="请求内容最多 {{.MaxRequestBodyBytes}} 字节">请求大小限制：{{byteSize .MaxRequestBodyBytes}}</span>{{end}}
+                        {{if .RequiredScopes}}<span class="badge scope">权限范围（{{.ScopeLogic}}）：{{join .RequiredScopes ","}}</span>{{end}}
+                        {{range .Links}}<a class="badge link" href="{{.URL}}" target="_blank">{{if .Label}}{{.Label}}{{else}}{{.URL}}{{end}}</a>{{end}}
+                        {{with .GraphQL}}<span class="badge graphql" title="GraphQL {{.Type}}">GraphQL: {{.Operation}}</span>{{end}}
+                        {{range .Contracts}}<a class="badge contract" href="{{.URL}}" target="_blank" title="{{.Suite}}">Contract Tests</a>{{end}}
+                        {{if .Todos}}<span class="badge todo" title="{{range .Todos}}{{.}}&#10;{{end}}">TODO</span>{{end}}
+                    </h3>
+
+                    <div class="content">
+                        {{range .BreakingChanges}}<div class="breaking-change"><strong>Breaking Change in v{{.Version}}</strong>{{.Description}}</div>{{end}}
+                        {{if .Description}}<p class="description">{{.Description}}</p>{{end}}
+                        {{range .Notes}}<div class="note note-{{.Type}}">{{.Text}}</div>{{end}}
+                        {{range .Environments}}<div class="note note-environment"><span class="environment">{{.Environment}}</span>{{.Text}}</div>{{end}}
+                        {{with .FeatureFlag}}<div class="note note-feature-flag"><span class="feature-flag">Feature Flag: {{.Name}}</span>该接口受功能开关控制，可能尚未对所有用户开放{{if .Provider}}（{{.Provider}}）{{end}}</div>{{end}}
+                        {{with .Tenant}}<div class="note note-tenant"><span class="tenant">Multi-Tenant</span>{{tenantNote .}}</div>{{end}}
+                        {{with .Async}}<div class="note note-async"><span class="async">Async</span>该接口异步处理请求，返回 202 和任务 ID{{if .PollURL}}，可以通过 {{.PollURL}} 查询处理状态{{end}}{{if .CallbackHeader}}，可以通过报头 {{.CallbackHeader}} 指定接收处理结果的地址{{end}}</div>{{end}}
+                        {{with .CircuitBreaker}}<div class="note note-resilience"><span class="resilience">Resilience</span>建议客户端启用熔断{{if .Threshold}}，错误率达到 {{.Threshold}}% 时熔断{{end}}{{if .Timeout}}，{{.Timeout}} 后进入半开状态{{end}}{{if .HalfOpenRequests}}，半开状态下允许 {{.HalfOpenRequests}} 个请求通过{{end}}</div>{{end}}
+                        {{with .SLA}}<div class="note note-sla"><span class="sla">SLA</span>可用性 {{.Availability}}%{{if .RPO}}，RPO {{.RPO}}{{end}}{{if .RTO}}，RTO {{.RTO}}{{end}}</div>{{end}}
+
+                        {{if .Queries}}<h5>查询参数</h5>{{template "params" .Queries}}{{end}}
+                        {{if .Params}}<h5>参数</h5>{{template "params" .Params}}{{end}}
+
+                        {{with .Request}}
+                        <div class="request">
+                            <h4>请求{{if .Type}}:&#160;{{.Type}}{{end}}</h4>
+                            {{if .Headers}}<h5>报头:</h5>{{template "headers" .Headers}}{{end}}
+                            {{if .Params}}<h5>参数:</h5>{{template "params" .Params}}{{end}}
+                            {{if .Examples}}<h5>示例:</h5>{{template "examples" .Examples}}{{end}}
+                        </div>
+                        {{end}}
+
+                        {{with .Success}}
+                        <div class="response success">
+                            <h4><span class="success">SUCCESS:</span>{{.Code}},&#160;{{.Summary}}{{with $api.MediaType}}<span class="media-type" title="{{if .Extension}}.{{.Extension}}{{else}}{{.Type}}{{end}}">&#x2913;&#160;{{.Type}}</span>{{end}}</h4>
+                            {{template "response" .}}
+                        </div>
+                        {{end}}
+
+                        {{with .Error}}
+                        <div class="response error">
+                            <h4><span class="error">ERROR:</span>{{.Code}},&#160;{{.Summary}}</h4>
+                            {{template "response" .}}
+                        </div>
+                        {{end}}
+
+                        {{if .ResponseHeaders}}
+                        <div class="response-headers">
+                            <h4>返回报头</h4>
+                            {{range $code, $headers := .ResponseHeaders}}
+                            <h5>{{$code}}:</h5>
+                            <table class="params">
+                                <thead><tr><th>名称</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range $headers}}<tr><th>{{.Name}}</th><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                            {{end}}
+                        </div>
+                        {{end}}
+
+                        {{with .TimeBudget}}
+                        <div class="time-budget">
+                            <h4>响应时间预算：{{.Total}}ms</h4>
+                            {{budgetChart .}}
+                        </div>
+                        {{end}}
+
+                        {{with .CachePolicy}}
+                        <div class="cache-policy">
+                            <h4>缓存策略</h4>
+                            <table>
+                                <tbody>
+                                    <tr><th>Cache-Control</th><td>{{.String}}</td></tr>
+                                    {{if .Vary}}<tr><th>Vary</th><td>{{range .Vary}}{{.}} {{end}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{with .CORSPolicy}}
+                        <div class="cors-policy">
+                            <h4>跨域策略</h4>
+                            <table>
+                                <tbody>
+                                    {{if .Origin}}<tr><th>Access-Control-Allow-Origin</th><td>{{.Origin}}</td></tr>{{end}}
+                                    {{if .Methods}}<tr><th>Access-Control-Allow-Methods</th><td>{{join .Methods ", "}}</td></tr>{{end}}
+                                    {{if .Headers}}<tr><th>Access-Control-Allow-Headers</th><td>{{join .Headers ", "}}</td></tr>{{end}}
+                                    {{if .MaxAge}}<tr><th>Access-Control-Max-Age</th><td>{{.MaxAge}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{with .Retry}}
+                        <div class="retry">
+                            <h4>重试策略</h4>
+                            <table>
+                                <tbody>
+                                    <tr><th>策略</th><td>{{.Strategy}}</td></tr>
+                                    {{if .MaxAttempts}}<tr><th>最大尝试次数</th><td>{{.MaxAttempts}}</td></tr>{{end}}
+                                    {{if .Backoff}}<tr><th>退避方式</th><td>{{.Backoff}}</td></tr>{{end}}
+                                    {{if .RetryOn}}<tr><th>重试的状态码</th><td>{{range .RetryOn}}{{.}} {{end}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .Metrics}}
+                        <div class="metrics">
+                            <h4>服务指标</h4>
+                            <table>
+                                <thead><tr><th>名称</th><th>值</th><th>单位</th></tr></thead>
+                                <tbody>
+                                {{range .Metrics}}<tr><th>{{.Name}}</th><td>{{.Value}}</td><td>{{.Unit}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .Events}}
+                        <div class="events">
+                            <h4>事件</h4>
+                            <table>
+                                <thead><tr><th>名称</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .Events}}<tr><th>{{.Name}}</th><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .WSSend}}
+                        <div class="ws-send">
+                            <h4>服务端发送的消息</h4>
+                            <table>
+                                <thead><tr><th>名称</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .WSSend}}<tr><th>{{.Name}}</th><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .WSReceive}}
+                        <div class="ws-receive">
+                            <h4>服务端接收的消息</h4>
+                            <table>
+                                <thead><tr><th>名称</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .WSReceive}}<tr><th>{{.Name}}</th><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .SocketIO}}
+                        <div class="socket-io">
+                            <h4>Socket.IO 事件</h4>
+                            {{range .SocketIO}}
+                            <h5>{{.Namespace}}</h5>
+                            <table>
+                                <thead><tr><th>事件</th><th>方向</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .Events}}<tr><th>{{.Event}}</th><td><span class="socket-io-direction {{.Direction}}">{{.Direction}}</span></td><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                            {{end}}
+                        </div>
+                        {{end}}
+
+                        {{if .ProducedEvents}}
+                        <div class="domain-events">
+                            <h4>产生的领域事件</h4>
+                            {{template "events" .ProducedEvents}}
+                        </div>
+                        {{end}}
+
+                        {{if .ConsumedEvents}}
+                        <div class="domain-events">
+                            <h4>消费的领域事件</h4>
+                            {{template "events" .ConsumedEvents}}
+                        </div>
+                        {{end}}
+
+                        {{if .Changelog}}
+                        <details class="changelog">
+                            <summary>变更记录</summary>
+                            <table>
+                                <thead><tr><th>版本</th><th>日期</th><th>变更内容</th></tr></thead>
+                                <tbody>
+                                {{range .Changelog}}<tr><th>{{.Version}}</th><td>{{.Date}}</td><td>{{.Description}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </details>
+                        {{end}}
+
+                        {{if .AuthErrors}}
+                        <div class="auth-errors">
+                            <h4>认证失败</h4>
+                            <table>
+                                <thead><tr><th>状态码</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .AuthErrors}}<tr><th>{{.Code}}</th><td>{{.Schema}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .Conflicts}}
+                        <div class="conflicts">
+                            <h4>409 冲突</h4>
+                            <table>
+                                <thead><tr><th>名称</th><th>类型</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .Conflicts}}<tr><th>{{.Condition}}</th><td>{{.Schema}}</td><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .ErrorCodes}}
+                        <div class="error-codes">
+                            <h4>错误代码</h4>
+                            <table>
+                                <thead><tr><th>代码</th><th>描述</th></tr></thead>
+                                <tbody>
+                                {{range .ErrorCodes}}<tr><th>{{.Code}}</th><td>{{.Summary}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{with .Region}}
+                        <div class="regions">
+                            <h4>区域可用性</h4>
+                            <table>
+                                <thead><tr><th>区域</th><th>状态</th></tr></thead>
+                                <tbody>
+                                {{range .Available}}<tr><th>{{.}}</th><td class="region-available">可用</td></tr>{{end}}
+                                {{range .Unavailable}}<tr><th>{{.}}</th><td class="region-unavailable">不可用</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if or .Throttle .Quota}}
+                        <div class="usage-limits">
+                            <h4>使用限制</h4>
+                            <table>
+                                <thead><tr><th>类型</th><th>次数</th><th>周期</th><th>范围</th></tr></thead>
+                                <tbody>
+                                {{with .Throttle}}<tr><th>频率限制</th><td>{{.Requests}}</td><td>{{.Window}}</td><td></td></tr>{{end}}
+                                {{with .Quota}}<tr><th>配额</th><td>{{.Limit}}</td><td>{{.Window}}</td><td>{{.Scope}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+
+                        {{if .Callbacks}}
+                        <div class="callbacks">
+                            <h4>回调</h4>
+                            <table>
+                                <thead><tr><th>名称</th><th>方法</th><th>地址</th></tr></thead>
+                                <tbody>
+                                {{range .Callbacks}}<tr><th>{{.Name}}</th><td>{{.Method}}</td><td>{{.Expression}}{{.Path}}</td></tr>{{end}}
+                                </tbody>
+                            </table>
+                        </div>
+                        {{end}}
+                    </div>
+
+                    {{with .Owner}}
+                    <footer class="owner">
+                        负责团队：{{.Team}}{{if .Email}}&#160;<a href="mailto:{{.Email}}">{{.Email}}</a>{{end}}
+                    </footer>
+                    {{end}}
+                </section>
+                {{end}}
+            </div>
+            {{end}}
+        </main>
+
+        <script>
+        function showGroup() {
+            var id = location.hash.substr(1) || 'content';
+            var groups = document.querySelectorAll('main .group');
+            for (var i = 0; i < groups.length; i++) {
+                groups[i].className = groups[i].id == id ? 'group active' : 'group';
+            }
+        }
+        window.addEventListener('hashchange', showGroup);
+        showGroup();
+        </script>
+    </body>
+</html>
+
+{{define "params"}}
+<table class="params">
+    <thead><tr><th>名称</th><th>类型</th><th>描述</th></tr></thead>
+    <tbody>
+    {{range .}}<tr><th>{{.Name}}</th><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+    </tbody>
+</table>
+{{end}}
+
+{{define "events"}}
+<table>
+    <thead><tr><th>名称</th><th>结构</th><th>描述</th></tr></thead>
+    <tbody>
+    {{range .}}<tr><th>{{.Name}}</th><td>{{.Type}}</td><td>{{.Summary}}</td></tr>{{end}}
+    </tbody>
+</table>
+{{end}}
+
+{{define "headers"}}
+<table>
+    <thead><tr><th>名称</th><th>描述</th></tr></thead>
+    <tbody>
+    {{range $key, $val := .}}<tr><th>{{$key}}</th><td>{{$val}}</td></tr>{{end}}
+    </tbody>
+</table>
+{{end}}
+
+{{define "examples"}}
+{{range .}}<pre><code class="language-{{.Type}}">{{.Code}}</code></pre>{{end}}
+{{end}}
+
+{{define "response"}}
+{{if .Headers}}<h5>报头</h5>{{template "headers" .Headers}}{{end}}
+{{if .Params}}<h5>参数:</h5>{{template "params" .Params}}{{end}}
+{{if .Examples}}<h5>示例:</h5>{{template "examples" .Examples}}{{end}}
+{{end}}
+`
+
+// 单页面模板所需要的数据
+type singlePage struct {
+	Page    *page
+	Style   template.CSS
+	Content template.HTML
+	Groups  []*singlePageGroup
+}
+
+type singlePageGroup struct {
+	*group
+	ID string // 在页面中的锚点
+}
+
+// 将 docs 以单个 html 文件的形式输出到 o.Dir 目录下。
+func renderSinglePage(docs *types.Doc, o *Options) error {
+	return WriteSinglePageHTML(docs, o, filepath.Join(o.Dir, vars.SinglePageFileName))
+}
+
+// WriteSinglePageHTML 将 docs 输出到 path 指定的单个 html 文件中。
+//
+// 与 html 类型的输出不同，生成的文件不依赖网络和其它文件，
+// 可以直接通过 file:// 访问，方便分享。o 中的 Type 和 Dir 会被忽略。
+func WriteSinglePageHTML(docs *types.Doc, o *Options, path string) error {
+	tpl, err := template.New("single").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+		"date": func(t time.Time) string {
+			return t.Format("2006-01-02 15:04:05")
+		},
+		"elapsed": func(d time.Duration) string {
+			return d.String()
+		},
+		"budgetChart": budgetChart,
+		"tenantNote":  tenantNote,
+		"byteSize":    byteSize,
+	}).Parse(singlePageTemplate)
+	if err != nil {
+		return err
+	}
+
+	style, _ := static.Asset("./style.css")
+
+	p, groups := buildPage(docs, o)
+	data := &singlePage{
+		Page:    p,
+		Style:   template.CSS(style),
+		Content: template.HTML(p.Content),
+		Groups:  make([]*singlePageGroup, 0, len(groups)),
+	}
+	for name, g := range groups {
+		if o.groupIsEnable(g.Name) {
+			data.Groups = append(data.Groups, &singlePageGroup{group: g, ID: "group-" + name})
+		}
+	}
+	sort.Slice(data.Groups, func(i, j int) bool {
+		return data.Groups[i].Name < data.Groups[j].Name
+	})
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tpl.Execute(file, data)
+}
+
+// 以文字的形式描述 @apiTenant 的隔离和区分方式。
+//
+// 需要与 static/app.js 中的 tenantNote 保持一致。
+func tenantNote(t *types.TenantPolicy) string {
+	note := "该接口区分租户"
+
+	switch t.Isolation {
+	case types.TenantIsolationShared:
+		note += "，所有租户共享资源"
+	case types.TenantIsolationDedicated:
+		note += "，每个租户使用独立的资源"
+	}
+
+	switch t.Scoping {
+	case types.TenantScopingHeader:
+		note += "，通过报头 " + t.Header + " 指定租户"
+	case types.TenantScopingPath:
+		note += "，通过请求路径指定租户"
+	case types.TenantScopingSubdomain:
+		note += "，通过子域名指定租户"
+	}
+
+	return note
+}
+
+// 字节数的单位，相邻的单位之间相差 1024 倍
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB"}
+
+// 将字节数转换成便于阅读的形式，最多保留两位小数，比如 1572864 转换成 1.5MB。
+//
+// 需要与 static/app.js 中的 byteSize 保持一致。
+func byteSize(n int64) string {
+	size := float64(n)
+	i := 0
+	for ; size >= 1024 && i < len(byteUnits)-1; i++ {
+		size /= 1024
+	}
+	return strconv.FormatFloat(math.Round(size*100)/100, 'f', -1, 64) + byteUnits[i]
+}
+
+// 时间预算饼图中各上游服务的颜色，超出数量时循环使用。
+//
+// 需要与 static/app.js 中的 budgetColors 保持一致。
+var budgetColors = []string{"#2185d0", "#21ba45", "#f2711c", "#a333c8", "#00b5ad", "#e03997", "#fbbd08"}
+
+// 以内联 SVG 饼图的形式显示 @apiTimeBudget 在各上游服务之间的分配，
+// 未分配的部分为服务本身的处理时间，以灰色的底色显示。
+//
+// 需要与 static/app.js 中的 budgetChart 保持一致。
+func budgetChart(b *types.TimeBudget) template.HTML {
+	total := b.Total
+	if allocated := b.Allocated(); allocated > total {
+		total = allocated
+	}
+
+	svg := new(strings.Builder)
+	legend := new(strings.Builder)
+	svg.WriteString(`<svg class="budget-chart" viewBox="0 0 32 32" width="120" height="120"><circle r="16" cx="16" cy="16" fill="#ddd"/>`)
+	legend.WriteString(`<ul class="budget-legend">`)
+
+	offset := 0.0
+	for i, u := range b.Upstreams {
+		color := budgetColors[i%len(budgetColors)]
+		name := template.HTMLEscapeString(u.Service)
+		percent := float64(u.Value) * 100 / float64(total)
+
+		fmt.Fprintf(svg, `<circle r="8" cx="16" cy="16" fill="none" stroke="%s" stroke-width="16" pathLength="100" stroke-dasharray="%.2f 100" stroke-dashoffset="%.2f" transform="rotate(-90 16 16)"><title>%s</title></circle>`,
+			color, percent, -offset, name)
+		fmt.Fprintf(legend, `<li><span class="swatch" style="background:%s"></span>%s：%dms</li>`, color, name, u.Value)
+		offset += percent
+	}
+	if rest := b.Total - b.Allocated(); rest > 0 {
+		fmt.Fprintf(legend, `<li><span class="swatch" style="background:#ddd"></span>本服务：%dms</li>`, rest)
+	}
+
+	svg.WriteString("</svg>")
+	legend.WriteString("</ul>")
+	return template.HTML(svg.String() + legend.String())
+}
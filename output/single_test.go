@@ -0,0 +1,228 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+func TestWriteSinglePageHTML(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := types.NewDoc()
+	docs.Title = "test"
+	docs.Content = "<p>content</p>"
+	docs.SDKs = []*types.SDK{
+		{Language: "go", Package: "github.com/caixw/apidoc-go", URL: "https://github.com/caixw/apidoc-go"},
+		{Language: "javascript", Package: "apidoc-js", URL: "https://www.npmjs.com/package/apidoc-js"},
+	}
+	docs.NewAPI(&types.API{
+		Method:              "GET",
+		URL:                 "/users/{id}",
+		Summary:             "get user",
+		Group:               "users",
+		Description:         "<script>alert(1)</script>",
+		Params:              []*types.Param{{Name: "id", Type: "int", Summary: "user id"}},
+		Success:             &types.Response{Code: "200", Summary: "OK"},
+		Todos:               []string{"补充返回值"},
+		Links:               []*types.Link{{URL: "https://example.com/issues/1", Label: "需求"}},
+		Contracts:           []*types.Contract{{Suite: "users", URL: "https://pact.example.com/users"}},
+		Metrics:             []*types.Metric{{Name: "p99-latency", Value: 200.5, Unit: "ms"}},
+		Owner:               &types.Owner{Team: "Platform Engineering", Email: "platform@example.com"},
+		Environments:        []*types.EnvironmentNote{{Environment: types.EnvironmentStaging, Text: "不限制请求次数"}},
+		AccessRoles:         []string{"admin", "editor"},
+		AccessSummary:       "只读用户无法访问",
+		Timeout:             &types.Timeout{Value: 300, Percentile: types.PercentileP90},
+		TimeBudget:          &types.TimeBudget{Total: 300, Upstreams: []*types.UpstreamBudget{{Service: "db", Value: 150}}},
+		SLA:                 &types.SLA{Availability: 99.9, RPO: "5m", RTO: "30m"},
+		CircuitBreaker:      &types.CircuitBreaker{Threshold: 50, HalfOpenRequests: 3},
+		DataClassification:  types.DataClassificationPII,
+		Region:              &types.RegionPolicy{Available: []string{"cn-north-1"}, Unavailable: []string{"eu-west-1"}},
+		Throttle:            &types.Throttle{Requests: 10, Window: types.ThrottleWindowSecond},
+		Quota:               &types.Quota{Limit: 100000, Window: types.QuotaWindowDay, Scope: types.QuotaScopeOrg},
+		Async:               &types.Async{PollURL: "/jobs/{jobId}", CallbackHeader: "X-Callback-URL"},
+		MaxRequestBodyBytes: 1572864,
+		Tenant:              &types.TenantPolicy{Isolation: types.TenantIsolationDedicated, Scoping: types.TenantScopingSubdomain},
+		RequiredScopes:      []string{"users:read", "admin"},
+		ScopeLogic:          types.ScopeLogicAny,
+		FeatureFlag:         &types.FeatureFlag{Name: "users-v2", Provider: types.FeatureFlagStatsig},
+		AuthErrors:          []*types.AuthError{{Code: "401", Schema: "AuthError", Summary: "未登录"}},
+		Conflicts:           []*types.Conflict{{Condition: "version-mismatch", Schema: "ConflictError", Summary: "数据已经被修改"}},
+		MediaType:           &types.MediaType{Type: "image/png", Extension: "png"},
+		CORSPolicy:          &types.CORSPolicy{Origin: "https://example.com", Methods: []string{"GET", "POST"}},
+		BreakingChanges: []*types.BreakingChange{
+			{Version: "2.0.0", Description: "删除了 email 字段"},
+		},
+		GRPCMethod: "users.UserService.GetUser",
+		GraphQL:    &types.GraphQL{Operation: "user", Type: types.GraphQLQuery},
+		SSE:        true,
+		Changelog:  []*types.ChangelogEntry{{Version: "1.0.0", Date: "2017-10-01", Description: "初始版本"}},
+		Events:     []*types.Event{{Name: "updated", Type: "object", Summary: "用户信息已更新"}},
+	})
+	docs.NewAPI(&types.API{
+		Method:    "GET",
+		URL:       "/users/chat",
+		Summary:   "chat",
+		Group:     "users",
+		WebSocket: true,
+		WSSend:    []*types.Event{{Name: "message", Type: "object", Summary: "新的聊天消息"}},
+		WSReceive: []*types.Event{{Name: "send", Type: "object"}},
+		SocketIO: []*types.SocketIONamespace{{
+			Namespace: "/chat",
+			Events: []*types.SocketIOEvent{
+				{Event: "typing", Direction: types.SocketIOEmit, Type: "string", Summary: "对方正在输入"},
+				{Event: "typing", Direction: types.SocketIOOn, Type: "string", Summary: "正在输入"},
+			},
+		}},
+	})
+	docs.NewAPI(&types.API{
+		Method:         "POST",
+		URL:            "/users",
+		Summary:        "create user",
+		Group:          "users",
+		ProducedEvents: []*types.Event{{Name: "UserCreated", Type: "User", Summary: "用户已创建"}},
+	})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/admin", Summary: "admin", Group: "admin"})
+
+	path := filepath.Join(dir, "index.html")
+	a.NotError(WriteSinglePageHTML(docs, &Options{Groups: []string{"users"}}, path))
+	data, err := os.ReadFile(path)
+	a.NotError(err)
+	html := string(data)
+
+	// 不引用任何外部的样式和脚本
+	a.False(regexp.MustCompile(`<link[^>]*rel="stylesheet"`).MatchString(html))
+	a.False(regexp.MustCompile(`<script[^>]*src=`).MatchString(html))
+	a.True(strings.Contains(html, "<style>")).
+		True(strings.Contains(html, "aside menu")) // style.css 的内容
+
+	a.True(strings.Contains(html, "<p>content</p>")).
+		True(strings.Contains(html, `id="group-users"`)).
+		True(strings.Contains(html, "/users/{id}")).
+		True(strings.Contains(html, "user id")).
+		True(strings.Contains(html, "&lt;script&gt;alert(1)&lt;/script&gt;")).
+		True(strings.Contains(html, `<a class="badge link" href="https://example.com/issues/1" target="_blank">需求</a>`)).
+		True(strings.Contains(html, `负责团队：Platform Engineering&#160;<a href="mailto:platform@example.com">platform@example.com</a>`)).
+		True(strings.Contains(html, `<div class="note note-environment"><span class="environment">staging</span>不限制请求次数</div>`)).
+		True(strings.Contains(html, `<span class="badge access" title="只读用户无法访问">需要角色：admin,editor</span>`)).
+		True(strings.Contains(html, "<tr><th>p99-latency</th><td>200.5</td><td>ms</td></tr>")).
+		True(strings.Contains(html, `<span class="badge">sse</span>`)).
+		True(strings.Contains(html, "<summary>变更记录</summary>")).
+		True(strings.Contains(html, "<tr><th>1.0.0</th><td>2017-10-01</td><td>初始版本</td></tr>")).
+		True(strings.Contains(html, `<span class="badge">websocket</span>`)).
+		True(strings.Contains(html, "<h4>服务端发送的消息</h4>")).
+		True(strings.Contains(html, "<tr><th>message</th><td>object</td><td>新的聊天消息</td></tr>")).
+		True(strings.Contains(html, "<h4>服务端接收的消息</h4>")).
+		True(strings.Contains(html, "<tr><th>send</th><td>object</td><td></td></tr>")).
+		True(strings.Contains(html, "<h4>Socket.IO 事件</h4>")).
+		True(strings.Contains(html, "<h5>/chat</h5>")).
+		True(strings.Contains(html, `<tr><th>typing</th><td><span class="socket-io-direction emit">emit</span></td><td>string</td><td>对方正在输入</td></tr>`)).
+		True(strings.Contains(html, `<tr><th>typing</th><td><span class="socket-io-direction on">on</span></td><td>string</td><td>正在输入</td></tr>`)).
+		True(strings.Contains(html, "<tr><th>updated</th><td>object</td><td>用户信息已更新</td></tr>")).
+		True(strings.Contains(html, `<span class="grpc" title="gRPC">users.UserService.GetUser</span>`)).
+		True(strings.Contains(html, `<span class="badge timeout" title="预期的响应时间">p90 &le; 300ms</span>`)).
+		True(strings.Contains(html, `<a class="badge contract" href="https://pact.example.com/users" target="_blank" title="users">Contract Tests</a>`)).
+		True(strings.Contains(html, "<tr><th>401</th><td>AuthError</td><td>未登录</td></tr>")).
+		True(strings.Contains(html, "<h4>跨域策略</h4>")).
+		True(strings.Contains(html, "<tr><th>Access-Control-Allow-Methods</th><td>GET, POST</td></tr>")).
+		False(strings.Contains(html, "<th>Access-Control-Max-Age</th>")).
+		True(strings.Contains(html, `<div class="breaking-change"><strong>Breaking Change in v2.0.0</strong>删除了 email 字段</div>`)).
+		True(strings.Contains(html, `<span class="badge classification pii" title="数据分级">pii</span>`)).
+		True(strings.Contains(html, "<h4>区域可用性</h4>")).
+		True(strings.Contains(html, `<tr><th>cn-north-1</th><td class="region-available">可用</td></tr>`)).
+		True(strings.Contains(html, `<tr><th>eu-west-1</th><td class="region-unavailable">不可用</td></tr>`)).
+		True(strings.Contains(html, `<div class="note note-async"><span class="async">Async</span>该接口异步处理请求，返回 202 和任务 ID，可以通过 /jobs/{jobId} 查询处理状态，可以通过报头 X-Callback-URL 指定接收处理结果的地址</div>`)).
+		True(strings.Contains(html, `<span class="badge content-length" title="请求内容最多 1572864 字节">请求大小限制：1.5MB</span>`)).
+		True(strings.Contains(html, "<h4>使用限制</h4>")).
+		True(strings.Contains(html, "<h4>官方 SDK</h4>")).
+		True(strings.Contains(html, `<li><span class="sdk-icon" data-lang="go">go</span><a href="https://github.com/caixw/apidoc-go">github.com/caixw/apidoc-go</a></li>`)).
+		True(strings.Contains(html, `<li><span class="sdk-icon" data-lang="javascript">javascript</span><a href="https://www.npmjs.com/package/apidoc-js">apidoc-js</a></li>`)).
+		True(strings.Contains(html, "<h4>409 冲突</h4>")).
+		True(strings.Contains(html, "<tr><th>version-mismatch</th><td>ConflictError</td><td>数据已经被修改</td></tr>")).
+		True(strings.Contains(html, "<tr><th>频率限制</th><td>10</td><td>second</td><td></td></tr>")).
+		True(strings.Contains(html, "<tr><th>配额</th><td>100000</td><td>day</td><td>org</td></tr>")).
+		True(strings.Contains(html, `<div class="note note-tenant"><span class="tenant">Multi-Tenant</span>该接口区分租户，每个租户使用独立的资源，通过子域名指定租户</div>`)).
+		True(strings.Contains(html, `<span class="badge scope">权限范围（any）：users:read,admin</span>`)).
+		True(strings.Contains(html, `<div class="note note-resilience"><span class="resilience">Resilience</span>建议客户端启用熔断，错误率达到 50% 时熔断，半开状态下允许 3 个请求通过</div>`)).
+		True(strings.Contains(html, `<div class="note note-sla"><span class="sla">SLA</span>可用性 99.9%，RPO 5m，RTO 30m</div>`)).
+		True(strings.Contains(html, `<span class="badge todo" title="补充返回值&#10;">TODO</span>`)).
+		True(strings.Contains(html, "<h4>产生的领域事件</h4>")).
+		True(strings.Contains(html, "<tr><th>UserCreated</th><td>User</td><td>用户已创建</td></tr>")).
+		False(strings.Contains(html, "<h4>消费的领域事件</h4>")).
+		True(strings.Contains(html, `<div class="note note-feature-flag"><span class="feature-flag">Feature Flag: users-v2</span>该接口受功能开关控制，可能尚未对所有用户开放（statsig）</div>`)).
+		True(strings.Contains(html, "<h4>响应时间预算：300ms</h4>")).
+		True(strings.Contains(html, `<svg class="budget-chart"`)).
+		True(strings.Contains(html, "本服务：150ms")).
+		True(strings.Contains(html, `<span class="badge graphql" title="GraphQL query">GraphQL: user</span>`)).
+		True(strings.Contains(html, `<span class="media-type" title=".png">&#x2913;&#160;image/png</span>`)).
+		False(strings.Contains(html, "/admin")) // 未指定的分组不输出
+
+	// 通过 Render 输出
+	o := &Options{Type: TypeSingle, Dir: filepath.Join(dir, "doc")}
+	a.NotError(o.Sanitize())
+	a.NotError(Render(docs, o))
+	data, err = os.ReadFile(filepath.Join(o.Dir, vars.SinglePageFileName))
+	a.NotError(err)
+	a.True(strings.Contains(string(data), "/admin"))
+}
+
+func TestBudgetChart(t *testing.T) {
+	a := assert.New(t)
+
+	html := string(budgetChart(&types.TimeBudget{Total: 200, Upstreams: []*types.UpstreamBudget{
+		{Service: "users", Value: 50},
+		{Service: "<orders>", Value: 100},
+	}}))
+	a.True(strings.HasPrefix(html, `<svg class="budget-chart"`))
+	a.True(strings.Contains(html, `stroke-dasharray="25.00 100" stroke-dashoffset="-0.00"`)).
+		True(strings.Contains(html, `stroke-dasharray="50.00 100" stroke-dashoffset="-25.00"`)).
+		True(strings.Contains(html, "<title>&lt;orders&gt;</title>")).
+		True(strings.Contains(html, "&lt;orders&gt;：100ms</li>")).
+		True(strings.Contains(html, "本服务：50ms"))
+
+	// 超出总时间时，按分配的时间之和计算比例，且没有本服务的时间
+	html = string(budgetChart(&types.TimeBudget{Total: 100, Upstreams: []*types.UpstreamBudget{
+		{Service: "users", Value: 100},
+		{Service: "orders", Value: 100},
+	}}))
+	a.True(strings.Contains(html, `stroke-dasharray="50.00 100" stroke-dashoffset="-50.00"`)).
+		False(strings.Contains(html, "本服务"))
+}
+
+func TestByteSize(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(byteSize(1), "1B")
+	a.Equal(byteSize(1023), "1023B")
+	a.Equal(byteSize(1024), "1KB")
+	a.Equal(byteSize(1536), "1.5KB")
+	a.Equal(byteSize(1000000), "976.56KB")
+	a.Equal(byteSize(10485760), "10MB")
+	a.Equal(byteSize(10737418240), "10GB")
+	a.Equal(byteSize(1<<50), "1024TB")
+}
+
+func TestTenantNote(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(tenantNote(&types.TenantPolicy{}), "该接口区分租户")
+	a.Equal(tenantNote(&types.TenantPolicy{Isolation: types.TenantIsolationShared, Scoping: types.TenantScopingHeader, Header: "X-Org"}),
+		"该接口区分租户，所有租户共享资源，通过报头 X-Org 指定租户")
+	a.Equal(tenantNote(&types.TenantPolicy{Scoping: types.TenantScopingPath}), "该接口区分租户，通过请求路径指定租户")
+	a.Equal(tenantNote(&types.TenantPolicy{Isolation: types.TenantIsolationDedicated}), "该接口区分租户，每个租户使用独立的资源")
+}
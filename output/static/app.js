@@ -13,14 +13,21 @@ $(()=>{
 function initTemplate() {
     Handlebars.registerPartial('examples', $('#examples').html())
     Handlebars.registerPartial('params', $('#params').html())
+    Handlebars.registerPartial('events', $('#events').html())
+    Handlebars.registerPartial('usageLimits', $('#usageLimits').html())
     Handlebars.registerPartial('headers', $('#headers').html())
     Handlebars.registerPartial('response', $('#response').html())
+    Handlebars.registerPartial('home', $('#home').html())
 
     Handlebars.registerHelper('dateFormat', formatDate)
     Handlebars.registerHelper('elapsedFormat', formatElapsed)
+    Handlebars.registerHelper('budgetChart', budgetChart)
+    Handlebars.registerHelper('tenantNote', tenantNote)
+    Handlebars.registerHelper('byteSize', byteSize)
 
     let pageTpl = Handlebars.compile($('#page').html())
     let apiTpl = Handlebars.compile($('#api').html())
+    let homeTpl = Handlebars.compile($('#home').html())
 
     fetch('./'+dataDirName+'/page.json').then((resp)=>{
         return resp.json();
@@ -35,7 +42,7 @@ function initTemplate() {
     function loadApis(json) {
         let menu = $('aside .menu')
         menu.find('li.content').on('click', (event)=>{
-            $('main').html(json.content)
+            $('main').html(homeTpl(json))
         })
 
         menu.find('li.api').on('click', (event)=>{
@@ -48,6 +55,7 @@ function initTemplate() {
                 indentCode()
                 prettifyParams()
                 highlightCode()
+                initTryIt()
             }).catch((reason)=>{
                 console.error(reason)
             })
@@ -64,6 +72,54 @@ function prettifyParams() {
     })
 }
 
+// 在线调试，根据表单中的内容向 data-base-url 指定的地址发起请求。
+function initTryIt() {
+    $('main form.try-it').on('submit', (event)=>{
+        event.preventDefault()
+
+        let form = $(event.target)
+        let url = form.attr('data-url')
+        let queries = []
+        let init = {method: form.attr('data-method').toUpperCase(), headers: {}}
+
+        form.find('[data-in=path]').each((index, elem)=>{
+            url = url.replace('{'+elem.name+'}', encodeURIComponent($(elem).val()))
+        })
+        form.find('[data-in=query]').each((index, elem)=>{
+            let val = $(elem).val()
+            if (val != '') {
+                queries.push(encodeURIComponent(elem.name)+'='+encodeURIComponent(val))
+            }
+        })
+        form.find('[data-in=body]').each((index, elem)=>{
+            let type = $(elem).attr('data-type')
+            if (type != '') {
+                init.headers['Content-Type'] = type.split(',')[0]
+            }
+            init.body = $(elem).val()
+        })
+
+        let token = form.attr('data-token')
+        if (token != '') {
+            init.headers['Authorization'] = token
+        }
+
+        url = form.attr('data-base-url').replace(/\/+$/, '') + '/' + url.replace(/^\/+/, '')
+        if (queries.length > 0) {
+            url += '?' + queries.join('&')
+        }
+
+        let result = form.find('.try-it-result')
+        fetch(url, init).then((resp)=>{
+            return resp.text().then((text)=>{
+                result.text(resp.status + ' ' + resp.statusText + '\n\n' + text)
+            })
+        }).catch((reason)=>{
+            result.text(reason)
+        })
+    })
+}
+
 // 代码高亮，依赖于是否能访问网络。
 function highlightCode() {
     if (typeof(Prism) != 'undefined') {
@@ -116,3 +172,84 @@ function formatDate(unix) {
 function formatElapsed(number) {
     return (number / 100000000).toFixed(4) + '秒'
 }
+
+// 时间预算饼图中各上游服务的颜色，超出数量时循环使用。
+//
+// 需要与 output/single.go 中的 budgetColors 保持一致。
+let budgetColors = ['#2185d0', '#21ba45', '#f2711c', '#a333c8', '#00b5ad', '#e03997', '#fbbd08']
+
+// 以内联 SVG 饼图的形式显示 @apiTimeBudget 在各上游服务之间的分配，
+// 未分配的部分为服务本身的处理时间，以灰色的底色显示。
+//
+// 需要与 output/single.go 中的 budgetChart 保持一致。
+function budgetChart(budget) {
+    let upstreams = budget.upstreams || []
+    let allocated = upstreams.reduce((sum, u)=>sum+u.value, 0)
+    let total = Math.max(budget.total, allocated)
+
+    let svg = ['<svg class="budget-chart" viewBox="0 0 32 32" width="120" height="120"><circle r="16" cx="16" cy="16" fill="#ddd"/>']
+    let legend = ['<ul class="budget-legend">']
+
+    let offset = 0
+    upstreams.forEach((u, i)=>{
+        let color = budgetColors[i%budgetColors.length]
+        let name = Handlebars.escapeExpression(u.service)
+        let percent = u.value * 100 / total
+
+        svg.push('<circle r="8" cx="16" cy="16" fill="none" stroke="', color, '" stroke-width="16" pathLength="100" stroke-dasharray="', percent.toFixed(2), ' 100" stroke-dashoffset="', (-offset).toFixed(2), '" transform="rotate(-90 16 16)"><title>', name, '</title></circle>')
+        legend.push('<li><span class="swatch" style="background:', color, '"></span>', name, '：', u.value, 'ms</li>')
+        offset += percent
+    })
+    if (budget.total > allocated) {
+        legend.push('<li><span class="swatch" style="background:#ddd"></span>本服务：', budget.total-allocated, 'ms</li>')
+    }
+
+    svg.push('</svg>')
+    legend.push('</ul>')
+    return new Handlebars.SafeString(svg.join('') + legend.join(''))
+}
+
+// 以文字的形式描述 @apiTenant 的隔离和区分方式。
+//
+// 需要与 output/single.go 中的 tenantNote 保持一致。
+function tenantNote(tenant) {
+    let note = '该接口区分租户'
+
+    switch (tenant.isolation) {
+    case 'shared':
+        note += '，所有租户共享资源'
+        break
+    case 'dedicated':
+        note += '，每个租户使用独立的资源'
+        break
+    }
+
+    switch (tenant.scoping) {
+    case 'header':
+        note += '，通过报头 ' + tenant.header + ' 指定租户'
+        break
+    case 'path':
+        note += '，通过请求路径指定租户'
+        break
+    case 'subdomain':
+        note += '，通过子域名指定租户'
+        break
+    }
+
+    return note
+}
+
+// 字节数的单位，相邻的单位之间相差 1024 倍
+let byteUnits = ['B', 'KB', 'MB', 'GB', 'TB']
+
+// 将字节数转换成便于阅读的形式，最多保留两位小数，比如 1572864 转换成 1.5MB。
+//
+// 需要与 output/single.go 中的 byteSize 保持一致。
+function byteSize(n) {
+    let size = n
+    let i = 0
+    for (; size >= 1024 && i < byteUnits.length-1; i++) {
+        size /= 1024
+    }
+    return Math.round(size*100)/100 + byteUnits[i]
+}
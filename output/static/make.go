@@ -14,7 +14,6 @@ package main
 import (
 	"bytes"
 	"go/format"
-	"io/ioutil"
 	"os"
 )
 
@@ -68,7 +67,7 @@ func main() {
 func makeStatic(w *bytes.Buffer) {
 	w.WriteString("var assets=map[string][]byte{\n")
 	for _, file := range assets {
-		data, err := ioutil.ReadFile(file)
+		data, err := os.ReadFile(file)
 		if err != nil {
 			panic(err)
 		}
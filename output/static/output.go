@@ -8,7 +8,6 @@ package static
 //go:generate go run make.go
 
 import (
-	"io/ioutil"
 	"os"
 	"path/filepath"
 )
@@ -17,9 +16,15 @@ import (
 func Output(dir string) error {
 	for path, content := range assets {
 		path = filepath.Join(dir, path)
-		if err := ioutil.WriteFile(path, content, os.ModePerm); err != nil {
+		if err := os.WriteFile(path, content, os.ModePerm); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// Asset 返回 name 对应的静态文件内容，name 为 make.go 中 assets 指定的文件名，比如 ./style.css。
+func Asset(name string) ([]byte, bool) {
+	content, found := assets[name]
+	return content, found
+}
@@ -76,6 +76,46 @@ main {
     padding: 1rem;
 }
 
+/*=============== .sdks ================*/
+
+main .sdks .sdk-grid{
+    display:grid;
+    grid-template-columns:repeat(auto-fill, minmax(15rem, 1fr));
+    gap:.5rem;
+    padding:0;
+    list-style:none;
+}
+
+main .sdks .sdk-grid li{
+    display:flex;
+    align-items:center;
+    padding:.5rem;
+    border:1px solid #eee;
+}
+
+main .sdks .sdk-icon{
+    display:inline-block;
+    min-width:5rem;
+    margin-right:.5rem;
+    padding:.2rem .4rem;
+    border-radius:3px;
+    background:#767676;
+    color:#fff;
+    font-size:.8rem;
+    text-align:center;
+}
+
+main .sdks .sdk-icon[data-lang="go"]{ background:#00add8; }
+main .sdks .sdk-icon[data-lang="java"]{ background:#b07219; }
+main .sdks .sdk-icon[data-lang="javascript"],
+main .sdks .sdk-icon[data-lang="typescript"]{ background:#3178c6; }
+main .sdks .sdk-icon[data-lang="python"]{ background:#3572a5; }
+main .sdks .sdk-icon[data-lang="php"]{ background:#4f5d95; }
+main .sdks .sdk-icon[data-lang="ruby"]{ background:#701516; }
+main .sdks .sdk-icon[data-lang="rust"]{ background:#dea584; }
+main .sdks .sdk-icon[data-lang="swift"]{ background:#f05138; }
+main .sdks .sdk-icon[data-lang="csharp"]{ background:#178600; }
+
 /*=============== .api ================*/
 
 main .api{
@@ -133,6 +173,252 @@ main .api{
     margin-right:2rem;
 }
 
+.api h3 .grpc{
+    margin-right:2rem;
+    font-family:monospace;
+    font-weight:normal;
+    color:#767676;
+}
+
+.api h3 .badge{
+    margin-left:.5rem;
+    padding:0rem .4rem;
+    font-size:.8rem;
+    font-weight:normal;
+    border:1px solid #ccc;
+    border-radius:.2rem;
+    color:#666;
+}
+
+.api h3 .badge.link{
+    color:#2185d0;
+    text-decoration:none;
+}
+
+.api h3 .badge.graphql{
+    border-color:#e10098;
+    color:#e10098;
+}
+
+.api h3 .badge.contract{
+    border-color:#21ba45;
+    color:#21ba45;
+    text-decoration:none;
+}
+
+.api h3 .badge.classification{
+    text-transform:uppercase;
+    color:#fff;
+}
+
+.api h3 .badge.classification.pii{
+    border-color:#db2828;
+    background:#db2828;
+}
+
+.api h3 .badge.classification.sensitive{
+    border-color:#f2711c;
+    background:#f2711c;
+}
+
+.api h3 .badge.classification.confidential{
+    border-color:#fbbd08;
+    background:#fbbd08;
+}
+
+.api h3 .badge.classification.public{
+    border-color:#21ba45;
+    background:#21ba45;
+}
+
+.api h3 .badge.access{
+    border-color:#a333c8;
+    color:#a333c8;
+}
+
+.api h3 .badge.scope{
+    border-color:#6435c9;
+    color:#6435c9;
+}
+
+.api h3 .badge.content-length{
+    border-color:#a5673f;
+    color:#a5673f;
+}
+
+.api h3 .badge.timeout{
+    border-color:#00b5ad;
+    color:#00b5ad;
+}
+
+.api h3 .badge.todo{
+    border-color:#fbbd08;
+    background:#fffbe6;
+    color:#b58105;
+}
+
+.api .changelog summary{
+    margin:1rem 0 .5rem;
+    font-weight:bold;
+    cursor:pointer;
+}
+
+.api .owner{
+    margin-top:1rem;
+    padding-top:.5rem;
+    border-top:1px dashed #ccc;
+    font-size:.9rem;
+    color:#666;
+}
+
+.api .breaking-change{
+    margin:.5rem 0rem;
+    padding:.5rem 1rem;
+    border:2px solid #db2828;
+    border-radius:.2rem;
+    background:#fff6f6;
+    color:#9f3a38;
+}
+
+.api .breaking-change strong{
+    display:block;
+    margin-bottom:.3rem;
+    color:#db2828;
+}
+
+.api .note{
+    margin:.5rem 0rem;
+    padding:.5rem 1rem;
+    border-left:4px solid #2185d0;
+    background:#f0f7fd;
+    white-space:pre-wrap;
+}
+
+.api .note-warning{
+    border-color:rgb(240,114,11);
+    background:#fef6ee;
+}
+
+.api .note-danger{
+    border-color:red;
+    background:#fdeeee;
+}
+
+.api .note-tip{
+    border-color:green;
+    background:#eef8ee;
+}
+
+.api .note-environment{
+    border-color:#888;
+    background:#f5f5f5;
+}
+
+.api .note-environment .environment{
+    margin-right:.5rem;
+    padding:0rem .3rem;
+    border-radius:3px;
+    background:#888;
+    color:#fff;
+    font-size:.8rem;
+}
+
+.api .note-feature-flag{
+    border-color:#f2711c;
+    background:#fff5ec;
+}
+
+.api .note-feature-flag .feature-flag{
+    margin-right:.5rem;
+    padding:0rem .3rem;
+    border-radius:3px;
+    background:#f2711c;
+    color:#fff;
+    font-size:.8rem;
+}
+
+.api .note-tenant{
+    border-color:#00b5ad;
+    background:#eafaf9;
+}
+
+.api .note-tenant .tenant{
+    margin-right:.5rem;
+    padding:0rem .3rem;
+    border-radius:3px;
+    background:#00b5ad;
+    color:#fff;
+    font-size:.8rem;
+}
+
+.api .note-async{
+    border-color:#f2711c;
+    background:#fef4ed;
+}
+
+.api .note-async .async{
+    margin-right:.5rem;
+    padding:0rem .3rem;
+    border-radius:3px;
+    background:#f2711c;
+    color:#fff;
+    font-size:.8rem;
+}
+
+.api .note-resilience{
+    border-color:#6435c9;
+    background:#f4effc;
+}
+
+.api .note-resilience .resilience{
+    margin-right:.5rem;
+    padding:0rem .3rem;
+    border-radius:3px;
+    background:#6435c9;
+    color:#fff;
+    font-size:.8rem;
+}
+
+.api .note-sla{
+    border-color:#00b5ad;
+    background:#effbfa;
+}
+
+.api .note-sla .sla{
+    margin-right:.5rem;
+    padding:0rem .3rem;
+    border-radius:3px;
+    background:#00b5ad;
+    color:#fff;
+    font-size:.8rem;
+}
+
+.api .try-it{
+    margin:1rem 0rem;
+    padding:.5rem 1rem;
+    border:1px solid #ddd;
+}
+
+.api .try-it label{
+    display:block;
+    margin:.3rem 0rem;
+}
+
+.api .try-it label span{
+    display:inline-block;
+    width:8rem;
+}
+
+.api .try-it textarea{
+    width:30rem;
+    height:6rem;
+    vertical-align:top;
+}
+
+.api .try-it-result{
+    white-space:pre-wrap;
+}
+
 .api h4 .success{
     color:green;
     margin-right:1rem;
@@ -143,6 +429,40 @@ main .api{
     margin-right:1rem;
 }
 
+.api h4 .media-type{
+    margin-left:1rem;
+    padding:0rem .4rem;
+    font-size:.8rem;
+    font-weight:normal;
+    font-family:monospace;
+    border:1px solid #2185d0;
+    border-radius:.2rem;
+    color:#2185d0;
+}
+
+.api .time-budget{
+    display:flex;
+    flex-wrap:wrap;
+    align-items:center;
+}
+
+.api .time-budget h4{
+    width:100%;
+}
+
+.api .time-budget .budget-legend{
+    margin-left:2rem;
+    list-style:none;
+}
+
+.api .time-budget .swatch{
+    display:inline-block;
+    width:.8rem;
+    height:.8rem;
+    margin-right:.5rem;
+    border-radius:2px;
+}
+
 .api table{
     text-align:left;
     border-collapse:collapse;
@@ -166,6 +486,27 @@ main .api{
     padding:.3rem 1rem;
 }
 
+.api .regions .region-available{
+    color:#21ba45;
+}
+
+.api .regions .region-unavailable{
+    color:#db2828;
+}
+
+.api .socket-io .socket-io-direction{
+    padding:0 .3rem;
+    border-radius:3px;
+    color:#fff;
+}
+
+.api .socket-io .socket-io-direction.emit{
+    background:#2185d0;
+}
+
+.api .socket-io .socket-io-direction.on{
+    background:#21ba45;
+}
 `), "./app.js": []byte(`"use strict";
 
 // 代码缩进的空格数量。
@@ -181,14 +522,21 @@ $(()=>{
 function initTemplate() {
     Handlebars.registerPartial('examples', $('#examples').html())
     Handlebars.registerPartial('params', $('#params').html())
+    Handlebars.registerPartial('events', $('#events').html())
+    Handlebars.registerPartial('usageLimits', $('#usageLimits').html())
     Handlebars.registerPartial('headers', $('#headers').html())
     Handlebars.registerPartial('response', $('#response').html())
+    Handlebars.registerPartial('home', $('#home').html())
 
     Handlebars.registerHelper('dateFormat', formatDate)
     Handlebars.registerHelper('elapsedFormat', formatElapsed)
+    Handlebars.registerHelper('budgetChart', budgetChart)
+    Handlebars.registerHelper('tenantNote', tenantNote)
+    Handlebars.registerHelper('byteSize', byteSize)
 
     let pageTpl = Handlebars.compile($('#page').html())
     let apiTpl = Handlebars.compile($('#api').html())
+    let homeTpl = Handlebars.compile($('#home').html())
 
     fetch('./'+dataDirName+'/page.json').then((resp)=>{
         return resp.json();
@@ -203,7 +551,7 @@ function initTemplate() {
     function loadApis(json) {
         let menu = $('aside .menu')
         menu.find('li.content').on('click', (event)=>{
-            $('main').html(json.content)
+            $('main').html(homeTpl(json))
         })
 
         menu.find('li.api').on('click', (event)=>{
@@ -216,6 +564,7 @@ function initTemplate() {
                 indentCode()
                 prettifyParams()
                 highlightCode()
+                initTryIt()
             }).catch((reason)=>{
                 console.error(reason)
             })
@@ -232,6 +581,54 @@ function prettifyParams() {
     })
 }
 
+// 在线调试，根据表单中的内容向 data-base-url 指定的地址发起请求。
+function initTryIt() {
+    $('main form.try-it').on('submit', (event)=>{
+        event.preventDefault()
+
+        let form = $(event.target)
+        let url = form.attr('data-url')
+        let queries = []
+        let init = {method: form.attr('data-method').toUpperCase(), headers: {}}
+
+        form.find('[data-in=path]').each((index, elem)=>{
+            url = url.replace('{'+elem.name+'}', encodeURIComponent($(elem).val()))
+        })
+        form.find('[data-in=query]').each((index, elem)=>{
+            let val = $(elem).val()
+            if (val != '') {
+                queries.push(encodeURIComponent(elem.name)+'='+encodeURIComponent(val))
+            }
+        })
+        form.find('[data-in=body]').each((index, elem)=>{
+            let type = $(elem).attr('data-type')
+            if (type != '') {
+                init.headers['Content-Type'] = type.split(',')[0]
+            }
+            init.body = $(elem).val()
+        })
+
+        let token = form.attr('data-token')
+        if (token != '') {
+            init.headers['Authorization'] = token
+        }
+
+        url = form.attr('data-base-url').replace(/\/+$/, '') + '/' + url.replace(/^\/+/, '')
+        if (queries.length > 0) {
+            url += '?' + queries.join('&')
+        }
+
+        let result = form.find('.try-it-result')
+        fetch(url, init).then((resp)=>{
+            return resp.text().then((text)=>{
+                result.text(resp.status + ' ' + resp.statusText + '\n\n' + text)
+            })
+        }).catch((reason)=>{
+            result.text(reason)
+        })
+    })
+}
+
 // 代码高亮，依赖于是否能访问网络。
 function highlightCode() {
     if (typeof(Prism) != 'undefined') {
@@ -284,6 +681,87 @@ function formatDate(unix) {
 function formatElapsed(number) {
     return (number / 100000000).toFixed(4) + '秒'
 }
+
+// 时间预算饼图中各上游服务的颜色，超出数量时循环使用。
+//
+// 需要与 output/single.go 中的 budgetColors 保持一致。
+let budgetColors = ['#2185d0', '#21ba45', '#f2711c', '#a333c8', '#00b5ad', '#e03997', '#fbbd08']
+
+// 以内联 SVG 饼图的形式显示 @apiTimeBudget 在各上游服务之间的分配，
+// 未分配的部分为服务本身的处理时间，以灰色的底色显示。
+//
+// 需要与 output/single.go 中的 budgetChart 保持一致。
+function budgetChart(budget) {
+    let upstreams = budget.upstreams || []
+    let allocated = upstreams.reduce((sum, u)=>sum+u.value, 0)
+    let total = Math.max(budget.total, allocated)
+
+    let svg = ['<svg class="budget-chart" viewBox="0 0 32 32" width="120" height="120"><circle r="16" cx="16" cy="16" fill="#ddd"/>']
+    let legend = ['<ul class="budget-legend">']
+
+    let offset = 0
+    upstreams.forEach((u, i)=>{
+        let color = budgetColors[i%budgetColors.length]
+        let name = Handlebars.escapeExpression(u.service)
+        let percent = u.value * 100 / total
+
+        svg.push('<circle r="8" cx="16" cy="16" fill="none" stroke="', color, '" stroke-width="16" pathLength="100" stroke-dasharray="', percent.toFixed(2), ' 100" stroke-dashoffset="', (-offset).toFixed(2), '" transform="rotate(-90 16 16)"><title>', name, '</title></circle>')
+        legend.push('<li><span class="swatch" style="background:', color, '"></span>', name, '：', u.value, 'ms</li>')
+        offset += percent
+    })
+    if (budget.total > allocated) {
+        legend.push('<li><span class="swatch" style="background:#ddd"></span>本服务：', budget.total-allocated, 'ms</li>')
+    }
+
+    svg.push('</svg>')
+    legend.push('</ul>')
+    return new Handlebars.SafeString(svg.join('') + legend.join(''))
+}
+
+// 以文字的形式描述 @apiTenant 的隔离和区分方式。
+//
+// 需要与 output/single.go 中的 tenantNote 保持一致。
+function tenantNote(tenant) {
+    let note = '该接口区分租户'
+
+    switch (tenant.isolation) {
+    case 'shared':
+        note += '，所有租户共享资源'
+        break
+    case 'dedicated':
+        note += '，每个租户使用独立的资源'
+        break
+    }
+
+    switch (tenant.scoping) {
+    case 'header':
+        note += '，通过报头 ' + tenant.header + ' 指定租户'
+        break
+    case 'path':
+        note += '，通过请求路径指定租户'
+        break
+    case 'subdomain':
+        note += '，通过子域名指定租户'
+        break
+    }
+
+    return note
+}
+
+// 字节数的单位，相邻的单位之间相差 1024 倍
+let byteUnits = ['B', 'KB', 'MB', 'GB', 'TB']
+
+// 将字节数转换成便于阅读的形式，最多保留两位小数，比如 1572864 转换成 1.5MB。
+//
+// 需要与 output/single.go 中的 byteSize 保持一致。
+function byteSize(n) {
+    let size = n
+    let i = 0
+    for (; size >= 1024 && i < byteUnits.length-1; i++) {
+        size /= 1024
+    }
+    return Math.round(size*100)/100 + byteUnits[i]
+}
 `), "./index.html": []byte(`<!DOCTYPE html>
 <html lang="zh-cmn-Hans">
     <head>
@@ -324,13 +802,27 @@ function formatElapsed(number) {
                 </footer>
             </aside>
 
-            <main id="main">{{{content}}}</main>
+            <main id="main">{{> home}}</main>
+        </script>
+
+        <script id="home" type="text/x-handlebars-template">
+            {{{content}}}
+            {{#if sdks}}
+            <div class="sdks">
+                <h4>官方 SDK</h4>
+                <ul class="sdk-grid">
+                    {{#each sdks}}
+                    <li><span class="sdk-icon" data-lang="{{language}}">{{language}}</span><a href="{{url}}">{{package}}</a></li>
+                    {{/each}}
+                </ul>
+            </div>
+            {{/if}}
         </script>
 
 
         <script id="examples" type="text/x-handlebars-template">
             {{#each examples}}
-            <pre><code class="language-{{type}}">{{code}}
+            <pre><code class="language-{{lang}}" title="{{type}}">{{code}}
             </code></pre>
             {{/each}}
         </script>
@@ -352,6 +844,52 @@ function formatElapsed(number) {
             </table>
         </script>
 
+        <script id="events" type="text/x-handlebars-template">
+            <table>
+                <thead>
+                    <tr><th>名称</th><th>结构</th><th>描述</th></tr>
+                </thead>
+                <tbody>
+                {{#each events}}
+                <tr>
+                    <th>{{name}}</th>
+                    <td>{{type}}</td>
+                    <td>{{summary}}</td>
+                </tr>
+                {{/each}}
+                </tbody>
+            </table>
+        </script>
+
+        <script id="usageLimits" type="text/x-handlebars-template">
+            <div class="usage-limits">
+                <h4>使用限制</h4>
+                <table>
+                    <thead>
+                        <tr><th>类型</th><th>次数</th><th>周期</th><th>范围</th></tr>
+                    </thead>
+                    <tbody>
+                    {{#if throttle}}
+                    <tr>
+                        <th>频率限制</th>
+                        <td>{{throttle.requests}}</td>
+                        <td>{{throttle.window}}</td>
+                        <td></td>
+                    </tr>
+                    {{/if}}
+                    {{#if quota}}
+                    <tr>
+                        <th>配额</th>
+                        <td>{{quota.limit}}</td>
+                        <td>{{quota.window}}</td>
+                        <td>{{quota.scope}}</td>
+                    </tr>
+                    {{/if}}
+                    </tbody>
+                </table>
+            </div>
+        </script>
+
         <script id="headers" type="text/x-handlebars-template">
             <table>
                 <thead>
@@ -394,14 +932,56 @@ function formatElapsed(number) {
                 <h3>
                     <span class="method {{method}}">{{method}}</span>
                     <span class="url">{{url}}</span>
+                    {{#if grpcMethod}}<span class="grpc" title="gRPC">{{grpcMethod}}</span>{{/if}}
                     <span class="summary">{{summary}}</span>
+                    {{#if safe}}<span class="badge">safe</span>{{/if}}
+                    {{#if idempotent}}<span class="badge">idempotent</span>{{/if}}
+                    {{#if sse}}<span class="badge">sse</span>{{/if}}
+                    {{#if websocket}}<span class="badge">websocket</span>{{/if}}
+                    {{#if timeout}}<span class="badge timeout" title="预期的响应时间">{{timeout.percentile}} &le; {{timeout.value}}ms</span>{{/if}}
+                    {{#if dataClassification}}<span class="badge classification {{dataClassification}}" title="数据分级">{{dataClassification}}</span>{{/if}}
+                    {{#if accessRoles}}<span class="badge access" title="{{accessSummary}}">需要角色：{{#each accessRoles}}{{#if @index}},{{/if}}{{this}}{{/each}}</span>{{/if}}
+                    {{#if maxRequestBodyBytes}}<span class="badge content-length" title="请求内容最多 {{maxRequestBodyBytes}} 字节">请求大小限制：{{byteSize maxRequestBodyBytes}}</span>{{/if}}
+                    {{#if requiredScopes}}<span class="badge scope">权限范围（{{scopeLogic}}）：{{#each requiredScopes}}{{#if @index}},{{/if}}{{this}}{{/each}}</span>{{/if}}
+                    {{#each links}}<a class="badge link" href="{{url}}" target="_blank">{{#if label}}{{label}}{{else}}{{url}}{{/if}}</a>{{/each}}
+                    {{#if graphql}}<span class="badge graphql" title="GraphQL {{graphql.type}}">GraphQL: {{graphql.operation}}</span>{{/if}}
+                    {{#each contracts}}<a class="badge contract" href="{{url}}" target="_blank" title="{{suite}}">Contract Tests</a>{{/each}}
+                    {{#if todos}}<span class="badge todo" title="{{#each todos}}{{this}}&#10;{{/each}}">TODO</span>{{/if}}
                 </h3>
 
                 <div class="content">
+                    {{#each breakingChanges}}
+                    <div class="breaking-change"><strong>Breaking Change in v{{version}}</strong>{{description}}</div>
+                    {{/each}}
+
                     {{#if description}}
                     <p class="description">{{description}}</p>
                     {{/if}}
 
+                    {{#each notes}}
+                    <div class="note note-{{type}}">{{text}}</div>
+                    {{/each}}
+
+                    {{#each environments}}
+                    <div class="note note-environment"><span class="environment">{{environment}}</span>{{text}}</div>
+                    {{/each}}
+
+                    {{#if featureFlag}}
+                    <div class="note note-feature-flag"><span class="feature-flag">Feature Flag: {{featureFlag.name}}</span>该接口受功能开关控制，可能尚未对所有用户开放{{#if featureFlag.provider}}（{{featureFlag.provider}}）{{/if}}</div>
+                    {{/if}}
+                    {{#if tenant}}
+                    <div class="note note-tenant"><span class="tenant">Multi-Tenant</span>{{tenantNote tenant}}</div>
+                    {{/if}}
+                    {{#if async}}
+                    <div class="note note-async"><span class="async">Async</span>该接口异步处理请求，返回 202 和任务 ID{{#if async.pollUrl}}，可以通过 {{async.pollUrl}} 查询处理状态{{/if}}{{#if async.callbackHeader}}，可以通过报头 {{async.callbackHeader}} 指定接收处理结果的地址{{/if}}</div>
+                    {{/if}}
+                    {{#if circuitBreaker}}
+                    <div class="note note-resilience"><span class="resilience">Resilience</span>建议客户端启用熔断{{#if circuitBreaker.threshold}}，错误率达到 {{circuitBreaker.threshold}}% 时熔断{{/if}}{{#if circuitBreaker.timeout}}，{{circuitBreaker.timeout}} 后进入半开状态{{/if}}{{#if circuitBreaker.halfOpenRequests}}，半开状态下允许 {{circuitBreaker.halfOpenRequests}} 个请求通过{{/if}}</div>
+                    {{/if}}
+                    {{#if sla}}
+                    <div class="note note-sla"><span class="sla">SLA</span>可用性 {{sla.availability}}%{{#if sla.rpo}}，RPO {{sla.rpo}}{{/if}}{{#if sla.rto}}，RTO {{sla.rto}}{{/if}}</div>
+                    {{/if}}
+
                     {{#if queries}}
                         <h5>查询参数</h5>
                         {{> params params=queries}}
@@ -436,7 +1016,7 @@ function formatElapsed(number) {
 
                     {{#if success}}
                     <div class="response success">
-                        <h4><span class="success">SUCCESS:</span>{{success.code}},&#160;{{success.summary}}</h4>
+                        <h4><span class="success">SUCCESS:</span>{{success.code}},&#160;{{success.summary}}{{#if mediaType}}<span class="media-type" title="{{#if mediaType.extension}}.{{mediaType.extension}}{{else}}{{mediaType.type}}{{/if}}">&#x2913;&#160;{{mediaType.type}}</span>{{/if}}</h4>
                         {{> response response=success}}
                     </div>
                     {{/if}}
@@ -447,7 +1027,347 @@ function formatElapsed(number) {
                         {{> response response=error}}
                     </div>
                     {{/if}}
+
+                    {{#if responseHeaders}}
+                    <div class="response-headers">
+                        <h4>返回报头</h4>
+                        {{#each responseHeaders}}
+                            <h5>{{@key}}:</h5>
+                            {{> params params=this}}
+                        {{/each}}
+                    </div>
+                    {{/if}}
+
+                    {{#if timeBudget}}
+                    <div class="time-budget">
+                        <h4>响应时间预算：{{timeBudget.total}}ms</h4>
+                        {{budgetChart timeBudget}}
+                    </div>
+                    {{/if}}
+
+                    {{#if cachePolicy}}
+                    <div class="cache-policy">
+                        <h4>缓存策略</h4>
+                        <table>
+                            <tbody>
+                                <tr>
+                                    <th>Cache-Control</th>
+                                    <td>
+                                        {{#if cachePolicy.maxAge}}max-age={{cachePolicy.maxAge}} {{/if}}
+                                        {{#if cachePolicy.noCache}}no-cache {{/if}}
+                                        {{#if cachePolicy.noStore}}no-store {{/if}}
+                                        {{#if cachePolicy.private}}private {{/if}}
+                                        {{#if cachePolicy.public}}public{{/if}}
+                                    </td>
+                                </tr>
+                                {{#if cachePolicy.vary}}<tr><th>Vary</th><td>{{#each cachePolicy.vary}}{{this}} {{/each}}</td></tr>{{/if}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if corsPolicy}}
+                    <div class="cors-policy">
+                        <h4>跨域策略</h4>
+                        <table>
+                            <tbody>
+                                {{#if corsPolicy.origin}}<tr><th>Access-Control-Allow-Origin</th><td>{{corsPolicy.origin}}</td></tr>{{/if}}
+                                {{#if corsPolicy.methods}}<tr><th>Access-Control-Allow-Methods</th><td>{{#each corsPolicy.methods}}{{#if @index}}, {{/if}}{{this}}{{/each}}</td></tr>{{/if}}
+                                {{#if corsPolicy.headers}}<tr><th>Access-Control-Allow-Headers</th><td>{{#each corsPolicy.headers}}{{#if @index}}, {{/if}}{{this}}{{/each}}</td></tr>{{/if}}
+                                {{#if corsPolicy.maxAge}}<tr><th>Access-Control-Max-Age</th><td>{{corsPolicy.maxAge}}</td></tr>{{/if}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if retry}}
+                    <div class="retry">
+                        <h4>重试策略</h4>
+                        <table>
+                            <tbody>
+                                <tr><th>策略</th><td>{{retry.strategy}}</td></tr>
+                                {{#if retry.maxAttempts}}<tr><th>最大尝试次数</th><td>{{retry.maxAttempts}}</td></tr>{{/if}}
+                                {{#if retry.backoff}}<tr><th>退避方式</th><td>{{retry.backoff}}</td></tr>{{/if}}
+                                {{#if retry.retryOn}}<tr><th>重试的状态码</th><td>{{#each retry.retryOn}}{{this}} {{/each}}</td></tr>{{/if}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if metrics}}
+                    <div class="metrics">
+                        <h4>服务指标</h4>
+                        <table>
+                            <thead>
+                                <tr><th>名称</th><th>值</th><th>单位</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each metrics}}
+                            <tr>
+                                <th>{{name}}</th>
+                                <td>{{value}}</td>
+                                <td>{{unit}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if events}}
+                    <div class="events">
+                        <h4>事件</h4>
+                        <table>
+                            <thead>
+                                <tr><th>名称</th><th>类型</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each events}}
+                            <tr>
+                                <th>{{name}}</th>
+                                <td>{{type}}</td>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if wsSend}}
+                    <div class="ws-send">
+                        <h4>服务端发送的消息</h4>
+                        <table>
+                            <thead>
+                                <tr><th>名称</th><th>类型</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each wsSend}}
+                            <tr>
+                                <th>{{name}}</th>
+                                <td>{{type}}</td>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if wsReceive}}
+                    <div class="ws-receive">
+                        <h4>服务端接收的消息</h4>
+                        <table>
+                            <thead>
+                                <tr><th>名称</th><th>类型</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each wsReceive}}
+                            <tr>
+                                <th>{{name}}</th>
+                                <td>{{type}}</td>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if socketIO}}
+                    <div class="socket-io">
+                        <h4>Socket.IO 事件</h4>
+                        {{#each socketIO}}
+                        <h5>{{namespace}}</h5>
+                        <table>
+                            <thead>
+                                <tr><th>事件</th><th>方向</th><th>类型</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each events}}
+                            <tr>
+                                <th>{{event}}</th>
+                                <td><span class="socket-io-direction {{direction}}">{{direction}}</span></td>
+                                <td>{{type}}</td>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                        {{/each}}
+                    </div>
+                    {{/if}}
+
+                    {{#if producedEvents}}
+                    <div class="domain-events">
+                        <h4>产生的领域事件</h4>
+                        {{> events events=producedEvents}}
+                    </div>
+                    {{/if}}
+
+                    {{#if consumedEvents}}
+                    <div class="domain-events">
+                        <h4>消费的领域事件</h4>
+                        {{> events events=consumedEvents}}
+                    </div>
+                    {{/if}}
+
+                    {{#if changelog}}
+                    <details class="changelog">
+                        <summary>变更记录</summary>
+                        <table>
+                            <thead>
+                                <tr><th>版本</th><th>日期</th><th>变更内容</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each changelog}}
+                            <tr>
+                                <th>{{version}}</th>
+                                <td>{{date}}</td>
+                                <td>{{description}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </details>
+                    {{/if}}
+
+                    {{#if authErrors}}
+                    <div class="auth-errors">
+                        <h4>认证失败</h4>
+                        <table>
+                            <thead>
+                                <tr><th>状态码</th><th>类型</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each authErrors}}
+                            <tr>
+                                <th>{{code}}</th>
+                                <td>{{schema}}</td>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if conflicts}}
+                    <div class="conflicts">
+                        <h4>409 冲突</h4>
+                        <table>
+                            <thead>
+                                <tr><th>名称</th><th>类型</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each conflicts}}
+                            <tr>
+                                <th>{{condition}}</th>
+                                <td>{{schema}}</td>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if errorCodes}}
+                    <div class="error-codes">
+                        <h4>错误代码</h4>
+                        <table>
+                            <thead>
+                                <tr><th>代码</th><th>描述</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each errorCodes}}
+                            <tr>
+                                <th>{{code}}</th>
+                                <td>{{summary}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if region}}
+                    <div class="regions">
+                        <h4>区域可用性</h4>
+                        <table>
+                            <thead>
+                                <tr><th>区域</th><th>状态</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each region.available}}
+                            <tr>
+                                <th>{{this}}</th>
+                                <td class="region-available">可用</td>
+                            </tr>
+                            {{/each}}
+                            {{#each region.unavailable}}
+                            <tr>
+                                <th>{{this}}</th>
+                                <td class="region-unavailable">不可用</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if throttle}}
+                    {{> usageLimits}}
+                    {{else if quota}}
+                    {{> usageLimits}}
+                    {{/if}}
+
+                    {{#if callbacks}}
+                    <div class="callbacks">
+                        <h4>回调</h4>
+                        <table>
+                            <thead>
+                                <tr><th>名称</th><th>方法</th><th>地址</th></tr>
+                            </thead>
+                            <tbody>
+                            {{#each callbacks}}
+                            <tr>
+                                <th>{{name}}</th>
+                                <td>{{method}}</td>
+                                <td>{{expression}}{{path}}</td>
+                            </tr>
+                            {{/each}}
+                            </tbody>
+                        </table>
+                    </div>
+                    {{/if}}
+
+                    {{#if tryIt}}
+                    <form class="try-it" data-method="{{method}}" data-url="{{url}}" data-base-url="{{tryIt.baseURL}}" data-token="{{tryIt.token}}">
+                        <h4>在线调试</h4>
+                        {{#each params}}
+                        <label><span>{{name}}</span><input data-in="path" name="{{name}}" placeholder="{{type}}" title="{{summary}}" /></label>
+                        {{/each}}
+
+                        {{#each queries}}
+                        <label><span>{{name}}</span><input data-in="query" name="{{name}}" placeholder="{{type}}" title="{{summary}}" /></label>
+                        {{/each}}
+
+                        {{#if request}}
+                        <label><span>body</span><textarea data-in="body" name="body" data-type="{{request.type}}">{{request.example.[0].code}}</textarea></label>
+                        {{/if}}
+
+                        <button type="submit">发送</button>
+                        <pre class="try-it-result"></pre>
+                    </form>
+                    {{/if}}
                 </div>
+
+                {{#if owner}}
+                <footer class="owner">
+                    负责团队：{{owner.team}}{{#if owner.email}}&#160;<a href="mailto:{{owner.email}}">{{owner.email}}</a>{{/if}}
+                </footer>
+                {{/if}}
             </section>
             {{/each}}
         </script>
@@ -0,0 +1,217 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// docx 中除 word/document.xml 之外的固定内容
+var wordFiles = []struct {
+	name, content string
+}{
+	{
+		name: "[Content_Types].xml",
+		content: xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>`,
+	},
+	{
+		name: "_rels/.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`,
+	},
+	{
+		name: "word/_rels/document.xml.rels",
+		content: xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+	},
+	{
+		name: "word/styles.xml",
+		content: xml.Header + `<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:b/><w:sz w:val="56"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:pPr><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:sz w:val="28"/></w:rPr></w:style>
+<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblBorders>
+<w:top w:val="single" w:sz="4"/><w:left w:val="single" w:sz="4"/><w:bottom w:val="single" w:sz="4"/>
+<w:right w:val="single" w:sz="4"/><w:insideH w:val="single" w:sz="4"/><w:insideV w:val="single" w:sz="4"/>
+</w:tblBorders></w:tblPr></w:style>
+</w:styles>`,
+	},
+}
+
+// 将 docs 以 docx 的格式输出到 o.Dir 目录下。
+func renderWord(docs *types.Doc, o *Options) error {
+	file, err := os.Create(filepath.Join(o.Dir, vars.WordFileName))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return WriteWordDoc(file, docs, o)
+}
+
+// WriteWordDoc 将 docs 转换成 Word 文档（docx 格式），写入到 w 中。
+//
+// 第一页为标题页，包含文档的标题、版本和介绍；
+// 之后每个组对应一个一级标题，每个 API 对应一个二级标题，参数以表格的形式输出。
+func WriteWordDoc(w io.Writer, docs *types.Doc, o *Options) error {
+	z := zip.NewWriter(w)
+
+	for _, f := range wordFiles {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err = io.WriteString(fw, f.content); err != nil {
+			return err
+		}
+	}
+
+	fw, err := z.Create("word/document.xml")
+	if err != nil {
+		return err
+	}
+	if _, err = wordDocument(docs, o).WriteTo(fw); err != nil {
+		return err
+	}
+
+	return z.Close()
+}
+
+// 生成 word/document.xml 的内容
+func wordDocument(docs *types.Doc, o *Options) *bytes.Buffer {
+	basePath := docs.BasePath
+	if len(o.BasePath) > 0 {
+		basePath = o.BasePath
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>`)
+
+	// 标题页
+	wordParagraph(buf, "Title", docs.Title)
+	if len(docs.Version) > 0 {
+		wordParagraph(buf, "", docs.Version)
+	}
+	if len(docs.BaseURL) > 0 {
+		wordParagraph(buf, "", joinPath(docs.BaseURL, basePath))
+		basePath = ""
+	}
+	wordParagraphs(buf, docs.Content)
+	buf.WriteString(`<w:p><w:r><w:br w:type="page"/></w:r></w:p>`)
+
+	groups := make(map[string][]*types.API, 10)
+	for _, api := range docs.Apis {
+		if o.groupIsEnable(api.Group) {
+			groups[api.Group] = append(groups[api.Group], api)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		apis := groups[name]
+		sortAPIs(apis)
+
+		wordParagraph(buf, "Heading1", name)
+		for _, api := range apis {
+			wordAPI(buf, api, basePath)
+		}
+	}
+
+	buf.WriteString(`</w:body></w:document>`)
+	return buf
+}
+
+func wordAPI(buf *bytes.Buffer, api *types.API, basePath string) {
+	wordParagraph(buf, "Heading2", strings.ToUpper(api.Method)+" "+joinPath(basePath, api.URL)+" "+api.Summary)
+	wordParagraphs(buf, api.Description)
+	for _, note := range api.Notes {
+		wordParagraphs(buf, notesMarkdown([]*types.Note{note}, ""))
+	}
+
+	wordParams(buf, "参数", api.Params)
+	wordParams(buf, "查询参数", api.Queries)
+	if api.Request != nil {
+		wordParams(buf, "请求", api.Request.Params)
+	}
+	for _, resp := range []*types.Response{api.Success, api.Error} {
+		if resp == nil {
+			continue
+		}
+		wordParagraph(buf, "", resp.Code+" "+resp.Summary)
+		wordParams(buf, "", resp.Params)
+	}
+}
+
+// 输出一个段落，style 为空表示使用默认的样式。
+func wordParagraph(buf *bytes.Buffer, style, text string) {
+	buf.WriteString("<w:p>")
+	if len(style) > 0 {
+		buf.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	buf.WriteString(`<w:r><w:t xml:space="preserve">`)
+	xml.EscapeText(buf, []byte(text))
+	buf.WriteString("</w:t></w:r></w:p>")
+}
+
+// 将多行的 text 按行输出为多个段落，忽略空行。
+func wordParagraphs(buf *bytes.Buffer, text string) {
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); len(line) > 0 {
+			wordParagraph(buf, "", line)
+		}
+	}
+}
+
+// 以表格的形式输出参数，title 不为空时，在表格之前输出 title 段落。
+func wordParams(buf *bytes.Buffer, title string, params []*types.Param) {
+	if len(params) == 0 {
+		return
+	}
+
+	if len(title) > 0 {
+		wordParagraph(buf, "", title)
+	}
+
+	buf.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/></w:tblPr>`)
+	wordRow(buf, "名称", "类型", "描述")
+	for _, p := range params {
+		wordRow(buf, p.Name, p.Type, p.Summary)
+	}
+	buf.WriteString("</w:tbl>")
+}
+
+func wordRow(buf *bytes.Buffer, cells ...string) {
+	buf.WriteString("<w:tr>")
+	for _, cell := range cells {
+		buf.WriteString("<w:tc>")
+		wordParagraph(buf, "", cell)
+		buf.WriteString("</w:tc>")
+	}
+	buf.WriteString("</w:tr>")
+}
@@ -0,0 +1,118 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package output
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+
+	"github.com/caixw/apidoc/types"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 读取 docx 中 name 文件的内容
+func readDocx(a *assert.Assertion, data []byte, name string) string {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	a.NotError(err)
+
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+
+		rc, err := f.Open()
+		a.NotError(err)
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		a.NotError(err)
+		return string(content)
+	}
+
+	a.True(false, "未找到 "+name)
+	return ""
+}
+
+func TestWriteWordDoc(t *testing.T) {
+	a := assert.New(t)
+
+	docs := types.NewDoc()
+	docs.Title = "test & title"
+	docs.Version = "1.0.0"
+	docs.Content = "line1\nline2"
+	docs.NewAPI(&types.API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Summary: "get user",
+		Group:   "users",
+		Params:  []*types.Param{{Name: "id", Type: "int", Summary: "<user id>"}},
+		Success: &types.Response{Code: "200", Summary: "OK", Params: []*types.Param{{Name: "name", Type: "string", Summary: "name"}}},
+	})
+	docs.NewAPI(&types.API{Method: "GET", URL: "/admin", Summary: "admin", Group: "admin"})
+
+	buf := new(bytes.Buffer)
+	a.NotError(WriteWordDoc(buf, docs, &Options{}))
+	data := buf.Bytes()
+
+	a.True(strings.Contains(readDocx(a, data, "[Content_Types].xml"), "/word/document.xml"))
+	a.True(strings.Contains(readDocx(a, data, "word/styles.xml"), `w:styleId="Heading1"`))
+	readDocx(a, data, "_rels/.rels")
+	readDocx(a, data, "word/_rels/document.xml.rels")
+
+	doc := readDocx(a, data, "word/document.xml")
+
+	// 是一个合法的 XML 文档
+	d := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		a.NotError(err)
+	}
+
+	a.True(strings.Contains(doc, `<w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">test &amp; title</w:t>`)).
+		True(strings.Contains(doc, `<w:t xml:space="preserve">line2</w:t>`)).
+		True(strings.Contains(doc, `<w:br w:type="page"/>`)).
+		True(strings.Contains(doc, `<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">users</w:t>`)).
+		True(strings.Contains(doc, `<w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t xml:space="preserve">GET /users/{id} get user</w:t>`)).
+		True(strings.Contains(doc, `&lt;user id&gt;`)).
+		Equal(strings.Count(doc, "<w:tbl>"), 2)
+
+	// 组按名称排序
+	a.True(strings.Index(doc, ">admin</w:t>") < strings.Index(doc, ">users</w:t>"))
+
+	// 仅输出指定的组
+	buf.Reset()
+	a.NotError(WriteWordDoc(buf, docs, &Options{Groups: []string{"users"}}))
+	doc = readDocx(a, buf.Bytes(), "word/document.xml")
+	a.False(strings.Contains(doc, "/admin"))
+}
+
+func TestRender_word(t *testing.T) {
+	a := assert.New(t)
+
+	dir, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(dir)
+
+	docs := types.NewDoc()
+	docs.NewAPI(&types.API{Method: "GET", URL: "/users", Summary: "users", Group: "users"})
+
+	o := &Options{Type: TypeWord, Dir: dir}
+	a.NotError(o.Sanitize())
+	a.NotError(Render(docs, o))
+
+	data, err := os.ReadFile(filepath.Join(dir, vars.WordFileName))
+	a.NotError(err)
+	a.True(strings.Contains(readDocx(a, data, "word/document.xml"), "/users"))
+}
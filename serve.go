@@ -0,0 +1,227 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/locale"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/vars"
+)
+
+// 检测源文件是否有变化的时间间隔
+const watchInterval = time.Second
+
+// 提供文档浏览的服务。
+//
+// 文档以 html 的形式生成在临时目录中，源文件有变化时，
+// 会在新的临时目录中重新生成，再替换掉旧的目录。
+// 旧的目录会在所有正在进行的访问结束之后才被删除，不会影响正在进行的访问。
+type server struct {
+	wd           string
+	basePath     string
+	tryItBaseURL string
+
+	mu  sync.RWMutex
+	dir *docDir // 当前文档所在的目录
+
+	modified time.Time // 最后一次生成文档时，源文件的最后修改时间
+	files    int       // 最后一次生成文档时，源文件的数量
+}
+
+// 生成的文档目录
+type docDir struct {
+	path string
+	wg   sync.WaitGroup // 正在访问该目录的请求
+}
+
+// 启动文档服务，并在源文件变化时自动重新生成文档。
+func runServe(wd, addr, basePath, tryItBaseURL string) error {
+	s := &server{wd: wd, basePath: basePath, tryItBaseURL: tryItBaseURL}
+	if err := s.build(); err != nil {
+		return err
+	}
+	defer s.close()
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.watch(watchInterval, done)
+
+	info.Println(locale.Sprintf(locale.FlagServeListening, addr))
+	return http.ListenAndServe(addr, s)
+}
+
+// 重新生成文档
+func (s *server) build() error {
+	cfg, err := load(s.wd)
+	if err != nil {
+		return err
+	}
+
+	modified, files, err := lastModified(s.wd, cfg)
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", vars.Name)
+	if err != nil {
+		return err
+	}
+
+	o := *cfg.Output
+	o.Type = output.TypeHTML
+	o.Dir = dir
+	if len(s.basePath) > 0 {
+		o.BasePath = s.basePath
+	}
+	if len(s.tryItBaseURL) > 0 {
+		o.TryItBaseURL = s.tryItBaseURL
+	}
+
+	docs, elapsed := input.Parse(cfg.Inputs...)
+	for _, err := range docs.Validate() {
+		erro.Println(err)
+	}
+
+	o.Elapsed = elapsed
+	if err := output.Render(docs, &o); err != nil {
+		os.RemoveAll(dir)
+		return err
+	}
+
+	s.mu.Lock()
+	old := s.dir
+	s.dir = &docDir{path: dir}
+	s.modified = modified
+	s.files = files
+	s.mu.Unlock()
+
+	if old == nil {
+		return nil
+	}
+
+	// 替换之后不会再有新的请求访问 old，等待已有的请求结束即可。
+	old.wg.Wait()
+	return os.RemoveAll(old.path)
+}
+
+// 每隔 interval 检测一次源文件，有变化时重新生成文档，直到 done 被关闭。
+func (s *server) watch(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			changed, err := s.changed()
+			if err != nil {
+				erro.Println(err)
+				continue
+			}
+			if !changed {
+				continue
+			}
+
+			if err := s.build(); err != nil {
+				erro.Println(err)
+				continue
+			}
+			info.Println(locale.Sprintf(locale.FlagServeRebuild))
+		}
+	}
+}
+
+// 源文件或是配置文件在最后一次生成文档之后是否有变化
+func (s *server) changed() (bool, error) {
+	cfg, err := load(s.wd)
+	if err != nil {
+		return false, err
+	}
+
+	modified, files, err := lastModified(s.wd, cfg)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !modified.Equal(s.modified) || files != s.files, nil
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	dir := s.dir
+	if dir != nil {
+		dir.wg.Add(1)
+	}
+	s.mu.RUnlock()
+
+	if dir == nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer dir.wg.Done()
+
+	http.FileServer(http.Dir(dir.path)).ServeHTTP(w, r)
+}
+
+// 删除生成的文档
+func (s *server) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dir == nil {
+		return nil
+	}
+	err := os.RemoveAll(s.dir.path)
+	s.dir = nil
+	return err
+}
+
+// 返回配置文件及所有输入目录下文件的最后修改时间和文件数量。
+//
+// 文件的增删不一定会改变最后修改时间，所以同时返回文件数量作为判断依据。
+func lastModified(wd string, cfg *config) (modified time.Time, files int, err error) {
+	fi, err := os.Stat(filepath.Join(wd, vars.ConfigFilename))
+	if err != nil {
+		return modified, 0, err
+	}
+	modified = fi.ModTime()
+
+	for _, o := range cfg.Inputs {
+		walk := func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if fi.IsDir() {
+				if !o.Recursive && path != o.Dir {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
+			files++
+			if fi.ModTime().After(modified) {
+				modified = fi.ModTime()
+			}
+			return nil
+		}
+
+		if err = filepath.Walk(o.Dir, walk); err != nil {
+			return modified, 0, err
+		}
+	}
+
+	return modified, files, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/caixw/apidoc/input"
+	"github.com/caixw/apidoc/output"
+	"github.com/caixw/apidoc/vars"
+)
+
+func TestServer(t *testing.T) {
+	a := assert.New(t)
+
+	wd, err := os.MkdirTemp("", "apidoc")
+	a.NotError(err)
+	defer os.RemoveAll(wd)
+
+	src := filepath.Join(wd, "main.go")
+	code := `package main
+
+// @api get /users users
+// @apiGroup users
+// @apiSuccess 200 OK
+func users() {}
+`
+	a.NotError(os.WriteFile(src, []byte(code), os.ModePerm))
+
+	cfg := &config{
+		Version: vars.Version(),
+		Inputs:  []*input.Options{{Lang: "go", Dir: wd}},
+		Output:  &output.Options{Dir: filepath.Join(wd, "doc")},
+	}
+	data, err := yaml.Marshal(cfg)
+	a.NotError(err)
+	a.NotError(os.WriteFile(filepath.Join(wd, vars.ConfigFilename), data, os.ModePerm))
+
+	s := &server{wd: wd}
+	a.NotError(s.build())
+	defer s.close()
+	first := s.dir.path
+
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/")
+	a.NotError(err)
+	a.Equal(resp.StatusCode, http.StatusOK)
+	resp.Body.Close()
+
+	groupURL := srv.URL + "/" + vars.JSONDataDirName + "/" + vars.GroupFilePrefix + "users.json"
+	a.True(strings.Contains(get(a, groupURL), "/users"))
+
+	// 没有变化
+	changed, err := s.changed()
+	a.NotError(err).False(changed)
+
+	// 修改源文件之后，重新生成文档
+	code = strings.Replace(code, "/users", "/members", -1)
+	a.NotError(os.WriteFile(src, []byte(code), os.ModePerm))
+	future := time.Now().Add(time.Hour) // 防止文件系统的时间精度不够
+	a.NotError(os.Chtimes(src, future, future))
+
+	changed, err = s.changed()
+	a.NotError(err).True(changed)
+	a.NotError(s.build())
+	a.NotEqual(s.dir.path, first)
+	_, err = os.Stat(first)
+	a.True(os.IsNotExist(err)) // 旧的目录已经被删除
+
+	a.True(strings.Contains(get(a, groupURL), "/members"))
+
+	// 有正在进行的访问时，旧的目录在访问结束之后才被删除
+	second := s.dir
+	second.wg.Add(1)
+	built := make(chan error, 1)
+	go func() {
+		built <- s.build()
+	}()
+	for {
+		s.mu.RLock()
+		swapped := s.dir != second
+		s.mu.RUnlock()
+		if swapped {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	a.True(strings.Contains(get(a, groupURL), "/members"))
+	_, err = os.Stat(second.path)
+	a.NotError(err) // 访问未结束，旧的目录依然存在
+	second.wg.Done()
+	a.NotError(<-built)
+	_, err = os.Stat(second.path)
+	a.True(os.IsNotExist(err))
+
+	// 新增文件
+	a.NotError(os.WriteFile(filepath.Join(wd, "other.go"), []byte("package main"), os.ModePerm))
+	a.NotError(os.Chtimes(filepath.Join(wd, "other.go"), future, future))
+	changed, err = s.changed()
+	a.NotError(err).True(changed)
+}
+
+func get(a *assert.Assertion, url string) string {
+	resp, err := http.Get(url)
+	a.NotError(err)
+	defer resp.Body.Close()
+	a.Equal(resp.StatusCode, http.StatusOK)
+
+	data, err := io.ReadAll(resp.Body)
+	a.NotError(err)
+	return string(data)
+}
@@ -4,40 +4,255 @@
 
 package types
 
-import "sync"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+)
 
 // Doc 表示一个项目的完整文档列表。
 type Doc struct {
 	Title       string // 文档标题
 	Version     string // 文档的版本号
 	BaseURL     string // 基地址
+	BasePath    string // 所有 API 地址的前缀，若指定了 BaseURL，则附加在 BaseURL 之后
 	LicenseName string // 文档版权名称
 	LicenseURL  string // 文档版权地址，可忽略
 	Content     string // 首页的简要介绍内容
 	Apis        []*API
 	apisLocker  sync.Mutex // 控制 Apis 字段的多协程写入
+
+	// 可用的认证方式，键名为 Security.Name
+	SecuritySchemes map[string]*Security
+
+	// 官方提供的各语言 SDK，由 @apiSDK 指定
+	SDKs []*SDK
+}
+
+// SDK 表示官方为某一语言提供的 SDK，由 @apiSDK 指定。
+type SDK struct {
+	Language string `json:"language"` // 语言名称，在同一文档中唯一
+	Package  string `json:"package"`  // 包名，比如 github.com/caixw/apidoc
+	URL      string `json:"url"`      // SDK 的地址
+}
+
+// 认证方式的类型
+const (
+	SecurityTypeAPIKey        = "apiKey"
+	SecurityTypeHTTP          = "http"
+	SecurityTypeOAuth2        = "oauth2"
+	SecurityTypeOpenIDConnect = "openIdConnect"
+)
+
+// Security 表示一种认证方式，由 @apiAuth 引用。
+type Security struct {
+	Name   string `json:"name"`             // 名称，供 @apiAuth 引用
+	Type   string `json:"type"`             // 类型，可以是 apiKey、http、oauth2 和 openIdConnect
+	In     string `json:"in,omitempty"`     // apiKey 所在的位置，可以是 header、query 或是 cookie
+	Key    string `json:"key,omitempty"`    // apiKey 的名称
+	Scheme string `json:"scheme,omitempty"` // http 的认证方案，比如 basic、bearer 等
+	URL    string `json:"url,omitempty"`    // oauth2 的 token 地址或是 openIdConnect 的地址
+
+	// oauth2 中可以申请的权限范围，供 @apiScope 引用
+	Scopes []string `json:"scopes,omitempty"`
 }
 
+// 多个权限范围之间的关系
+const (
+	ScopeLogicAll = "all" // 需要所有的权限范围
+	ScopeLogicAny = "any" // 只需要其中任意一个
+)
+
 // API 表示一个 API 文档。
 type API struct {
 	Method      string    `json:"method"`                // 请求的方法，GET，POST 等
 	URL         string    `json:"url"`                   // 请求地址
 	Summary     string    `json:"summary"`               // 简要描述
+	Name        string    `json:"name,omitempty"`        // 对应的代码声明名称，比如 protobuf 中的 rpc 名称
+	GRPCMethod  string    `json:"grpcMethod,omitempty"`  // 对应的 gRPC 方法，格式为 Service.Method，由 @apiGRPC 指定
+	OperationID string    `json:"operationId,omitempty"` // 操作 ID，供 SDK 生成工具用作方法名，由 @apiOperationID 指定
+	GraphQL     *GraphQL  `json:"graphql,omitempty"`     // 对应的 GraphQL 操作，由 @apiGraphQL 指定
 	Description string    `json:"description,omitempty"` // 详细描述
+	Notes       []*Note   `json:"notes,omitempty"`       // 提示信息
 	Group       string    `json:"group,omitempty"`       // 所属分组
+	Safe        bool      `json:"safe,omitempty"`        // 是否为安全的请求，即不会产生副作用
+	Idempotent  bool      `json:"idempotent,omitempty"`  // 是否为幂等的请求，即多次请求的结果相同
+	Multipart   bool      `json:"multipart,omitempty"`   // 请求参数是否以 multipart/form-data 表单的形式提交
+	SSE         bool      `json:"sse,omitempty"`         // 是否为 Server-Sent Events 接口，返回内容为 text/event-stream 的事件流
+	WebSocket   bool      `json:"websocket,omitempty"`   // 是否为 WebSocket 接口，通过 GET 请求升级协议之后双向通信
+	Order       int       `json:"order,omitempty"`       // 在输出中的排序，值越小越靠前，0 表示未指定，排在所有指定值的 API 之后
 	Queries     []*Param  `json:"queries,omitempty"`     // 查询参数
 	Params      []*Param  `json:"params,omitempty"`      // URL 参数
 	Request     *Request  `json:"request,omitempty"`     // 若是 GET，则使用此描述请求的具体数据
 	Success     *Response `json:"success,omitempty"`     // 成功时的响应内容
 	Error       *Response `json:"error,omitempty"`       // 出错时的响应内容
+	Produces    []string  `json:"produces,omitempty"`    // 可返回的内容类型，对应 Accept 报头
+	Consumes    []string  `json:"consumes,omitempty"`    // 可接收的内容类型，对应 Content-Type 报头
+	Auth        []string  `json:"auth,omitempty"`        // 所需要的认证方式，对应 Doc.SecuritySchemes 中的键名
+
+	// SSE 接口可能推送的事件，由 @apiEvent 指定
+	Events []*Event `json:"events,omitempty"`
+
+	// WebSocket 接口中服务端发送和接收的消息，由 @apiWSSend 和 @apiWSReceive 指定
+	WSSend    []*Event `json:"wsSend,omitempty"`
+	WSReceive []*Event `json:"wsReceive,omitempty"`
+
+	// Socket.IO 中各命名空间下的事件，由 @apiSocketIO 指定，按命名空间首次出现的顺序排列
+	SocketIO []*SocketIONamespace `json:"socketIO,omitempty"`
+
+	// 产生和消费的领域事件，由 @apiProducesEvent 和 @apiConsumesEvent 指定
+	ProducedEvents []*Event `json:"producedEvents,omitempty"`
+	ConsumedEvents []*Event `json:"consumedEvents,omitempty"`
+
+	// 访问该 API 所需要的角色或是权限范围，由 @apiAccess 指定
+	AccessRoles   []string `json:"accessRoles,omitempty"`
+	AccessSummary string   `json:"accessSummary,omitempty"` // 对访问控制的补充说明
+
+	// 访问该 API 所需要的 oauth2 权限范围及其之间的关系，由 @apiScope 指定
+	RequiredScopes []string `json:"requiredScopes,omitempty"`
+	ScopeLogic     string   `json:"scopeLogic,omitempty"` // 可以是 all 或是 any
+
+	// 该 API 在处理请求时同步调用的其它 API，由 @apiCalls 指定
+	Calls []Endpoint `json:"calls,omitempty"`
+
+	// 参数值的格式，键名为参数名称，键值为 date、email 等格式，由 @apiFormat 指定
+	Formats map[string]string `json:"formats,omitempty"`
+
+	// 服务端在处理请求之后，向客户端发起的回调请求
+	Callbacks []*Callback `json:"callbacks,omitempty"`
+
+	// 异步处理的设置，为空表示同步处理，由 @apiAsync 指定
+	Async *Async `json:"async,omitempty"`
+
+	// 可以通过 Accept 报头协商的返回内容类型，是 Produces 的子集，
+	// 作为 Success 的内容类型，@apiContentType 指定的值优先。
+	Negotiation []string `json:"negotiation,omitempty"`
+
+	// 应用层面的错误代码，与 HTTP 状态码相互独立
+	ErrorCodes []*ErrorCode `json:"errorCodes,omitempty"`
+
+	// 认证失败时的返回内容，由 @apiAuthError 指定
+	AuthErrors []*AuthError `json:"authErrors,omitempty"`
+
+	// 返回 409 的各种冲突情况，由 @apiConflict 指定
+	Conflicts []*Conflict `json:"conflicts,omitempty"`
+
+	// 以二进制内容返回的数据，比如图片、PDF 等，由 @apiMediaType 指定
+	MediaType *MediaType `json:"mediaType,omitempty"`
+
+	// 请求内容的最大字节数，0 表示不限制，由 @apiContentLength 指定
+	MaxRequestBodyBytes int64 `json:"maxRequestBodyBytes,omitempty"`
+
+	// 尚未完成的文档内容，由 @apiTodo 指定
+	Todos []string `json:"todos,omitempty"`
+
+	// 相关的外部资源
+	Links []*Link `json:"links,omitempty"`
+
+	// 契约测试的结果，由 @apiContract 指定
+	Contracts []*Contract `json:"contracts,omitempty"`
+
+	// 变更记录，按版本号从新到旧排列，由 @apiChangelog 指定
+	Changelog []*ChangelogEntry `json:"changelog,omitempty"`
+
+	// 不兼容的变更，由 @apiBreakingChange 指定
+	BreakingChanges []*BreakingChange `json:"breakingChanges,omitempty"`
+
+	// 运维相关的指标，比如延迟和吞吐量等
+	Metrics []*Metric `json:"metrics,omitempty"`
+
+	// 在不同环境下的特殊说明，由 @apiEnvironment 指定
+	Environments []*EnvironmentNote `json:"environments,omitempty"`
+
+	// 面向的受众，为空表示只面向 AudiencePublic，由 @apiAudience 指定
+	Audiences []string `json:"audiences,omitempty"`
+
+	// 所处理数据的敏感程度，为空表示未指定，由 @apiDataClassification 指定
+	DataClassification string `json:"dataClassification,omitempty"`
+
+	// 负责该 API 的团队，为空表示未指定
+	Owner *Owner `json:"owner,omitempty"`
+
+	// 客户端需要提供的幂等键，为空表示未指定
+	IdempotencyKey *IdempotencyKey `json:"idempotencyKey,omitempty"`
+
+	// 用于关联请求的 ID 所在的报头，为空表示未指定
+	RequestID *RequestID `json:"requestID,omitempty"`
+
+	// 预期的响应时间，为空表示未指定
+	Timeout *Timeout `json:"timeout,omitempty"`
+
+	// 响应时间在各上游服务之间的分配，为空表示未指定，由 @apiTimeBudget 指定
+	TimeBudget *TimeBudget `json:"timeBudget,omitempty"`
+
+	// 服务等级协议，为空表示未指定
+	SLA *SLA `json:"sla,omitempty"`
+
+	// 控制该 API 是否可用的功能开关，由 @apiFeatureFlag 指定
+	FeatureFlag *FeatureFlag `json:"featureFlag,omitempty"`
+
+	// 模拟服务在返回之前等待的毫秒数，0 表示不等待，由 @apiMockDelay 指定
+	MockDelay int `json:"mockDelay,omitempty"`
+
+	// 模拟服务中的访问频率限制，为空表示不限制，由 @apiThrottle 指定
+	Throttle *Throttle `json:"throttle,omitempty"`
+
+	// 较长周期内的使用配额，为空表示不限制，由 @apiQuota 指定
+	Quota *Quota `json:"quota,omitempty"`
+
+	// 批量处理的设置，为空表示不是批量接口，由 @apiBatch 指定
+	Batch *Batch `json:"batch,omitempty"`
+
+	// 重试策略，为空表示未指定
+	Retry *Retry `json:"retry,omitempty"`
+
+	// 客户端熔断器的建议配置，为空表示未指定，由 @apiCircuitBreaker 指定
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty"`
+
+	// 多租户的隔离和区分方式，为空表示不区分租户，由 @apiTenant 指定
+	Tenant *TenantPolicy `json:"tenant,omitempty"`
+
+	// 可用和不可用的区域，为空表示在所有区域都可用，由 @apiRegion 指定
+	Region *RegionPolicy `json:"region,omitempty"`
+
+	// 缓存策略，为空表示未指定
+	CachePolicy *CachePolicy `json:"cachePolicy,omitempty"`
+
+	// 跨域策略，为空表示未指定
+	CORSPolicy *CORSPolicy `json:"corsPolicy,omitempty"`
+
+	// 在线调试的设置，为空表示不提供在线调试的功能
+	TryIt *TryIt `json:"tryIt,omitempty"`
+
+	// 返回的报头，键名为 HTTP 状态码，* 表示适用于所有的状态码
+	ResponseHeaders map[string][]*ResponseHeader `json:"responseHeaders,omitempty"`
+
+	// 各状态码对应的内容类型，会覆盖 Produces 中的值。
+	// 键名为 HTTP 状态码，键值为该状态码下可返回的内容类型。
+	ContentTypes map[string][]string `json:"contentTypes,omitempty"`
+
+	// 以 x- 开头的扩展字段，键名为字段名，键值为 JSON 格式的内容
+	Extensions map[string]json.RawMessage `json:"extensions,omitempty"`
+
+	// 生成 SDK 时各语言的提示信息，由 @apiCodegen 指定。
+	// 键名为语言名称，键值为该语言下的选项名称和值。
+	Codegen map[string]map[string]string `json:"codegen,omitempty"`
 }
 
+// MultipartFormData 为指定了 @apiMultipart 的 API 的请求内容类型，
+// 请求参数中类型为 file 的为文件，其它的为普通的表单字段。
+const MultipartFormData = "multipart/form-data"
+
 // Request 表示用户请求所表示的数据。
 type Request struct {
 	Type     string            `json:"type"`              // 请求所支持的数据类型，多个用逗号分隔
 	Headers  map[string]string `json:"headers,omitempty"` // 请求必须携带的头
 	Params   []*Param          `json:"params,omitempty"`  // 提交的各个字段的描述
 	Examples []*Example        `json:"example,omitempty"` // 请求数据的示例
+
+	// 多态的请求内容中，用于区分具体类型的字段
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
 }
 
 // Response 表示一次请求或是返回的数据。
@@ -48,25 +263,492 @@ type Response struct {
 	Headers  map[string]string `json:"headers,omitempty"`  // 返回的头信息。
 	Params   []*Param          `json:"params,omitempty"`   // 返回数据的各个字段描述
 	Examples []*Example        `json:"examples,omitempty"` // 返回数据的示例
+
+	// 多态的返回内容中，用于区分具体类型的字段
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+}
+
+// Discriminator 表示多态的请求或是返回内容中，用于区分具体类型的字段，
+// 由 @apiRequest、@apiSuccess 和 @apiError 中的 @apiDiscriminator 指定。
+type Discriminator struct {
+	Property string            `json:"property"`          // 字段名称，需要由 @apiParam 声明
+	Mapping  map[string]string `json:"mapping,omitempty"` // 字段的值与具体类型的对应关系
 }
 
 // Param 用于描述提交和返回的参数信息。
 type Param struct {
-	Name    string `json:"name"`    // 参数名称
-	Type    string `json:"type"`    // 类型
-	Summary string `json:"summary"` // 参数介绍
+	Name     string `json:"name"`               // 参数名称
+	Type     string `json:"type"`               // 类型
+	Summary  string `json:"summary"`            // 参数介绍
+	Nullable bool   `json:"nullable,omitempty"` // 是否可以为 null，由 @apiNullable 指定
+}
+
+// ResponseHeader 表示返回的报头，由 @apiResponseHeader 指定。
+type ResponseHeader struct {
+	Name    string `json:"name"`    // 报头名称
+	Type    string `json:"type"`    // 报头值的类型
+	Summary string `json:"summary"` // 报头介绍
+}
+
+// IdempotencyKey 表示客户端需要在报头中提供的幂等键，由 @apiIdempotencyKey 指定。
+//
+// 服务端根据该值识别重复的请求，常见于支付和预订等非幂等的 API。
+type IdempotencyKey struct {
+	Header  string `json:"header"`  // 报头名称，比如 Idempotency-Key
+	Summary string `json:"summary"` // 描述
+}
+
+// 请求 ID 的提供方式
+const (
+	RequestIDAuto     = "auto"     // 客户端未提供时，由服务端生成
+	RequestIDRequired = "required" // 客户端必须提供
+	RequestIDOptional = "optional" // 客户端可以提供，未提供时也不会生成
+)
+
+// RequestID 表示用于在多个服务之间关联请求的报头，由 @apiRequestID 指定。
+//
+// 请求和返回内容中都会包含该报头，返回的值与请求中的值相同。
+type RequestID struct {
+	Header string `json:"header"` // 报头名称，比如 X-Request-ID
+	Mode   string `json:"mode"`   // 提供方式，可以是 auto、required 和 optional
+}
+
+// 响应时间的统计方式
+const (
+	PercentileP50 = "p50" // 50% 的请求在此时间内返回
+	PercentileP90 = "p90" // 90% 的请求在此时间内返回
+	PercentileP99 = "p99" // 99% 的请求在此时间内返回
+	PercentileMax = "max" // 所有请求都在此时间内返回
+)
+
+// Timeout 表示 API 预期的响应时间，由 @apiTimeout 指定。
+//
+// 客户端可以据此设置请求的超时时间。
+type Timeout struct {
+	Value      int    `json:"value"`      // 响应时间，单位为毫秒
+	Percentile string `json:"percentile"` // 统计方式，可以是 p50、p90、p99 和 max
+}
+
+// TimeBudget 表示 API 的响应时间预算及其在上游服务之间的分配，由 @apiTimeBudget 指定。
+//
+// 总时间中未分配给上游服务的部分，即为服务本身的处理时间。
+type TimeBudget struct {
+	Total     int               `json:"total"`               // 总的响应时间，单位为毫秒
+	Upstreams []*UpstreamBudget `json:"upstreams,omitempty"` // 分配给各上游服务的时间
+}
+
+// UpstreamBudget 表示分配给某一上游服务的时间
+type UpstreamBudget struct {
+	Service string `json:"service"` // 上游服务的名称
+	Value   int    `json:"value"`   // 分配的时间，单位为毫秒
+}
+
+// Allocated 返回分配给所有上游服务的时间之和
+func (b *TimeBudget) Allocated() int {
+	sum := 0
+	for _, u := range b.Upstreams {
+		sum += u.Value
+	}
+	return sum
+}
+
+// 访问频率限制的时间窗口
+const (
+	ThrottleWindowSecond = "second"
+	ThrottleWindowMinute = "minute"
+	ThrottleWindowHour   = "hour"
+)
+
+// Throttle 表示模拟服务中的访问频率限制，由 @apiThrottle 指定。
+//
+// 每个客户端在一个时间窗口内最多只能访问 Requests 次，超出的返回 429。
+type Throttle struct {
+	Requests int    `json:"requests"` // 时间窗口内允许的请求次数
+	Window   string `json:"window"`   // 时间窗口，可以是 second、minute 和 hour
+}
+
+// Seconds 返回时间窗口的秒数
+func (t *Throttle) Seconds() int {
+	switch t.Window {
+	case ThrottleWindowMinute:
+		return 60
+	case ThrottleWindowHour:
+		return 3600
+	default:
+		return 1
+	}
+}
+
+// 使用配额的统计周期
+const (
+	QuotaWindowDay   = "day"
+	QuotaWindowMonth = "month"
+	QuotaWindowYear  = "year"
+)
+
+// 使用配额的计算范围
+const (
+	QuotaScopeOrg  = "org"  // 按组织计算
+	QuotaScopeUser = "user" // 按用户计算
+	QuotaScopeKey  = "key"  // 按 API Key 计算
+)
+
+// Quota 表示 API 在较长周期内的使用配额，由 @apiQuota 指定。
+//
+// 与 Throttle 不同，配额在整个周期内累计，用完之后需要等到下一个周期才能继续使用。
+type Quota struct {
+	Limit  int    `json:"limit"`           // 周期内允许的请求次数
+	Window string `json:"window"`          // 统计周期，可以是 day、month 和 year
+	Scope  string `json:"scope,omitempty"` // 计算范围，可以是 org、user 和 key，为空表示未指定
+}
+
+// 批量请求中各子请求的处理方式
+const (
+	BatchParallel   = "parallel"   // 并行处理，返回结果的顺序与请求一致
+	BatchSequential = "sequential" // 按顺序依次处理
+)
+
+// Batch 表示 API 可以在一次请求中包含多个子请求，由 @apiBatch 指定。
+//
+// 请求内容为 {"items": [...]}，返回内容为 {"results": [...]}，
+// 数组元素分别为 @apiRequest 和 @apiSuccess 中定义的内容。
+type Batch struct {
+	MaxItems int    `json:"maxItems"` // 单次请求最多包含的子请求数量
+	Mode     string `json:"mode"`     // 处理方式，可以是 parallel 和 sequential
+}
+
+// 功能开关的提供方
+const (
+	FeatureFlagLaunchDarkly = "launchDarkly"
+	FeatureFlagStatsig      = "statsig"
+	FeatureFlagCustom       = "custom"
+)
+
+// FeatureFlag 表示 API 受某一功能开关控制，由 @apiFeatureFlag 指定。
+type FeatureFlag struct {
+	Name     string `json:"name"`               // 开关的名称
+	Provider string `json:"provider,omitempty"` // 提供方，可以是 launchDarkly、statsig 和 custom，为空表示未指定
+}
+
+// SLA 表示 API 的服务等级协议，由 @apiSLA 指定。
+type SLA struct {
+	Availability float64 `json:"availability"`  // 可用性，以百分比表示，取值范围为 (0, 100]
+	RPO          string  `json:"rpo,omitempty"` // 恢复点目标，即最多可能丢失多长时间的数据
+	RTO          string  `json:"rto,omitempty"` // 恢复时间目标，即故障后多长时间内恢复服务
+}
+
+// Owner 表示负责 API 的团队，由 @apiOwner 指定。
+type Owner struct {
+	Team  string `json:"team"`            // 团队名称
+	Email string `json:"email,omitempty"` // 团队的联系邮箱
+}
+
+// Metric 表示 API 的服务指标，比如 P99 延迟、错误预算等，由 @apiMetric 指定。
+type Metric struct {
+	Name  string  `json:"name"`  // 指标名称，在同一 API 中唯一，比如 p99-latency
+	Value float64 `json:"value"` // 指标的值
+	Unit  string  `json:"unit"`  // 值的单位，比如 ms、%、rps 等
+}
+
+// Link 表示与 API 相关的外部资源，比如需求文档和 RFC 等，由 @apiLinkTo 指定。
+type Link struct {
+	URL   string `json:"url"`             // 资源的地址
+	Label string `json:"label,omitempty"` // 显示的文字，为空表示直接显示 URL
+}
+
+// Contract 表示 API 的契约测试，由 @apiContract 指定。
+type Contract struct {
+	Suite string `json:"suite"` // 测试套件的名称，在同一 API 中唯一
+	URL   string `json:"url"`   // 测试结果的地址，比如 Pact Broker 中的页面
+}
+
+// ErrorCode 表示 API 可能返回的应用层面的错误，由 @apiThrows 指定。
+//
+// 比如 gRPC 的状态码或是自定义的错误代码，这些错误可能共用同一个 HTTP 状态码。
+type ErrorCode struct {
+	Code    string `json:"code"`    // 错误代码，在同一 API 中唯一
+	Summary string `json:"summary"` // 错误的描述
+}
+
+// AuthError 表示认证失败时的返回内容，由 @apiAuthError 指定。
+type AuthError struct {
+	Code    string `json:"code"`    // HTTP 状态码，只能是 401 或是 403
+	Schema  string `json:"schema"`  // 返回内容的类型名称
+	Summary string `json:"summary"` // 出错的原因
+}
+
+// Conflict 表示返回 409 的一种冲突情况，由 @apiConflict 指定。
+//
+// 同一 API 中的多种冲突情况共同组成 409 的返回内容，以 Condition 区分。
+type Conflict struct {
+	Condition string `json:"condition"` // 冲突的名称，比如 version-mismatch，在同一 API 中唯一
+	Schema    string `json:"schema"`    // 返回内容的类型名称
+	Summary   string `json:"summary"`   // 冲突的原因
+}
+
+// GraphQL 操作的类型
+const (
+	GraphQLQuery        = "query"
+	GraphQLMutation     = "mutation"
+	GraphQLSubscription = "subscription"
+)
+
+// GraphQL 表示与 API 功能相同的 GraphQL 操作，由 @apiGraphQL 指定。
+type GraphQL struct {
+	Operation string `json:"operation"` // 操作名称
+	Type      string `json:"type"`      // 操作类型，可以是 query、mutation 和 subscription
+}
+
+// MediaType 表示 API 返回的二进制内容，由 @apiMediaType 指定。
+type MediaType struct {
+	Type      string `json:"type"`                // 内容类型，比如 application/pdf
+	Extension string `json:"extension,omitempty"` // 下载时使用的文件扩展名，不包含 .
+}
+
+// ChangelogEntry 表示 API 在某一版本中的变更，由 @apiChangelog 指定。
+type ChangelogEntry struct {
+	Version     string `json:"version"`     // 版本号
+	Date        string `json:"date"`        // 发布日期，格式为 YYYY-MM-DD
+	Description string `json:"description"` // 变更内容
+}
+
+// BreakingChange 表示 API 在某一版本中不向后兼容的变更，由 @apiBreakingChange 指定。
+type BreakingChange struct {
+	Version     string `json:"version"`     // 引入该变更的版本号，不带 v 前缀
+	Description string `json:"description"` // 变更内容
+}
+
+// Event 表示 SSE 接口推送的一种事件，由 @apiEvent 指定；
+// 或是 WebSocket 接口收发的一种消息，由 @apiWSSend 和 @apiWSReceive 指定；
+// 或是 API 产生和消费的领域事件，由 @apiProducesEvent 和 @apiConsumesEvent 指定。
+type Event struct {
+	Name    string `json:"name"`              // 名称，SSE 中对应事件流的 event 字段，在同一 API 的同类型中唯一
+	Type    string `json:"type"`              // 数据的类型，SSE 中对应事件流的 data 字段，领域事件中为其结构的引用
+	Summary string `json:"summary,omitempty"` // 描述
+}
+
+// Socket.IO 事件的方向
+const (
+	SocketIOEmit = "emit" // 由服务端发送给客户端
+	SocketIOOn   = "on"   // 由客户端发送给服务端
+)
+
+// SocketIONamespace 表示 Socket.IO 中的一个命名空间及其下的事件。
+type SocketIONamespace struct {
+	Namespace string           `json:"namespace"` // 命名空间，以 / 开头
+	Events    []*SocketIOEvent `json:"events"`
+}
+
+// SocketIOEvent 表示 Socket.IO 中的一种事件，由 @apiSocketIO 指定。
+type SocketIOEvent struct {
+	Event     string `json:"event"`     // 事件名称
+	Direction string `json:"direction"` // 方向，SocketIOEmit 或是 SocketIOOn
+	Type      string `json:"type"`      // 事件数据的结构引用
+	Summary   string `json:"summary"`   // 描述
+}
+
+// Callback 表示服务端在处理请求之后，向客户端发起的回调请求，由 @apiCallback 指定。
+type Callback struct {
+	Name       string `json:"name"`       // 名称，在同一 API 中唯一
+	Expression string `json:"expression"` // 回调地址的运行时表达式，比如 {$request.body#/callbackUrl}
+	Method     string `json:"method"`     // 回调请求的方法
+	Path       string `json:"path"`       // 附加在 Expression 之后的路径
+}
+
+// Async 表示接口以异步的方式处理请求，由 @apiAsync 指定。
+//
+// 服务端接受请求之后立即返回 202 和任务 ID，客户端通过轮询 PollURL
+// 或是等待服务端推送来获取处理结果。
+type Async struct {
+	PollURL        string `json:"pollUrl,omitempty"`        // 查询任务状态的地址模板，比如 /jobs/{jobId}
+	CallbackHeader string `json:"callbackHeader,omitempty"` // 客户端通过该报头指定接收推送的地址
+}
+
+// AsyncJobID 为异步接口在 202 返回内容中表示任务 ID 的字段名
+const AsyncJobID = "jobId"
+
+// CachePolicy 表示返回内容的缓存策略，由 @apiCacheControl 指定。
+type CachePolicy struct {
+	MaxAge  int      `json:"maxAge,omitempty"`  // 缓存的秒数，0 表示未指定
+	NoCache bool     `json:"noCache,omitempty"` // 每次使用缓存之前，都需要向服务端验证
+	NoStore bool     `json:"noStore,omitempty"` // 不能缓存
+	Private bool     `json:"private,omitempty"` // 仅允许客户端缓存
+	Public  bool     `json:"public,omitempty"`  // 允许中间代理缓存
+	Vary    []string `json:"vary,omitempty"`    // 缓存所依赖的请求报头
+}
+
+// CORSPolicy 表示跨域资源共享的策略，由 @apiCORS 指定。
+type CORSPolicy struct {
+	Origin  string   `json:"origin,omitempty"`  // 允许的来源，* 表示任意来源
+	Methods []string `json:"methods,omitempty"` // 允许的请求方法
+	Headers []string `json:"headers,omitempty"` // 允许的请求报头
+	MaxAge  int      `json:"maxAge,omitempty"`  // 预检请求结果的缓存秒数，0 表示未指定
+}
+
+// String 返回 Cache-Control 报头的值
+func (c *CachePolicy) String() string {
+	directives := make([]string, 0, 5)
+	if c.MaxAge > 0 {
+		directives = append(directives, "max-age="+strconv.Itoa(c.MaxAge))
+	}
+	if c.NoCache {
+		directives = append(directives, "no-cache")
+	}
+	if c.NoStore {
+		directives = append(directives, "no-store")
+	}
+	if c.Private {
+		directives = append(directives, "private")
+	}
+	if c.Public {
+		directives = append(directives, "public")
+	}
+	return strings.Join(directives, ", ")
+}
+
+// 重试的退避方式
+const (
+	BackoffLinear      = "linear"
+	BackoffExponential = "exponential"
+)
+
+// Retry 表示客户端的重试策略，由 @apiRetry 指定。
+type Retry struct {
+	Strategy    string `json:"strategy"`              // 重试策略的名称
+	MaxAttempts int    `json:"maxAttempts,omitempty"` // 最大的尝试次数，0 表示未指定
+	Backoff     string `json:"backoff,omitempty"`     // 退避方式，可以是 linear 或是 exponential
+	RetryOn     []int  `json:"retryOn,omitempty"`     // 需要重试的状态码
+}
+
+// CircuitBreaker 表示客户端熔断器的建议配置，由 @apiCircuitBreaker 指定。
+type CircuitBreaker struct {
+	Threshold        int    `json:"threshold,omitempty"`        // 触发熔断的错误率，以百分比表示，0 表示未指定
+	Timeout          string `json:"timeout,omitempty"`          // 熔断之后，多长时间进入半开状态
+	HalfOpenRequests int    `json:"halfOpenRequests,omitempty"` // 半开状态下允许通过的请求数量，0 表示未指定
+}
+
+// 租户的隔离方式
+const (
+	TenantIsolationShared    = "shared"
+	TenantIsolationDedicated = "dedicated"
+)
+
+// 区分租户的方式
+const (
+	TenantScopingHeader    = "header"
+	TenantScopingPath      = "path"
+	TenantScopingSubdomain = "subdomain"
+)
+
+// DefaultTenantHeader 未指定报头名称时，通过此报头区分租户。
+const DefaultTenantHeader = "X-Tenant-ID"
+
+// TenantPolicy 表示多租户的隔离和区分方式，由 @apiTenant 指定。
+type TenantPolicy struct {
+	Isolation string `json:"isolation,omitempty"` // 隔离方式，可以是 shared 和 dedicated，为空表示未指定
+	Scoping   string `json:"scoping,omitempty"`   // 区分方式，可以是 header、path 和 subdomain，为空表示未指定
+	Header    string `json:"header,omitempty"`    // Scoping 为 header 时，携带租户 ID 的报头
+}
+
+// RegionPolicy 表示 API 在哪些区域可用，由 @apiRegion 指定。
+type RegionPolicy struct {
+	Available   []string `json:"available,omitempty"`   // 可用的区域，为空表示未指定
+	Unavailable []string `json:"unavailable,omitempty"` // 不可用的区域
+}
+
+// TryIt 表示在线调试的设置，由 @apiTryIt 指定。
+type TryIt struct {
+	BaseURL string `json:"baseURL"`         // 发起请求时使用的基地址
+	Token   string `json:"token,omitempty"` // 请求时附带的 Authorization 报头
+}
+
+// CacheHeaders 返回由 @apiCacheControl 产生的 Cache-Control 和 Vary 报头，
+// 这些报头仅适用于成功时的返回内容。
+func (api *API) CacheHeaders() []*ResponseHeader {
+	if api.CachePolicy == nil {
+		return nil
+	}
+
+	headers := make([]*ResponseHeader, 0, 2)
+	if cc := api.CachePolicy.String(); len(cc) > 0 {
+		headers = append(headers, &ResponseHeader{Name: "Cache-Control", Type: "string", Summary: cc})
+	}
+	if len(api.CachePolicy.Vary) > 0 {
+		headers = append(headers, &ResponseHeader{Name: "Vary", Type: "string", Summary: strings.Join(api.CachePolicy.Vary, ", ")})
+	}
+	return headers
+}
+
+// ResponseHeadersOf 返回状态码 code 对应的报头，包含了适用于所有状态码的报头。
+func (api *API) ResponseHeadersOf(code string) []*ResponseHeader {
+	headers := make([]*ResponseHeader, 0, len(api.ResponseHeaders[code])+len(api.ResponseHeaders["*"]))
+	headers = append(headers, api.ResponseHeaders["*"]...)
+	return append(headers, api.ResponseHeaders[code]...)
+}
+
+// 提示信息的类型
+const (
+	NoteTypeInfo    = "info"
+	NoteTypeWarning = "warning"
+	NoteTypeDanger  = "danger"
+	NoteTypeTip     = "tip"
+)
+
+// Note 表示一条提示信息，由 @apiNote 指定。
+type Note struct {
+	Type string `json:"type"` // 类型，可以是 info、warning、danger 和 tip
+	Text string `json:"text"` // 提示内容
+}
+
+// 环境的名称，EnvironmentAll 表示适用于所有的环境。
+const (
+	EnvironmentProd    = "prod"
+	EnvironmentStaging = "staging"
+	EnvironmentDev     = "dev"
+	EnvironmentAll     = "all"
+)
+
+// 文档的受众
+const (
+	AudiencePublic   = "public"
+	AudiencePartner  = "partner"
+	AudienceInternal = "internal"
+)
+
+// 数据的分级，按敏感程度从高到低排列
+const (
+	DataClassificationPII          = "pii"          // 个人身份信息
+	DataClassificationSensitive    = "sensitive"    // 敏感数据
+	DataClassificationConfidential = "confidential" // 内部机密数据
+	DataClassificationPublic       = "public"       // 公开数据
+)
+
+// DataClassifications 所有的数据分级，按敏感程度从高到低排列。
+var DataClassifications = []string{
+	DataClassificationPII,
+	DataClassificationSensitive,
+	DataClassificationConfidential,
+	DataClassificationPublic,
+}
+
+// EnvironmentNote 表示 API 在某一环境下的特殊说明，由 @apiEnvironment 指定。
+type EnvironmentNote struct {
+	Environment string `json:"environment"` // 环境名称，可以是 prod、staging、dev 和 all
+	Text        string `json:"text"`        // 说明内容
 }
 
 // Example 表示示例代码
 type Example struct {
-	Type string `json:"type"` // 示例代码的语言类型
+	Type string `json:"type"` // 示例代码的内容类型
+	Lang string `json:"lang"` // 代码高亮所使用的语言
 	Code string `json:"code"` // 示例代码
 }
 
 // NewDoc 声明一个 Doc 对象。
 func NewDoc() *Doc {
 	return &Doc{
-		Apis: make([]*API, 0, 100),
+		Apis:            make([]*API, 0, 100),
+		SecuritySchemes: make(map[string]*Security, 5),
 	}
 }
 
@@ -76,3 +758,59 @@ func (d *Doc) NewAPI(api *API) {
 	d.Apis = append(d.Apis, api)
 	d.apisLocker.Unlock()
 }
+
+// FilterEnvironment 去掉所有与 env 无关的环境说明，
+// 只保留环境为 env 或是 EnvironmentAll 的内容。
+func (d *Doc) FilterEnvironment(env string) {
+	for _, api := range d.Apis {
+		notes := api.Environments[:0]
+		for _, note := range api.Environments {
+			if note.Environment == env || note.Environment == EnvironmentAll {
+				notes = append(notes, note)
+			}
+		}
+
+		if len(notes) == 0 {
+			notes = nil
+		}
+		api.Environments = notes
+	}
+}
+
+// ForAudience 返回只包含面向 audience 的 API 的文档。
+//
+// 返回的是一个新的 Doc 实例，d 本身不会被修改，但两者共用相同的 API 实例。
+func (d *Doc) ForAudience(audience string) *Doc {
+	doc := &Doc{
+		Title:           d.Title,
+		Version:         d.Version,
+		BaseURL:         d.BaseURL,
+		BasePath:        d.BasePath,
+		LicenseName:     d.LicenseName,
+		LicenseURL:      d.LicenseURL,
+		Content:         d.Content,
+		Apis:            make([]*API, 0, len(d.Apis)),
+		SecuritySchemes: d.SecuritySchemes,
+	}
+
+	for _, api := range d.Apis {
+		if api.HasAudience(audience) {
+			doc.Apis = append(doc.Apis, api)
+		}
+	}
+	return doc
+}
+
+// HasAudience 是否面向 audience，未指定 @apiAudience 的只面向 AudiencePublic。
+func (api *API) HasAudience(audience string) bool {
+	if len(api.Audiences) == 0 {
+		return audience == AudiencePublic
+	}
+
+	for _, a := range api.Audiences {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
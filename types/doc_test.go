@@ -0,0 +1,113 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestAPI_ResponseHeadersOf(t *testing.T) {
+	a := assert.New(t)
+
+	api := &API{}
+	a.Empty(api.ResponseHeadersOf("200"))
+
+	all := &ResponseHeader{Name: "X-Request-ID", Type: "string"}
+	location := &ResponseHeader{Name: "Location", Type: "string"}
+	api.ResponseHeaders = map[string][]*ResponseHeader{
+		"*":   {all},
+		"201": {location},
+	}
+	a.Equal(api.ResponseHeadersOf("201"), []*ResponseHeader{all, location})
+	a.Equal(api.ResponseHeadersOf("400"), []*ResponseHeader{all})
+}
+
+func TestCachePolicy_String(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal((&CachePolicy{}).String(), "")
+	a.Equal((&CachePolicy{MaxAge: 3600}).String(), "max-age=3600")
+	a.Equal((&CachePolicy{NoCache: true}).String(), "no-cache")
+	a.Equal((&CachePolicy{NoStore: true}).String(), "no-store")
+	a.Equal((&CachePolicy{Private: true}).String(), "private")
+	a.Equal((&CachePolicy{Public: true}).String(), "public")
+	a.Equal((&CachePolicy{MaxAge: 60, Public: true}).String(), "max-age=60, public")
+}
+
+func TestThrottle_Seconds(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal((&Throttle{Requests: 1, Window: ThrottleWindowSecond}).Seconds(), 1)
+	a.Equal((&Throttle{Requests: 1, Window: ThrottleWindowMinute}).Seconds(), 60)
+	a.Equal((&Throttle{Requests: 1, Window: ThrottleWindowHour}).Seconds(), 3600)
+}
+
+func TestAPI_CacheHeaders(t *testing.T) {
+	a := assert.New(t)
+
+	api := &API{}
+	a.Nil(api.CacheHeaders())
+
+	api.CachePolicy = &CachePolicy{Vary: []string{"Accept", "Accept-Encoding"}}
+	a.Equal(api.CacheHeaders(), []*ResponseHeader{
+		{Name: "Vary", Type: "string", Summary: "Accept, Accept-Encoding"},
+	})
+
+	api.CachePolicy.MaxAge = 60
+	a.Equal(api.CacheHeaders(), []*ResponseHeader{
+		{Name: "Cache-Control", Type: "string", Summary: "max-age=60"},
+		{Name: "Vary", Type: "string", Summary: "Accept, Accept-Encoding"},
+	})
+}
+
+func TestDoc_FilterEnvironment(t *testing.T) {
+	a := assert.New(t)
+
+	prod := &EnvironmentNote{Environment: EnvironmentProd, Text: "prod"}
+	staging := &EnvironmentNote{Environment: EnvironmentStaging, Text: "staging"}
+	all := &EnvironmentNote{Environment: EnvironmentAll, Text: "all"}
+
+	d := NewDoc()
+	d.NewAPI(&API{Environments: []*EnvironmentNote{prod, staging, all}})
+	d.NewAPI(&API{Environments: []*EnvironmentNote{staging}})
+	d.NewAPI(&API{})
+
+	d.FilterEnvironment(EnvironmentProd)
+	a.Equal(d.Apis[0].Environments, []*EnvironmentNote{prod, all})
+	a.Nil(d.Apis[1].Environments)
+	a.Nil(d.Apis[2].Environments)
+}
+
+func TestDoc_ForAudience(t *testing.T) {
+	a := assert.New(t)
+
+	pub := &API{URL: "/public"} // 未指定，默认为 public
+	partner := &API{URL: "/partner", Audiences: []string{AudiencePartner}}
+	both := &API{URL: "/both", Audiences: []string{AudiencePublic, AudiencePartner}}
+	internal := &API{URL: "/internal", Audiences: []string{AudienceInternal}}
+
+	d := NewDoc()
+	d.Title = "test"
+	d.Version = "1.0.0"
+	d.SecuritySchemes["token"] = &Security{Name: "token", Type: SecurityTypeHTTP, Scheme: "bearer"}
+	for _, api := range []*API{pub, partner, both, internal} {
+		d.NewAPI(api)
+	}
+
+	doc := d.ForAudience(AudiencePublic)
+	a.Equal(doc.Apis, []*API{pub, both}).
+		Equal(doc.Title, "test").
+		Equal(doc.Version, "1.0.0").
+		Equal(len(doc.SecuritySchemes), 1)
+
+	a.Equal(d.ForAudience(AudiencePartner).Apis, []*API{partner, both})
+	a.Equal(d.ForAudience(AudienceInternal).Apis, []*API{internal})
+	a.Empty(d.ForAudience("unknown").Apis)
+
+	// 原文档不受影响
+	a.Equal(len(d.Apis), 4)
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WriteGoTypes 将文档中的请求和返回参数转换成 Go 的结构体定义，写入到 w 中。
+//
+// 每个 API 的 @apiRequest、@apiSuccess 和 @apiError 中的参数各生成一个结构体，
+// 名称由 API.Name 或是请求方法和地址组成，再分别加上 Request、Success 和 Error 后缀。
+// 参数名中的 . 表示子元素，会被转换成嵌套的结构体。
+func (d *Doc) WriteGoTypes(w io.Writer, packageName string) error {
+	apis := make([]*API, len(d.Apis))
+	copy(apis, d.Apis)
+	sort.SliceStable(apis, func(i, j int) bool {
+		if apis[i].URL != apis[j].URL {
+			return apis[i].URL < apis[j].URL
+		}
+		return apis[i].Method < apis[j].Method
+	})
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by apidoc. DO NOT EDIT.\n\n")
+	buf.WriteString("package " + packageName + "\n")
+
+	names := make(map[string]int, len(apis)*2)
+	for _, api := range apis {
+		prefix := goName(api.Name)
+		if len(prefix) == 0 {
+			prefix = goName(strings.ToLower(api.Method) + " " + api.URL)
+		}
+
+		if api.Request != nil {
+			writeGoStruct(buf, names, prefix+"Request", api.Summary, api.Request.Params)
+		}
+		if api.Success != nil {
+			writeGoStruct(buf, names, prefix+"Success", api.Success.Summary, api.Success.Params)
+		}
+		if api.Error != nil {
+			writeGoStruct(buf, names, prefix+"Error", api.Error.Summary, api.Error.Params)
+		}
+	}
+
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+// 参数组成的树状结构，对应结构体中的一个字段。
+type goField struct {
+	param    *Param
+	children []*goField
+}
+
+func writeGoStruct(buf *bytes.Buffer, names map[string]int, name, summary string, params []*Param) {
+	if len(params) == 0 {
+		return
+	}
+
+	// 名称相同的结构体，加上数字后缀以示区别
+	names[name]++
+	if n := names[name]; n > 1 {
+		name += strconv.Itoa(n)
+	}
+
+	buf.WriteString("\n// " + name + " " + summary + "\n")
+	buf.WriteString("type " + name + " ")
+	writeGoFields(buf, buildGoFields(params))
+	buf.WriteString("\n")
+}
+
+// 将 a.b 形式的参数名转换成树状结构，父元素不存在的参数放在顶层。
+func buildGoFields(params []*Param) []*goField {
+	root := []*goField{}
+	fields := make(map[string]*goField, len(params))
+
+	for _, p := range params {
+		f := &goField{param: p}
+		fields[p.Name] = f
+
+		index := strings.LastIndexByte(p.Name, '.')
+		if index <= 0 {
+			root = append(root, f)
+			continue
+		}
+
+		if parent, found := fields[p.Name[:index]]; found {
+			parent.children = append(parent.children, f)
+		} else {
+			root = append(root, f)
+		}
+	}
+
+	return root
+}
+
+func writeGoFields(buf *bytes.Buffer, fields []*goField) {
+	buf.WriteString("struct {\n")
+	for _, f := range fields {
+		name := f.param.Name
+		if index := strings.LastIndexByte(name, '.'); index >= 0 {
+			name = name[index+1:]
+		}
+
+		if len(f.param.Summary) > 0 {
+			buf.WriteString("// " + strings.Replace(f.param.Summary, "\n", " ", -1) + "\n")
+		}
+		buf.WriteString(goName(name) + " ")
+
+		typ := goType(f.param.Type)
+		switch {
+		case len(f.children) == 0:
+			buf.WriteString(typ)
+		case typ == "[]interface{}":
+			buf.WriteString("[]")
+			writeGoFields(buf, f.children)
+		default:
+			writeGoFields(buf, f.children)
+		}
+
+		buf.WriteString(" `json:\"" + name + "\"`\n")
+	}
+	buf.WriteString("}")
+}
+
+// 将参数类型转换成 Go 的类型，无法识别的类型统一为 interface{}。
+func goType(typ string) string {
+	switch strings.ToLower(typ) {
+	case "int", "integer", "int32", "int64", "uint", "long":
+		return "int64"
+	case "float", "double", "number", "float32", "float64":
+		return "float64"
+	case "bool", "boolean":
+		return "bool"
+	case "string", "file":
+		return "string"
+	case "array":
+		return "[]interface{}"
+	case "object":
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+// 将任意字符串转换成导出的 Go 标识符，比如 get /users/{id} 转换成 GetUsersId。
+func goName(s string) string {
+	buf := new(bytes.Buffer)
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+
+	name := buf.String()
+	if len(name) > 0 && unicode.IsDigit(rune(name[0])) {
+		name = "X" + name
+	}
+	return name
+}
@@ -0,0 +1,83 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestDoc_WriteGoTypes(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.NewAPI(&API{
+		Method: "POST",
+		URL:    "/users",
+		Request: &Request{Params: []*Param{
+			{Name: "name", Type: "string", Summary: "用户名"},
+			{Name: "age", Type: "int", Summary: "年龄"},
+			{Name: "tags", Type: "array", Summary: "标签"},
+			{Name: "tags.name", Type: "string", Summary: "标签名"},
+			{Name: "profile", Type: "object", Summary: "资料"},
+			{Name: "profile.score", Type: "float", Summary: "积分"},
+			{Name: "extra", Type: "object", Summary: "扩展"},
+		}},
+		Success: &Response{Code: "201", Summary: "created", Params: []*Param{
+			{Name: "id", Type: "integer", Summary: "id"},
+		}},
+		Error: &Response{Code: "400", Summary: "bad request"},
+	})
+	d.NewAPI(&API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Name:    "getUser",
+		Success: &Response{Code: "200", Summary: "OK", Params: []*Param{{Name: "admin", Type: "bool"}}},
+	})
+
+	buf := new(bytes.Buffer)
+	a.NotError(d.WriteGoTypes(buf, "client"))
+	src := buf.String()
+	a.True(strings.HasPrefix(src, "// Code generated by apidoc. DO NOT EDIT.\n"))
+
+	// 生成的代码能正常编译
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "client.go", src, parser.ParseComments)
+	a.NotError(err)
+	a.Equal(f.Name.Name, "client")
+	pkg, err := (&gotypes.Config{}).Check("client", fset, []*ast.File{f}, nil)
+	a.NotError(err)
+
+	// 没有参数的 PostUsersError 不会生成
+	a.Equal(pkg.Scope().Names(), []string{"GetUserSuccess", "PostUsersRequest", "PostUsersSuccess"})
+
+	req := pkg.Scope().Lookup("PostUsersRequest").Type().Underlying().(*gotypes.Struct)
+	a.Equal(req.NumFields(), 5)
+	a.Equal(req.Field(0).Name(), "Name").Equal(req.Field(0).Type().String(), "string").Equal(req.Tag(0), `json:"name"`)
+	a.Equal(req.Field(1).Type().String(), "int64")
+	a.Equal(req.Field(2).Type().String(), "[]struct{Name string \"json:\\\"name\\\"\"}")
+	a.Equal(req.Field(3).Type().String(), "struct{Score float64 \"json:\\\"score\\\"\"}")
+	a.Equal(req.Field(4).Type().String(), "map[string]interface{}")
+
+	// 无效的包名
+	a.Error(d.WriteGoTypes(new(bytes.Buffer), "1client"))
+}
+
+func TestGoName(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(goName("get /users/{id}"), "GetUsersId")
+	a.Equal(goName("user_name"), "UserName")
+	a.Equal(goName("getUser"), "GetUser")
+	a.Equal(goName("2fa"), "X2fa")
+	a.Equal(goName(""), "")
+}
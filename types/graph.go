@@ -0,0 +1,139 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Endpoint 表示调用关系中的一个 API，由 @apiCalls 指定被调用的 API。
+type Endpoint struct {
+	Method string `json:"method"` // 请求方法，统一为大写
+	URL    string `json:"url"`    // 请求地址，不包含查询参数
+}
+
+func (e Endpoint) String() string {
+	return e.Method + " " + e.URL
+}
+
+// EndpointGraph 表示 API 之间的同步调用关系，是一个有向图。
+type EndpointGraph struct {
+	// 所有的节点，包括未在文档中定义，但被 @apiCalls 引用的 API。
+	// 按地址和请求方法排序。
+	Nodes []Endpoint
+
+	// 键名表示调用方，键值为其调用的 API，顺序与 @apiCalls 的顺序相同。
+	Edges map[Endpoint][]Endpoint
+}
+
+// ToGraph 根据 @apiCalls 生成各个 API 之间的调用关系图。
+func (d *Doc) ToGraph() *EndpointGraph {
+	g := &EndpointGraph{
+		Nodes: make([]Endpoint, 0, len(d.Apis)),
+		Edges: make(map[Endpoint][]Endpoint, len(d.Apis)),
+	}
+
+	exists := make(map[Endpoint]bool, len(d.Apis))
+	add := func(e Endpoint) {
+		if !exists[e] {
+			exists[e] = true
+			g.Nodes = append(g.Nodes, e)
+		}
+	}
+
+	for _, api := range d.Apis {
+		from := api.Endpoint()
+		add(from)
+
+		for _, to := range api.Calls {
+			add(to)
+			g.Edges[from] = append(g.Edges[from], to)
+		}
+	}
+
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		if g.Nodes[i].URL != g.Nodes[j].URL {
+			return g.Nodes[i].URL < g.Nodes[j].URL
+		}
+		return g.Nodes[i].Method < g.Nodes[j].Method
+	})
+
+	return g
+}
+
+// Endpoint 返回表示当前 API 的 Endpoint 实例
+func (api *API) Endpoint() Endpoint {
+	url := api.URL
+	if index := strings.IndexByte(url, '?'); index >= 0 {
+		url = url[:index]
+	}
+
+	return Endpoint{Method: strings.ToUpper(api.Method), URL: url}
+}
+
+// Cycles 查找所有的循环调用。
+//
+// 每个循环只返回一次，以 Nodes 中排序最靠前的节点作为起点，
+// 最后一个节点调用了第一个节点。自己调用自己也算作一个循环。
+func (g *EndpointGraph) Cycles() [][]Endpoint {
+	index := make(map[Endpoint]int, len(g.Nodes))
+	for i, n := range g.Nodes {
+		index[n] = i
+	}
+
+	cycles := make([][]Endpoint, 0, 5)
+	for i, start := range g.Nodes {
+		path := []Endpoint{start}
+		visited := map[Endpoint]bool{start: true}
+
+		// 只访问排在 start 之后的节点，保证每个循环只被找到一次。
+		var visit func(Endpoint)
+		visit = func(n Endpoint) {
+			for _, next := range g.Edges[n] {
+				if next == start {
+					cycle := make([]Endpoint, len(path))
+					copy(cycle, path)
+					cycles = append(cycles, cycle)
+					continue
+				}
+
+				if visited[next] || index[next] < i {
+					continue
+				}
+
+				visited[next] = true
+				path = append(path, next)
+				visit(next)
+				path = path[:len(path)-1]
+				visited[next] = false
+			}
+		}
+		visit(start)
+	}
+
+	return cycles
+}
+
+// Dot 将调用关系转换成 Graphviz 的 DOT 格式
+func (g *EndpointGraph) Dot() string {
+	buf := new(bytes.Buffer)
+	buf.WriteString("digraph apidoc {\n")
+
+	for _, n := range g.Nodes {
+		buf.WriteString("    " + strconv.Quote(n.String()) + ";\n")
+	}
+
+	for _, from := range g.Nodes {
+		for _, to := range g.Edges[from] {
+			buf.WriteString("    " + strconv.Quote(from.String()) + " -> " + strconv.Quote(to.String()) + ";\n")
+		}
+	}
+
+	buf.WriteString("}\n")
+	return buf.String()
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func newGraphDoc(calls map[string][]string) *Doc {
+	d := NewDoc()
+	for url, targets := range calls {
+		api := &API{Method: "get", URL: url}
+		for _, target := range targets {
+			api.Calls = append(api.Calls, Endpoint{Method: "GET", URL: target})
+		}
+		d.NewAPI(api)
+	}
+	return d
+}
+
+func endpoint(url string) Endpoint {
+	return Endpoint{Method: "GET", URL: url}
+}
+
+func TestDoc_ToGraph(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.NewAPI(&API{Method: "post", URL: "/orders?async=bool", Calls: []Endpoint{
+		{Method: "GET", URL: "/users/{id}"},
+		{Method: "POST", URL: "/payments"},
+	}})
+	d.NewAPI(&API{Method: "get", URL: "/users/{id}"})
+
+	g := d.ToGraph()
+	a.Equal(g.Nodes, []Endpoint{
+		{Method: "POST", URL: "/orders"},
+		{Method: "POST", URL: "/payments"}, // 未在文档中定义
+		{Method: "GET", URL: "/users/{id}"},
+	})
+	a.Equal(g.Edges, map[Endpoint][]Endpoint{
+		{Method: "POST", URL: "/orders"}: {{Method: "GET", URL: "/users/{id}"}, {Method: "POST", URL: "/payments"}},
+	})
+}
+
+func TestEndpointGraph_Cycles(t *testing.T) {
+	a := assert.New(t)
+
+	// 没有循环
+	g := newGraphDoc(map[string][]string{
+		"/a": {"/b", "/c"},
+		"/b": {"/c"},
+		"/c": nil,
+	}).ToGraph()
+	a.Empty(g.Cycles())
+
+	// 自己调用自己
+	g = newGraphDoc(map[string][]string{"/a": {"/a"}}).ToGraph()
+	a.Equal(g.Cycles(), [][]Endpoint{{endpoint("/a")}})
+
+	// a -> b -> c -> a
+	g = newGraphDoc(map[string][]string{
+		"/a": {"/b"},
+		"/b": {"/c"},
+		"/c": {"/a"},
+	}).ToGraph()
+	a.Equal(g.Cycles(), [][]Endpoint{{endpoint("/a"), endpoint("/b"), endpoint("/c")}})
+
+	// 起点不是排序最靠前的节点，依然从 /a 开始
+	g = newGraphDoc(map[string][]string{
+		"/x": {"/b"},
+		"/b": {"/a"},
+		"/a": {"/b"},
+	}).ToGraph()
+	a.Equal(g.Cycles(), [][]Endpoint{{endpoint("/a"), endpoint("/b")}})
+
+	// 多个共享节点的循环
+	g = newGraphDoc(map[string][]string{
+		"/a": {"/b", "/c"},
+		"/b": {"/a", "/c"},
+		"/c": {"/a"},
+	}).ToGraph()
+	a.Equal(g.Cycles(), [][]Endpoint{
+		{endpoint("/a"), endpoint("/b")},
+		{endpoint("/a"), endpoint("/b"), endpoint("/c")},
+		{endpoint("/a"), endpoint("/c")},
+	})
+}
+
+func TestEndpointGraph_Dot(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.NewAPI(&API{Method: "post", URL: "/orders", Calls: []Endpoint{
+		{Method: "GET", URL: "/users/{id}"},
+		{Method: "POST", URL: "/payments"},
+	}})
+	d.NewAPI(&API{Method: "post", URL: "/payments", Calls: []Endpoint{{Method: "POST", URL: "/orders"}}})
+	d.NewAPI(&API{Method: "get", URL: "/health"})
+
+	a.Equal(d.ToGraph().Dot(), `digraph apidoc {
+    "GET /health";
+    "POST /orders";
+    "POST /payments";
+    "GET /users/{id}";
+    "POST /orders" -> "GET /users/{id}";
+    "POST /orders" -> "POST /payments";
+    "POST /payments" -> "POST /orders";
+}
+`)
+
+	a.Equal(NewDoc().ToGraph().Dot(), "digraph apidoc {\n}\n")
+}
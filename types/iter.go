@@ -0,0 +1,49 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import "context"
+
+// Endpoints 返回所有 API 对应的 Endpoint，顺序与 Apis 相同。
+func (d *Doc) Endpoints() []Endpoint {
+	endpoints := make([]Endpoint, 0, len(d.Apis))
+	for e := range d.Iter() {
+		endpoints = append(endpoints, e)
+	}
+	return endpoints
+}
+
+// Iter 依次返回所有 API 对应的 Endpoint，顺序与 Apis 相同。
+//
+// 与 Endpoints 不同，不需要一次性分配所有的内容，适合只遍历一次的场景。
+// 调用方必须读完通道中的所有内容，否则产生数据的协程无法退出，
+// 需要中途退出的，应该使用 IterContext。
+func (d *Doc) Iter() <-chan Endpoint {
+	return d.IterContext(context.Background())
+}
+
+// IterContext 与 Iter 相同，但是在 ctx 取消之后，会关闭通道并退出协程。
+func (d *Doc) IterContext(ctx context.Context) <-chan Endpoint {
+	ch := make(chan Endpoint)
+
+	go func() {
+		defer close(ch)
+
+		for _, api := range d.Apis {
+			// select 在多个分支同时就绪时是随机选择的，需要先判断 ctx 是否已经取消。
+			if ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case ch <- api.Endpoint():
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
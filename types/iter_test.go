@@ -0,0 +1,88 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/issue9/assert"
+)
+
+func newIterDoc(size int) *Doc {
+	d := NewDoc()
+	for i := 0; i < size; i++ {
+		d.NewAPI(&API{Method: "get", URL: "/users/" + strconv.Itoa(i) + "?fields=string"})
+	}
+	return d
+}
+
+func TestDoc_Endpoints(t *testing.T) {
+	a := assert.New(t)
+
+	a.Empty(NewDoc().Endpoints())
+
+	d := newIterDoc(3)
+	a.Equal(d.Endpoints(), []Endpoint{
+		{Method: "GET", URL: "/users/0"},
+		{Method: "GET", URL: "/users/1"},
+		{Method: "GET", URL: "/users/2"},
+	})
+}
+
+func TestDoc_Iter(t *testing.T) {
+	a := assert.New(t)
+
+	d := newIterDoc(100)
+	i := 0
+	for e := range d.Iter() {
+		a.Equal(e, d.Apis[i].Endpoint())
+		i++
+	}
+	a.Equal(i, 100)
+
+	// 空文档，通道直接关闭
+	_, ok := <-NewDoc().Iter()
+	a.False(ok)
+}
+
+func TestDoc_IterContext(t *testing.T) {
+	a := assert.New(t)
+
+	d := newIterDoc(1000)
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := d.IterContext(ctx)
+	for i := 0; i < 10; i++ {
+		a.Equal(<-ch, d.Apis[i].Endpoint())
+	}
+	cancel()
+
+	// 取消之后，最多还能读到一个已经在发送中的值，之后通道关闭
+	count := 0
+	for range ch {
+		count++
+	}
+	a.True(count <= 1)
+
+	// 协程已经退出
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	a.True(runtime.NumGoroutine() <= before)
+
+	// 已经取消的 ctx
+	ch = d.IterContext(ctx)
+	count = 0
+	for range ch {
+		count++
+	}
+	a.True(count <= 1)
+}
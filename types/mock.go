@@ -0,0 +1,228 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"go/format"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 模拟服务的固定代码，routes、defaultPort 和 defaultMaxDelay 由 WriteMockServer 生成。
+const mockServerCode = `
+type route struct {
+	method      string
+	pattern     string
+	status      int
+	contentType string
+	body        string
+	delay       int // 返回之前等待的毫秒数
+	throttle    int // 每个客户端在 window 秒内允许的请求次数，0 表示不限制
+	window      int
+}
+
+// 等待时间的上限，单位为毫秒
+var maxDelay int
+
+// 记录各个客户端在当前时间窗口内的请求次数
+type throttleKey struct {
+	route  *route
+	client string
+}
+
+type throttleCounter struct {
+	start time.Time
+	count int
+}
+
+var (
+	counters      = map[throttleKey]*throttleCounter{}
+	countersMutex sync.Mutex
+)
+
+func main() {
+	port := flag.String("port", defaultPort, "监听的地址，可以只指定端口号")
+	flag.IntVar(&maxDelay, "max-delay", defaultMaxDelay, "返回之前等待时间的上限，单位为毫秒")
+	flag.Parse()
+
+	addr := *port
+	if !strings.Contains(addr, ":") {
+		addr = ":" + addr
+	}
+
+	log.Printf("listening on %s\n", addr)
+	log.Fatal(http.ListenAndServe(addr, http.HandlerFunc(serve)))
+}
+
+func serve(w http.ResponseWriter, r *http.Request) {
+	for _, route := range routes {
+		if route.method != r.Method || !match(route.pattern, r.URL.Path) {
+			continue
+		}
+
+		if route.throttle > 0 {
+			if wait := throttle(route, r); wait > 0 {
+				w.Header().Set("Retry-After", strconv.Itoa(wait))
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		if delay := route.delay; delay > 0 {
+			if delay > maxDelay {
+				delay = maxDelay
+			}
+			time.Sleep(time.Duration(delay) * time.Millisecond)
+		}
+
+		if route.contentType != "" {
+			w.Header().Set("Content-Type", route.contentType)
+		}
+		w.WriteHeader(route.status)
+		io.WriteString(w, route.body)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// 记录一次对 route 的请求，超出限制时返回需要等待的秒数，否则返回 0。
+func throttle(route *route, r *http.Request) int {
+	client, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		client = r.RemoteAddr
+	}
+	key := throttleKey{route: route, client: client}
+	window := time.Duration(route.window) * time.Second
+
+	countersMutex.Lock()
+	defer countersMutex.Unlock()
+
+	now := time.Now()
+	c, found := counters[key]
+	if !found || now.Sub(c.start) >= window {
+		c = &throttleCounter{start: now}
+		counters[key] = c
+	}
+
+	if c.count >= route.throttle {
+		return int((c.start.Add(window).Sub(now) + time.Second - 1) / time.Second)
+	}
+	c.count++
+	return 0
+}
+
+// path 是否与 pattern 匹配，pattern 中的 {name} 可以匹配任意非空的路径片段。
+func match(pattern, path string) bool {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	ss := strings.Split(strings.Trim(path, "/"), "/")
+	if len(ps) != len(ss) {
+		return false
+	}
+
+	for i, p := range ps {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if ss[i] == "" {
+				return false
+			}
+			continue
+		}
+
+		if p != ss[i] {
+			return false
+		}
+	}
+
+	return true
+}
+`
+
+// WriteMockServer 将文档转换成一个独立的 Go 程序，写入到 w 中。
+//
+// 生成的程序只依赖标准库，每个 API 以 @apiSuccess 中的状态码和第一个示例作为返回内容，
+// 其它请求一律返回 404。addr 为程序 -port 参数的默认值。
+//
+// 指定了 @apiMockDelay 的 API 会在返回之前等待相应的时间，但不会超过 maxDelay 毫秒，
+// maxDelay 为程序 -max-delay 参数的默认值；指定了 @apiThrottle 的 API，
+// 同一客户端的请求超出限制时返回 429，并通过 Retry-After 报头指定需要等待的秒数。
+func (d *Doc) WriteMockServer(w io.Writer, addr string, maxDelay int) error {
+	apis := make([]*API, len(d.Apis))
+	copy(apis, d.Apis)
+	sort.SliceStable(apis, func(i, j int) bool {
+		if apis[i].URL != apis[j].URL {
+			return apis[i].URL < apis[j].URL
+		}
+		return apis[i].Method < apis[j].Method
+	})
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by apidoc. DO NOT EDIT.\n\n")
+	buf.WriteString("package main\n\n")
+	buf.WriteString("import (\n\"flag\"\n\"io\"\n\"log\"\n\"net\"\n\"net/http\"\n\"strconv\"\n\"strings\"\n\"sync\"\n\"time\"\n)\n\n")
+
+	buf.WriteString("const defaultPort = " + strconv.Quote(addr) + "\n\n")
+	buf.WriteString("const defaultMaxDelay = " + strconv.Itoa(maxDelay) + "\n\n")
+	buf.WriteString("var routes = []*route{\n")
+	for _, api := range apis {
+		writeMockRoute(buf, d.BasePath, api)
+	}
+	buf.WriteString("}\n")
+
+	buf.WriteString(mockServerCode)
+
+	data, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func writeMockRoute(buf *bytes.Buffer, basePath string, api *API) {
+	path := api.URL
+	if index := strings.IndexByte(path, '?'); index >= 0 {
+		path = path[:index]
+	}
+	path = strings.TrimSuffix(basePath, "/") + "/" + strings.TrimPrefix(path, "/")
+
+	status := http.StatusOK
+	var contentType, body string
+	if resp := api.Success; resp != nil {
+		if code, err := strconv.Atoi(resp.Code); err == nil {
+			status = code
+		}
+
+		if len(resp.Examples) > 0 {
+			contentType = resp.Examples[0].Type
+			body = resp.Examples[0].Code
+		}
+	}
+	if len(contentType) == 0 && len(api.Produces) > 0 {
+		contentType = api.Produces[0]
+	}
+	if len(contentType) > 0 && strings.IndexByte(contentType, '/') < 0 {
+		contentType = "application/" + contentType
+	}
+
+	buf.WriteString("{")
+	buf.WriteString("method: " + strconv.Quote(strings.ToUpper(api.Method)) + ", ")
+	buf.WriteString("pattern: " + strconv.Quote(path) + ", ")
+	buf.WriteString("status: " + strconv.Itoa(status) + ", ")
+	buf.WriteString("contentType: " + strconv.Quote(contentType) + ", ")
+	buf.WriteString("body: " + strconv.Quote(body))
+	if api.MockDelay > 0 {
+		buf.WriteString(", delay: " + strconv.Itoa(api.MockDelay))
+	}
+	if api.Throttle != nil {
+		buf.WriteString(", throttle: " + strconv.Itoa(api.Throttle.Requests))
+		buf.WriteString(", window: " + strconv.Itoa(api.Throttle.Seconds()))
+	}
+	buf.WriteString("},\n")
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestDoc_WriteMockServer(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.BasePath = "/v1/"
+	d.NewAPI(&API{
+		Method: "get",
+		URL:    "/users/{id}",
+		Success: &Response{
+			Code:     "200",
+			Examples: []*Example{{Type: "json", Code: "{\"id\": 1, \"name\": \"100%\"}"}},
+		},
+	})
+	d.NewAPI(&API{
+		Method:   "DELETE",
+		URL:      "users/{id}?force=bool",
+		Produces: []string{"text/plain"},
+		Success:  &Response{Code: "204"},
+	})
+	d.NewAPI(&API{Method: "POST", URL: "/users", MockDelay: 50, Throttle: &Throttle{Requests: 10, Window: ThrottleWindowMinute}})
+
+	buf := new(bytes.Buffer)
+	a.NotError(d.WriteMockServer(buf, ":8080", 500))
+	src := buf.String()
+	a.True(strings.HasPrefix(src, "// Code generated by apidoc. DO NOT EDIT.\n"))
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.go", src, 0)
+	a.NotError(err)
+	a.Equal(f.Name.Name, "main")
+
+	a.True(strings.Contains(src, `const defaultPort = ":8080"`)).
+		True(strings.Contains(src, `const defaultMaxDelay = 500`)).
+		True(strings.Contains(src, `{method: "DELETE", pattern: "/v1/users/{id}", status: 204, contentType: "text/plain", body: ""}`)).
+		True(strings.Contains(src, `{method: "GET", pattern: "/v1/users/{id}", status: 200, contentType: "application/json", body: "{\"id\": 1, \"name\": \"100%\"}"}`)).
+		True(strings.Contains(src, `{method: "POST", pattern: "/v1/users", status: 200, contentType: "", body: "", delay: 50, throttle: 10, window: 60}`))
+}
@@ -0,0 +1,82 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+// Stats 文档的统计信息，用于衡量文档的完整程度。
+type Stats struct {
+	Total       int     `json:"total"`       // API 的总数
+	Description int     `json:"description"` // 带有详细描述的 API 数量
+	Params      int     `json:"params"`      // 至少有一个参数描述的 API 数量
+	Responses   int     `json:"responses"`   // 返回内容有参数或是示例描述的 API 数量
+	Coverage    float64 `json:"coverage"`    // 同时带有详细描述和参数描述的 API 所占的百分比
+	Todos       int     `json:"todos"`       // @apiTodo 的数量
+
+	// 各数据分级的 API 数量，键名为 @apiDataClassification 的值，未指定的不计入
+	Classifications map[string]int `json:"classifications,omitempty"`
+}
+
+// Stats 统计文档的完整程度
+func (d *Doc) Stats() *Stats {
+	s := &Stats{Total: len(d.Apis)}
+	if s.Total == 0 {
+		return s
+	}
+
+	covered := 0
+	for _, api := range d.Apis {
+		desc := len(api.Description) > 0
+		params := api.HasParams()
+
+		if desc {
+			s.Description++
+		}
+		if params {
+			s.Params++
+		}
+		if api.Success.documented() || api.Error.documented() {
+			s.Responses++
+		}
+		if desc && params {
+			covered++
+		}
+		s.Todos += len(api.Todos)
+
+		if c := api.DataClassification; len(c) > 0 {
+			if s.Classifications == nil {
+				s.Classifications = make(map[string]int, len(DataClassifications))
+			}
+			s.Classifications[c]++
+		}
+	}
+
+	s.Coverage = float64(covered) * 100 / float64(s.Total)
+	return s
+}
+
+// Uncovered 返回未被计入 Stats.Coverage 的 API，
+// 即缺少详细描述或是参数描述的 API。
+func (d *Doc) Uncovered() []*API {
+	apis := make([]*API, 0, len(d.Apis))
+	for _, api := range d.Apis {
+		if len(api.Description) == 0 || !api.HasParams() {
+			apis = append(apis, api)
+		}
+	}
+	return apis
+}
+
+// HasParams 是否至少有一个参数的描述，包括查询参数、URL 参数和请求参数。
+func (api *API) HasParams() bool {
+	if len(api.Queries) > 0 || len(api.Params) > 0 {
+		return true
+	}
+
+	return api.Request != nil && len(api.Request.Params) > 0
+}
+
+// 返回内容是否有参数或是示例的描述
+func (resp *Response) documented() bool {
+	return resp != nil && (len(resp.Params) > 0 || len(resp.Examples) > 0)
+}
@@ -0,0 +1,97 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestDoc_Stats(t *testing.T) {
+	a := assert.New(t)
+
+	// 空文档
+	d := NewDoc()
+	s := d.Stats()
+	a.Equal(s.Total, 0).Equal(s.Coverage, 0)
+
+	// 所有内容都完整
+	d.NewAPI(&API{
+		Description: "desc",
+		Queries:     []*Param{{Name: "page", Type: "int", Summary: "page"}},
+		Success:     &Response{Code: "200", Params: []*Param{{Name: "id", Type: "int", Summary: "id"}}},
+	})
+	s = d.Stats()
+	a.Equal(s.Total, 1).
+		Equal(s.Description, 1).
+		Equal(s.Params, 1).
+		Equal(s.Responses, 1).
+		Equal(s.Coverage, 100).
+		Equal(s.Todos, 0)
+
+	// 仅有请求参数
+	d.NewAPI(&API{
+		Request: &Request{Params: []*Param{{Name: "id", Type: "int", Summary: "id"}}},
+		Success: &Response{Code: "200"},
+	})
+	s = d.Stats()
+	a.Equal(s.Total, 2).
+		Equal(s.Description, 1).
+		Equal(s.Params, 2).
+		Equal(s.Responses, 1).
+		Equal(s.Coverage, 50)
+
+	// 仅有描述和错误信息的示例
+	d.NewAPI(&API{
+		Description: "desc",
+		Success:     &Response{Code: "204"},
+		Error:       &Response{Code: "400", Examples: []*Example{{Type: "json", Code: "{}"}}},
+	})
+	d.NewAPI(&API{Success: &Response{Code: "204"}, Todos: []string{"参数", "返回值"}})
+	s = d.Stats()
+	a.Equal(s.Total, 4).
+		Equal(s.Description, 2).
+		Equal(s.Params, 2).
+		Equal(s.Responses, 2).
+		Equal(s.Coverage, 25).
+		Equal(s.Todos, 2)
+}
+
+func TestDoc_Stats_classifications(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	a.Nil(d.Stats().Classifications)
+
+	d.NewAPI(&API{URL: "/users", DataClassification: DataClassificationPII})
+	d.NewAPI(&API{URL: "/orders", DataClassification: DataClassificationPII})
+	d.NewAPI(&API{URL: "/payments", DataClassification: DataClassificationSensitive})
+	d.NewAPI(&API{URL: "/reports", DataClassification: DataClassificationConfidential})
+	d.NewAPI(&API{URL: "/news", DataClassification: DataClassificationPublic})
+	d.NewAPI(&API{URL: "/health"}) // 未指定的不计入
+	a.Equal(d.Stats().Classifications, map[string]int{
+		DataClassificationPII:          2,
+		DataClassificationSensitive:    1,
+		DataClassificationConfidential: 1,
+		DataClassificationPublic:       1,
+	})
+}
+
+func TestDoc_Uncovered(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	a.Empty(d.Uncovered())
+
+	d.NewAPI(&API{URL: "/covered", Description: "desc", Params: []*Param{{Name: "id", Type: "int", Summary: "id"}}})
+	d.NewAPI(&API{URL: "/no-desc", Params: []*Param{{Name: "id", Type: "int", Summary: "id"}}})
+	d.NewAPI(&API{URL: "/no-params", Description: "desc"})
+
+	apis := d.Uncovered()
+	a.Equal(len(apis), 2).
+		Equal(apis[0].URL, "/no-desc").
+		Equal(apis[1].URL, "/no-params")
+}
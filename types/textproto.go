@@ -0,0 +1,160 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WriteTextProto 将文档以 Protocol Buffers 的 text format 格式写入到 w 中。
+//
+// 内容对应 schema/apidoc.proto 中的 DocSet，API 按地址和请求方法排序。
+// 与 proto3 的行为相同，值为空的字段不会输出。
+func (d *Doc) WriteTextProto(w io.Writer) error {
+	apis := make([]*API, len(d.Apis))
+	copy(apis, d.Apis)
+	sort.SliceStable(apis, func(i, j int) bool {
+		if apis[i].URL != apis[j].URL {
+			return apis[i].URL < apis[j].URL
+		}
+		return apis[i].Method < apis[j].Method
+	})
+
+	p := &textProtoWriter{buf: new(bytes.Buffer)}
+	p.string("title", d.Title)
+	p.string("version", d.Version)
+	p.string("base_url", d.BaseURL)
+	p.string("base_path", d.BasePath)
+	p.string("license_name", d.LicenseName)
+	p.string("license_url", d.LicenseURL)
+
+	for _, api := range apis {
+		p.begin("endpoints")
+		p.string("method", strings.ToUpper(api.Method))
+		p.string("url", api.URL)
+		p.string("summary", api.Summary)
+		p.string("description", api.Description)
+		p.string("group", api.Group)
+		p.string("name", api.Name)
+		p.params("queries", api.Queries)
+		p.params("params", api.Params)
+		if req := api.Request; req != nil {
+			p.begin("request")
+			p.string("type", req.Type)
+			p.params("params", req.Params)
+			p.end()
+		}
+		p.response("success", api.Success)
+		p.response("error", api.Error)
+		for _, mimetype := range api.Produces {
+			p.string("produces", mimetype)
+		}
+		for _, mimetype := range api.Consumes {
+			p.string("consumes", mimetype)
+		}
+		p.end()
+	}
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+type textProtoWriter struct {
+	buf   *bytes.Buffer
+	depth int
+}
+
+func (p *textProtoWriter) field(name string) {
+	p.buf.WriteString(strings.Repeat("  ", p.depth))
+	p.buf.WriteString(name)
+}
+
+func (p *textProtoWriter) begin(name string) {
+	p.field(name)
+	p.buf.WriteString(" {\n")
+	p.depth++
+}
+
+func (p *textProtoWriter) end() {
+	p.depth--
+	p.buf.WriteString(strings.Repeat("  ", p.depth))
+	p.buf.WriteString("}\n")
+}
+
+func (p *textProtoWriter) string(name, value string) {
+	if len(value) == 0 {
+		return
+	}
+
+	p.field(name)
+	p.buf.WriteString(": ")
+	p.buf.WriteString(textProtoQuote(value))
+	p.buf.WriteByte('\n')
+}
+
+func (p *textProtoWriter) bool(name string, value bool) {
+	if value {
+		p.field(name)
+		p.buf.WriteString(": true\n")
+	}
+}
+
+func (p *textProtoWriter) params(name string, params []*Param) {
+	for _, param := range params {
+		p.begin(name)
+		p.string("name", param.Name)
+		p.string("type", param.Type)
+		p.string("summary", param.Summary)
+		p.bool("nullable", param.Nullable)
+		p.end()
+	}
+}
+
+func (p *textProtoWriter) response(name string, resp *Response) {
+	if resp == nil {
+		return
+	}
+
+	p.begin(name)
+	p.string("code", resp.Code)
+	p.string("summary", resp.Summary)
+	p.params("params", resp.Params)
+	p.end()
+}
+
+// 将 s 转换成 text format 中的字符串，
+// 非 ASCII 字符原样输出，控制字符以八进制的形式转义。
+func textProtoQuote(s string) string {
+	buf := make([]byte, 0, len(s)+2)
+	buf = append(buf, '"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			buf = append(buf, `\"`...)
+		case '\\':
+			buf = append(buf, `\\`...)
+		case '\n':
+			buf = append(buf, `\n`...)
+		case '\r':
+			buf = append(buf, `\r`...)
+		case '\t':
+			buf = append(buf, `\t`...)
+		default:
+			if c < 0x20 || c == 0x7f {
+				o := strconv.FormatInt(int64(c), 8)
+				buf = append(buf, '\\')
+				buf = append(buf, strings.Repeat("0", 3-len(o))...)
+				buf = append(buf, o...)
+			} else {
+				buf = append(buf, c)
+			}
+		}
+	}
+	return string(append(buf, '"'))
+}
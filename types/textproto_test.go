@@ -0,0 +1,171 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"text/scanner"
+
+	"github.com/issue9/assert"
+)
+
+// 解析后的 text format 消息，键名为字段名，
+// 键值为字段的所有值，可以是 string、bool 或是嵌套的 textProtoMessage。
+type textProtoMessage map[string][]interface{}
+
+func (m textProtoMessage) message(name string, index int) textProtoMessage {
+	return m[name][index].(textProtoMessage)
+}
+
+// 简单的 text format 解析，仅支持 WriteTextProto 会输出的内容。
+func parseTextProto(src string) (textProtoMessage, error) {
+	s := &scanner.Scanner{}
+	s.Init(strings.NewReader(src))
+	s.Mode = scanner.ScanIdents | scanner.ScanStrings
+
+	return parseTextProtoMessage(s, scanner.EOF)
+}
+
+func parseTextProtoMessage(s *scanner.Scanner, end rune) (textProtoMessage, error) {
+	m := textProtoMessage{}
+	for {
+		tok := s.Scan()
+		if tok == end {
+			return m, nil
+		}
+		if tok != scanner.Ident {
+			return nil, fmt.Errorf("%s: 无效的字段名 %s", s.Position, s.TokenText())
+		}
+		name := s.TokenText()
+
+		switch s.Scan() {
+		case '{':
+			sub, err := parseTextProtoMessage(s, '}')
+			if err != nil {
+				return nil, err
+			}
+			m[name] = append(m[name], sub)
+		case ':':
+			switch s.Scan() {
+			case scanner.String:
+				v, err := strconv.Unquote(s.TokenText())
+				if err != nil {
+					return nil, err
+				}
+				m[name] = append(m[name], v)
+			case scanner.Ident:
+				v, err := strconv.ParseBool(s.TokenText())
+				if err != nil {
+					return nil, err
+				}
+				m[name] = append(m[name], v)
+			default:
+				return nil, fmt.Errorf("%s: 无效的值 %s", s.Position, s.TokenText())
+			}
+		default:
+			return nil, fmt.Errorf("%s: 无效的字符 %s", s.Position, s.TokenText())
+		}
+	}
+}
+
+func TestDoc_WriteTextProto(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.Title = "测试文档"
+	d.Version = "1.0.0"
+	d.BaseURL = "https://api.example.com"
+	d.NewAPI(&API{
+		Method:      "post",
+		URL:         "/users",
+		Summary:     "添加用户",
+		Description: "第一行\n第二行，包含 \"引号\"、\\ 和\t制表符\x01",
+		Group:       "users",
+		Request: &Request{Type: "json", Params: []*Param{
+			{Name: "name", Type: "string", Summary: "名称"},
+			{Name: "email", Type: "string", Summary: "邮箱", Nullable: true},
+		}},
+		Success:  &Response{Code: "201", Summary: "OK"},
+		Error:    &Response{Code: "400", Summary: "ERROR", Params: []*Param{{Name: "message", Type: "string", Summary: "错误信息"}}},
+		Consumes: []string{"application/json", "application/xml"},
+	})
+	d.NewAPI(&API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Summary: "获取用户",
+		Params:  []*Param{{Name: "id", Type: "int", Summary: "用户 ID"}},
+		Queries: []*Param{{Name: "fields", Type: "array", Summary: "返回的字段"}},
+	})
+	d.NewAPI(&API{Method: "DELETE", URL: "/users/{id}", Summary: "删除用户"})
+
+	buf := new(bytes.Buffer)
+	a.NotError(d.WriteTextProto(buf))
+
+	m, err := parseTextProto(buf.String())
+	a.NotError(err, buf.String())
+
+	a.Equal(m["title"], []interface{}{"测试文档"}).
+		Equal(m["version"], []interface{}{"1.0.0"}).
+		Equal(m["base_url"], []interface{}{"https://api.example.com"}).
+		Nil(m["base_path"]). // 空值不输出
+		Nil(m["license_name"]).
+		Equal(len(m["endpoints"]), 3)
+
+	// 按地址和请求方法排序
+	post := m.message("endpoints", 0)
+	a.Equal(post["method"], []interface{}{"POST"}).
+		Equal(post["url"], []interface{}{"/users"}).
+		Equal(post["summary"], []interface{}{"添加用户"}).
+		Equal(post["description"], []interface{}{d.Apis[0].Description}).
+		Equal(post["group"], []interface{}{"users"}).
+		Equal(post["consumes"], []interface{}{"application/json", "application/xml"}).
+		Nil(post["produces"]).
+		Nil(post["params"])
+
+	req := post.message("request", 0)
+	a.Equal(req["type"], []interface{}{"json"}).
+		Equal(len(req["params"]), 2)
+	a.Equal(req.message("params", 0), textProtoMessage{
+		"name":    {"name"},
+		"type":    {"string"},
+		"summary": {"名称"},
+	})
+	a.Equal(req.message("params", 1), textProtoMessage{
+		"name":     {"email"},
+		"type":     {"string"},
+		"summary":  {"邮箱"},
+		"nullable": {true},
+	})
+
+	a.Equal(post.message("success", 0), textProtoMessage{"code": {"201"}, "summary": {"OK"}})
+	a.Equal(post.message("error", 0)["code"], []interface{}{"400"}).
+		Equal(post.message("error", 0).message("params", 0)["name"], []interface{}{"message"})
+
+	a.Equal(m.message("endpoints", 1)["method"], []interface{}{"DELETE"})
+	get := m.message("endpoints", 2)
+	a.Equal(get["method"], []interface{}{"GET"}).
+		Equal(get.message("params", 0)["name"], []interface{}{"id"}).
+		Equal(get.message("queries", 0)["name"], []interface{}{"fields"}).
+		Nil(get["request"]).
+		Nil(get["success"])
+
+	// 空文档
+	buf.Reset()
+	a.NotError(NewDoc().WriteTextProto(buf))
+	a.Empty(buf.String())
+}
+
+func TestTextProtoQuote(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(textProtoQuote(""), `""`)
+	a.Equal(textProtoQuote("中文"), `"中文"`)
+	a.Equal(textProtoQuote("a\"b\\c\n\r\t"), `"a\"b\\c\n\r\t"`)
+	a.Equal(textProtoQuote("\x00\x1f\x7f"), `"\000\037\177"`)
+}
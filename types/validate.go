@@ -0,0 +1,119 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+
+	"github.com/caixw/apidoc/locale"
+)
+
+// Validate 检测跨越多个 API 的内容是否正确，比如 @apiAuth 引用的认证方式是否存在，
+// @apiScope 的权限范围是否已经定义，@apiOperationID 是否有重复等。
+//
+// 此类错误无法在解析单个代码块时发现，需要在所有文档都解析完成之后调用。
+// 返回所有的错误信息，若没有错误，则返回空值。
+func (d *Doc) Validate() []error {
+	var errs []error
+	operationIDs := make(map[string]*API, len(d.Apis))
+
+	for _, api := range d.Apis {
+		if id := api.OperationID; len(id) > 0 {
+			if prev, found := operationIDs[id]; found {
+				errs = append(errs, errors.New(locale.Sprintf(locale.ErrDuplicateOperationID, api.Method, api.URL, id, prev.Method, prev.URL)))
+			} else {
+				operationIDs[id] = api
+			}
+		}
+
+		for _, name := range api.Auth {
+			if _, found := d.SecuritySchemes[name]; !found {
+				errs = append(errs, errors.New(locale.Sprintf(locale.ErrSecurityNotFound, api.Method, api.URL, name)))
+			}
+		}
+
+		if len(api.RequiredScopes) > 0 {
+			scopes := d.oauth2Scopes(api.Auth)
+			for _, scope := range api.RequiredScopes {
+				if !scopes[scope] {
+					errs = append(errs, errors.New(locale.Sprintf(locale.ErrScopeNotFound, api.Method, api.URL, scope)))
+				}
+			}
+		}
+
+		for name, val := range api.Extensions {
+			if !json.Valid(val) {
+				errs = append(errs, errors.New(locale.Sprintf(locale.ErrInvalidExtension, api.Method, api.URL, name)))
+			}
+		}
+
+		names := make([]string, 0, len(api.Formats))
+		for name := range api.Formats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if !api.hasParam(name) {
+				errs = append(errs, errors.New(locale.Sprintf(locale.ErrFormatParamNotFound, api.Method, api.URL, name)))
+			}
+		}
+
+		if req := api.Request; req != nil && req.Discriminator != nil && !containsParam(req.Params, req.Discriminator.Property) {
+			errs = append(errs, errors.New(locale.Sprintf(locale.ErrDiscriminatorNotFound, api.Method, api.URL, req.Discriminator.Property)))
+		}
+		for _, resp := range []*Response{api.Success, api.Error} {
+			if resp != nil && resp.Discriminator != nil && !containsParam(resp.Params, resp.Discriminator.Property) {
+				errs = append(errs, errors.New(locale.Sprintf(locale.ErrDiscriminatorNotFound, api.Method, api.URL, resp.Discriminator.Property)))
+			}
+		}
+	}
+
+	return errs
+}
+
+// 获取 auth 所引用的 oauth2 认证方式中定义的所有权限范围
+func (d *Doc) oauth2Scopes(auth []string) map[string]bool {
+	scopes := make(map[string]bool, 10)
+	for _, name := range auth {
+		if s, found := d.SecuritySchemes[name]; found && s.Type == SecurityTypeOAuth2 {
+			for _, scope := range s.Scopes {
+				scopes[scope] = true
+			}
+		}
+	}
+	return scopes
+}
+
+// 是否存在名为 name 的参数，包括 URL 参数、查询参数以及请求和返回内容中的参数。
+func (api *API) hasParam(name string) bool {
+	lists := [][]*Param{api.Params, api.Queries}
+	if api.Request != nil {
+		lists = append(lists, api.Request.Params)
+	}
+	for _, resp := range []*Response{api.Success, api.Error} {
+		if resp != nil {
+			lists = append(lists, resp.Params)
+		}
+	}
+
+	for _, params := range lists {
+		if containsParam(params, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// params 中是否存在名为 name 的参数
+func containsParam(params []*Param, name string) bool {
+	for _, p := range params {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
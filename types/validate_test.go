@@ -0,0 +1,117 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestDoc_Validate(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.SecuritySchemes["token"] = &Security{Name: "token", Type: SecurityTypeAPIKey, In: "header", Key: "X-Token"}
+	d.SecuritySchemes["basic"] = &Security{Name: "basic", Type: SecurityTypeHTTP, Scheme: "basic"}
+	d.NewAPI(&API{Method: "GET", URL: "/users", Auth: []string{"token", "basic"}})
+	d.NewAPI(&API{Method: "GET", URL: "/public"})
+	a.Empty(d.Validate())
+
+	// 引用了不存在的认证方式
+	d.NewAPI(&API{Method: "POST", URL: "/users", Auth: []string{"token", "oauth"}})
+	errs := d.Validate()
+	a.Equal(len(errs), 1)
+
+	// 扩展字段的值不是合法的 JSON
+	d.NewAPI(&API{Method: "GET", URL: "/ext", Extensions: map[string]json.RawMessage{
+		"x-valid":   json.RawMessage(`{"a":1}`),
+		"x-invalid": json.RawMessage(`{a}`),
+	}})
+	errs = d.Validate()
+	a.Equal(len(errs), 2)
+
+	// @apiFormat 引用了不存在的参数
+	d.NewAPI(&API{
+		Method:  "GET",
+		URL:     "/users/{id}",
+		Params:  []*Param{{Name: "id", Type: "string"}},
+		Queries: []*Param{{Name: "since", Type: "string"}},
+		Success: &Response{Code: "200", Params: []*Param{{Name: "email", Type: "string"}}},
+		Formats: map[string]string{"id": "uuid", "since": "date", "email": "email", "created": "date-time"},
+	})
+	errs = d.Validate()
+	a.Equal(len(errs), 3)
+	a.True(strings.Contains(errs[2].Error(), "created"))
+
+	// @apiDiscriminator 引用了不存在的字段
+	d.NewAPI(&API{
+		Method: "POST",
+		URL:    "/pets",
+		Request: &Request{
+			Params:        []*Param{{Name: "kind", Type: "string"}},
+			Discriminator: &Discriminator{Property: "kind", Mapping: map[string]string{"dog": "Dog", "cat": "Cat"}},
+		},
+		Success: &Response{
+			Code:          "201",
+			Params:        []*Param{{Name: "id", Type: "int"}},
+			Discriminator: &Discriminator{Property: "type"},
+		},
+	})
+	errs = d.Validate()
+	a.Equal(len(errs), 4)
+	a.True(strings.Contains(errs[3].Error(), "type"))
+}
+
+func TestDoc_Validate_scopes(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.SecuritySchemes["oauth"] = &Security{Name: "oauth", Type: SecurityTypeOAuth2, URL: "https://example.com/token", Scopes: []string{"users:read", "users:write"}}
+	d.SecuritySchemes["admin"] = &Security{Name: "admin", Type: SecurityTypeOAuth2, URL: "https://example.com/admin/token", Scopes: []string{"admin"}}
+	d.SecuritySchemes["token"] = &Security{Name: "token", Type: SecurityTypeAPIKey, In: "header", Key: "X-Token"}
+	d.NewAPI(&API{Method: "GET", URL: "/users", Auth: []string{"oauth"}, RequiredScopes: []string{"users:read"}, ScopeLogic: ScopeLogicAll})
+	d.NewAPI(&API{Method: "DELETE", URL: "/users", Auth: []string{"oauth", "admin"}, RequiredScopes: []string{"users:write", "admin"}, ScopeLogic: ScopeLogicAny})
+	a.Empty(d.Validate())
+
+	// 未在所使用的认证方式中定义，即使其它认证方式中有定义
+	d.NewAPI(&API{Method: "POST", URL: "/users", Auth: []string{"oauth"}, RequiredScopes: []string{"users:write", "admin"}, ScopeLogic: ScopeLogicAll})
+	errs := d.Validate()
+	a.Equal(len(errs), 1)
+	a.True(strings.Contains(errs[0].Error(), "admin"))
+
+	// 非 oauth2 的认证方式没有权限范围
+	d.NewAPI(&API{Method: "GET", URL: "/reports", Auth: []string{"token"}, RequiredScopes: []string{"reports"}, ScopeLogic: ScopeLogicAny})
+	errs = d.Validate()
+	a.Equal(len(errs), 2)
+	a.True(strings.Contains(errs[1].Error(), "reports"))
+
+	// 未指定认证方式
+	d.NewAPI(&API{Method: "GET", URL: "/orders", RequiredScopes: []string{"users:read"}, ScopeLogic: ScopeLogicAll})
+	errs = d.Validate()
+	a.Equal(len(errs), 3)
+}
+
+func TestDoc_Validate_operationID(t *testing.T) {
+	a := assert.New(t)
+
+	d := NewDoc()
+	d.NewAPI(&API{Method: "GET", URL: "/users", OperationID: "listUsers"})
+	d.NewAPI(&API{Method: "POST", URL: "/users", OperationID: "createUser"})
+	d.NewAPI(&API{Method: "GET", URL: "/public"}) // 未指定的不参与比较
+	d.NewAPI(&API{Method: "GET", URL: "/health"})
+	a.Empty(d.Validate())
+
+	// 重复的 operationId
+	d.NewAPI(&API{Method: "GET", URL: "/v2/users", OperationID: "listUsers"})
+	d.NewAPI(&API{Method: "GET", URL: "/v3/users", OperationID: "listUsers"})
+	errs := d.Validate()
+	a.Equal(len(errs), 2)
+	a.True(strings.Contains(errs[0].Error(), "/v2/users")).
+		True(strings.Contains(errs[0].Error(), "listUsers")).
+		True(strings.Contains(errs[1].Error(), "/v3/users"))
+}
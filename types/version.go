@@ -0,0 +1,34 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// CompareVersion 比较两个版本号的大小，v1 大于 v2 时返回正数，小于时返回负数，相等返回 0。
+//
+// 版本号以 . 分隔成多段，依次比较各段：都是数值的按数值比较，否则按字符串比较；
+// 前面各段都相同时，段数多的版本号较大。可以带 v 前缀，比如 v1.2 与 1.2 相等。
+func CompareVersion(v1, v2 string) int {
+	s1 := strings.Split(strings.TrimPrefix(strings.ToLower(v1), "v"), ".")
+	s2 := strings.Split(strings.TrimPrefix(strings.ToLower(v2), "v"), ".")
+
+	for i := 0; i < len(s1) && i < len(s2); i++ {
+		n1, err1 := strconv.Atoi(s1[i])
+		n2, err2 := strconv.Atoi(s2[i])
+		switch {
+		case err1 == nil && err2 == nil:
+			if n1 != n2 {
+				return n1 - n2
+			}
+		case s1[i] != s2[i]:
+			return strings.Compare(s1[i], s2[i])
+		}
+	}
+
+	return len(s1) - len(s2)
+}
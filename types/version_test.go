@@ -0,0 +1,22 @@
+// Copyright 2017 by caixw, All rights reserved.
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package types
+
+import (
+	"testing"
+
+	"github.com/issue9/assert"
+)
+
+func TestCompareVersion(t *testing.T) {
+	a := assert.New(t)
+
+	a.Equal(CompareVersion("1.0.0", "1.0.0"), 0)
+	a.Equal(CompareVersion("v1.0", "1.0"), 0)
+	a.True(CompareVersion("1.10.0", "1.9.0") > 0)
+	a.True(CompareVersion("1.2", "1.2.1") < 0)
+	a.True(CompareVersion("2.0.0", "10.0.0") < 0)
+	a.True(CompareVersion("1.0.b", "1.0.a") > 0)
+}
@@ -6,19 +6,86 @@ package vars
 
 // 所有标签的定义
 const (
-	API        = "@api"
-	APIDoc     = "@apidoc"
-	APILicense = "@apiLicense"
-	APIVersion = "@apiVersion"
-	APIParam   = "@apiParam"
-	APIQuery   = "@apiQuery"
-	APIHeader  = "@apiHeader"
-	APISuccess = "@apiSuccess"
-	APIError   = "@apiError"
-	APIRequest = "@apiRequest"
-	APIBaseURL = "@apiBaseURL"
-	APIGroup   = "@apiGroup"
-	APIIgnore  = "@apiIgnore"
-	APIContent = "@apiContent"
-	APIExample = "@apiExample"
+	API                   = "@api"
+	APIDoc                = "@apidoc"
+	APILicense            = "@apiLicense"
+	APIVersion            = "@apiVersion"
+	APIParam              = "@apiParam"
+	APIQuery              = "@apiQuery"
+	APIHeader             = "@apiHeader"
+	APISuccess            = "@apiSuccess"
+	APIError              = "@apiError"
+	APIRequest            = "@apiRequest"
+	APIBaseURL            = "@apiBaseURL"
+	APIBasePath           = "@apiBasePath"
+	APIGroup              = "@apiGroup"
+	APIIgnore             = "@apiIgnore"
+	APIContent            = "@apiContent"
+	APIExample            = "@apiExample"
+	APIProduces           = "@apiProduces"
+	APIConsumes           = "@apiConsumes"
+	APISecurity           = "@apiSecurity"
+	APIAuth               = "@apiAuth"
+	APIExtension          = "@apiExtension"
+	APIContentType        = "@apiContentType"
+	APIOrder              = "@apiOrder"
+	APINote               = "@apiNote"
+	APISafe               = "@apiSafe"
+	APIIdempotent         = "@apiIdempotent"
+	APIResponseHeader     = "@apiResponseHeader"
+	APITryIt              = "@apiTryIt"
+	APICallback           = "@apiCallback"
+	APIRetry              = "@apiRetry"
+	APICacheControl       = "@apiCacheControl"
+	APIThrows             = "@apiThrows"
+	APITodo               = "@apiTodo"
+	APILinkTo             = "@apiLinkTo"
+	APIMetric             = "@apiMetric"
+	APIOwner              = "@apiOwner"
+	APIEnvironment        = "@apiEnvironment"
+	APIContentNegotiation = "@apiContentNegotiation"
+	APIIdempotencyKey     = "@apiIdempotencyKey"
+	APIMultipart          = "@apiMultipart"
+	APIAccess             = "@apiAccess"
+	APICalls              = "@apiCalls"
+	APIFormat             = "@apiFormat"
+	APINullable           = "@apiNullable"
+	APIDiscriminator      = "@apiDiscriminator"
+	APIRequestID          = "@apiRequestID"
+	APITimeout            = "@apiTimeout"
+	APIGRPC               = "@apiGRPC"
+	APISSE                = "@apiSSE"
+	APIEvent              = "@apiEvent"
+	APIWebSocket          = "@apiWebSocket"
+	APIWSSend             = "@apiWSSend"
+	APIWSReceive          = "@apiWSReceive"
+	APIChangelog          = "@apiChangelog"
+	APISLA                = "@apiSLA"
+	APIBreakingChange     = "@apiBreakingChange"
+	APIContract           = "@apiContract"
+	APICORS               = "@apiCORS"
+	APIMockDelay          = "@apiMockDelay"
+	APIThrottle           = "@apiThrottle"
+	APIAuthError          = "@apiAuthError"
+	APIOperationID        = "@apiOperationID"
+	APIMediaType          = "@apiMediaType"
+	APIBatch              = "@apiBatch"
+	APIGraphQL            = "@apiGraphQL"
+	APIProducesEvent      = "@apiProducesEvent"
+	APIConsumesEvent      = "@apiConsumesEvent"
+	APIFeatureFlag        = "@apiFeatureFlag"
+	APIAudience           = "@apiAudience"
+	APITimeBudget         = "@apiTimeBudget"
+	APICircuitBreaker     = "@apiCircuitBreaker"
+	APIScope              = "@apiScope"
+	APITenant             = "@apiTenant"
+	APIRegion             = "@apiRegion"
+	APIDataClassification = "@apiDataClassification"
+	APICodegen            = "@apiCodegen"
+	APISocketIO           = "@apiSocketIO"
+	APIQuota              = "@apiQuota"
+	APIAsync              = "@apiAsync"
+	APIConflict           = "@apiConflict"
+	APIContentLength      = "@apiContentLength"
+	APISDK                = "@apiSDK"
 )
@@ -21,6 +21,9 @@ const (
 	// 配置文件名称。
 	ConfigFilename = ".apidoc.yaml"
 
+	// 忽略文件列表的文件名，位于输入目录的根目录下，格式与 .gitignore 相似。
+	IgnoreFilename = ".apidocignore"
+
 	// 默认的文档标题
 	DefaultTitle = "APIDOC"
 
@@ -35,18 +38,50 @@ const (
 	PprofCPU = "cpu"
 	PprofMem = "mem"
 
+	// 统计信息等内容的输出格式
+	FormatText = "text"
+	FormatJSON = "json"
+
+	// -diff 额外支持的输出格式，以 GitHub Actions 的注解形式输出
+	FormatGitHubActions = "github-actions"
+
 	// 生成的 JSON 数据存放的目录
 	JSONDataDirName = "data"
 
 	// JSON 生成的 JSON 文件，缩进量
 	JSONIndent = 2
 
+	// 模拟服务中 @apiMockDelay 等待时间的默认上限，单位为毫秒
+	DefaultMaxMockDelay = 1000
+
 	// 页面信息的文件名
 	PageFileName = "page"
 
 	// 组文件的前缀，有前缀，不会与现有文件重名
 	GroupFilePrefix = "group_"
 
+	// 指定文档生成时间的环境变量，值为 Unix 时间戳。
+	// 指定之后，相同的文档多次生成的 JSON 内容完全相同。
+	SourceDateEpochEnv = "SOURCE_DATE_EPOCH"
+
+	// RAML 文档的文件名
+	RAMLFileName = "apidoc.raml"
+
+	// API Blueprint 文档的文件名
+	BlueprintFileName = "apidoc.apib"
+
+	// 单页面文档的文件名
+	SinglePageFileName = "apidoc.html"
+
+	// Word 文档的文件名
+	WordFileName = "apidoc.docx"
+
+	// PDF 文档的文件名
+	PDFFileName = "apidoc.pdf"
+
+	// AsciiDoc 文档的文件名
+	AsciidocFileName = "apidoc.adoc"
+
 	// 控制台的颜色
 	InfoColor = colors.Green
 	WarnColor = colors.Cyan